@@ -0,0 +1,240 @@
+// Package bench measures routers on realistic route sets, so the routing
+// strategies of drouter can be compared with other routers on the route
+// shapes of an application before adopting it.
+//
+// A workload is a list of routes, e.g. one of the public API route sets
+// GitHubAPI, GPlusAPI and ParseAPI, a table generated by Random or the routes
+// of the application. Adapters build a router serving the routes, so the
+// same workload runs against each of them:
+//
+//	func BenchmarkRouters(b *testing.B) {
+//		bench.Compare(b, bench.GitHubAPI(), bench.HttpRouter(), bench.Tree(), chiAdapter)
+//	}
+//
+// The package does not depend on other routers. An adapter for e.g. chi is
+// written in a few lines:
+//
+//	var chiAdapter = bench.Adapter{
+//		Name: "chi",
+//		Build: func(routes []bench.Route, h http.Handler) (http.Handler, error) {
+//			r := chi.NewRouter()
+//			for _, rt := range routes {
+//				r.Method(rt.Method, bench.BracePath(rt.Path), h)
+//			}
+//			return r, nil
+//		},
+//	}
+package bench
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+	"github.com/thekhanj/drouter/dhttprouter"
+)
+
+// Route is a route of a workload. The path uses the pattern syntax of
+// drouter, e.g. "/users/:id" or "/files/*filepath".
+type Route struct {
+	Method string
+	Path   string
+}
+
+// Adapter builds a router of a kind under test.
+type Adapter struct {
+	Name string
+
+	// Build returns a router serving each of the routes with h. The params
+	// need not be passed on to h.
+	Build func(routes []Route, h http.Handler) (http.Handler, error)
+}
+
+// HttpRouter returns an adapter building a dhttprouter.HttpRouter. The
+// optional configure function is called before the routes are registered,
+// e.g. to compare the options of the router:
+//
+//	bench.Compare(b, routes,
+//		bench.HttpRouter(),
+//		bench.HttpRouter(func(r *dhttprouter.HttpRouter) { r.SaveMatchedRoutePath = true }),
+//	)
+//
+// Adapters with a configure function are named "HttpRouter/<n>", with n
+// counting the functions.
+func HttpRouter(configure ...func(*dhttprouter.HttpRouter)) Adapter {
+	name := "HttpRouter"
+	if len(configure) > 0 {
+		name = fmt.Sprintf("HttpRouter/%d", len(configure))
+	}
+	return Adapter{
+		Name: name,
+		Build: func(routes []Route, h http.Handler) (http.Handler, error) {
+			r := dhttprouter.New()
+			for _, c := range configure {
+				c(r)
+			}
+			handle := func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+				h.ServeHTTP(w, req)
+			}
+			for _, rt := range routes {
+				if err := r.TryHandle(rt.Method, rt.Path, handle); err != nil {
+					return nil, err
+				}
+			}
+			return r, nil
+		},
+	}
+}
+
+// Tree returns an adapter building a drouter.Router per method, which
+// measures the lookup in the radix tree without the features of the
+// HttpRouter, e.g. redirects and 405 responses.
+func Tree() Adapter {
+	return Adapter{
+		Name: "Tree",
+		Build: func(routes []Route, h http.Handler) (http.Handler, error) {
+			t := &treeHandler{routers: map[string]*drouter.Router[http.Handler]{}}
+			var maxParams uint16
+			for _, rt := range routes {
+				router := t.routers[rt.Method]
+				if router == nil {
+					router = drouter.New[http.Handler]()
+					t.routers[rt.Method] = router
+				}
+				if err := router.TryAddRoute(rt.Path, h); err != nil {
+					return nil, err
+				}
+				if n := drouter.CountParams(rt.Path); n > maxParams {
+					maxParams = n
+				}
+			}
+			t.params.New = func() interface{} {
+				ps := make(drouter.Params, 0, maxParams)
+				return &ps
+			}
+			return t, nil
+		},
+	}
+}
+
+// treeHandler serves requests with the trees of the Tree adapter, pooling
+// the Params like the HttpRouter.
+type treeHandler struct {
+	routers map[string]*drouter.Router[http.Handler]
+	params  sync.Pool
+}
+
+func (t *treeHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if router := t.routers[req.Method]; router != nil {
+		ps := t.params.Get().(*drouter.Params)
+		*ps = (*ps)[:0]
+		h, _ := router.Lookup(req.URL.Path, ps)
+		t.params.Put(ps)
+		if h != nil {
+			h.ServeHTTP(w, req)
+			return
+		}
+	}
+	http.NotFound(w, req)
+}
+
+// BracePath converts a path to the pattern syntax of routers using braces,
+// e.g. "/users/:id/*rest" to "/users/{id}/*".
+func BracePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		switch {
+		case strings.HasPrefix(s, ":"):
+			segments[i] = "{" + s[1:] + "}"
+		case strings.HasPrefix(s, "*"):
+			segments[i] = "*"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Requests returns a request per route, with the name of each param as its
+// value, e.g. "/users/id" for "/users/:id" and "/files/filepath" for
+// "/files/*filepath".
+func Requests(routes []Route) []*http.Request {
+	reqs := make([]*http.Request, 0, len(routes))
+	for _, rt := range routes {
+		segments := strings.Split(rt.Path, "/")
+		for i, s := range segments {
+			if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+				segments[i] = s[1:]
+			}
+		}
+		req, err := http.NewRequest(rt.Method, strings.Join(segments, "/"), nil)
+		if err != nil {
+			panic(err)
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs
+}
+
+// Verify builds the router of the adapter and checks that the request of
+// each route, see Requests, is served, so a benchmark does not measure 404
+// responses.
+func Verify(routes []Route, a Adapter) error {
+	served := false
+	h, err := a.Build(routes, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		served = true
+	}))
+	if err != nil {
+		return fmt.Errorf("%s: %w", a.Name, err)
+	}
+	for _, req := range Requests(routes) {
+		served = false
+		h.ServeHTTP(&discardWriter{header: http.Header{}}, req)
+		if !served {
+			return fmt.Errorf("%s: %s %s is not served", a.Name, req.Method, req.URL.Path)
+		}
+	}
+	return nil
+}
+
+// Run serves the requests with h b.N times, reporting allocations.
+func Run(b *testing.B, h http.Handler, reqs []*http.Request) {
+	w := &discardWriter{header: http.Header{}}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, req := range reqs {
+			h.ServeHTTP(w, req)
+		}
+	}
+}
+
+// Compare runs the requests of the routes against the router of each
+// adapter, as sub-benchmarks named after the adapters. It fails the
+// benchmark if a router does not serve all routes, see Verify.
+func Compare(b *testing.B, routes []Route, adapters ...Adapter) {
+	reqs := Requests(routes)
+	noop := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})
+	for _, a := range adapters {
+		b.Run(a.Name, func(b *testing.B) {
+			if err := Verify(routes, a); err != nil {
+				b.Fatal(err)
+			}
+			h, err := a.Build(routes, noop)
+			if err != nil {
+				b.Fatal(err)
+			}
+			Run(b, h, reqs)
+		})
+	}
+}
+
+// discardWriter is a http.ResponseWriter discarding the response.
+type discardWriter struct {
+	header http.Header
+}
+
+func (w *discardWriter) Header() http.Header         { return w.header }
+func (w *discardWriter) Write(p []byte) (int, error) { return len(p), nil }
+func (w *discardWriter) WriteHeader(int)             {}
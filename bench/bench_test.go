@@ -0,0 +1,64 @@
+package bench
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/thekhanj/drouter/dhttprouter"
+)
+
+func TestVerify(t *testing.T) {
+	adapters := []Adapter{
+		HttpRouter(),
+		HttpRouter(func(r *dhttprouter.HttpRouter) { r.SaveMatchedRoutePath = true }),
+		Tree(),
+	}
+	workloads := map[string][]Route{
+		"GitHub": GitHubAPI(),
+		"GPlus":  GPlusAPI(),
+		"Parse":  ParseAPI(),
+		"Random": Random(1, 500, 5, 0.3),
+		"Static": {{http.MethodGet, "/"}, {http.MethodGet, "/files/*filepath"}},
+	}
+	for name, routes := range workloads {
+		for _, a := range adapters {
+			if err := Verify(routes, a); err != nil {
+				t.Errorf("%s: %v", name, err)
+			}
+		}
+	}
+
+	// Routes which are not served fail
+	broken := Adapter{Name: "broken", Build: func([]Route, http.Handler) (http.Handler, error) {
+		return http.NotFoundHandler(), nil
+	}}
+	if err := Verify(ParseAPI(), broken); err == nil || !strings.HasPrefix(err.Error(), "broken: ") {
+		t.Errorf("wrong error %v", err)
+	}
+	conflict := []Route{{http.MethodGet, "/a/:b"}, {http.MethodGet, "/a/:c"}}
+	if err := Verify(conflict, HttpRouter()); err == nil {
+		t.Error("no error for conflicting routes")
+	}
+}
+
+func TestRequests(t *testing.T) {
+	reqs := Requests([]Route{{http.MethodPut, "/users/:id/files/*path"}})
+	if len(reqs) != 1 || reqs[0].Method != http.MethodPut || reqs[0].URL.Path != "/users/id/files/path" {
+		t.Errorf("wrong requests %v", reqs)
+	}
+}
+
+func TestBracePath(t *testing.T) {
+	if got := BracePath("/users/:id/files/*path"); got != "/users/{id}/files/*" {
+		t.Errorf("wrong path %q", got)
+	}
+}
+
+func BenchmarkGitHubAPI(b *testing.B) {
+	Compare(b, GitHubAPI(), HttpRouter(), Tree())
+}
+
+func BenchmarkRandom(b *testing.B) {
+	Compare(b, Random(1, 1000, 6, 0.5), HttpRouter(), Tree())
+}
@@ -0,0 +1,63 @@
+package bench
+
+import (
+	"math/rand"
+	"net/http"
+	"strconv"
+)
+
+// Random returns n GET routes of the given depth in segments, generated
+// from the seed. Each segment but the first is a param with the probability
+// paramShare, shared by all routes with the same prefix, and static
+// otherwise, e.g.
+// "/r0/s3/:p2/s0". Routes with a high share of params measure the cost of
+// capturing params, routes with a low share the cost of matching static
+// segments. The result is the same for the same arguments.
+func Random(seed int64, n, depth int, paramShare float64) []Route {
+	if n <= 0 || depth <= 0 {
+		return nil
+	}
+	rnd := rand.New(rand.NewSource(seed))
+
+	// Static nodes fan out, so the leaves at the given depth outnumber n
+	fanout := 2
+	for pow(fanout, depth) < n {
+		fanout++
+	}
+
+	var routes []Route
+	var walk func(prefix string, level int)
+	walk = func(prefix string, level int) {
+		if len(routes) == n {
+			return
+		}
+		if level == depth {
+			routes = append(routes, Route{Method: http.MethodGet, Path: prefix})
+			return
+		}
+		if rnd.Float64() < paramShare {
+			walk(prefix+"/:p"+strconv.Itoa(level), level+1)
+			return
+		}
+		for i := 0; i < fanout; i++ {
+			walk(prefix+"/s"+strconv.Itoa(i), level+1)
+		}
+	}
+	for len(routes) < n {
+		// The root is static, so the routes are unique
+		before := len(routes)
+		walk("/r"+strconv.Itoa(len(routes)), 1)
+		if len(routes) == before {
+			break
+		}
+	}
+	return routes
+}
+
+func pow(base, exp int) int {
+	n := 1
+	for i := 0; i < exp; i++ {
+		n *= base
+	}
+	return n
+}
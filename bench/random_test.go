@@ -0,0 +1,39 @@
+package bench
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRandom(t *testing.T) {
+	routes := Random(7, 300, 4, 0.5)
+	if len(routes) != 300 {
+		t.Fatalf("wrong number of routes %d", len(routes))
+	}
+
+	params := 0
+	seen := map[string]bool{}
+	for _, rt := range routes {
+		if seen[rt.Path] {
+			t.Errorf("duplicate route %s", rt.Path)
+		}
+		seen[rt.Path] = true
+		if n := strings.Count(rt.Path, "/"); n != 4 {
+			t.Errorf("wrong depth of %s", rt.Path)
+		}
+		params += strings.Count(rt.Path, ":")
+	}
+	if params == 0 {
+		t.Error("no params generated")
+	}
+
+	again := Random(7, 300, 4, 0.5)
+	for i := range routes {
+		if again[i] != routes[i] {
+			t.Fatalf("routes differ for the same seed: %v, %v", again[i], routes[i])
+		}
+	}
+	if static := Random(7, 50, 3, 0); strings.Contains(static[len(static)-1].Path, ":") {
+		t.Errorf("param without share: %v", static)
+	}
+}
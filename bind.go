@@ -0,0 +1,277 @@
+package drouter
+
+import (
+	"encoding"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// BindError is returned by Params.Bind and the typed accessors of Params if
+// the value of a parameter cannot be converted to the requested type.
+type BindError struct {
+	// Name of the parameter
+	Key string
+
+	// Value of the parameter
+	Value string
+
+	// Name of the struct field, empty for the typed accessors
+	Field string
+
+	// The conversion error
+	Err error
+}
+
+// ErrMissingParam is reported by the typed accessors of Params if the
+// parameter is missing.
+var ErrMissingParam = errors.New("missing parameter")
+
+func (e *BindError) Error() string {
+	if e.Err == ErrMissingParam {
+		return "missing parameter '" + e.Key + "'"
+	}
+	return "invalid value '" + e.Value + "' for parameter '" + e.Key + "': " + e.Err.Error()
+}
+
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+)
+
+// Bind stores the values of the Params in the fields of the struct dst points
+// to. Fields are bound to the parameter named by their `param` tag, fields
+// without tag and parameters without field are ignored, as are fields whose
+// parameter is missing. Embedded structs are bound recursively.
+//
+//	var args struct {
+//		User  int       `param:"user"`
+//		Since time.Time `param:"since" layout:"2006-01-02"`
+//	}
+//	if err := ps.Bind(&args); err != nil {
+//		http.Error(w, err.Error(), http.StatusBadRequest)
+//		return
+//	}
+//
+// Values are converted to the type of the field. Supported are strings, bools,
+// signed and unsigned integers, floats, time.Duration, time.Time, parsed with
+// the layout of the field's `layout` tag or RFC 3339, types implementing
+// encoding.TextUnmarshaler, and 16 byte arrays like UUID types, parsed from
+// the canonical 8-4-4-4-12 form. Values which cannot be converted are
+// reported as *BindError naming the parameter.
+func (ps Params) Bind(dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New("bind destination must be a non-nil pointer to a struct")
+	}
+	return ps.bindStruct(v.Elem())
+}
+
+func (ps Params) bindStruct(v reflect.Value) error {
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		key, ok := field.Tag.Lookup("param")
+		if !ok {
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				if err := ps.bindStruct(v.Field(i)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if key == "-" || field.PkgPath != "" {
+			continue
+		}
+
+		value, found := ps.lookup(key)
+		if !found {
+			continue
+		}
+		if err := bindValue(v.Field(i), value, field.Tag.Get("layout")); err != nil {
+			return &BindError{Key: key, Value: value, Field: field.Name, Err: err}
+		}
+	}
+	return nil
+}
+
+// lookup returns the value of the first Param with the given key and whether
+// there is one.
+func (ps Params) lookup(key string) (string, bool) {
+	for _, p := range ps {
+		if p.Key == key {
+			return p.Value, true
+		}
+	}
+	return "", false
+}
+
+// bindValue converts the value to the type of v and stores it in v.
+func bindValue(v reflect.Value, value, layout string) error {
+	switch v.Type() {
+	case timeType:
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Array:
+		if v.Len() != 16 || v.Type().Elem().Kind() != reflect.Uint8 {
+			return errors.New("unsupported field type " + v.Type().String())
+		}
+		if !isUUID(value) {
+			return errors.New("not a UUID")
+		}
+		var id [16]byte
+		hex.Decode(id[:], []byte(value[0:8]+value[9:13]+value[14:18]+value[19:23]+value[24:]))
+		reflect.Copy(v, reflect.ValueOf(id[:]))
+	default:
+		return errors.New("unsupported field type " + v.Type().String())
+	}
+	return nil
+}
+
+// value returns the value of the named Param, or a *BindError wrapping
+// ErrMissingParam if there is none.
+func (ps Params) value(name string) (string, error) {
+	value, ok := ps.lookup(name)
+	if !ok {
+		return "", &BindError{Key: name, Err: ErrMissingParam}
+	}
+	return value, nil
+}
+
+// Int returns the value of the named Param as int. Missing and invalid
+// values are reported as *BindError.
+func (ps Params) Int(name string) (int, error) {
+	value, err := ps.value(name)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseInt(value, 10, 0)
+	if err != nil {
+		return 0, &BindError{Key: name, Value: value, Err: err}
+	}
+	return int(n), nil
+}
+
+// Int64 returns the value of the named Param as int64. Missing and invalid
+// values are reported as *BindError.
+func (ps Params) Int64(name string) (int64, error) {
+	value, err := ps.value(name)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, &BindError{Key: name, Value: value, Err: err}
+	}
+	return n, nil
+}
+
+// Uint returns the value of the named Param as uint64. Missing and invalid
+// values are reported as *BindError.
+func (ps Params) Uint(name string) (uint64, error) {
+	value, err := ps.value(name)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, &BindError{Key: name, Value: value, Err: err}
+	}
+	return n, nil
+}
+
+// Float returns the value of the named Param as float64. Missing and invalid
+// values are reported as *BindError.
+func (ps Params) Float(name string) (float64, error) {
+	value, err := ps.value(name)
+	if err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, &BindError{Key: name, Value: value, Err: err}
+	}
+	return f, nil
+}
+
+// Bool returns the value of the named Param as bool, see strconv.ParseBool.
+// Missing and invalid values are reported as *BindError.
+func (ps Params) Bool(name string) (bool, error) {
+	value, err := ps.value(name)
+	if err != nil {
+		return false, err
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, &BindError{Key: name, Value: value, Err: err}
+	}
+	return b, nil
+}
+
+// Time returns the value of the named Param parsed with the given layout,
+// see time.Parse. Missing and invalid values are reported as *BindError.
+func (ps Params) Time(name, layout string) (time.Time, error) {
+	value, err := ps.value(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}, &BindError{Key: name, Value: value, Err: err}
+	}
+	return t, nil
+}
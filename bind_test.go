@@ -0,0 +1,159 @@
+package drouter
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testUUID [16]byte
+
+type upper string
+
+func (u *upper) UnmarshalText(text []byte) error {
+	*u = upper(strings.ToUpper(string(text)))
+	return nil
+}
+
+func TestParamsBind(t *testing.T) {
+	type Page struct {
+		Page uint8 `param:"page"`
+	}
+	var args struct {
+		Page
+		User    int           `param:"user"`
+		Name    upper         `param:"name"`
+		Draft   bool          `param:"draft"`
+		Ratio   float64       `param:"ratio"`
+		Since   time.Time     `param:"since" layout:"2006-01-02"`
+		At      time.Time     `param:"at"`
+		TTL     time.Duration `param:"ttl"`
+		ID      testUUID      `param:"id"`
+		Missing string        `param:"missing"`
+		Ignored string
+	}
+	args.Missing = "unchanged"
+
+	ps := Params{
+		{"user", "-42"},
+		{"name", "gopher"},
+		{"draft", "true"},
+		{"ratio", "0.5"},
+		{"since", "2024-02-29"},
+		{"at", "2024-02-29T12:00:00Z"},
+		{"ttl", "90s"},
+		{"id", "123e4567-e89b-12d3-a456-426614174000"},
+		{"page", "3"},
+		{"Ignored", "x"},
+	}
+	if err := ps.Bind(&args); err != nil {
+		t.Fatal(err)
+	}
+
+	if args.User != -42 || args.Name != "GOPHER" || !args.Draft || args.Ratio != 0.5 || args.Page.Page != 3 {
+		t.Errorf("wrong values: %+v", args)
+	}
+	if !args.Since.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)) ||
+		!args.At.Equal(time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("wrong times: %v, %v", args.Since, args.At)
+	}
+	if args.TTL != 90*time.Second {
+		t.Errorf("wrong duration: %v", args.TTL)
+	}
+	if args.ID != (testUUID{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}) {
+		t.Errorf("wrong UUID: %x", args.ID)
+	}
+	if args.Missing != "unchanged" || args.Ignored != "" {
+		t.Errorf("unbound fields changed: %q, %q", args.Missing, args.Ignored)
+	}
+}
+
+func TestParamsBindErrors(t *testing.T) {
+	var args struct {
+		User  int      `param:"user"`
+		Page  uint8    `param:"page"`
+		ID    testUUID `param:"id"`
+		Flags []string `param:"flags"`
+	}
+
+	tests := []struct {
+		ps    Params
+		key   string
+		field string
+	}{
+		{Params{{"user", "gopher"}}, "user", "User"},
+		{Params{{"page", "256"}}, "page", "Page"},
+		{Params{{"id", "123"}}, "id", "ID"},
+		{Params{{"flags", "a,b"}}, "flags", "Flags"},
+	}
+	for _, test := range tests {
+		err := test.ps.Bind(&args)
+		var berr *BindError
+		if !errors.As(err, &berr) {
+			t.Errorf("%v: expected BindError, got %v", test.ps, err)
+			continue
+		}
+		if berr.Key != test.key || berr.Field != test.field {
+			t.Errorf("%v: wrong key or field: %q, %q", test.ps, berr.Key, berr.Field)
+		}
+		if !strings.Contains(err.Error(), "'"+test.key+"'") {
+			t.Errorf("%v: error does not name the parameter: %v", test.ps, err)
+		}
+	}
+
+	err := Params{{"page", "256"}}.Bind(&args)
+	if !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("conversion error is not wrapped: %v", err)
+	}
+
+	if err := (Params{}).Bind(args); err == nil {
+		t.Error("no error for non-pointer destination")
+	}
+}
+
+func TestParamsAccessors(t *testing.T) {
+	ps := Params{
+		{"id", "42"},
+		{"offset", "-7"},
+		{"ratio", "1.5"},
+		{"draft", "false"},
+		{"day", "2024-02-29"},
+		{"name", "gopher"},
+	}
+
+	if n, err := ps.Int("id"); n != 42 || err != nil {
+		t.Errorf("Int: %d, %v", n, err)
+	}
+	if n, err := ps.Int64("offset"); n != -7 || err != nil {
+		t.Errorf("Int64: %d, %v", n, err)
+	}
+	if n, err := ps.Uint("id"); n != 42 || err != nil {
+		t.Errorf("Uint: %d, %v", n, err)
+	}
+	if f, err := ps.Float("ratio"); f != 1.5 || err != nil {
+		t.Errorf("Float: %v, %v", f, err)
+	}
+	if b, err := ps.Bool("draft"); b || err != nil {
+		t.Errorf("Bool: %v, %v", b, err)
+	}
+	if d, err := ps.Time("day", "2006-01-02"); !d.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)) || err != nil {
+		t.Errorf("Time: %v, %v", d, err)
+	}
+
+	if _, err := ps.Uint("offset"); err == nil {
+		t.Error("no error for negative Uint")
+	}
+	var berr *BindError
+	if _, err := ps.Int("name"); !errors.As(err, &berr) || berr.Key != "name" || berr.Value != "gopher" {
+		t.Errorf("wrong error for invalid value: %v", err)
+	}
+	_, err := ps.Bool("missing")
+	if !errors.Is(err, ErrMissingParam) {
+		t.Errorf("wrong error for missing value: %v", err)
+	}
+	if err.Error() != "missing parameter 'missing'" {
+		t.Errorf("wrong message: %q", err.Error())
+	}
+}
@@ -0,0 +1,74 @@
+// Command drouter-gen generates a Go file with a matcher for a fixed list
+// of routes, see package gen.
+//
+// Usage:
+//
+//	drouter-gen -pkg routes [-o routes_gen.go] [routes.txt]
+//
+// The routes are read from the named file, or from standard input, one per
+// line with the method, the path and an optional name of the generated
+// constant:
+//
+//	GET  /users/:id  GetUser
+//	POST /users
+//
+// The generated file is written to standard output unless -o is given.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/thekhanj/drouter/gen"
+)
+
+func main() {
+	pkg := flag.String("pkg", "", "package name of the generated file")
+	out := flag.String("o", "", "output file, standard output if empty")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: drouter-gen -pkg name [-o file] [routes]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *pkg == "" || flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(*pkg, *out, flag.Arg(0)); err != nil {
+		fmt.Fprintln(os.Stderr, "drouter-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(pkg, out, in string) error {
+	var rd io.Reader = os.Stdin
+	name := "stdin"
+	if in != "" {
+		name = in
+		f, err := os.Open(in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		rd = f
+	}
+
+	routes, err := gen.ParseRoutes(rd)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	src, err := gen.Generate(pkg, routes)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return ioutil.WriteFile(out, src, 0o644)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	in, out := filepath.Join(dir, "routes.txt"), filepath.Join(dir, "routes_gen.go")
+	if err := ioutil.WriteFile(in, []byte("GET /users/:id GetUser\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := run("routes", out, in); err != nil {
+		t.Fatal(err)
+	}
+	src, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(src), "package routes") || !strings.Contains(string(src), `GetUser: "/users/:id"`) {
+		t.Errorf("wrong output:\n%s", src)
+	}
+
+	if err := ioutil.WriteFile(in, []byte("GET /users/:id|int\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := run("routes", out, in); err == nil || !strings.HasPrefix(err.Error(), in+": GET /users/:id|int: ") {
+		t.Errorf("wrong error %v", err)
+	}
+}
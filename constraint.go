@@ -0,0 +1,161 @@
+package drouter
+
+import "sync"
+
+// registerMu serializes the registration of constraints and transforms,
+// which share their names.
+var registerMu sync.Mutex
+
+var constraints = struct {
+	sync.RWMutex
+	m map[string]func(string) bool
+}{
+	m: map[string]func(string) bool{
+		"int":   isInt,
+		"uint":  isUint,
+		"alpha": isAlpha,
+		"alnum": isAlnum,
+		"hex":   isHex,
+		"uuid":  isUUID,
+	},
+}
+
+// RegisterConstraint registers a named parameter constraint, which can then be
+// attached to named parameters of routes, e.g. "/users/:id|even".
+// The function reports whether a parameter value satisfies the constraint.
+// A request path whose value does not satisfy it does not match the route.
+//
+// The following constraints are built in:
+//
+//	int    optionally signed decimal number
+//	uint   unsigned decimal number
+//	alpha  ASCII letters
+//	alnum  ASCII letters and digits
+//	hex    hexadecimal digits
+//	uuid   UUID in its canonical 8-4-4-4-12 form
+//
+// Constraints may be preceded by transforms, see RegisterTransform. They are
+// resolved when a route is added, so they must be registered before.
+// RegisterConstraint panics if the name is empty or contains '/', '|', ':'
+// or '*', if fn is nil or if a constraint or transform with the name is
+// already registered.
+func RegisterConstraint(name string, fn func(string) bool) {
+	if name == "" {
+		panic("constraint name must not be empty")
+	}
+	for i := 0; i < len(name); i++ {
+		switch name[i] {
+		case '/', '|', ':', '*':
+			panic("invalid character '" + name[i:i+1] + "' in constraint name '" + name + "'")
+		}
+	}
+	if fn == nil {
+		panic("constraint function must not be nil")
+	}
+
+	registerMu.Lock()
+	defer registerMu.Unlock()
+	if lookupTransform(name) != nil {
+		panic("a transform named '" + name + "' is already registered")
+	}
+
+	constraints.Lock()
+	defer constraints.Unlock()
+	if _, ok := constraints.m[name]; ok {
+		panic("a constraint named '" + name + "' is already registered")
+	}
+	constraints.m[name] = fn
+}
+
+// Constraint returns the function of the constraint registered with the given
+// name, or nil if there is none.
+func Constraint(name string) func(string) bool {
+	return lookupConstraint(name)
+}
+
+// lookupConstraint returns the constraint registered with the given name, or
+// nil if there is none.
+func lookupConstraint(name string) func(string) bool {
+	constraints.RLock()
+	fn := constraints.m[name]
+	constraints.RUnlock()
+	return fn
+}
+
+func isUint(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isInt(s string) bool {
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+	return isUint(s)
+}
+
+func isAlpha(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i] | 0x20; c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlnum(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < '0' || c > '9') && !isAlpha(s[i:i+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c|0x20 >= 'a' && c|0x20 <= 'f')
+}
+
+func isHex(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isHexDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return false
+			}
+		default:
+			if !isHexDigit(s[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+package drouter
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestTreeConstraints(t *testing.T) {
+	tree := &node[Handle]{}
+
+	routes := [...]string{
+		"/users/:id|int",
+		"/users/:id|int/posts/:slug|alpha",
+		"/objects/:uuid|uuid",
+		"/colors/:hex|hex/",
+		"/plain/:name",
+	}
+	for _, route := range routes {
+		tree.addRoute(route, fakeHandle(route))
+	}
+
+	checkRequests(t, tree, testRequests{
+		{"/users/42", false, "/users/:id|int", Params{Param{"id", "42"}}},
+		{"/users/-7", false, "/users/:id|int", Params{Param{"id", "-7"}}},
+		{"/users/gopher", true, "", nil},
+		{"/users/", true, "", nil},
+		{"/users/42/posts/Hello", false, "/users/:id|int/posts/:slug|alpha", Params{Param{"id", "42"}, Param{"slug", "Hello"}}},
+		{"/users/42/posts/hello-world", true, "", Params{Param{"id", "42"}}},
+		{"/users/x/posts/hello", true, "", nil},
+		{"/objects/123e4567-e89b-12d3-a456-426614174000", false, "/objects/:uuid|uuid", Params{Param{"uuid", "123e4567-e89b-12d3-a456-426614174000"}}},
+		{"/objects/123e4567", true, "", nil},
+		{"/colors/ff00AA/", false, "/colors/:hex|hex/", Params{Param{"hex", "ff00AA"}}},
+		{"/colors/red/", true, "", nil},
+		{"/plain/anything", false, "/plain/:name", Params{Param{"name", "anything"}}},
+	})
+
+	// No case-insensitive match for violating values either
+	if _, found := tree.findCaseInsensitivePath("/USERS/gopher", true); found {
+		t.Error("case-insensitive lookup matched a value violating the constraint")
+	}
+	if out, found := tree.findCaseInsensitivePath("/USERS/42", true); !found || out != "/users/42" {
+		t.Errorf("wrong case-insensitive lookup result: found=%t, out=%q", found, out)
+	}
+}
+
+func TestTreeConstraintInvalid(t *testing.T) {
+	tests := []struct {
+		path string
+		err  string
+	}{
+		{"/users/:id|nope", "unknown constraint"},
+		{"/users/:id|", "unknown constraint"},
+		{"/users/:|int", "non-empty name"},
+		{"/src/*filepath|int", "only allowed for named parameters"},
+	}
+	for _, test := range tests {
+		recv := catchPanic(func() {
+			tree := &node[Handle]{}
+			tree.addRoute(test.path, fakeHandle(test.path))
+		})
+		if rs, ok := recv.(string); !ok || !strings.Contains(rs, test.err) {
+			t.Errorf("wrong panic for %s: want %q, got %v", test.path, test.err, recv)
+		}
+	}
+
+	// Different constraints for the same wildcard conflict
+	testRoutes(t, []testRoute{
+		{"/users/:id|int", false},
+		{"/users/:id|uuid", true},
+		{"/users/:id", true},
+	})
+}
+
+func TestRegisterConstraint(t *testing.T) {
+	RegisterConstraint("test-even", func(s string) bool {
+		return isUint(s) && (s[len(s)-1]-'0')%2 == 0
+	})
+
+	router := New[Handle]()
+	router.AddRoute("/numbers/:n|test-even", fakeHandle("even"))
+	if handle, _ := router.Lookup("/numbers/42", nil); handle == nil {
+		t.Error("custom constraint rejected valid value")
+	}
+	if handle, _ := router.Lookup("/numbers/43", nil); handle != nil {
+		t.Error("custom constraint accepted invalid value")
+	}
+
+	for _, name := range []string{"", "a|b", "a/b", "test-even", "int"} {
+		recv := catchPanic(func() {
+			RegisterConstraint(name, isUint)
+		})
+		if recv == nil {
+			t.Errorf("registering constraint %q did not panic", name)
+		}
+	}
+	if recv := catchPanic(func() { RegisterConstraint("test-nil", nil) }); recv == nil {
+		t.Error("registering nil constraint did not panic")
+	}
+}
+
+func TestRegisterConstraintTransformRace(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		name := "test-race-" + strconv.Itoa(i)
+		var wg sync.WaitGroup
+		var panics int32
+		register := func(fn func()) {
+			defer wg.Done()
+			if catchPanic(fn) != nil {
+				atomic.AddInt32(&panics, 1)
+			}
+		}
+		wg.Add(2)
+		go register(func() { RegisterConstraint(name, isUint) })
+		go register(func() { RegisterTransform(name, strings.TrimSpace) })
+		wg.Wait()
+
+		if panics != 1 {
+			t.Fatalf("%d of the registrations of %q panicked; want 1", panics, name)
+		}
+	}
+}
+
+func TestBuiltinConstraints(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid []string
+		wrong []string
+	}{
+		{"int", []string{"0", "42", "-1", "+7"}, []string{"", "-", "4.2", "1e3", "x"}},
+		{"uint", []string{"0", "42"}, []string{"", "-1", "+1", "x"}},
+		{"alpha", []string{"abc", "ABC", "aBc"}, []string{"", "a1", "a-b", "ä", "@", "["}},
+		{"alnum", []string{"abc", "a1", "42"}, []string{"", "a-1", "a_1"}},
+		{"hex", []string{"ff", "00AF", "9"}, []string{"", "fg", "0x1"}},
+		{"uuid", []string{"123e4567-e89b-12d3-a456-426614174000"}, []string{"", "123e4567e89b12d3a456426614174000", "123e4567-e89b-12d3-a456-42661417400g"}},
+	}
+	for _, test := range tests {
+		fn := lookupConstraint(test.name)
+		for _, s := range test.valid {
+			if !fn(s) {
+				t.Errorf("constraint %s rejected %q", test.name, s)
+			}
+		}
+		for _, s := range test.wrong {
+			if fn(s) {
+				t.Errorf("constraint %s accepted %q", test.name, s)
+			}
+		}
+	}
+}
+
+func TestConstraint(t *testing.T) {
+	if check := Constraint("uint"); check == nil || !check("42") || check("-1") {
+		t.Error("wrong built-in constraint")
+	}
+	if Constraint("test-missing") != nil {
+		t.Error("unknown constraint is not nil")
+	}
+}
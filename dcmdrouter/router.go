@@ -0,0 +1,232 @@
+// Package dcmdrouter routes command-line argument vectors onto handlers,
+// using the radix tree of drouter, which scales better to large CLIs than
+// nested switch statements. Commands are declared as space-separated words
+// with positional parameters in angle brackets and an optional trailing
+// parameter for the remaining arguments:
+//
+//	router := dcmdrouter.New[func(m dcmdrouter.Match) error]()
+//	router.Handle("remote add <name> <url>", remoteAdd)
+//	router.Handle("remote remove <name>", remoteRemove)
+//	router.Handle("add <paths...>", add)
+//
+//	m, ok := router.Lookup(os.Args[1:])
+//	if !ok {
+//		fmt.Fprintln(os.Stderr, "unknown command, did you mean:", router.Suggest(os.Args[1:], 3))
+//		os.Exit(2)
+//	}
+//	err := m.Handler(m.Match)
+//
+// Arguments are matched as they are, so flags are best parsed by the handlers
+// from the remaining arguments, e.g. with a flag.FlagSet.
+package dcmdrouter
+
+import (
+	"strings"
+
+	"github.com/thekhanj/drouter"
+)
+
+// Match holds the arguments of a matched command.
+type Match struct {
+	// The command as registered, e.g. "remote add <name> <url>"
+	Command string
+
+	// Values of the positional parameters, e.g. name and url
+	Params drouter.Params
+
+	// Arguments matched by the trailing parameter, e.g. paths
+	Rest []string
+}
+
+// HandlerMatch is a Match with the handler of the command.
+type HandlerMatch[H any] struct {
+	Match
+	Handler H
+}
+
+// Router maps argument vectors onto handlers of type H.
+type Router[H any] struct {
+	tree      *drouter.Router[*entry[H]]
+	commands  map[string]string
+	maxParams uint16
+}
+
+// entry is a registered handler. The tree stores pointers, so a missing
+// handler is told apart from a nil one.
+type entry[H any] struct {
+	command string
+	rest    bool
+	handler H
+}
+
+// New returns a new empty router for handlers of type H.
+func New[H any]() *Router[H] {
+	return &Router[H]{
+		tree:     drouter.New[*entry[H]](),
+		commands: make(map[string]string),
+	}
+}
+
+// Handle registers the handler for the given command. It panics if the
+// command is invalid or conflicts with a registered one, e.g. a parameter
+// and a word at the same position.
+func (r *Router[H]) Handle(command string, handler H) {
+	if err := r.TryHandle(command, handler); err != nil {
+		panic(err.Error())
+	}
+}
+
+// TryHandle is like Handle, but returns a *drouter.RouteError instead of
+// panicking if the command can not be registered.
+func (r *Router[H]) TryHandle(command string, handler H) error {
+	path, rest, err := compile(command)
+	if err != nil {
+		return err
+	}
+	err = r.tree.TryAddRoute(path, &entry[H]{command: command, rest: rest, handler: handler})
+	if err != nil {
+		return err
+	}
+	r.commands[path] = command
+	if n := drouter.CountParams(path); n > r.maxParams {
+		r.maxParams = n
+	}
+	return nil
+}
+
+// compile translates a command into a route path of the tree and reports
+// whether it ends with a parameter for the remaining arguments.
+func compile(command string) (path string, rest bool, err error) {
+	invalid := func(msg string) error {
+		return &drouter.RouteError{
+			Kind:    drouter.InvalidPath,
+			Path:    command,
+			Message: msg + " in command '" + command + "'",
+		}
+	}
+
+	words := strings.Fields(command)
+	if len(words) == 0 {
+		return "", false, invalid("command must not be empty")
+	}
+	var b strings.Builder
+	for i, word := range words {
+		b.WriteByte('/')
+		if !strings.HasPrefix(word, "<") {
+			if strings.ContainsAny(word, "<>") {
+				return "", false, invalid("parameters must occupy an entire word")
+			}
+			b.WriteString(escape(word))
+			continue
+		}
+
+		if len(word) < 3 || word[len(word)-1] != '>' || strings.ContainsAny(word[1:len(word)-1], "<>:*/") {
+			return "", false, invalid("invalid parameter '" + word + "'")
+		}
+		name := word[1 : len(word)-1]
+		if strings.HasSuffix(name, "...") {
+			if i != len(words)-1 {
+				return "", false, invalid("'" + word + "' must be the last word")
+			}
+			b.WriteString("*" + strings.TrimSuffix(name, "..."))
+			rest = true
+		} else {
+			b.WriteString(":" + name)
+		}
+	}
+	return b.String(), rest, nil
+}
+
+// escape escapes the characters of an argument with a meaning in route
+// paths, so arguments like file paths stay a single segment.
+func escape(arg string) string {
+	if !strings.ContainsAny(arg, "%/") {
+		return arg
+	}
+	return strings.NewReplacer("%", "%25", "/", "%2F").Replace(arg)
+}
+
+// unescape reverses escape.
+func unescape(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+	return strings.NewReplacer("%2F", "/", "%25", "%").Replace(s)
+}
+
+// toPath returns the route path of the arguments.
+func toPath(args []string) string {
+	var b strings.Builder
+	for _, arg := range args {
+		b.WriteByte('/')
+		b.WriteString(escape(arg))
+	}
+	return b.String()
+}
+
+// Lookup returns the command matching the arguments, without the program
+// name, e.g. os.Args[1:]. If no command matches, ok is false.
+func (r *Router[H]) Lookup(args []string) (m HandlerMatch[H], ok bool) {
+	if len(args) == 0 {
+		return m, false
+	}
+	var ps drouter.Params
+	if r.maxParams > 0 {
+		ps = make(drouter.Params, 0, r.maxParams)
+	}
+	path := toPath(args)
+	e, tsr := r.tree.Lookup(path, &ps)
+	empty := false
+	if e == nil && tsr {
+		// A command ending with the trailing parameter also matches without
+		// remaining arguments
+		ps = ps[:0]
+		if e, _ = r.tree.Lookup(path+"/", &ps); e != nil && !e.rest {
+			e = nil
+		}
+		empty = true
+	}
+	if e == nil {
+		return m, false
+	}
+
+	if e.rest {
+		// The catch-all value holds the remaining arguments as path
+		last := ps[len(ps)-1]
+		ps = ps[:len(ps)-1]
+		if !empty {
+			for _, arg := range strings.Split(last.Value[1:], "/") {
+				m.Rest = append(m.Rest, unescape(arg))
+			}
+		}
+	}
+	for i := range ps {
+		ps[i].Value = unescape(ps[i].Value)
+	}
+	if len(ps) > 0 {
+		m.Params = ps
+	}
+	m.Command = e.command
+	m.Handler = e.handler
+	return m, true
+}
+
+// Suggest returns the registered commands closest to the arguments, e.g. for
+// "did you mean" hints, ordered by ascending distance. See
+// drouter.Router.Suggest for the distance.
+func (r *Router[H]) Suggest(args []string, maxDistance int) []string {
+	paths := r.tree.Suggest(toPath(args), maxDistance)
+	commands := make([]string, len(paths))
+	for i, path := range paths {
+		commands[i] = r.commands[path]
+	}
+	return commands
+}
+
+// Walk calls fn for every registered command with its handler, until fn
+// returns false, e.g. to print the usage.
+func (r *Router[H]) Walk(fn func(command string, handler H) bool) {
+	r.tree.Walk(func(_ string, e *entry[H]) bool {
+		return fn(e.command, e.handler)
+	})
+}
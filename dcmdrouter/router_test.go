@@ -0,0 +1,77 @@
+package dcmdrouter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouter(t *testing.T) {
+	router := New[string]()
+	router.Handle("remote add <name> <url>", "remote-add")
+	router.Handle("remote remove <name>", "remote-remove")
+	router.Handle("add <paths...>", "add")
+	router.Handle("status", "status")
+
+	tests := []struct {
+		args    string
+		handler string
+		params  drouter.Params
+		rest    []string
+	}{
+		{"remote add origin https://example.com/repo.git", "remote-add", drouter.Params{
+			{Key: "name", Value: "origin"},
+			{Key: "url", Value: "https://example.com/repo.git"},
+		}, nil},
+		{"remote remove origin", "remote-remove", drouter.Params{{Key: "name", Value: "origin"}}, nil},
+		{"add src/main.go 100%.txt", "add", nil, []string{"src/main.go", "100%.txt"}},
+		{"add", "add", nil, nil},
+		{"status", "status", nil, nil},
+		{"status now", "", nil, nil},
+		{"remote", "", nil, nil},
+		{"", "", nil, nil},
+	}
+	for _, tt := range tests {
+		m, ok := router.Lookup(strings.Fields(tt.args))
+		if ok != (tt.handler != "") || m.Handler != tt.handler {
+			t.Errorf("%q: want handler %q, got %q", tt.args, tt.handler, m.Handler)
+			continue
+		}
+		if !reflect.DeepEqual(m.Params, tt.params) || !reflect.DeepEqual(m.Rest, tt.rest) {
+			t.Errorf("%q: wrong match %+v", tt.args, m.Match)
+		}
+	}
+
+	if m, _ := router.Lookup([]string{"status"}); m.Command != "status" {
+		t.Errorf("wrong command %q", m.Command)
+	}
+
+	if s := router.Suggest([]string{"remote", "ad", "origin", "url"}, 2); len(s) != 1 || s[0] != "remote add <name> <url>" {
+		t.Errorf("wrong suggestions %v", s)
+	}
+
+	commands := 0
+	router.Walk(func(string, string) bool {
+		commands++
+		return true
+	})
+	if commands != 4 {
+		t.Errorf("wrong number of commands: %d", commands)
+	}
+}
+
+func TestRouterInvalidCommand(t *testing.T) {
+	router := New[string]()
+	for _, command := range []string{"", "  ", "add <>", "add <a", "add a<b>", "add <a...> b", "add <a:b>"} {
+		if err := router.TryHandle(command, "h"); err == nil {
+			t.Errorf("no error for command %q", command)
+		}
+	}
+
+	router.Handle("remote add <name>", "h")
+	if err := router.TryHandle("remote <action> <name>", "h"); err == nil {
+		t.Error("no error for conflicting command")
+	}
+}
@@ -0,0 +1,124 @@
+// Package dgrpcrouter routes full gRPC method names, e.g.
+// "/pkg.Service/Method", onto handlers or interceptors, using the radix tree
+// of drouter. Besides exact method names, patterns may end with a wildcard
+// matching all methods of a service, e.g. "/pkg.Service/*", or contain named
+// parameters, e.g. "/pkg.:service/Get".
+//
+// The package does not depend on gRPC itself, the type of the handlers is
+// chosen by the user. A typical use is the dispatch of unknown services, e.g.
+// by a gRPC proxy:
+//
+//	router := dgrpcrouter.New[grpc.StreamHandler]()
+//	router.Handle("/billing.Invoices/*", invoicesProxy)
+//	router.Handle("/billing.Payments/Refund", refund)
+//
+//	server := grpc.NewServer(grpc.UnknownServiceHandler(func(srv any, stream grpc.ServerStream) error {
+//		method, _ := grpc.MethodFromServerStream(stream)
+//		handler, _, ok := router.Lookup(method)
+//		if !ok {
+//			return status.Errorf(codes.Unimplemented, "unknown method %s", method)
+//		}
+//		return handler(srv, stream)
+//	}))
+package dgrpcrouter
+
+import (
+	"strings"
+
+	"github.com/thekhanj/drouter"
+)
+
+// MethodParam is the name of the Param holding the method name matched by
+// the wildcard of a pattern ending with "/*".
+const MethodParam = "method"
+
+// Router maps gRPC method names onto handlers of type H.
+type Router[H any] struct {
+	tree      *drouter.Router[*entry[H]]
+	maxParams uint16
+}
+
+// entry is a registered handler. The tree stores pointers, so a missing
+// handler is told apart from a nil one.
+type entry[H any] struct {
+	pattern string
+	handler H
+}
+
+// New returns a new empty router for handlers of type H.
+func New[H any]() *Router[H] {
+	return &Router[H]{tree: drouter.New[*entry[H]]()}
+}
+
+// Handle registers the handler for the given method name pattern. It panics
+// if the pattern is invalid or conflicts with a registered one.
+func (r *Router[H]) Handle(pattern string, handler H) {
+	if err := r.TryHandle(pattern, handler); err != nil {
+		panic(err.Error())
+	}
+}
+
+// TryHandle is like Handle, but returns a *drouter.RouteError instead of
+// panicking if the pattern can not be registered.
+func (r *Router[H]) TryHandle(pattern string, handler H) error {
+	path, err := compile(pattern)
+	if err != nil {
+		return err
+	}
+	if err := r.tree.TryAddRoute(path, &entry[H]{pattern: pattern, handler: handler}); err != nil {
+		return err
+	}
+	if n := drouter.CountParams(path); n > r.maxParams {
+		r.maxParams = n
+	}
+	return nil
+}
+
+// compile translates a method name pattern into a route path of the tree.
+func compile(pattern string) (string, error) {
+	if len(pattern) < 1 || pattern[0] != '/' {
+		return "", &drouter.RouteError{
+			Kind:    drouter.InvalidPath,
+			Path:    pattern,
+			Message: "method pattern must begin with '/' in pattern '" + pattern + "'",
+		}
+	}
+	if pattern == "/*" || strings.HasSuffix(pattern, "/*") {
+		return pattern + MethodParam, nil
+	}
+	if strings.Count(pattern, "/") != 2 {
+		return "", &drouter.RouteError{
+			Kind:    drouter.InvalidPath,
+			Path:    pattern,
+			Message: "method pattern must have the form '/package.Service/Method' in pattern '" + pattern + "'",
+		}
+	}
+	return pattern, nil
+}
+
+// Lookup returns the handler registered for the full method name, e.g.
+// "/pkg.Service/Method", along with the values of the pattern's parameters.
+// If no handler matches, ok is false.
+func (r *Router[H]) Lookup(fullMethod string) (handler H, ps drouter.Params, ok bool) {
+	if r.maxParams > 0 {
+		ps = make(drouter.Params, 0, r.maxParams)
+	}
+	e, _ := r.tree.Lookup(fullMethod, &ps)
+	if e == nil {
+		return handler, nil, false
+	}
+	if strings.HasSuffix(e.pattern, "/*") {
+		// The catch-all value starts with the slash before the method
+		last := &ps[len(ps)-1]
+		last.Value = last.Value[1:]
+	}
+	return e.handler, ps, true
+}
+
+// Walk calls fn for every registered pattern with its handler, until fn
+// returns false.
+func (r *Router[H]) Walk(fn func(pattern string, handler H) bool) {
+	r.tree.Walk(func(_ string, e *entry[H]) bool {
+		return fn(e.pattern, e.handler)
+	})
+}
@@ -0,0 +1,65 @@
+package dgrpcrouter
+
+import (
+	"testing"
+)
+
+func TestRouter(t *testing.T) {
+	router := New[string]()
+	router.Handle("/billing.Invoices/*", "invoices")
+	router.Handle("/billing.Payments/Refund", "refund")
+	router.Handle("/catalog.:service/Get", "get")
+
+	tests := []struct {
+		method  string
+		handler string
+		key     string
+		value   string
+	}{
+		{"/billing.Invoices/List", "invoices", MethodParam, "List"},
+		{"/billing.Payments/Refund", "refund", "", ""},
+		{"/catalog.Products/Get", "get", "service", "Products"},
+		{"/billing.Payments/Charge", "", "", ""},
+		{"/unknown.Service/Method", "", "", ""},
+	}
+	for _, tt := range tests {
+		handler, ps, ok := router.Lookup(tt.method)
+		if ok != (tt.handler != "") || handler != tt.handler {
+			t.Errorf("%s: want handler %q, got %q", tt.method, tt.handler, handler)
+		}
+		if tt.key != "" && ps.ByName(tt.key) != tt.value {
+			t.Errorf("%s: want %s=%q, got %v", tt.method, tt.key, tt.value, ps)
+		}
+	}
+
+	var patterns []string
+	router.Walk(func(pattern string, _ string) bool {
+		patterns = append(patterns, pattern)
+		return true
+	})
+	if len(patterns) != 3 {
+		t.Errorf("wrong patterns %v", patterns)
+	}
+}
+
+func TestRouterNilHandler(t *testing.T) {
+	router := New[func()]()
+	router.Handle("/pkg.Service/Method", nil)
+	if _, _, ok := router.Lookup("/pkg.Service/Method"); !ok {
+		t.Error("nil handler was not found")
+	}
+}
+
+func TestRouterInvalidPattern(t *testing.T) {
+	router := New[string]()
+	for _, pattern := range []string{"", "pkg.Service/Method", "/pkg.Service", "/pkg/Service/Method"} {
+		if err := router.TryHandle(pattern, "h"); err == nil {
+			t.Errorf("no error for pattern %q", pattern)
+		}
+	}
+
+	router.Handle("/pkg.Service/*", "all")
+	if err := router.TryHandle("/pkg.Service/*", "again"); err == nil {
+		t.Error("no error for conflicting pattern")
+	}
+}
@@ -0,0 +1,99 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+// Logger receives an access log entry per request, see HttpRouter.Logger.
+// It is implemented by *slog.Logger, e.g.
+//
+//	router.Logger = slog.New(slog.NewJSONHandler(os.Stdout, nil))
+type Logger interface {
+	// Info logs the message with alternating keys and values.
+	Info(msg string, args ...interface{})
+}
+
+// accessWriter records the response and the matched route of a request for
+// its access log entry.
+type accessWriter struct {
+	statusWriter
+
+	// Pattern and number of params of the matched route, if any
+	route  string
+	params int
+
+	// Request path with redacted params masked, if any, see RedactionPolicy
+	path string
+
+	// Log fields of the params, see LogFields
+	fields []interface{}
+}
+
+// Write counts the bytes of the body.
+func (w *accessWriter) Write(p []byte) (int, error) {
+	n, err := w.statusWriter.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// matched records the route serving the request, if w logs it. The fields
+// of the params are taken now, as the Params are reused once the handle
+// returned.
+func (r *HttpRouter) matched(w http.ResponseWriter, req *http.Request, rt *route, ps drouter.Params) {
+	if aw, ok := w.(*accessWriter); ok {
+		aw.route = rt.path
+		aw.params = len(ps)
+		aw.path = r.redactPath(rt, req, ps)
+		if r.Logger != nil {
+			aw.fields = paramFields(nil, ps, func(key string) bool {
+				return r.redacts(rt, key)
+			})
+		}
+	}
+}
+
+// logAccess passes the access log entry of the request to the Logger and
+// the DecisionLog, whichever is set, with redacted params masked, see
+// RedactionPolicy. It must be deferred, so requests whose handle panicked are
+// logged as well.
+func (r *HttpRouter) logAccess(w *accessWriter, req *http.Request, start time.Time) {
+	status := w.Status()
+	p := recover()
+	if p != nil {
+		status = http.StatusInternalServerError
+	}
+
+	duration := time.Since(start)
+	path := w.path
+	if path == "" {
+		path = req.URL.Path
+	}
+	if r.Logger != nil {
+		r.Logger.Info("http request", append([]interface{}{
+			"method", req.Method,
+			"path", path,
+			"route", w.route,
+			"params", w.params,
+			"status", status,
+			"size", w.size,
+			"duration", duration,
+		}, w.fields...)...)
+	}
+	if r.Decisions != nil {
+		r.Decisions.add(RoutingRecord{
+			Time:     start,
+			Method:   req.Method,
+			Path:     path,
+			Route:    w.route,
+			Status:   status,
+			Duration: duration,
+		})
+	}
+
+	if p != nil {
+		panic(p)
+	}
+}
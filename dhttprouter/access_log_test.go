@@ -0,0 +1,67 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+type testLogger struct {
+	entries []map[string]interface{}
+}
+
+func (l *testLogger) Info(msg string, args ...interface{}) {
+	entry := map[string]interface{}{"msg": msg}
+	for i := 0; i+1 < len(args); i += 2 {
+		entry[args[i].(string)] = args[i+1]
+	}
+	l.entries = append(l.entries, entry)
+}
+
+func TestRouterLogger(t *testing.T) {
+	logger := &testLogger{}
+	router := New()
+	router.Logger = logger
+	router.PanicHandler = func(w http.ResponseWriter, _ *http.Request, _ interface{}) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	router.GET("/users/:id/posts/:post", func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("hello"))
+	})
+	router.GET("/panic", func(http.ResponseWriter, *http.Request, drouter.Params) {
+		panic("boom")
+	})
+
+	for _, path := range []string{"/users/1/posts/2", "/missing", "/panic"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if len(logger.entries) != 3 {
+		t.Fatalf("wrong number of entries: %d", len(logger.entries))
+	}
+	tests := []struct {
+		path, route    string
+		params, status int
+		size           int64
+	}{
+		{"/users/1/posts/2", "/users/:id/posts/:post", 2, http.StatusCreated, 5},
+		{"/missing", "", 0, http.StatusNotFound, 19},
+		{"/panic", "/panic", 0, http.StatusInternalServerError, 0},
+	}
+	for i, tt := range tests {
+		e := logger.entries[i]
+		if e["msg"] != "http request" || e["method"] != http.MethodGet || e["path"] != tt.path {
+			t.Errorf("%s: wrong entry %v", tt.path, e)
+		}
+		if e["route"] != tt.route || e["params"] != tt.params || e["status"] != tt.status || e["size"] != tt.size {
+			t.Errorf("%s: wrong entry %v", tt.path, e)
+		}
+		if _, ok := e["duration"].(time.Duration); !ok {
+			t.Errorf("%s: duration is missing", tt.path)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package dhttprouter
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/thekhanj/drouter"
+)
+
+// Admin configures the API mounted with MountAdmin.
+type Admin struct {
+	// Function authorizing the requests of the API, e.g. by checking a
+	// bearer token. Unauthorized requests are answered with 403 Forbidden.
+	// It must be set, as the API can change every route of the router.
+	Authorize func(req *http.Request) bool
+
+	// Handles and middleware which routes added through the API refer to by
+	// name, see RouteConfig
+	Registry Registry
+}
+
+// MountAdmin mounts an API under the given prefix which lists, adds,
+// disables and removes the routes of r while it serves requests:
+//
+//	GET    /routes                   list the routes as JSON, see Routes
+//	POST   /routes                   add the route of the RouteConfig in the body, 201
+//	DELETE /routes/:method/*path     remove a route, 204
+//	PUT    /disabled/:method/*path   disable a route, 204, see DisableRoute
+//	DELETE /disabled/:method/*path   enable a route again, 204, see EnableRoute
+//	GET    /snapshot                 serve a Snapshot as JSON
+//	GET    /decisions                serve the Decisions of r, if set
+//
+// The path of a route follows its method verbatim, e.g.
+// DELETE /_admin/routes/GET/users/:id. Routes are added and removed with
+// Update, so the changes take effect atomically. Unknown routes are answered
+// with 404, routes which can not be added with 409 Conflict if they conflict
+// with a registered route and with 400 otherwise.
+//
+// The API is opt-in and is not part of the routes it manages. MountAdmin
+// panics if admin has no Authorize function, see also Mount.
+func (r *HttpRouter) MountAdmin(prefix string, admin Admin) {
+	if admin.Authorize == nil {
+		panic("admin API must have an Authorize function for prefix '" + prefix + "'")
+	}
+
+	a := &adminAPI{router: r, registry: admin.Registry}
+	api := New()
+	api.HandleE(http.MethodGet, "/routes", a.list)
+	api.HandleE(http.MethodPost, "/routes", a.add)
+	api.HandleE(http.MethodDelete, "/routes/:method/*path", a.remove)
+	api.HandleE(http.MethodPut, "/disabled/:method/*path", a.disable)
+	api.HandleE(http.MethodDelete, "/disabled/:method/*path", a.enable)
+	api.HandleE(http.MethodGet, "/snapshot", a.snapshot)
+	api.HandleE(http.MethodGet, "/decisions", a.decisions)
+
+	r.Mount(prefix, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !admin.Authorize(req) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		api.ServeHTTP(w, req)
+	}))
+}
+
+type adminAPI struct {
+	router   *HttpRouter
+	registry Registry
+}
+
+func (a *adminAPI) list(w http.ResponseWriter, _ *http.Request, _ drouter.Params) error {
+	return writeJSON(w, http.StatusOK, a.router.Routes())
+}
+
+func (a *adminAPI) add(w http.ResponseWriter, req *http.Request, _ drouter.Params) error {
+	var rc RouteConfig
+	dec := json.NewDecoder(http.MaxBytesReader(w, req.Body, 1<<20))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&rc); err != nil {
+		return &HTTPError{Code: http.StatusBadRequest, Message: "invalid body: " + err.Error()}
+	}
+
+	err := a.router.Update(func() error {
+		return a.router.RegisterRoutes([]RouteConfig{rc}, a.registry)
+	})
+	var errs ConfigErrors
+	if errors.As(err, &errs) {
+		err = errs[0].Err
+		var rerr *drouter.RouteError
+		if errors.As(err, &rerr) && rerr.Kind == drouter.Conflict {
+			return &HTTPError{Code: http.StatusConflict, Message: rerr.Error()}
+		}
+		return &HTTPError{Code: http.StatusBadRequest, Message: err.Error()}
+	}
+	if err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusCreated)
+	return nil
+}
+
+func (a *adminAPI) remove(w http.ResponseWriter, _ *http.Request, ps drouter.Params) error {
+	method, path := ps.ByName("method"), ps.ByName("path")
+	removed := false
+	a.router.Update(func() error {
+		removed = a.router.Remove(method, path)
+		return nil
+	})
+	return noContent(w, removed)
+}
+
+func (a *adminAPI) disable(w http.ResponseWriter, _ *http.Request, ps drouter.Params) error {
+	return noContent(w, a.router.DisableRoute(ps.ByName("method"), ps.ByName("path")))
+}
+
+func (a *adminAPI) enable(w http.ResponseWriter, _ *http.Request, ps drouter.Params) error {
+	method, path := ps.ByName("method"), ps.ByName("path")
+	if a.router.loadTable().lookupRoute(method, path) == nil {
+		return noContent(w, false)
+	}
+	a.router.EnableRoute(method, path)
+	return noContent(w, true)
+}
+
+func (a *adminAPI) snapshot(w http.ResponseWriter, _ *http.Request, _ drouter.Params) error {
+	return writeJSON(w, http.StatusOK, a.router.Snapshot())
+}
+
+func (a *adminAPI) decisions(w http.ResponseWriter, req *http.Request, _ drouter.Params) error {
+	if a.router.Decisions == nil {
+		return &HTTPError{Code: http.StatusNotFound, Message: "no decision log"}
+	}
+	a.router.Decisions.ServeHTTP(w, req)
+	return nil
+}
+
+// noContent answers with 204 if the route was found, with 404 otherwise.
+func noContent(w http.ResponseWriter, found bool) error {
+	if !found {
+		return &HTTPError{Code: http.StatusNotFound, Message: "no such route"}
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
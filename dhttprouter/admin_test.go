@@ -0,0 +1,133 @@
+package dhttprouter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterMountAdmin(t *testing.T) {
+	getUser := func(w http.ResponseWriter, _ *http.Request, ps drouter.Params) {
+		w.Write([]byte("user " + ps.ByName("id")))
+	}
+	auth := func(next HttpHandle) HttpHandle {
+		return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+			w.Header().Set("X-Auth", "checked")
+			next(w, req, ps)
+		}
+	}
+
+	router := New()
+	router.GET("/health", func(http.ResponseWriter, *http.Request, drouter.Params) {})
+	router.MountAdmin("/_admin", Admin{
+		Authorize: func(req *http.Request) bool {
+			return req.Header.Get("Authorization") == "Bearer secret"
+		},
+		Registry: Registry{
+			Handlers:   map[string]HttpHandle{"getUser": getUser},
+			Middleware: map[string]Middleware{"auth": auth},
+		},
+	})
+
+	serve := func(method, path, body string, authorized bool) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if authorized {
+			req.Header.Set("Authorization", "Bearer secret")
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := serve(http.MethodGet, "/_admin/routes", "", false); w.Code != http.StatusForbidden {
+		t.Errorf("unauthorized request was served: %d", w.Code)
+	}
+
+	tests := []struct {
+		method, path, body string
+		code               int
+	}{
+		{http.MethodPost, "/_admin/routes", `{"method": "GET", "path": "/users/:id", "handler": "getUser", "middleware": ["auth"]}`, http.StatusCreated},
+		{http.MethodPost, "/_admin/routes", `{"method": "GET", "path": "/users/:name", "handler": "getUser"}`, http.StatusConflict},
+		{http.MethodPost, "/_admin/routes", `{"method": "GET", "path": "/posts", "handler": "getPost"}`, http.StatusBadRequest},
+		{http.MethodPost, "/_admin/routes", `{"method": "GET", "path": "/posts", "handle": "getUser"}`, http.StatusBadRequest},
+		{http.MethodDelete, "/_admin/routes/GET/posts", "", http.StatusNotFound},
+		{http.MethodPut, "/_admin/disabled/GET/posts", "", http.StatusNotFound},
+		{http.MethodGet, "/_admin/decisions", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if w := serve(tt.method, tt.path, tt.body, true); w.Code != tt.code {
+			t.Errorf("%s %s: wrong status: want %d, got %d: %s", tt.method, tt.path, tt.code, w.Code, w.Body)
+		}
+	}
+
+	w := serve(http.MethodGet, "/users/1", "", false)
+	if w.Code != http.StatusOK || w.Body.String() != "user 1" || w.Header().Get("X-Auth") != "checked" {
+		t.Errorf("added route is not served: %d %q", w.Code, w.Body)
+	}
+
+	var routes []RouteInfo
+	w = serve(http.MethodGet, "/_admin/routes", "", true)
+	if err := json.Unmarshal(w.Body.Bytes(), &routes); err != nil {
+		t.Fatal(err)
+	}
+	found := map[string]bool{}
+	for _, info := range routes {
+		found[info.Method+" "+info.Path] = true
+	}
+	if !found["GET /health"] || !found["GET /users/:id"] || found["GET /_admin/routes"] {
+		t.Errorf("wrong routes %+v", routes)
+	}
+
+	// Disabled routes are answered with 503 until they are enabled again
+	if w := serve(http.MethodPut, "/_admin/disabled/GET/users/:id", "", true); w.Code != http.StatusNoContent {
+		t.Errorf("wrong status: %d", w.Code)
+	}
+	if w := serve(http.MethodGet, "/users/1", "", false); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("disabled route was served: %d", w.Code)
+	}
+	if info, _ := router.Snapshot().Route(http.MethodGet, "/users/:id"); !info.Disabled {
+		t.Errorf("route is not reported as disabled: %+v", info)
+	}
+	if w := serve(http.MethodDelete, "/_admin/disabled/GET/users/:id", "", true); w.Code != http.StatusNoContent {
+		t.Errorf("wrong status: %d", w.Code)
+	}
+	if w := serve(http.MethodGet, "/users/1", "", false); w.Code != http.StatusOK {
+		t.Errorf("enabled route was not served: %d", w.Code)
+	}
+
+	var s Snapshot
+	w = serve(http.MethodGet, "/_admin/snapshot", "", true)
+	if err := json.Unmarshal(w.Body.Bytes(), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Version != router.Version() || !s.Settings.HandleOPTIONS {
+		t.Errorf("wrong snapshot %+v", s)
+	}
+
+	if w := serve(http.MethodDelete, "/_admin/routes/GET/users/:id", "", true); w.Code != http.StatusNoContent {
+		t.Errorf("wrong status: %d", w.Code)
+	}
+	if w := serve(http.MethodGet, "/users/1", "", false); w.Code != http.StatusNotFound {
+		t.Errorf("removed route was served: %d", w.Code)
+	}
+
+	router.Decisions = NewDecisionLog(10)
+	serve(http.MethodGet, "/health", "", false)
+	var records []RoutingRecord
+	w = serve(http.MethodGet, "/_admin/decisions", "", true)
+	if err := json.Unmarshal(w.Body.Bytes(), &records); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Route != "/health" {
+		t.Errorf("wrong records %+v", records)
+	}
+
+	if rcv := catchPanic(func() { New().MountAdmin("/_admin", Admin{}) }); rcv == nil {
+		t.Error("admin API without Authorize was mounted")
+	}
+}
@@ -0,0 +1,180 @@
+package dhttprouter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"path"
+)
+
+// Length of the content hashes in hex digits
+const assetHashLen = 12
+
+// AssetManifest fingerprints the files of a file system by their content, so
+// they can be served under hashed URLs like "/css/app.3f2a1b9c0d4e.css" which
+// are cached forever by clients and proxies. As a new version of a file gets a
+// new URL, no cache ever serves stale assets.
+//
+// The manifest is built once, usually at startup. Files embedded into the
+// binary can be fingerprinted with http.FS:
+//
+//	//go:embed static
+//	var static embed.FS
+//
+//	assets, err := NewAssetManifest(http.FS(static))
+//
+// An AssetManifest is a http.FileSystem serving the files under both their
+// original and their hashed names, and a http.Handler serving them with cache
+// headers, see ServeAssets. Templates link the assets with Path:
+//
+//	funcs := template.FuncMap{"asset": func(name string) string {
+//		return "/static" + assets.Path(name)
+//	}}
+type AssetManifest struct {
+	root  http.FileSystem
+	files http.Handler
+
+	// Content hashes per file name, e.g. "/css/app.css"
+	hashes map[string]string
+
+	// Original file names per hashed name
+	originals map[string]string
+}
+
+// NewAssetManifest reads all files of root and returns their manifest.
+func NewAssetManifest(root http.FileSystem) (*AssetManifest, error) {
+	m := &AssetManifest{
+		root:      root,
+		hashes:    make(map[string]string),
+		originals: make(map[string]string),
+	}
+	m.files = http.FileServer(m)
+	if err := m.walk("/"); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// walk hashes the file or directory with the given name recursively.
+func (m *AssetManifest) walk(name string) error {
+	f, err := m.root.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		h := sha256.New()
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+		hash := hex.EncodeToString(h.Sum(nil))[:assetHashLen]
+		m.hashes[name] = hash
+		m.originals[hashedName(name, hash)] = name
+		return nil
+	}
+
+	entries, err := f.Readdir(-1)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := m.walk(path.Join(name, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hashedName inserts the hash before the extension of the file name.
+func hashedName(name, hash string) string {
+	ext := path.Ext(name)
+	return name[:len(name)-len(ext)] + "." + hash + ext
+}
+
+// cleanAssetName returns the canonical manifest name of a file.
+func cleanAssetName(name string) string {
+	return path.Clean("/" + name)
+}
+
+// Hash returns the content hash of the named file, or an empty string if the
+// file is not in the manifest.
+func (m *AssetManifest) Hash(name string) string {
+	return m.hashes[cleanAssetName(name)]
+}
+
+// Path returns the hashed name of the file, e.g. "/css/app.3f2a1b9c0d4e.css"
+// for "/css/app.css". Names which are not in the manifest are returned as
+// they are, so a missing asset shows up as a 404 instead of a broken template.
+func (m *AssetManifest) Path(name string) string {
+	name = cleanAssetName(name)
+	if hash, ok := m.hashes[name]; ok {
+		return hashedName(name, hash)
+	}
+	return name
+}
+
+// ETag returns the strong entity tag of the named file, or an empty string if
+// the file is not in the manifest.
+func (m *AssetManifest) ETag(name string) string {
+	name = cleanAssetName(name)
+	if orig, ok := m.originals[name]; ok {
+		name = orig
+	}
+	if hash, ok := m.hashes[name]; ok {
+		return `"` + hash + `"`
+	}
+	return ""
+}
+
+// Manifest returns the hashed names per file name, e.g. to be written to a
+// manifest.json for other tools.
+func (m *AssetManifest) Manifest() map[string]string {
+	manifest := make(map[string]string, len(m.hashes))
+	for name, hash := range m.hashes {
+		manifest[name] = hashedName(name, hash)
+	}
+	return manifest
+}
+
+// Open opens the named file, which may be either its original or its hashed
+// name.
+func (m *AssetManifest) Open(name string) (http.File, error) {
+	if orig, ok := m.originals[cleanAssetName(name)]; ok {
+		name = orig
+	}
+	return m.root.Open(name)
+}
+
+// ServeHTTP serves the file named by the request path like http.FileServer.
+// Responses carry the content hash as ETag, so conditional requests are
+// answered with 304. Files requested by their hashed name are marked as
+// immutable, all other files have to be revalidated by caches.
+func (m *AssetManifest) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	name := cleanAssetName(req.URL.Path)
+	if etag := m.ETag(name); etag != "" {
+		h := w.Header()
+		h.Set("ETag", etag)
+		if _, ok := m.originals[name]; ok {
+			h.Set("Cache-Control", "public, max-age=31536000, immutable")
+		} else {
+			h.Set("Cache-Control", "no-cache")
+		}
+	}
+	m.files.ServeHTTP(w, req)
+}
+
+// ServeAssets serves the files of the manifest like ServeFiles, under both
+// their original and their hashed names, with the cache headers described
+// at AssetManifest.ServeHTTP. The path must end with "/*filepath".
+//
+//	assets, err := NewAssetManifest(http.Dir("static"))
+//	router.ServeAssets("/static/*filepath", assets)
+func (r *HttpRouter) ServeAssets(path string, m *AssetManifest) {
+	r.serveFiles(path, Files{Root: m}, m)
+}
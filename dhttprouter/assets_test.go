@@ -0,0 +1,88 @@
+package dhttprouter
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAssetManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "drouter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	os.MkdirAll(filepath.Join(dir, "css"), 0755)
+	ioutil.WriteFile(filepath.Join(dir, "css", "app.css"), []byte("body{}"), 0644)
+	ioutil.WriteFile(filepath.Join(dir, "LICENSE"), []byte("MIT"), 0644)
+
+	assets, err := NewAssetManifest(http.Dir(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewAssetManifest(http.Dir(filepath.Join(dir, "missing"))); err == nil {
+		t.Error("no error for missing directory")
+	}
+
+	hash := assets.Hash("css/app.css")
+	if len(hash) != assetHashLen {
+		t.Fatalf("wrong hash %q", hash)
+	}
+	hashed := "/css/app." + hash + ".css"
+	if got := assets.Path("/css/app.css"); got != hashed {
+		t.Errorf("wrong path: want %q, got %q", hashed, got)
+	}
+	if got := assets.Path("/LICENSE"); got != "/LICENSE."+assets.Hash("/LICENSE") {
+		t.Errorf("wrong path without extension: %q", got)
+	}
+	if got := assets.Path("/missing.js"); got != "/missing.js" {
+		t.Errorf("missing asset was rewritten: %q", got)
+	}
+	if got := assets.Manifest()["/css/app.css"]; got != hashed {
+		t.Errorf("wrong manifest entry: %q", got)
+	}
+
+	router := New()
+	router.ServeAssets("/static/*filepath", assets)
+
+	tests := []struct {
+		path, ifNoneMatch string
+		code              int
+		cacheControl      string
+	}{
+		{"/static" + hashed, "", http.StatusOK, "public, max-age=31536000, immutable"},
+		{"/static/css/app.css", "", http.StatusOK, "no-cache"},
+		{"/static/css/app.css", `"` + hash + `"`, http.StatusNotModified, "no-cache"},
+		{"/static/css/app.0123456789ab.css", "", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", tt.ifNoneMatch)
+		}
+		router.ServeHTTP(w, req)
+		if w.Code != tt.code {
+			t.Errorf("%s: wrong status code: want %d, got %d", tt.path, tt.code, w.Code)
+		}
+		if got := w.Header().Get("Cache-Control"); got != tt.cacheControl {
+			t.Errorf("%s: wrong Cache-Control: want %q, got %q", tt.path, tt.cacheControl, got)
+		}
+		if tt.code == http.StatusOK {
+			if got := w.Header().Get("ETag"); got != `"`+hash+`"` {
+				t.Errorf("%s: wrong ETag %q", tt.path, got)
+			}
+			if body := w.Body.String(); body != "body{}" {
+				t.Errorf("%s: wrong body %q", tt.path, body)
+			}
+			if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/css") {
+				t.Errorf("%s: wrong Content-Type %q", tt.path, ct)
+			}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package dhttprouter
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"net/http"
+	"strconv"
+
+	"github.com/thekhanj/drouter"
+)
+
+// Sticky selects the request header or cookie whose value keys the arm of a
+// weighted route, see HttpRouter.SetSticky. The header takes priority over the
+// cookie.
+type Sticky struct {
+	Header string
+	Cookie string
+}
+
+// key returns the value keying the arm of the request, or an empty string.
+func (s Sticky) key(req *http.Request) string {
+	if s.Header != "" {
+		if v := req.Header.Get(s.Header); v != "" {
+			return v
+		}
+	}
+	if s.Cookie != "" {
+		if c, err := req.Cookie(s.Cookie); err == nil {
+			return c.Value
+		}
+	}
+	return ""
+}
+
+// canaryArm is a handle of a weighted route.
+type canaryArm struct {
+	weight int
+	handle HttpHandle
+}
+
+// canary splits the requests of a weighted route between its arms.
+type canary struct {
+	arms   []canaryArm
+	total  int
+	sticky Sticky
+
+	// Source of random numbers in [0,n), for testing
+	random func(n int) int
+}
+
+// pick returns the arm serving the request.
+func (c *canary) pick(req *http.Request) HttpHandle {
+	if c.total == 0 {
+		return c.arms[0].handle
+	}
+
+	var n int
+	if key := c.sticky.key(req); key != "" {
+		h := fnv.New32a()
+		h.Write([]byte(key))
+		n = int(h.Sum32() % uint32(c.total))
+	} else if c.random != nil {
+		n = c.random(c.total)
+	} else {
+		n = rand.Intn(c.total)
+	}
+
+	for _, arm := range c.arms {
+		if n < arm.weight {
+			return arm.handle
+		}
+		n -= arm.weight
+	}
+	return c.arms[len(c.arms)-1].handle
+}
+
+func (c *canary) serve(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+	c.pick(req)(w, req, ps)
+}
+
+// HandleWeighted registers a handle for the given method and path which
+// serves a share of the requests given by its weight. Registering several
+// handles for the same method and path splits the requests between them,
+// e.g. to send 5% of the requests to a canary:
+//
+//	router.HandleWeighted(http.MethodGet, "/search", 95, search)
+//	router.HandleWeighted(http.MethodGet, "/search", 5, searchV2)
+//
+// The middleware applies to the given handle only. Requests are assigned at
+// random, unless the route is made sticky with SetSticky. A handle with a
+// weight of zero receives no requests. HandleWeighted panics if the weight is
+// negative or the method and path are registered with another function.
+func (r *HttpRouter) HandleWeighted(method, path string, weight int, handle HttpHandle, middleware ...Middleware) {
+	if weight < 0 {
+		panic("weight must not be negative, got " + strconv.Itoa(weight) + " for path '" + path + "'")
+	}
+	if handle == nil {
+		panic("handle must not be nil")
+	}
+
+	if r.CheckCanceled {
+		handle = r.checkedChain(handle, middleware)
+	} else {
+		handle = chain(handle, middleware)
+	}
+	arm := canaryArm{weight: weight, handle: handle}
+
+	if rt := r.mutableTable().lookupRoute(method, path); rt != nil && rt.canary != nil {
+		rt.canary.arms = append(rt.canary.arms, arm)
+		rt.canary.total += weight
+		r.bumpVersion()
+		return
+	}
+
+	c := &canary{arms: []canaryArm{arm}, total: weight}
+	if err := r.tryHandle(method, path, c.serve, c, nil); err != nil {
+		panic(err.Error())
+	}
+	r.mutableTable().lookupRoute(method, path).canary = c
+}
+
+// SetSticky makes the weighted route registered with the given method and
+// path assign requests carrying the header or cookie selected by s to the
+// same handle, by hashing its value. Requests without it are assigned at
+// random. Adding handles or changing weights reassigns part of the keys.
+// SetSticky panics if no such weighted route exists. Like the registration
+// of routes, it must not be called concurrently with ServeHTTP.
+func (r *HttpRouter) SetSticky(method, path string, s Sticky) {
+	rt := r.mutableTable().lookupRoute(method, path)
+	if rt == nil || rt.canary == nil {
+		panic("no weighted route registered for " + method + " '" + path + "'")
+	}
+	rt.canary.sticky = s
+}
@@ -0,0 +1,75 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterHandleWeighted(t *testing.T) {
+	served := map[string]int{}
+	arm := func(name string) HttpHandle {
+		return func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+			served[name]++
+		}
+	}
+	var middlewareRan bool
+	router := New()
+	router.HandleWeighted(http.MethodGet, "/search", 3, arm("stable"))
+	router.HandleWeighted(http.MethodGet, "/search", 1, arm("canary"), func(next HttpHandle) HttpHandle {
+		return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+			middlewareRan = true
+			next(w, req, ps)
+		}
+	})
+	router.HandleWeighted(http.MethodGet, "/search", 0, arm("drained"))
+
+	c := router.mutableTable().lookupRoute(http.MethodGet, "/search").canary
+	i := 0
+	c.random = func(n int) int {
+		i++
+		return i % n
+	}
+	for j := 0; j < 8; j++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/search", nil))
+	}
+	if served["stable"] != 6 || served["canary"] != 2 || served["drained"] != 0 {
+		t.Errorf("wrong split %v", served)
+	}
+	if !middlewareRan {
+		t.Error("middleware of the canary did not run")
+	}
+
+	// Sticky requests are always served by the same handle
+	router.SetSticky(http.MethodGet, "/search", Sticky{Header: "X-User", Cookie: "user"})
+	for user := 0; user < 20; user++ {
+		served = map[string]int{}
+		for j := 0; j < 5; j++ {
+			req := httptest.NewRequest(http.MethodGet, "/search", nil)
+			if user%2 == 0 {
+				req.Header.Set("X-User", strconv.Itoa(user))
+			} else {
+				req.AddCookie(&http.Cookie{Name: "user", Value: strconv.Itoa(user)})
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+		}
+		if len(served) != 1 {
+			t.Errorf("user %d was served by %v", user, served)
+		}
+	}
+
+	handle := func(http.ResponseWriter, *http.Request, drouter.Params) {}
+	router.GET("/plain", handle)
+	if recv := catchPanic(func() { router.HandleWeighted(http.MethodGet, "/plain", 1, handle) }); recv == nil {
+		t.Error("no panic for weighted handle on plain route")
+	}
+	if recv := catchPanic(func() { router.HandleWeighted(http.MethodGet, "/neg", -1, handle) }); recv == nil {
+		t.Error("no panic for negative weight")
+	}
+	if recv := catchPanic(func() { router.SetSticky(http.MethodGet, "/plain", Sticky{Header: "X-User"}) }); recv == nil {
+		t.Error("no panic for sticky plain route")
+	}
+}
@@ -0,0 +1,52 @@
+package dhttprouter
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/thekhanj/drouter"
+)
+
+// StatusClientClosedRequest is the non-standard status code recorded for
+// requests skipped by CheckCanceled because the client disconnected, as
+// introduced by nginx. The client never sees it.
+const StatusClientClosedRequest = 499
+
+// Canceled returns the number of requests skipped so far because their
+// context was done before the handle ran, see CheckCanceled.
+func (r *HttpRouter) Canceled() uint64 {
+	return atomic.LoadUint64(&r.canceled)
+}
+
+// checkedChain is like chain, but checks for canceled requests before each
+// middleware and the handle.
+func (r *HttpRouter) checkedChain(handle HttpHandle, middleware []Middleware) HttpHandle {
+	handle = r.checkCanceled(handle)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if middleware[i] == nil {
+			panic("middleware must not be nil")
+		}
+		handle = r.checkCanceled(middleware[i](handle))
+	}
+	return handle
+}
+
+// checkCanceled returns a handle which skips the given one if the context of
+// the request is done.
+func (r *HttpRouter) checkCanceled(handle HttpHandle) HttpHandle {
+	return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		err := req.Context().Err()
+		if err == nil {
+			handle(w, req, ps)
+			return
+		}
+
+		atomic.AddUint64(&r.canceled, 1)
+		if err == context.DeadlineExceeded {
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(StatusClientClosedRequest)
+		}
+	}
+}
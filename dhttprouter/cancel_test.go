@@ -0,0 +1,88 @@
+package dhttprouter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterCheckCanceled(t *testing.T) {
+	var calls []string
+	var cancel context.CancelFunc
+	middleware := func(name string, cancels bool) Middleware {
+		return func(handle HttpHandle) HttpHandle {
+			return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+				calls = append(calls, name)
+				if cancels {
+					cancel()
+				}
+				handle(w, req, ps)
+			}
+		}
+	}
+	handle := func(http.ResponseWriter, *http.Request, drouter.Params) {
+		calls = append(calls, "handle")
+	}
+
+	router := New()
+	router.GET("/unchecked", handle, middleware("a", true))
+	router.CheckCanceled = true
+	router.GET("/checked", handle, middleware("a", true), middleware("b", false))
+
+	serve := func(path string, ctx context.Context) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil).WithContext(ctx))
+		return w.Code
+	}
+
+	tests := []struct {
+		path     string
+		canceled bool
+		code     int
+		calls    []string
+	}{
+		// Canceled before the match, nothing runs
+		{"/checked", true, StatusClientClosedRequest, nil},
+
+		// Canceled by the first middleware, the second one is skipped
+		{"/checked", false, StatusClientClosedRequest, []string{"a"}},
+
+		// Routes registered before the option are not checked
+		{"/unchecked", true, http.StatusOK, []string{"a", "handle"}},
+	}
+	for i, tt := range tests {
+		calls = nil
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		if tt.canceled {
+			cancel()
+		}
+		if code := serve(tt.path, ctx); code != tt.code {
+			t.Errorf("%d: want %d, got %d", i, tt.code, code)
+		}
+		if len(calls) != len(tt.calls) {
+			t.Errorf("%d: wrong calls %v", i, calls)
+			continue
+		}
+		for j := range calls {
+			if calls[j] != tt.calls[j] {
+				t.Errorf("%d: wrong calls %v", i, calls)
+			}
+		}
+		cancel()
+	}
+	if n := router.Canceled(); n != 2 {
+		t.Errorf("wrong number of canceled requests: %d", n)
+	}
+
+	ctx, cancelDeadline := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelDeadline()
+	cancel = func() {}
+	if code := serve("/checked", ctx); code != http.StatusServiceUnavailable {
+		t.Errorf("passed deadline: want 503, got %d", code)
+	}
+}
@@ -0,0 +1,154 @@
+package dhttprouter
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+
+	"github.com/thekhanj/drouter"
+)
+
+// Coalesce merges concurrent identical requests into a single execution of
+// the handle, whose response is sent to all of them. It protects expensive
+// backends from thundering herds and can be used as per-route Middleware via
+// its Wrap method, e.g.
+//
+//	c := &dhttprouter.Coalesce{Headers: []string{"Accept"}}
+//	router.GET("/reports/:id", handle, c.Wrap)
+//
+// Requests are identical if they have the same method, host, path, query,
+// credentials and values of the selected Headers. The credentials are the
+// Authorization, Proxy-Authorization, Cookie, X-Api-Key and X-Auth-Token
+// headers, so requests of different users never share a response, while
+// anonymous requests are still coalesced. Only GET and HEAD requests are
+// coalesced, requests with other methods are passed to the handle as they
+// are.
+// The response is buffered, so Coalesce is not suited for streaming handles.
+// If the executing handle panics or its request is canceled, e.g. because
+// the client went away, the waiting requests are served by their own
+// execution of the handle, as the response may be incomplete.
+type Coalesce struct {
+	// Request headers distinguishing otherwise identical requests in
+	// addition to the credentials, e.g. Accept
+	Headers []string
+
+	mu    sync.Mutex
+	calls map[string]*coalescedCall
+}
+
+type coalescedCall struct {
+	done     chan struct{}
+	failed   bool
+	response bufferedResponse
+}
+
+// Wrap returns a handle which coalesces the requests to the given handle.
+func (c *Coalesce) Wrap(handle HttpHandle) HttpHandle {
+	return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			handle(w, req, ps)
+			return
+		}
+
+		key := c.key(req)
+		c.mu.Lock()
+		if call := c.calls[key]; call != nil {
+			c.mu.Unlock()
+			select {
+			case <-call.done:
+			case <-req.Context().Done():
+				return
+			}
+			if call.failed {
+				handle(w, req, ps)
+			} else {
+				call.response.writeTo(w)
+			}
+			return
+		}
+
+		call := &coalescedCall{
+			done:     make(chan struct{}),
+			failed:   true,
+			response: bufferedResponse{header: make(http.Header)},
+		}
+		if c.calls == nil {
+			c.calls = make(map[string]*coalescedCall)
+		}
+		c.calls[key] = call
+		c.mu.Unlock()
+
+		defer func() {
+			c.mu.Lock()
+			delete(c.calls, key)
+			c.mu.Unlock()
+			close(call.done)
+		}()
+
+		handle(&call.response, req, ps)
+		call.failed = req.Context().Err() != nil
+		call.response.writeTo(w)
+	}
+}
+
+func (c *Coalesce) key(req *http.Request) string {
+	var b bytes.Buffer
+	b.WriteString(req.Method)
+	b.WriteByte(' ')
+	b.WriteString(req.Host)
+	b.WriteString(req.URL.RequestURI())
+	writeHeaderValues(&b, req.Header, credentialHeaders)
+	writeHeaderValues(&b, req.Header, c.Headers)
+	return b.String()
+}
+
+// writeHeaderValues writes the values of the named headers to b, each header
+// on its own line.
+func writeHeaderValues(b *bytes.Buffer, h http.Header, names []string) {
+	for _, name := range names {
+		b.WriteByte('\n')
+		for i, v := range h[http.CanonicalHeaderKey(name)] {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(v)
+		}
+	}
+}
+
+// bufferedResponse is a http.ResponseWriter keeping the response in memory,
+// so it can be written to several clients.
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) WriteHeader(code int) {
+	if b.status == 0 {
+		b.status = code
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// writeTo sends a copy of the buffered response to w.
+func (b *bufferedResponse) writeTo(w http.ResponseWriter) {
+	h := w.Header()
+	for k, v := range b.header {
+		h[k] = append([]string(nil), v...)
+	}
+	if b.status != 0 {
+		w.WriteHeader(b.status)
+	}
+	w.Write(b.body.Bytes())
+}
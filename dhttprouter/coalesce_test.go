@@ -0,0 +1,188 @@
+package dhttprouter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestCoalesce(t *testing.T) {
+	var executions int32
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+
+	handle := func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		atomic.AddInt32(&executions, 1)
+		started <- struct{}{}
+		<-release
+		w.Header().Set("X-Report", ps.ByName("id"))
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("report " + ps.ByName("id") + " " + req.Header.Get("Accept")))
+	}
+
+	c := &Coalesce{Headers: []string{"accept"}}
+	router := New()
+	router.GET("/reports/:id", handle, c.Wrap)
+	router.POST("/reports/:id", handle, c.Wrap)
+
+	type result struct {
+		code   int
+		header string
+		body   string
+	}
+	do := func(method, path, accept string, results chan<- result) {
+		r, _ := http.NewRequest(method, path, nil)
+		r.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		results <- result{w.Code, w.Header().Get("X-Report"), w.Body.String()}
+	}
+
+	results := make(chan result, 10)
+	go do(http.MethodGet, "/reports/1", "text/csv", results)
+	<-started
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			do(http.MethodGet, "/reports/1", "text/csv", results)
+		}()
+	}
+	// A different selected header value is a different request
+	go do(http.MethodGet, "/reports/1", "application/json", results)
+	<-started
+
+	// Let the followers arrive
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for i := 0; i < 7; i++ {
+		res := <-results
+		want := result{http.StatusAccepted, "1", "report 1 text/csv"}
+		if res.body == "report 1 application/json" {
+			want.body = res.body
+		}
+		if res != want {
+			t.Errorf("wrong coalesced response: %+v", res)
+		}
+	}
+	if n := atomic.LoadInt32(&executions); n != 2 {
+		t.Errorf("wrong number of executions: want 2, got %d", n)
+	}
+
+	// Other methods are not coalesced, the handle is not blocked anymore
+	atomic.StoreInt32(&executions, 0)
+	for i := 0; i < 2; i++ {
+		do(http.MethodPost, "/reports/2", "", results)
+		<-started
+		<-results
+	}
+	if n := atomic.LoadInt32(&executions); n != 2 {
+		t.Errorf("POST requests were coalesced: %d executions", n)
+	}
+}
+
+func TestCoalescePanic(t *testing.T) {
+	var executions int32
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+
+	c := &Coalesce{}
+	handle := c.Wrap(func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		if atomic.AddInt32(&executions, 1) == 1 {
+			started <- struct{}{}
+			<-release
+			panic("backend failed")
+		}
+		w.Write([]byte("ok"))
+	})
+
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	go catchPanic(func() { handle(httptest.NewRecorder(), r, nil) })
+	<-started
+
+	done := make(chan string)
+	go func() {
+		w := httptest.NewRecorder()
+		handle(w, r, nil)
+		done <- w.Body.String()
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	if body := <-done; body != "ok" {
+		t.Errorf("waiting request not served after panic: %q", body)
+	}
+}
+
+func TestCoalesceCanceled(t *testing.T) {
+	var executions int32
+	started := make(chan struct{}, 1)
+
+	c := &Coalesce{}
+	handle := c.Wrap(func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		if atomic.AddInt32(&executions, 1) == 1 {
+			// The leader gives up when its client goes away
+			w.Write([]byte("partial"))
+			started <- struct{}{}
+			<-req.Context().Done()
+			return
+		}
+		w.Write([]byte("complete"))
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leader := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	go handle(httptest.NewRecorder(), leader, nil)
+	<-started
+
+	done := make(chan string)
+	go func() {
+		w := httptest.NewRecorder()
+		handle(w, httptest.NewRequest(http.MethodGet, "/", nil), nil)
+		done <- w.Body.String()
+	}()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if body := <-done; body != "complete" {
+		t.Errorf("waiting request served the canceled response: %q", body)
+	}
+	if n := atomic.LoadInt32(&executions); n != 2 {
+		t.Errorf("wrong number of executions: want 2, got %d", n)
+	}
+}
+
+func TestCoalesceCredentials(t *testing.T) {
+	c := &Coalesce{}
+	request := func(header, value string) *http.Request {
+		r, _ := http.NewRequest(http.MethodGet, "/account", nil)
+		if header != "" {
+			r.Header.Set(header, value)
+		}
+		return r
+	}
+
+	anonymous := c.key(request("", ""))
+	if c.key(request("", "")) != anonymous || c.key(request("Accept", "text/csv")) != anonymous {
+		t.Error("identical anonymous requests are not coalesced")
+	}
+	for _, header := range []string{"Authorization", "Proxy-Authorization", "Cookie", "X-Api-Key", "X-Auth-Token"} {
+		alice, bob := c.key(request(header, "alice")), c.key(request(header, "bob"))
+		if alice == bob || alice == anonymous {
+			t.Errorf("requests with different %s are coalesced", header)
+		}
+		if c.key(request(header, "alice")) != alice {
+			t.Errorf("requests with the same %s are not coalesced", header)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package dhttprouter
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Compression configures the gzip compression of the routes of a router.
+// Responses are compressed if the client accepts gzip, request bodies sent
+// with "Content-Encoding: gzip" are decompressed before they reach the
+// handle. The bytes passing through are counted per route, see
+// CompressionStats.
+type Compression struct {
+	// Compression level, see compress/gzip. Defaults to
+	// gzip.DefaultCompression.
+	Level int
+
+	// Content types of responses which are compressed, e.g. "text/html" or
+	// "application/json". A trailing '*' matches any suffix, e.g. "text/*".
+	// If empty, all responses are compressed.
+	ContentTypes []string
+
+	pools sync.Map // level -> *sync.Pool
+}
+
+// CompressionStats holds the byte counters of a route, see CompressionStats.
+type CompressionStats struct {
+	Method string
+	Path   string
+
+	// Bytes of request bodies as received and as passed to the handle after
+	// decompression
+	RequestWire    uint64
+	RequestDecoded uint64
+
+	// Bytes of responses as written by the handle and as sent after
+	// compression
+	ResponseRaw  uint64
+	ResponseWire uint64
+}
+
+// ResponseRatio returns the ratio of sent to written response bytes, or 1 if
+// the route did not respond yet.
+func (s CompressionStats) ResponseRatio() float64 {
+	if s.ResponseRaw == 0 {
+		return 1
+	}
+	return float64(s.ResponseWire) / float64(s.ResponseRaw)
+}
+
+// byteCounters counts the bytes of requests and responses of a route.
+type byteCounters struct {
+	// Accessed atomically, keep 64-bit aligned
+	requestWire    uint64
+	requestDecoded uint64
+	responseRaw    uint64
+	responseWire   uint64
+}
+
+func (c *Compression) level() int {
+	if c.Level == 0 {
+		return gzip.DefaultCompression
+	}
+	return c.Level
+}
+
+func (c *Compression) getWriter(w io.Writer) *gzip.Writer {
+	level := c.level()
+	pool, _ := c.pools.LoadOrStore(level, &sync.Pool{})
+	if gz, ok := pool.(*sync.Pool).Get().(*gzip.Writer); ok {
+		gz.Reset(w)
+		return gz
+	}
+	gz, err := gzip.NewWriterLevel(w, level)
+	if err != nil {
+		panic("invalid gzip compression level")
+	}
+	return gz
+}
+
+func (c *Compression) putWriter(gz *gzip.Writer) {
+	pool, _ := c.pools.Load(c.level())
+	pool.(*sync.Pool).Put(gz)
+}
+
+// compressible reports whether responses of the given content type are
+// compressed.
+func (c *Compression) compressible(contentType string) bool {
+	if len(c.ContentTypes) == 0 {
+		return true
+	}
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	contentType = strings.TrimSpace(strings.ToLower(contentType))
+	for _, ct := range c.ContentTypes {
+		if strings.HasSuffix(ct, "*") {
+			if strings.HasPrefix(contentType, ct[:len(ct)-1]) {
+				return true
+			}
+		} else if ct == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsGzip reports whether the client accepts gzip encoded responses.
+func acceptsGzip(req *http.Request) bool {
+	for _, v := range req.Header["Accept-Encoding"] {
+		for _, enc := range strings.Split(v, ",") {
+			name, q, _ := strings.Cut(strings.TrimSpace(enc), ";")
+			if strings.EqualFold(strings.TrimSpace(name), "gzip") &&
+				strings.ReplaceAll(strings.TrimSpace(q), " ", "") != "q=0" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wrap prepares the request to the route for the handle, decompressing the
+// request body and compressing the response written to the returned writer,
+// which must be closed once the handle returned. It returns false if the
+// request was answered because its body can't be decompressed.
+func (c *Compression) wrap(rt *route, w http.ResponseWriter, req *http.Request) (*compressWriter, *http.Request, bool) {
+	counters := &rt.bytes
+
+	if req.Body != nil && req.Body != http.NoBody {
+		wire := &countingReader{r: req.Body, n: &counters.requestWire}
+		body := io.ReadCloser(wire)
+		if strings.EqualFold(req.Header.Get("Content-Encoding"), "gzip") {
+			gz, err := gzip.NewReader(wire)
+			if err != nil {
+				http.Error(w, "invalid gzip request body", http.StatusBadRequest)
+				return nil, nil, false
+			}
+			body = &gzipBody{Reader: gz, wire: wire}
+			req.Header.Del("Content-Encoding")
+			req.Header.Del("Content-Length")
+			req.ContentLength = -1
+		}
+		req.Body = &countingReader{r: body, n: &counters.requestDecoded}
+	}
+
+	w.Header().Add("Vary", "Accept-Encoding")
+	return &compressWriter{
+		ResponseWriter: w,
+		c:              c,
+		counters:       counters,
+		accepts:        acceptsGzip(req) && req.Method != http.MethodHead,
+	}, req, true
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.ReadCloser
+	n *uint64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	atomic.AddUint64(r.n, uint64(n))
+	return n, err
+}
+
+func (r *countingReader) Close() error {
+	return r.r.Close()
+}
+
+// gzipBody is a decompressed request body.
+type gzipBody struct {
+	*gzip.Reader
+	wire io.Closer
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.wire.Close()
+}
+
+// compressWriter compresses the response if the client accepts gzip and the
+// handle did not encode the response itself, counting the bytes.
+type compressWriter struct {
+	http.ResponseWriter
+	c        *Compression
+	counters *byteCounters
+	accepts  bool
+
+	wroteHeader bool
+	gz          *gzip.Writer
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.wroteHeader || code < 200 {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.wroteHeader = true
+
+	h := w.Header()
+	if w.accepts && h.Get("Content-Encoding") == "" &&
+		code != http.StatusNoContent && code != http.StatusNotModified &&
+		w.c.compressible(h.Get("Content-Type")) {
+		h.Set("Content-Encoding", "gzip")
+		h.Del("Content-Length")
+		w.gz = w.c.getWriter(wireWriter{w})
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", http.DetectContentType(p))
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+	atomic.AddUint64(&w.counters.responseRaw, uint64(len(p)))
+	if w.gz != nil {
+		return w.gz.Write(p)
+	}
+	return wireWriter{w}.Write(p)
+}
+
+// Flush implements http.Flusher.
+func (w *compressWriter) Flush() {
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker, e.g. for WebSocket handles. The response
+// is not compressed anymore once the connection is hijacked.
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("dhttprouter: response writer does not support hijacking")
+	}
+	conn, rw, err := hj.Hijack()
+	if err == nil {
+		w.wroteHeader = true
+		w.accepts = false
+		if w.gz != nil {
+			// Closing would write the gzip footer to the hijacked connection
+			w.c.putWriter(w.gz)
+			w.gz = nil
+		}
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *compressWriter) close() {
+	if w.gz != nil {
+		w.gz.Close()
+		w.c.putWriter(w.gz)
+		w.gz = nil
+	}
+}
+
+// wireWriter writes to the underlying writer, counting the bytes sent.
+type wireWriter struct {
+	w *compressWriter
+}
+
+func (ww wireWriter) Write(p []byte) (int, error) {
+	n, err := ww.w.ResponseWriter.Write(p)
+	atomic.AddUint64(&ww.w.counters.responseWire, uint64(n))
+	return n, err
+}
+
+// CompressionStats returns the byte counters of all routes which received or
+// sent a body through Compression, ordered by path and method.
+func (r *HttpRouter) CompressionStats() []CompressionStats {
+	return r.loadTable().compressionStats()
+}
+
+func (t *routeTable) compressionStats() []CompressionStats {
+	var stats []CompressionStats
+	for _, router := range t.routers {
+		router.Walk(func(_ string, rt *route) bool {
+			s := CompressionStats{
+				Method:         rt.method,
+				Path:           rt.path,
+				RequestWire:    atomic.LoadUint64(&rt.bytes.requestWire),
+				RequestDecoded: atomic.LoadUint64(&rt.bytes.requestDecoded),
+				ResponseRaw:    atomic.LoadUint64(&rt.bytes.responseRaw),
+				ResponseWire:   atomic.LoadUint64(&rt.bytes.responseWire),
+			}
+			if s.RequestWire > 0 || s.ResponseWire > 0 {
+				stats = append(stats, s)
+			}
+			return true
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Path != stats[j].Path {
+			return stats[i].Path < stats[j].Path
+		}
+		return stats[i].Method < stats[j].Method
+	})
+	return stats
+}
@@ -0,0 +1,143 @@
+package dhttprouter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterCompression(t *testing.T) {
+	page := strings.Repeat("<p>hello gopher</p>", 100)
+	echo := func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write(body)
+	}
+	html := func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		w.Write([]byte(page))
+	}
+	image := func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("png"))
+	}
+
+	router := New()
+	router.Compression = &Compression{ContentTypes: []string{"text/*"}}
+	router.POST("/echo", echo)
+	router.GET("/page", html)
+	router.GET("/logo.png", image)
+
+	// compressed response
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/page", nil)
+	req.Header.Set("Accept-Encoding", "br, gzip")
+	router.ServeHTTP(w, req)
+	if w.Header().Get("Content-Encoding") != "gzip" || w.Header().Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("response not compressed: %v", w.Header())
+	}
+	wire := w.Body.Len()
+	gz, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body, _ := ioutil.ReadAll(gz); string(body) != page {
+		t.Errorf("wrong decompressed body: %q", body)
+	}
+
+	// client without gzip support
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/page", nil)
+	req.Header.Set("Accept-Encoding", "gzip;q=0")
+	router.ServeHTTP(w, req)
+	if w.Header().Get("Content-Encoding") != "" || w.Body.String() != page {
+		t.Errorf("response compressed for client without gzip support")
+	}
+
+	// content type not compressed
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/logo.png", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	router.ServeHTTP(w, req)
+	if w.Header().Get("Content-Encoding") != "" || w.Body.String() != "png" {
+		t.Errorf("image response compressed")
+	}
+
+	// compressed request
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write([]byte(page))
+	zw.Close()
+	compressed := buf.Len()
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/echo", &buf)
+	req.Header.Set("Content-Encoding", "gzip")
+	router.ServeHTTP(w, req)
+	if w.Body.String() != page {
+		t.Errorf("request body not decompressed: %q", w.Body.String())
+	}
+
+	// invalid compressed request
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/echo", strings.NewReader("plain"))
+	req.Header.Set("Content-Encoding", "gzip")
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("wrong status for invalid body: %d", w.Code)
+	}
+
+	stats := router.CompressionStats()
+	if len(stats) != 3 {
+		t.Fatalf("wrong number of stats: %+v", stats)
+	}
+	if s := stats[0]; s.Path != "/echo" || s.RequestWire != uint64(compressed+len("plain")) ||
+		s.RequestDecoded != uint64(len(page)) || s.ResponseRaw != uint64(len(page)) || s.ResponseWire != uint64(len(page)) {
+		t.Errorf("wrong stats for /echo: %+v", s)
+	}
+	if s := stats[2]; s.Path != "/page" || s.ResponseRaw != uint64(2*len(page)) ||
+		s.ResponseWire != uint64(wire+len(page)) || s.ResponseRatio() >= 1 {
+		t.Errorf("wrong stats for /page: %+v", s)
+	}
+}
+
+func TestRouterCompressionHijack(t *testing.T) {
+	router := New()
+	router.Compression = &Compression{}
+	router.GET("/ws", func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			http.Error(w, "no hijacker", http.StatusInternalServerError)
+			return
+		}
+		conn, rw, err := hj.Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 2\r\nConnection: close\r\n\r\nhi")
+		rw.Flush()
+	})
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/ws", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "hi" || resp.Header.Get("Content-Encoding") != "" {
+		t.Errorf("wrong response: %d %q %v", resp.StatusCode, body, resp.Header)
+	}
+}
@@ -0,0 +1,253 @@
+package dhttprouter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// RouteConfig is a route declared in a configuration, see LoadRoutes.
+type RouteConfig struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+
+	// Name of the handle in the Registry
+	Handler string `json:"handler"`
+
+	// Names of the middleware in the Registry, outermost first
+	Middleware []string `json:"middleware,omitempty"`
+
+	// Position of the route in the configuration, counted from 1
+	Line, Column int `json:"-"`
+}
+
+// Registry maps the names used in a configuration onto handles and
+// middleware.
+type Registry struct {
+	Handlers   map[string]HttpHandle
+	Middleware map[string]Middleware
+}
+
+// ConfigError is an error in a configuration, see LoadRoutes.
+type ConfigError struct {
+	// Position of the error, counted from 1
+	Line, Column int
+
+	// Method and path of the route the error relates to, if any
+	Method, Path string
+
+	Err error
+}
+
+func (e *ConfigError) Error() string {
+	msg := "line " + strconv.Itoa(e.Line) + ", column " + strconv.Itoa(e.Column) + ": "
+	if e.Path != "" {
+		msg += e.Method + " " + e.Path + ": "
+	}
+	return msg + e.Err.Error()
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// ConfigErrors lists all errors found in a configuration.
+type ConfigErrors []*ConfigError
+
+func (e ConfigErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// ParseRouteConfig reads the routes of a JSON configuration of the form
+//
+//	{
+//		"routes": [
+//			{"method": "GET", "path": "/users/:id", "handler": "getUser", "middleware": ["auth"]},
+//			{"method": "POST", "path": "/users", "handler": "createUser"}
+//		]
+//	}
+//
+// and records the position of each route. Unknown fields are rejected, so
+// typos do not go unnoticed. Errors are returned as *ConfigError with the
+// position in the input.
+func ParseRouteConfig(rd io.Reader) ([]RouteConfig, error) {
+	data, err := ioutil.ReadAll(rd)
+	if err != nil {
+		return nil, err
+	}
+	p := configParser{data: data, dec: json.NewDecoder(bytes.NewReader(data))}
+	p.dec.DisallowUnknownFields()
+
+	if err := p.delim('{'); err != nil {
+		return nil, err
+	}
+	var routes []RouteConfig
+	for p.dec.More() {
+		start := p.offset()
+		tok, err := p.dec.Token()
+		if err != nil {
+			return nil, p.fail(start, err)
+		}
+		if tok != "routes" {
+			return nil, p.fail(start, fmt.Errorf("unknown field %q", tok))
+		}
+		if err := p.delim('['); err != nil {
+			return nil, err
+		}
+		for p.dec.More() {
+			start := p.offset()
+			var rc RouteConfig
+			if err := p.dec.Decode(&rc); err != nil {
+				var typeErr *json.UnmarshalTypeError
+				if errors.As(err, &typeErr) {
+					// The offset is relative to the route
+					return nil, p.fail(start+typeErr.Offset, err)
+				}
+				return nil, p.fail(start, err)
+			}
+			rc.Line, rc.Column = p.position(start)
+			routes = append(routes, rc)
+		}
+		if err := p.delim(']'); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.delim('}'); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// configParser tracks the positions in a configuration.
+type configParser struct {
+	data []byte
+	dec  *json.Decoder
+}
+
+// offset returns the offset of the next token.
+func (p *configParser) offset() int64 {
+	off := p.dec.InputOffset()
+	for off < int64(len(p.data)) {
+		switch p.data[off] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			off++
+			continue
+		}
+		break
+	}
+	return off
+}
+
+// position returns the line and column of the offset.
+func (p *configParser) position(off int64) (line, column int) {
+	if off > int64(len(p.data)) {
+		off = int64(len(p.data))
+	}
+	before := p.data[:off]
+	line = bytes.Count(before, []byte{'\n'}) + 1
+	column = int(off) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
+
+func (p *configParser) fail(off int64, err error) *ConfigError {
+	var syntaxErr *json.SyntaxError
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		err, off = io.ErrUnexpectedEOF, int64(len(p.data))
+	case errors.As(err, &syntaxErr) && syntaxErr.Offset >= int64(len(p.data)):
+		off = int64(len(p.data))
+	case errors.As(err, &syntaxErr) && syntaxErr.Offset > 0:
+		// The offset is past the invalid character
+		off = syntaxErr.Offset - 1
+	}
+	line, column := p.position(off)
+	return &ConfigError{Line: line, Column: column, Err: err}
+}
+
+// delim reads the expected delimiter.
+func (p *configParser) delim(want json.Delim) error {
+	start := p.offset()
+	tok, err := p.dec.Token()
+	if err != nil {
+		return p.fail(start, err)
+	}
+	if tok != want {
+		return p.fail(start, fmt.Errorf("expected %q, found %v", want, tok))
+	}
+	return nil
+}
+
+// LoadRoutes registers the routes of a JSON configuration, see
+// ParseRouteConfig, with the handles and middleware of the registry, e.g. in
+// a config-driven gateway:
+//
+//	reg := dhttprouter.Registry{
+//		Handlers:   map[string]dhttprouter.HttpHandle{"getUser": getUser},
+//		Middleware: map[string]dhttprouter.Middleware{"auth": auth.Wrap},
+//	}
+//	if err := router.LoadRoutes(f, reg); err != nil {
+//		log.Fatal(err) // e.g. line 4, column 5: GET /users/:id: unknown handler "getUsr"
+//	}
+//
+// It returns the first syntax error, or ConfigErrors listing every route
+// which names an unknown handle or middleware or cannot be registered. The
+// valid routes are registered nonetheless, so a configuration is best
+// loaded into a new HttpRouter which replaces the serving one with Swap once
+// it loaded without errors.
+func (r *HttpRouter) LoadRoutes(rd io.Reader, reg Registry) error {
+	routes, err := ParseRouteConfig(rd)
+	if err != nil {
+		return err
+	}
+	return r.RegisterRoutes(routes, reg)
+}
+
+// RegisterRoutes registers the routes with the handles and middleware of the
+// registry, see LoadRoutes.
+func (r *HttpRouter) RegisterRoutes(routes []RouteConfig, reg Registry) error {
+	var errs ConfigErrors
+	for _, rc := range routes {
+		fail := func(err error) {
+			errs = append(errs, &ConfigError{Line: rc.Line, Column: rc.Column, Method: rc.Method, Path: rc.Path, Err: err})
+		}
+
+		if rc.Method == "" {
+			fail(errors.New("method must not be empty"))
+			continue
+		}
+		handle := reg.Handlers[rc.Handler]
+		if handle == nil {
+			fail(fmt.Errorf("unknown handler %q", rc.Handler))
+			continue
+		}
+		middleware := make([]Middleware, 0, len(rc.Middleware))
+		for _, name := range rc.Middleware {
+			m := reg.Middleware[name]
+			if m == nil {
+				fail(fmt.Errorf("unknown middleware %q", name))
+				continue
+			}
+			middleware = append(middleware, m)
+		}
+		if len(middleware) < len(rc.Middleware) {
+			continue
+		}
+
+		if err := r.TryHandle(rc.Method, rc.Path, handle, middleware...); err != nil {
+			fail(err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package dhttprouter
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterLoadRoutes(t *testing.T) {
+	var calls []string
+	reg := Registry{
+		Handlers: map[string]HttpHandle{
+			"getUser": func(_ http.ResponseWriter, _ *http.Request, ps drouter.Params) {
+				calls = append(calls, "getUser "+ps.ByName("id"))
+			},
+			"createUser": func(http.ResponseWriter, *http.Request, drouter.Params) {
+				calls = append(calls, "createUser")
+			},
+		},
+		Middleware: map[string]Middleware{
+			"auth": func(next HttpHandle) HttpHandle {
+				return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+					calls = append(calls, "auth")
+					next(w, req, ps)
+				}
+			},
+		},
+	}
+
+	config := `{
+	"routes": [
+		{"method": "GET", "path": "/users/:id", "handler": "getUser", "middleware": ["auth"]},
+		{"method": "POST", "path": "/users", "handler": "createUser"}
+	]
+}`
+	router := New()
+	if err := router.LoadRoutes(strings.NewReader(config), reg); err != nil {
+		t.Fatal(err)
+	}
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/7", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users", nil))
+	if strings.Join(calls, ",") != "auth,getUser 7,createUser" {
+		t.Errorf("wrong calls %v", calls)
+	}
+
+	// Errors of all routes are reported with their position
+	config = `{"routes": [
+	{"method": "GET", "path": "/a", "handler": "missing"},
+	{"method": "GET", "path": "/b", "handler": "getUser", "middleware": ["auth", "cache"]},
+	{"method": "GET", "path": "/users/:id", "handler": "getUser"},
+	{"path": "/c", "handler": "getUser"}
+]}`
+	err := New().LoadRoutes(strings.NewReader(config), reg)
+	var errs ConfigErrors
+	if !errors.As(err, &errs) || len(errs) != 3 {
+		t.Fatalf("wrong errors %v", err)
+	}
+	want := []string{
+		`line 2, column 2: GET /a: unknown handler "missing"`,
+		`line 3, column 2: GET /b: unknown middleware "cache"`,
+		`line 5, column 2:  /c: method must not be empty`,
+	}
+	for i, w := range want {
+		if errs[i].Error() != w {
+			t.Errorf("wrong error %q, want %q", errs[i], w)
+		}
+	}
+
+	conflict := `{"routes": [{"method": "GET", "path": "/users/:id", "handler": "getUser"}]}`
+	err = router.LoadRoutes(strings.NewReader(conflict), reg)
+	var routeErr *drouter.RouteError
+	if !errors.As(err, &errs) || !errors.As(errs[0], &routeErr) || routeErr.Kind != drouter.Conflict {
+		t.Errorf("wrong error for conflict %v", err)
+	}
+}
+
+func TestParseRouteConfig(t *testing.T) {
+	routes, err := ParseRouteConfig(strings.NewReader("{\"routes\": [\n  {\"method\": \"GET\", \"path\": \"/\", \"handler\": \"index\"}]}"))
+	if err != nil || len(routes) != 1 || routes[0].Line != 2 || routes[0].Column != 3 || routes[0].Handler != "index" {
+		t.Errorf("wrong routes %+v, %v", routes, err)
+	}
+
+	tests := []struct {
+		config       string
+		line, column int
+	}{
+		{"{\"routes\": [\n  {\"method\": 1}]}", 2, 15},
+		{"{\"routes\": [\n  {\"methd\": \"GET\"}]}", 2, 3},
+		{"{\"paths\": []}", 1, 2},
+		{"{\"routes\": [\n  {\"method\": \"GET\",}]}", 2, 20},
+		{"{\"routes\": [", 1, 13},
+		{"[]", 1, 1},
+	}
+	for _, tt := range tests {
+		_, err := ParseRouteConfig(strings.NewReader(tt.config))
+		var configErr *ConfigError
+		if !errors.As(err, &configErr) {
+			t.Errorf("%q: wrong error %v", tt.config, err)
+			continue
+		}
+		if configErr.Line != tt.line || configErr.Column != tt.column {
+			t.Errorf("%q: wrong position of %v", tt.config, err)
+		}
+	}
+
+}
@@ -0,0 +1,72 @@
+package dhttprouter
+
+import (
+	"net/http"
+
+	"github.com/thekhanj/drouter"
+)
+
+// ContentLengthPolicy declares the preconditions on the Content-Length of
+// the requests of a route, which are checked before the handle runs. It can
+// be used as per-route Middleware via its Wrap method, e.g. to reject
+// chunked bodies on a legacy endpoint:
+//
+//	legacy := dhttprouter.ContentLengthPolicy{Required: true, Max: 1 << 20}
+//	router.POST("/legacy/upload", upload, legacy.Wrap)
+type ContentLengthPolicy struct {
+	// If enabled, requests whose length is unknown, e.g. chunked ones, are
+	// answered with 411 Length Required.
+	Required bool
+
+	// Minimum length of the body. Shorter requests are answered with 400 Bad
+	// Request. Disabled if zero.
+	Min int64
+
+	// Maximum length of the body. Longer requests are answered with 413
+	// Payload Too Large. Requests of unknown length are accepted, unless
+	// Required is set, but reading more than Max bytes of their body fails.
+	// Disabled if zero.
+	Max int64
+
+	// Optional function which is called for rejected requests with the
+	// status code. If it is not set, http.Error is used.
+	Rejected func(w http.ResponseWriter, req *http.Request, code int)
+}
+
+// Wrap returns a handle which enforces the preconditions on the given handle.
+func (p ContentLengthPolicy) Wrap(handle HttpHandle) HttpHandle {
+	return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		if code := p.check(req.ContentLength); code != 0 {
+			p.reject(w, req, code)
+			return
+		}
+		if p.Max > 0 && req.ContentLength < 0 {
+			req.Body = http.MaxBytesReader(w, req.Body, p.Max)
+		}
+		handle(w, req, ps)
+	}
+}
+
+// check returns the status code rejecting a request of the given length,
+// which is -1 if it is unknown, or zero if the request is accepted.
+func (p ContentLengthPolicy) check(length int64) int {
+	switch {
+	case length < 0:
+		if p.Required {
+			return http.StatusLengthRequired
+		}
+	case p.Max > 0 && length > p.Max:
+		return http.StatusRequestEntityTooLarge
+	case length < p.Min:
+		return http.StatusBadRequest
+	}
+	return 0
+}
+
+func (p ContentLengthPolicy) reject(w http.ResponseWriter, req *http.Request, code int) {
+	if p.Rejected != nil {
+		p.Rejected(w, req, code)
+	} else {
+		http.Error(w, http.StatusText(code), code)
+	}
+}
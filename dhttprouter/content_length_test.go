@@ -0,0 +1,67 @@
+package dhttprouter
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestContentLengthPolicy(t *testing.T) {
+	var readErr error
+	handle := func(_ http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		_, readErr = io.ReadAll(req.Body)
+	}
+
+	router := New()
+	router.POST("/legacy", handle, ContentLengthPolicy{Required: true, Min: 2, Max: 4}.Wrap)
+	router.POST("/upload", handle, ContentLengthPolicy{Max: 4}.Wrap)
+
+	tests := []struct {
+		path    string
+		body    string
+		chunked bool
+		code    int
+	}{
+		{"/legacy", "abc", false, http.StatusOK},
+		{"/legacy", "abc", true, http.StatusLengthRequired},
+		{"/legacy", "abcde", false, http.StatusRequestEntityTooLarge},
+		{"/legacy", "a", false, http.StatusBadRequest},
+		{"/upload", "abc", true, http.StatusOK},
+		{"/upload", "abcde", false, http.StatusRequestEntityTooLarge},
+	}
+	for i, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+		if tt.chunked {
+			req.ContentLength = -1
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tt.code {
+			t.Errorf("%d: want %d, got %d", i, tt.code, w.Code)
+		}
+	}
+
+	// Bodies of unknown length are limited while reading
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("abcdef"))
+	req.ContentLength = -1
+	readErr = nil
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	if readErr == nil {
+		t.Error("oversized chunked body was read")
+	}
+
+	var rejected int
+	p := ContentLengthPolicy{Required: true, Rejected: func(_ http.ResponseWriter, _ *http.Request, code int) {
+		rejected = code
+	}}
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("a"))
+	req.ContentLength = -1
+	p.Wrap(handle)(httptest.NewRecorder(), req, nil)
+	if rejected != http.StatusLengthRequired {
+		t.Errorf("custom rejection was not used: %d", rejected)
+	}
+}
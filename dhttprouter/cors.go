@@ -0,0 +1,191 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+// CORSPolicy configures cross-origin resource sharing for a route.
+type CORSPolicy struct {
+	// Origins allowed to access the route, e.g. "https://example.com".
+	// "*" allows all origins, a single '*' within an origin matches any
+	// subdomain, e.g. "https://*.example.com".
+	AllowedOrigins []string
+
+	// Methods allowed in cross-origin requests. Preflight requests are
+	// answered with the methods registered for the path, restricted to
+	// these if set.
+	AllowedMethods []string
+
+	// Request headers allowed in cross-origin requests. "*" allows all
+	// headers requested by the client.
+	AllowedHeaders []string
+
+	// Response headers exposed to the client.
+	ExposedHeaders []string
+
+	// Whether credentials like cookies are allowed in cross-origin requests.
+	AllowCredentials bool
+
+	// How long the results of a preflight request may be cached.
+	MaxAge time.Duration
+}
+
+// allowOrigin returns the value of the Access-Control-Allow-Origin header for
+// the given origin, or an empty string if the origin is not allowed.
+func (p *CORSPolicy) allowOrigin(origin string) string {
+	for _, o := range p.AllowedOrigins {
+		if o == "*" {
+			if p.AllowCredentials {
+				return origin
+			}
+			return "*"
+		}
+		if matchOrigin(o, origin) {
+			return origin
+		}
+	}
+	return ""
+}
+
+// matchOrigin reports whether the origin matches the pattern, which is an
+// origin with an optional single '*' matching any subdomain.
+func matchOrigin(pattern, origin string) bool {
+	if pattern == origin {
+		return true
+	}
+	i := strings.IndexByte(pattern, '*')
+	return i >= 0 && len(origin) > len(pattern)-1 &&
+		strings.HasPrefix(origin, pattern[:i]) && strings.HasSuffix(origin, pattern[i+1:])
+}
+
+// setHeaders sets the CORS headers of a response to an actual (non-preflight)
+// cross-origin request.
+func (p *CORSPolicy) setHeaders(w http.ResponseWriter, req *http.Request) {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return
+	}
+
+	h := w.Header()
+	h.Add("Vary", "Origin")
+	allow := p.allowOrigin(origin)
+	if allow == "" {
+		return
+	}
+
+	h.Set("Access-Control-Allow-Origin", allow)
+	if p.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if len(p.ExposedHeaders) > 0 {
+		h.Set("Access-Control-Expose-Headers", strings.Join(p.ExposedHeaders, ", "))
+	}
+}
+
+// preflight answers a CORS preflight request, given the methods allowed for
+// the path. It returns false if the request is not a preflight request.
+func (p *CORSPolicy) preflight(w http.ResponseWriter, req *http.Request, allow string) bool {
+	origin := req.Header.Get("Origin")
+	method := req.Header.Get("Access-Control-Request-Method")
+	if origin == "" || method == "" {
+		return false
+	}
+
+	h := w.Header()
+	h.Add("Vary", "Origin")
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+
+	allow = p.allowMethods(allow)
+	allowOrigin := p.allowOrigin(origin)
+	if allowOrigin == "" || !containsMethod(allow, method) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return true
+	}
+
+	h.Set("Access-Control-Allow-Origin", allowOrigin)
+	h.Set("Access-Control-Allow-Methods", allow)
+	if p.AllowCredentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if len(p.AllowedHeaders) == 1 && p.AllowedHeaders[0] == "*" {
+		if requested := req.Header.Get("Access-Control-Request-Headers"); requested != "" {
+			h.Set("Access-Control-Allow-Headers", requested)
+		}
+	} else if len(p.AllowedHeaders) > 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(p.AllowedHeaders, ", "))
+	}
+	if p.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(int(p.MaxAge/time.Second)))
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return true
+}
+
+// allowMethods restricts a comma-separated list of methods as returned by
+// allowed to AllowedMethods, if set.
+func (p *CORSPolicy) allowMethods(allow string) string {
+	if len(p.AllowedMethods) == 0 {
+		return allow
+	}
+	var methods []string
+	for _, m := range strings.Split(allow, ", ") {
+		for _, allowed := range p.AllowedMethods {
+			if m == allowed {
+				methods = append(methods, m)
+				break
+			}
+		}
+	}
+	return strings.Join(methods, ", ")
+}
+
+// containsMethod reports whether a comma-separated list of methods as
+// returned by allowed contains the given method.
+func containsMethod(allow, method string) bool {
+	for _, m := range strings.Split(allow, ", ") {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// SetCORS sets the CORS policy for all routes with the given path, taking
+// precedence over the router's CORS policy, and
+// registers an OPTIONS handle for the path which answers preflight requests
+// according to the policy. The allowed methods are derived from the routes
+// registered for the path at request time. Like all explicit OPTIONS handles,
+// it takes priority over GlobalOPTIONS.
+// SetCORS panics if an OPTIONS handle is already registered for the path.
+func (r *HttpRouter) SetCORS(path string, policy CORSPolicy) {
+	p := &policy
+
+	r.OPTIONS(path, func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		t := r.loadTable()
+		key := r.preflightKey(t, req, req.URL.Path, p)
+		if r.cachedPreflight(w, t, key, p) {
+			return
+		}
+		allow := t.allowed(req.URL.Path, http.MethodOptions)
+		if allow != "" {
+			w.Header().Set("Allow", allow)
+		}
+		sw := &statusWriter{ResponseWriter: w}
+		if !p.preflight(sw, req, allow) {
+			sw.WriteHeader(http.StatusNoContent)
+		}
+		r.storePreflight(t, key, p, w.Header(), sw.status)
+	})
+
+	t := r.mutableTable()
+	if t.corsPolicies == nil {
+		t.corsPolicies = make(map[string]*CORSPolicy)
+	}
+	t.corsPolicies[path] = p
+}
@@ -0,0 +1,184 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterSetCORS(t *testing.T) {
+	handlerFunc := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
+
+	globalOptions := false
+	router := New()
+	router.GlobalOPTIONS = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		globalOptions = true
+	})
+	router.GET("/api/items/:id", handlerFunc)
+	router.SetCORS("/api/items/:id", CORSPolicy{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		ExposedHeaders:   []string{"X-Total"},
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	})
+	// routes registered after the policy are covered as well
+	router.PUT("/api/items/:id", handlerFunc)
+
+	// preflight
+	r, _ := http.NewRequest(http.MethodOptions, "/api/items/1", nil)
+	r.Header.Set("Origin", "https://app.example.com")
+	r.Header.Set("Access-Control-Request-Method", http.MethodPut)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	h := w.Header()
+	if w.Code != http.StatusNoContent || globalOptions ||
+		h.Get("Access-Control-Allow-Origin") != "https://app.example.com" ||
+		h.Get("Access-Control-Allow-Methods") != "GET, OPTIONS, PUT" ||
+		h.Get("Access-Control-Allow-Headers") != "Content-Type, Authorization" ||
+		h.Get("Access-Control-Allow-Credentials") != "true" ||
+		h.Get("Access-Control-Max-Age") != "600" ||
+		h.Get("Allow") != "GET, OPTIONS, PUT" {
+		t.Errorf("preflight failed: Code=%d, Header=%v", w.Code, h)
+	}
+
+	// wildcard subdomain origin
+	r.Header.Set("Origin", "https://a.example.org")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "https://a.example.org" {
+		t.Errorf("preflight for subdomain failed: Code=%d, Header=%v", w.Code, w.Header())
+	}
+
+	// disallowed origin and method
+	for _, tr := range [][2]string{
+		{"https://evil.com", http.MethodGet},
+		{"https://app.example.com", http.MethodDelete},
+	} {
+		r.Header.Set("Origin", tr[0])
+		r.Header.Set("Access-Control-Request-Method", tr[1])
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != http.StatusForbidden || w.Header().Get("Access-Control-Allow-Origin") != "" {
+			t.Errorf("preflight for %v not rejected: Code=%d, Header=%v", tr, w.Code, w.Header())
+		}
+	}
+
+	// plain OPTIONS request
+	r, _ = http.NewRequest(http.MethodOptions, "/api/items/1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusNoContent || w.Header().Get("Allow") != "GET, OPTIONS, PUT" || globalOptions {
+		t.Errorf("OPTIONS failed: Code=%d, Header=%v", w.Code, w.Header())
+	}
+
+	// actual request
+	r, _ = http.NewRequest(http.MethodGet, "/api/items/1", nil)
+	r.Header.Set("Origin", "https://app.example.com")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if h := w.Header(); h.Get("Access-Control-Allow-Origin") != "https://app.example.com" ||
+		h.Get("Access-Control-Expose-Headers") != "X-Total" || h.Get("Vary") != "Origin" {
+		t.Errorf("CORS headers missing: Header=%v", h)
+	}
+
+	r.Header.Set("Origin", "https://evil.com")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if h := w.Header(); h.Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("CORS headers set for disallowed origin: Header=%v", h)
+	}
+
+	recv := catchPanic(func() {
+		router.SetCORS("/api/items/:id", CORSPolicy{})
+	})
+	if recv == nil {
+		t.Error("setting CORS policy twice did not panic")
+	}
+}
+
+func TestRouterCORS(t *testing.T) {
+	handlerFunc := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
+
+	globalOptions := false
+	router := New()
+	router.GlobalOPTIONS = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		globalOptions = true
+	})
+	router.CORS = &CORSPolicy{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPost},
+		MaxAge:         time.Hour,
+	}
+	router.GET("/users/:id", handlerFunc)
+	router.DELETE("/users/:id", handlerFunc)
+	router.POST("/users", handlerFunc)
+	router.GET("/public", handlerFunc)
+	router.SetCORS("/public", CORSPolicy{AllowedOrigins: []string{"*"}})
+
+	tests := []struct {
+		method, path, origin, requestMethod string
+		code                                int
+		allowOrigin, allowMethods           string
+	}{
+		{http.MethodOptions, "/users/1", "https://app.example.com", http.MethodGet, http.StatusNoContent, "https://app.example.com", "GET"},
+		{http.MethodOptions, "/users/1", "https://app.example.com", http.MethodDelete, http.StatusForbidden, "", ""},
+		{http.MethodOptions, "/users", "https://app.example.com", http.MethodPost, http.StatusNoContent, "https://app.example.com", "POST"},
+		{http.MethodOptions, "/users", "https://evil.example.com", http.MethodPost, http.StatusForbidden, "", ""},
+		{http.MethodGet, "/users/1", "https://app.example.com", "", http.StatusOK, "https://app.example.com", ""},
+		{http.MethodGet, "/public", "https://evil.example.com", "", http.StatusOK, "*", ""},
+	}
+	for _, tt := range tests {
+		r, _ := http.NewRequest(tt.method, tt.path, nil)
+		r.Header.Set("Origin", tt.origin)
+		if tt.requestMethod != "" {
+			r.Header.Set("Access-Control-Request-Method", tt.requestMethod)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		h := w.Header()
+		if w.Code != tt.code {
+			t.Errorf("%s %s from %s: wrong status: want %d, got %d", tt.method, tt.path, tt.origin, tt.code, w.Code)
+		}
+		if got := h.Get("Access-Control-Allow-Origin"); got != tt.allowOrigin {
+			t.Errorf("%s %s from %s: wrong allowed origin: want %q, got %q", tt.method, tt.path, tt.origin, tt.allowOrigin, got)
+		}
+		if got := h.Get("Access-Control-Allow-Methods"); got != tt.allowMethods {
+			t.Errorf("%s %s: wrong allowed methods: want %q, got %q", tt.method, tt.path, tt.allowMethods, got)
+		}
+		if tt.code == http.StatusNoContent && h.Get("Access-Control-Max-Age") != "3600" {
+			t.Errorf("%s %s: wrong max age %q", tt.method, tt.path, h.Get("Access-Control-Max-Age"))
+		}
+	}
+
+	// OPTIONS requests without preflight headers are passed to GlobalOPTIONS
+	r, _ := http.NewRequest(http.MethodOptions, "/users/1", nil)
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	if !globalOptions {
+		t.Error("GlobalOPTIONS not called for plain OPTIONS request")
+	}
+}
+
+func TestCORSPolicyWildcards(t *testing.T) {
+	p := &CORSPolicy{AllowedOrigins: []string{"*"}, AllowedHeaders: []string{"*"}}
+	if got := p.allowOrigin("https://x.com"); got != "*" {
+		t.Errorf("wrong allowed origin: want *, got %q", got)
+	}
+	p.AllowCredentials = true
+	if got := p.allowOrigin("https://x.com"); got != "https://x.com" {
+		t.Errorf("wrong allowed origin with credentials: got %q", got)
+	}
+
+	r, _ := http.NewRequest(http.MethodOptions, "/", nil)
+	r.Header.Set("Origin", "https://x.com")
+	r.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	r.Header.Set("Access-Control-Request-Headers", "X-Custom")
+	w := httptest.NewRecorder()
+	p.preflight(w, r, "GET, OPTIONS")
+	if got := w.Header().Get("Access-Control-Allow-Headers"); got != "X-Custom" {
+		t.Errorf("requested headers not reflected: got %q", got)
+	}
+}
@@ -0,0 +1,118 @@
+package dhttprouter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultDeadlineHeader is the request header in which DeadlineTransport
+// passes the remaining time to upstreams.
+const DefaultDeadlineHeader = "X-Request-Timeout"
+
+type deadlineKey struct{}
+
+// withDeadline returns a context carrying the given deadline, unless the
+// context already carries an earlier one.
+func withDeadline(ctx context.Context, deadline time.Time) context.Context {
+	if d, ok := DeadlineFromContext(ctx); ok && !deadline.Before(d) {
+		return ctx
+	}
+	return context.WithValue(ctx, deadlineKey{}, deadline)
+}
+
+// DeadlineFromContext returns the time at which the router gives up on the
+// request, i.e. the earliest deadline of the Timeouts wrapping the route and
+// the deadline of the context itself. ok is false if there is no deadline.
+//
+// The deadlines of a Timeout are not set as context deadline, as responses
+// which turn out to be streams are exempt from them, but the context is still
+// canceled once the Timeout gave up on the request.
+func DeadlineFromContext(ctx context.Context) (deadline time.Time, ok bool) {
+	deadline, ok = ctx.Value(deadlineKey{}).(time.Time)
+	if d, has := ctx.Deadline(); has && (!ok || d.Before(deadline)) {
+		deadline, ok = d, true
+	}
+	return deadline, ok
+}
+
+// DeadlineTransport is a http.RoundTripper which propagates the deadline of
+// the request, see DeadlineFromContext, to upstreams, so they can shed work
+// the router has already given up on. It is typically used as Transport of
+// the reverse proxies serving proxy routes, e.g.
+//
+//	proxy := httputil.NewSingleHostReverseProxy(upstream)
+//	proxy.Transport = &dhttprouter.DeadlineTransport{}
+//	timeout := dhttprouter.Timeout{Header: 2 * time.Second}
+//	router.GET("/api/*path", func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+//		proxy.ServeHTTP(w, req)
+//	}, timeout.Wrap)
+//
+// The upstream request gets the remaining time in whole milliseconds in the
+// header named by Header and carries the deadline as context deadline, so
+// the round trip itself is aborted once it passed. Requests whose deadline
+// already passed are not sent at all and fail with
+// context.DeadlineExceeded.
+type DeadlineTransport struct {
+	// The http.RoundTripper sending the requests. If not set,
+	// http.DefaultTransport is used.
+	Base http.RoundTripper
+
+	// Name of the header carrying the remaining time. Defaults to
+	// DefaultDeadlineHeader.
+	Header string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *DeadlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	deadline, ok := DeadlineFromContext(req.Context())
+	if !ok {
+		return base.RoundTrip(req)
+	}
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, context.DeadlineExceeded
+	}
+
+	header := t.Header
+	if header == "" {
+		header = DefaultDeadlineHeader
+	}
+
+	// The deadline ends with the request context, which the caller cancels
+	// after reading the response body
+	ctx, cancel := context.WithDeadline(req.Context(), deadline)
+	req = req.Clone(ctx)
+	req.Header.Set(header, strconv.FormatInt(int64((remaining+time.Millisecond-1)/time.Millisecond), 10))
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody releases the context of an upstream request once its response
+// body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
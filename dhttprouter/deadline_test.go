@@ -0,0 +1,97 @@
+package dhttprouter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestDeadlineFromContext(t *testing.T) {
+	if _, ok := DeadlineFromContext(context.Background()); ok {
+		t.Error("deadline without Timeout")
+	}
+
+	now := time.Now()
+	ctx := withDeadline(context.Background(), now.Add(time.Minute))
+	ctx = withDeadline(ctx, now.Add(time.Hour))
+	if d, ok := DeadlineFromContext(ctx); !ok || !d.Equal(now.Add(time.Minute)) {
+		t.Errorf("wrong deadline: %v, %v", d, ok)
+	}
+
+	ctx, cancel := context.WithDeadline(ctx, now.Add(time.Second))
+	defer cancel()
+	if d, ok := DeadlineFromContext(ctx); !ok || !d.Equal(now.Add(time.Second)) {
+		t.Errorf("context deadline was ignored: %v, %v", d, ok)
+	}
+}
+
+func TestDeadlineTransport(t *testing.T) {
+	var header string
+	var hasDeadline bool
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		header = req.Header.Get(DefaultDeadlineHeader)
+		w.Write([]byte("upstream"))
+	}))
+	defer upstream.Close()
+
+	client := &http.Client{Transport: &DeadlineTransport{Base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		_, hasDeadline = req.Context().Deadline()
+		return http.DefaultTransport.RoundTrip(req)
+	})}}
+	proxy := func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		ureq, _ := http.NewRequestWithContext(req.Context(), http.MethodGet, upstream.URL, nil)
+		resp, err := client.Do(ureq)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		w.WriteHeader(resp.StatusCode)
+	}
+
+	timeout := Timeout{Header: time.Second, Response: time.Minute}
+	router := New()
+	router.GET("/timeout", proxy, timeout.Wrap)
+	router.GET("/plain", proxy)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/timeout", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("wrong status code %d", w.Code)
+	}
+	if ms, err := strconv.Atoi(header); err != nil || ms <= 0 || ms > 1000 {
+		t.Errorf("wrong %s header %q", DefaultDeadlineHeader, header)
+	}
+	if !hasDeadline {
+		t.Error("upstream request has no context deadline")
+	}
+
+	header, hasDeadline = "", false
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/plain", nil))
+	if header != "" || hasDeadline {
+		t.Errorf("deadline without Timeout: %q, %v", header, hasDeadline)
+	}
+
+	// Expired deadlines are not sent upstream
+	header = ""
+	ctx := withDeadline(context.Background(), time.Now().Add(-time.Second))
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, upstream.URL, nil)
+	if _, err := client.Do(req); err == nil {
+		t.Error("no error for expired deadline")
+	}
+	if header != "" {
+		t.Error("request with expired deadline reached upstream")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
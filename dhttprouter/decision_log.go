@@ -0,0 +1,80 @@
+package dhttprouter
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RoutingRecord is an entry of a DecisionLog.
+type RoutingRecord struct {
+	Time   time.Time `json:"time"`
+	Method string    `json:"method"`
+	Path   string    `json:"path"`
+
+	// Pattern of the matched route, empty for requests which were not routed
+	// to a handle
+	Route string `json:"route"`
+
+	Status   int           `json:"status"`
+	Duration time.Duration `json:"duration"`
+}
+
+// DecisionLog keeps the routing records of the last requests in memory, for
+// triage in production without external logging infrastructure. It is an
+// http.Handler serving the records as JSON, newest first, which can be
+// registered on an admin router, e.g.
+//
+//	decisions := dhttprouter.NewDecisionLog(1000)
+//	router.Decisions = decisions
+//	admin.Handler(http.MethodGet, "/decisions", decisions)
+type DecisionLog struct {
+	mu      sync.Mutex
+	records []RoutingRecord
+	next    int
+	full    bool
+}
+
+// NewDecisionLog returns a log keeping the last n records. It panics if n is
+// not positive.
+func NewDecisionLog(n int) *DecisionLog {
+	if n <= 0 {
+		panic("decision log must keep at least one record")
+	}
+	return &DecisionLog{records: make([]RoutingRecord, n)}
+}
+
+// add adds the record, replacing the oldest one if the log is full.
+func (l *DecisionLog) add(rec RoutingRecord) {
+	l.mu.Lock()
+	l.records[l.next] = rec
+	l.next++
+	if l.next == len(l.records) {
+		l.next = 0
+		l.full = true
+	}
+	l.mu.Unlock()
+}
+
+// Records returns a copy of the records, newest first.
+func (l *DecisionLog) Records() []RoutingRecord {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	n := l.next
+	if l.full {
+		n = len(l.records)
+	}
+	records := make([]RoutingRecord, 0, n)
+	for i := 1; i <= n; i++ {
+		records = append(records, l.records[(l.next-i+len(l.records))%len(l.records)])
+	}
+	return records
+}
+
+// ServeHTTP serves the records as JSON array, newest first.
+func (l *DecisionLog) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(l.Records())
+}
@@ -0,0 +1,59 @@
+package dhttprouter
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterDecisions(t *testing.T) {
+	decisions := NewDecisionLog(2)
+	router := New()
+	router.Decisions = decisions
+	router.GET("/users/:id", func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	if records := decisions.Records(); len(records) != 0 {
+		t.Errorf("records of empty log: %v", records)
+	}
+
+	for _, path := range []string{"/users/1", "/missing", "/users/2"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	records := decisions.Records()
+	if len(records) != 2 {
+		t.Fatalf("wrong number of records: %v", records)
+	}
+	want := []struct {
+		path, route string
+		status      int
+	}{
+		{"/users/2", "/users/:id", http.StatusAccepted},
+		{"/missing", "", http.StatusNotFound},
+	}
+	for i, w := range want {
+		rec := records[i]
+		if rec.Method != http.MethodGet || rec.Path != w.path || rec.Route != w.route || rec.Status != w.status {
+			t.Errorf("%d: wrong record %+v", i, rec)
+		}
+		if rec.Time.IsZero() || rec.Duration < 0 {
+			t.Errorf("%d: wrong time or duration %+v", i, rec)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	decisions.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/decisions", nil))
+	var served []RoutingRecord
+	if err := json.NewDecoder(w.Body).Decode(&served); err != nil || len(served) != 2 || served[0].Path != "/users/2" {
+		t.Errorf("wrong records served: %v, %v", served, err)
+	}
+
+	if recv := catchPanic(func() { NewDecisionLog(0) }); recv == nil {
+		t.Error("no panic for empty log")
+	}
+}
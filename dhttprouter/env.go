@@ -0,0 +1,20 @@
+package dhttprouter
+
+// WhenEnv returns the router if its Env is one of the given environments.
+// Otherwise it returns a detached router which is never served, so routes
+// registered on it are absent from the router instead of being disabled at
+// request time:
+//
+//	router.Env = os.Getenv("APP_ENV")
+//	router.WhenEnv("development", "staging").GET("/debug/routes", dumpRoutes)
+//
+// The environment is evaluated when the routes are registered, so Env must be
+// set before.
+func (r *HttpRouter) WhenEnv(envs ...string) *HttpRouter {
+	for _, env := range envs {
+		if env == r.Env {
+			return r
+		}
+	}
+	return New()
+}
@@ -0,0 +1,39 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterWhenEnv(t *testing.T) {
+	handle := func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		w.Write([]byte("debug"))
+	}
+
+	staging := New()
+	staging.Env = "staging"
+	staging.WhenEnv("development", "staging").GET("/debug", handle)
+
+	production := New()
+	production.Env = "production"
+	production.WhenEnv("development", "staging").GET("/debug", handle)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/debug", nil)
+	staging.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.String() != "debug" {
+		t.Errorf("debug route missing in staging: %d %q", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	production.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("debug route served in production: %d", w.Code)
+	}
+	if routes := production.Routes(); len(routes) != 0 {
+		t.Errorf("production router has routes: %v", routes)
+	}
+}
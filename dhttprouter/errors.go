@@ -0,0 +1,120 @@
+package dhttprouter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/thekhanj/drouter"
+)
+
+// HttpHandleE is a handle which reports failures by returning an error,
+// which the router passes to its ErrorHandler. See HandleE.
+type HttpHandleE func(http.ResponseWriter, *http.Request, drouter.Params) error
+
+// HTTPError is an error with a status code and a message meant for the
+// client, e.g.
+//
+//	return &dhttprouter.HTTPError{Code: http.StatusNotFound, Message: "no such user"}
+type HTTPError struct {
+	Code int
+
+	// Message sent to the client. Defaults to the status text of Code.
+	Message string
+}
+
+func (e *HTTPError) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return http.StatusText(e.Code)
+}
+
+// ErrorTranslator maps an error onto an HTTP status code. It returns false if
+// it does not know the error.
+type ErrorTranslator func(err error) (code int, ok bool)
+
+// ErrorIs returns an ErrorTranslator which maps errors matching target
+// according to errors.Is onto the given status code, e.g.
+//
+//	ErrorIs(sql.ErrNoRows, http.StatusNotFound)
+func ErrorIs(target error, code int) ErrorTranslator {
+	return func(err error) (int, bool) {
+		return code, errors.Is(err, target)
+	}
+}
+
+type errorTranslatorsKey struct{}
+
+// withErrorTranslators returns a request whose context holds the given
+// translators in front of those already present.
+func withErrorTranslators(req *http.Request, translators []ErrorTranslator) *http.Request {
+	outer, _ := req.Context().Value(errorTranslatorsKey{}).([]ErrorTranslator)
+	if len(outer) > 0 {
+		translators = append(append([]ErrorTranslator(nil), translators...), outer...)
+	}
+	return req.WithContext(context.WithValue(req.Context(), errorTranslatorsKey{}, translators))
+}
+
+// ErrorStatus returns the status code for an error reported while serving a
+// request with the given context. The code of an *HTTPError in the chain of
+// err takes precedence. Otherwise the ErrorTranslators of the router which
+// matched the request are consulted first, followed by those of the routers
+// it is mounted into. If no translator knows the error, 500 (Internal Server
+// Error) is returned.
+func ErrorStatus(ctx context.Context, err error) int {
+	var herr *HTTPError
+	if errors.As(err, &herr) && herr.Code != 0 {
+		return herr.Code
+	}
+
+	translators, _ := ctx.Value(errorTranslatorsKey{}).([]ErrorTranslator)
+	for _, translate := range translators {
+		if code, ok := translate(err); ok {
+			return code
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// Error replies to the request with the status code ErrorStatus returns for
+// err and the matching status text. The error message itself is not sent, as
+// it may expose internal details, unless it is the message of an *HTTPError.
+// It is the default ErrorHandler of the router.
+func Error(w http.ResponseWriter, req *http.Request, err error) {
+	code := ErrorStatus(req.Context(), err)
+	msg := http.StatusText(code)
+
+	var herr *HTTPError
+	if errors.As(err, &herr) && herr.Code == code && herr.Message != "" {
+		msg = herr.Message
+	}
+	http.Error(w, msg, code)
+}
+
+// HandleE registers a handle returning an error, like Handle. Errors are
+// passed to the router's ErrorHandler.
+func (r *HttpRouter) HandleE(method, path string, handle HttpHandleE, middleware ...Middleware) {
+	if handle == nil {
+		panic("handle must not be nil")
+	}
+	if err := r.tryHandle(method, path, r.E(handle), handle, middleware); err != nil {
+		panic(err.Error())
+	}
+}
+
+// E adapts a handle returning an error to a HttpHandle, which passes the
+// errors to the router's ErrorHandler, e.g.
+//
+//	router.GET("/users/:id", router.E(getUser))
+func (r *HttpRouter) E(handle HttpHandleE) HttpHandle {
+	return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		if err := handle(w, req, ps); err != nil {
+			if r.ErrorHandler != nil {
+				r.ErrorHandler(w, req, err)
+			} else {
+				Error(w, req, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package dhttprouter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+type validationError struct{ field string }
+
+func (e validationError) Error() string { return "invalid " + e.field }
+
+func TestErrorTranslators(t *testing.T) {
+	errNotFound := errors.New("no rows")
+	errUnknown := errors.New("boom")
+
+	fail := func(err error) HttpHandle {
+		return func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+			Error(w, req, err)
+		}
+	}
+
+	api := New()
+	api.ErrorTranslators = []ErrorTranslator{
+		ErrorIs(errNotFound, http.StatusGone),
+		func(err error) (int, bool) {
+			var v validationError
+			return http.StatusUnprocessableEntity, errors.As(err, &v)
+		},
+	}
+	api.GET("/missing", fail(fmt.Errorf("loading user: %w", errNotFound)))
+	api.GET("/invalid", fail(validationError{"name"}))
+	api.GET("/unknown", fail(errUnknown))
+
+	router := New()
+	router.ErrorTranslators = []ErrorTranslator{
+		ErrorIs(errNotFound, http.StatusNotFound),
+	}
+	router.GET("/missing", fail(errNotFound))
+	router.GET("/invalid", fail(validationError{"name"}))
+	router.Mount("/api", api)
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		{"/missing", http.StatusNotFound},
+		{"/invalid", http.StatusInternalServerError},
+		{"/api/missing", http.StatusGone},
+		{"/api/invalid", http.StatusUnprocessableEntity},
+		{"/api/unknown", http.StatusInternalServerError},
+	}
+	for _, tr := range tests {
+		r, _ := http.NewRequest(http.MethodGet, tr.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != tr.code {
+			t.Errorf("wrong status for %s: want %d, got %d", tr.path, tr.code, w.Code)
+		}
+		if want := http.StatusText(tr.code) + "\n"; w.Body.String() != want {
+			t.Errorf("wrong body for %s: want %q, got %q", tr.path, want, w.Body.String())
+		}
+	}
+
+	// Without translators
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	if code := ErrorStatus(r.Context(), errNotFound); code != http.StatusInternalServerError {
+		t.Errorf("wrong default status: %d", code)
+	}
+}
+
+func TestRouterHandleE(t *testing.T) {
+	errNotFound := errors.New("no rows")
+
+	router := New()
+	router.ErrorTranslators = []ErrorTranslator{ErrorIs(errNotFound, http.StatusNotFound)}
+	router.HandleE(http.MethodGet, "/users/:id", func(w http.ResponseWriter, _ *http.Request, ps drouter.Params) error {
+		switch id := ps.ByName("id"); id {
+		case "1":
+			w.Write([]byte("user 1"))
+			return nil
+		case "2":
+			return fmt.Errorf("loading user: %w", errNotFound)
+		case "3":
+			return &HTTPError{Code: http.StatusForbidden, Message: "not your user"}
+		case "4":
+			return fmt.Errorf("wrapped: %w", &HTTPError{Code: http.StatusTeapot})
+		default:
+			return errors.New("database password is hunter2")
+		}
+	})
+	router.PUT("/users/:id", router.E(func(http.ResponseWriter, *http.Request, drouter.Params) error {
+		return &HTTPError{Code: http.StatusConflict, Message: "version mismatch"}
+	}))
+
+	tests := []struct {
+		method, path string
+		code         int
+		body         string
+	}{
+		{http.MethodGet, "/users/1", http.StatusOK, "user 1"},
+		{http.MethodGet, "/users/2", http.StatusNotFound, "Not Found\n"},
+		{http.MethodGet, "/users/3", http.StatusForbidden, "not your user\n"},
+		{http.MethodGet, "/users/4", http.StatusTeapot, "I'm a teapot\n"},
+		{http.MethodGet, "/users/5", http.StatusInternalServerError, "Internal Server Error\n"},
+		{http.MethodPut, "/users/1", http.StatusConflict, "version mismatch\n"},
+	}
+	for _, tr := range tests {
+		r, _ := http.NewRequest(tr.method, tr.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != tr.code || w.Body.String() != tr.body {
+			t.Errorf("%s %s: got %d %q, want %d %q", tr.method, tr.path, w.Code, w.Body.String(), tr.code, tr.body)
+		}
+	}
+
+	// Custom error handler, set after registration
+	var handled error
+	router.ErrorHandler = func(w http.ResponseWriter, req *http.Request, err error) {
+		handled = err
+		w.WriteHeader(ErrorStatus(req.Context(), err))
+	}
+	r, _ := http.NewRequest(http.MethodGet, "/users/2", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if !errors.Is(handled, errNotFound) || w.Code != http.StatusNotFound {
+		t.Errorf("custom error handler not used: %v, %d", handled, w.Code)
+	}
+
+	if msg := (&HTTPError{Code: http.StatusBadRequest}).Error(); msg != "Bad Request" {
+		t.Errorf("unexpected default message: %q", msg)
+	}
+
+	recv := catchPanic(func() {
+		router.HandleE(http.MethodPost, "/users", nil)
+	})
+	if recv == nil {
+		t.Error("registering nil handle did not panic")
+	}
+}
@@ -0,0 +1,124 @@
+package dhttprouter
+
+import (
+	"strconv"
+	"sync"
+)
+
+// EventKind is the kind of an Event.
+type EventKind int
+
+const (
+	// RouteAdded is emitted after a route was registered.
+	RouteAdded EventKind = iota
+
+	// RouteRemoved is emitted after a route was removed, see Remove.
+	RouteRemoved
+
+	// TableSwapped is emitted after the route table was replaced, see Swap.
+	TableSwapped
+
+	// LookupMiss is emitted after a request was answered with 404 or 405.
+	LookupMiss
+)
+
+var eventKindNames = [...]string{
+	RouteAdded:   "RouteAdded",
+	RouteRemoved: "RouteRemoved",
+	TableSwapped: "TableSwapped",
+	LookupMiss:   "LookupMiss",
+}
+
+func (k EventKind) String() string {
+	if k >= 0 && int(k) < len(eventKindNames) {
+		return eventKindNames[k]
+	}
+	return "EventKind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Event is a notification about a change of a router or a request it could
+// not route.
+type Event struct {
+	Kind EventKind
+
+	// Method and path of the added or removed route, or of the missed
+	// request. Empty for TableSwapped.
+	Method string
+	Path   string
+
+	// Version of the route table after the change, see HttpRouter.Version
+	Version uint64
+
+	// Why the request could not be routed. Only set for LookupMiss.
+	Decision *Decision
+}
+
+// EventBus dispatches the events of a router to its subscribers, e.g. to
+// update metrics, regenerate documentation or invalidate caches when the
+// routes change. Subscribers are called synchronously in the goroutine
+// causing the event, i.e. the one registering routes or, for LookupMiss,
+// serving the request, so they should return quickly.
+//
+// The zero value is ready to use:
+//
+//	router.Events = new(EventBus)
+//	router.Events.Subscribe(func(e Event) {
+//		if e.Kind == RouteAdded || e.Kind == RouteRemoved {
+//			docs.Regenerate(router.Routes())
+//		}
+//	})
+type EventBus struct {
+	mu   sync.RWMutex
+	next uint64
+	subs map[uint64]func(Event)
+}
+
+// Subscribe registers fn to be called for every event and returns a function
+// removing the subscription again.
+func (b *EventBus) Subscribe(fn func(Event)) (unsubscribe func()) {
+	if fn == nil {
+		panic("subscriber must not be nil")
+	}
+
+	b.mu.Lock()
+	if b.subs == nil {
+		b.subs = make(map[uint64]func(Event))
+	}
+	id := b.next
+	b.next++
+	b.subs[id] = fn
+	b.mu.Unlock()
+
+	return func() {
+		b.mu.Lock()
+		delete(b.subs, id)
+		b.mu.Unlock()
+	}
+}
+
+// Publish calls the subscribers with the event.
+func (b *EventBus) Publish(e Event) {
+	b.mu.RLock()
+	subs := make([]func(Event), 0, len(b.subs))
+	for _, fn := range b.subs {
+		subs = append(subs, fn)
+	}
+	b.mu.RUnlock()
+
+	for _, fn := range subs {
+		fn(e)
+	}
+}
+
+// emit publishes the event to the router's bus, if it has one.
+func (r *HttpRouter) emit(kind EventKind, method, path string, decision *Decision) {
+	if r.Events != nil {
+		r.Events.Publish(Event{
+			Kind:     kind,
+			Method:   method,
+			Path:     path,
+			Version:  r.Version(),
+			Decision: decision,
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterEvents(t *testing.T) {
+	handle := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
+
+	var events []string
+	var misses []int
+	router := New()
+	router.Events = new(EventBus)
+	unsubscribe := router.Events.Subscribe(func(e Event) {
+		events = append(events, e.Kind.String()+" "+e.Method+" "+e.Path)
+		if e.Version != router.Version() {
+			t.Errorf("%v: wrong version %d", e.Kind, e.Version)
+		}
+		if e.Kind == LookupMiss {
+			misses = append(misses, e.Decision.Status)
+		}
+	})
+
+	router.GET("/users/:id", handle)
+	router.PUT("/users/:id", handle)
+	router.Remove(http.MethodPut, "/users/:id")
+	router.Remove(http.MethodPut, "/users/:id")
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, "/users/1", nil)
+		router.ServeHTTP(w, req)
+	}
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/nope", nil)
+	router.ServeHTTP(w, req)
+
+	next := New()
+	next.GET("/", handle)
+	router.Swap(next)
+
+	unsubscribe()
+	router.GET("/unobserved", handle)
+
+	want := []string{
+		"RouteAdded GET /users/:id",
+		"RouteAdded PUT /users/:id",
+		"RouteRemoved PUT /users/:id",
+		"LookupMiss POST /users/1",
+		"LookupMiss GET /nope",
+		"TableSwapped  ",
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("wrong events:\nwant %q\n got %q", want, events)
+	}
+	if !reflect.DeepEqual(misses, []int{http.StatusMethodNotAllowed, http.StatusNotFound}) {
+		t.Errorf("wrong miss decisions: %v", misses)
+	}
+	if s := EventKind(42).String(); s != "EventKind(42)" {
+		t.Errorf("wrong name of unknown kind: %q", s)
+	}
+}
@@ -0,0 +1,164 @@
+package dhttprouter
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+// Variant is an arm of an Experiment.
+type Variant struct {
+	// Name of the variant, e.g. "control" or "new-checkout"
+	Name string
+
+	// Relative share of the clients assigned to the variant. A variant with
+	// a weight of zero receives no new clients, but keeps serving the clients
+	// already assigned to it.
+	Weight float64
+
+	Handle HttpHandle
+}
+
+// Experiment assigns clients of a route to one of several variants by weight
+// and keeps the assignment sticky with a cookie, so a client sees the same
+// variant on every visit. Its Handle method is used as the handle of the
+// route, e.g.
+//
+//	checkout := &dhttprouter.Experiment{
+//		Name: "checkout",
+//		Variants: []dhttprouter.Variant{
+//			{Name: "control", Weight: 9, Handle: oldCheckout},
+//			{Name: "one-page", Weight: 1, Handle: newCheckout},
+//		},
+//		Header: "X-Experiment-Checkout",
+//	}
+//	router.GET("/checkout", checkout.Handle)
+//
+// The handle of the variant finds the variant's name in the request context,
+// see VariantFromContext, so application code and analytics agree on the arm
+// which served the request.
+type Experiment struct {
+	// Name of the experiment, which must be a valid cookie name.
+	Name string
+
+	Variants []Variant
+
+	// Name of the cookie holding the assigned variant.
+	// Defaults to "experiment-" followed by the name of the experiment.
+	Cookie string
+
+	// Lifetime of the cookie. Defaults to 30 days.
+	CookieMaxAge time.Duration
+
+	// If set, the name of the assigned variant is sent in a response header
+	// with this name.
+	Header string
+
+	// Source of random numbers in [0.0,1.0) used to assign the variants.
+	// If not set, math/rand is used.
+	Random func() float64
+}
+
+type variantKey struct {
+	experiment string
+}
+
+// VariantFromContext returns the name of the variant the request was
+// assigned to in the named experiment, or an empty string if the request did
+// not pass through the experiment.
+func VariantFromContext(ctx context.Context, experiment string) string {
+	v, _ := ctx.Value(variantKey{experiment}).(string)
+	return v
+}
+
+func (e *Experiment) cookieName() string {
+	if e.Cookie != "" {
+		return e.Cookie
+	}
+	return "experiment-" + e.Name
+}
+
+// variant returns the variant with the given name, or nil if there is none.
+func (e *Experiment) variant(name string) *Variant {
+	for i := range e.Variants {
+		if e.Variants[i].Name == name {
+			return &e.Variants[i]
+		}
+	}
+	return nil
+}
+
+// assign picks a variant for a new client by weight.
+func (e *Experiment) assign() *Variant {
+	var total float64
+	for _, v := range e.Variants {
+		if v.Weight > 0 {
+			total += v.Weight
+		}
+	}
+	if total == 0 {
+		return &e.Variants[0]
+	}
+
+	var n float64
+	if e.Random != nil {
+		n = e.Random() * total
+	} else {
+		n = rand.Float64() * total
+	}
+	for i, v := range e.Variants {
+		if v.Weight <= 0 {
+			continue
+		}
+		if n < v.Weight {
+			return &e.Variants[i]
+		}
+		n -= v.Weight
+	}
+
+	// Rounding errors
+	for i := len(e.Variants) - 1; ; i-- {
+		if e.Variants[i].Weight > 0 {
+			return &e.Variants[i]
+		}
+	}
+}
+
+// Handle passes the request to the handle of the variant assigned to the
+// client. Clients without a valid assignment are assigned a variant, which is
+// stored in the cookie. It panics if the experiment has no variants.
+func (e *Experiment) Handle(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+	if len(e.Variants) == 0 {
+		panic("experiment '" + e.Name + "' has no variants")
+	}
+
+	var v *Variant
+	if c, err := req.Cookie(e.cookieName()); err == nil {
+		v = e.variant(c.Value)
+	}
+	if v == nil {
+		v = e.assign()
+
+		maxAge := e.CookieMaxAge
+		if maxAge <= 0 {
+			maxAge = 30 * 24 * time.Hour
+		}
+		http.SetCookie(w, &http.Cookie{
+			Name:     e.cookieName(),
+			Value:    v.Name,
+			Path:     "/",
+			MaxAge:   int(maxAge / time.Second),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+
+	if e.Header != "" {
+		w.Header().Set(e.Header, v.Name)
+	}
+	req = req.WithContext(context.WithValue(req.Context(), variantKey{e.Name}, v.Name))
+	v.Handle(w, req, ps)
+}
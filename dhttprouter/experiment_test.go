@@ -0,0 +1,70 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestExperiment(t *testing.T) {
+	variant := func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		w.Write([]byte(VariantFromContext(req.Context(), "checkout")))
+	}
+
+	random := 0.0
+	e := &Experiment{
+		Name: "checkout",
+		Variants: []Variant{
+			{Name: "control", Weight: 3, Handle: variant},
+			{Name: "retired", Weight: 0, Handle: variant},
+			{Name: "one-page", Weight: 1, Handle: variant},
+		},
+		Header: "X-Experiment-Checkout",
+		Random: func() float64 { return random },
+	}
+	router := New()
+	router.GET("/checkout", e.Handle)
+
+	tests := []struct {
+		random       float64
+		cookie, want string
+		setCookie    bool
+	}{
+		{0.5, "", "control", true},
+		{0.8, "", "one-page", true},
+		{0.99, "", "one-page", true},
+		{0.8, "control", "control", false},
+		{0.1, "retired", "retired", false},
+		{0.1, "unknown", "control", true},
+	}
+	for _, tt := range tests {
+		random = tt.random
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/checkout", nil)
+		if tt.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "experiment-checkout", Value: tt.cookie})
+		}
+		router.ServeHTTP(w, req)
+
+		if body := w.Body.String(); body != tt.want {
+			t.Errorf("random %v, cookie %q: wrong variant: want %q, got %q", tt.random, tt.cookie, tt.want, body)
+		}
+		if h := w.Header().Get("X-Experiment-Checkout"); h != tt.want {
+			t.Errorf("random %v, cookie %q: wrong header %q", tt.random, tt.cookie, h)
+		}
+		cookies := w.Result().Cookies()
+		if tt.setCookie != (len(cookies) == 1) {
+			t.Errorf("random %v, cookie %q: wrong cookies %v", tt.random, tt.cookie, cookies)
+			continue
+		}
+		if tt.setCookie && (cookies[0].Name != "experiment-checkout" || cookies[0].Value != tt.want || cookies[0].MaxAge != 30*24*3600) {
+			t.Errorf("random %v: wrong cookie %v", tt.random, cookies[0])
+		}
+	}
+
+	if v := VariantFromContext(httptest.NewRequest(http.MethodGet, "/", nil).Context(), "checkout"); v != "" {
+		t.Errorf("variant outside of experiment: %q", v)
+	}
+}
@@ -0,0 +1,116 @@
+package dhttprouter
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+// failoverSmoothing is the weight of a single response in the failure rate.
+const failoverSmoothing = 0.2
+
+// Failover splits the traffic of a route between a primary and a secondary
+// handle, typically reverse proxies to two upstreams, based on their passively
+// observed health. Its Handle method is used as the handle of the route, e.g.
+//
+//	f := &dhttprouter.Failover{Primary: proxyA, Secondary: proxyB}
+//	router.Handle(http.MethodGet, "/api/*path", f.Handle)
+//
+// The health of a handle is one minus its failure rate, an exponentially
+// weighted average over its recent responses. Responses with a 5xx status,
+// responses slower than Latency and panics count as failures.
+// As long as the primary is at least as healthy as the secondary, it receives
+// all requests. Otherwise the requests are split according to the ratio of
+// both healths, so an unavailable primary receives none.
+// Failure rates decay over time, so a recovered handle is probed again.
+type Failover struct {
+	Primary   HttpHandle
+	Secondary HttpHandle
+
+	// Responses taking longer are counted as failures. Disabled if zero.
+	Latency time.Duration
+
+	// Time after which half of the failure rate is forgotten.
+	// Defaults to 10 seconds.
+	HalfLife time.Duration
+
+	// Source of random numbers in [0.0,1.0) used to split the traffic.
+	// If not set, math/rand is used.
+	Random func() float64
+
+	mu        sync.Mutex
+	primary   failoverState
+	secondary failoverState
+}
+
+type failoverState struct {
+	rate float64
+	last time.Time
+}
+
+// decay returns the failure rate at the given time.
+func (s *failoverState) decay(now time.Time, halfLife time.Duration) float64 {
+	if s.rate == 0 {
+		return 0
+	}
+	return s.rate * math.Exp2(-float64(now.Sub(s.last))/float64(halfLife))
+}
+
+func (s *failoverState) observe(now time.Time, halfLife time.Duration, failed bool) {
+	s.rate = s.decay(now, halfLife)
+	if failed {
+		s.rate += (1 - s.rate) * failoverSmoothing
+	} else {
+		s.rate -= s.rate * failoverSmoothing
+	}
+	s.last = now
+}
+
+func (f *Failover) halfLife() time.Duration {
+	if f.HalfLife > 0 {
+		return f.HalfLife
+	}
+	return 10 * time.Second
+}
+
+// Health returns the current health of the primary and the secondary handle,
+// between 0 (always failing) and 1 (healthy).
+func (f *Failover) Health() (primary, secondary float64) {
+	now := time.Now()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return 1 - f.primary.decay(now, f.halfLife()), 1 - f.secondary.decay(now, f.halfLife())
+}
+
+// Handle serves the request with the primary or the secondary handle.
+func (f *Failover) Handle(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+	handle, state := f.Primary, &f.primary
+	if primary, secondary := f.Health(); secondary > primary {
+		random := f.Random
+		if random == nil {
+			random = rand.Float64
+		}
+		if random() >= primary/secondary {
+			handle, state = f.Secondary, &f.secondary
+		}
+	}
+
+	sw := &statusWriter{ResponseWriter: w}
+	start := time.Now()
+	done := false
+	defer func() {
+		now := time.Now()
+		failed := !done || sw.Status() >= 500 || (f.Latency > 0 && now.Sub(start) > f.Latency)
+
+		f.mu.Lock()
+		state.observe(now, f.halfLife(), failed)
+		f.mu.Unlock()
+	}()
+
+	handle(sw, req, ps)
+	done = true
+}
@@ -0,0 +1,110 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestFailover(t *testing.T) {
+	var hit string
+	failing := true
+	random := 0.99
+
+	f := &Failover{
+		Primary: func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+			hit = "primary"
+			if failing {
+				w.WriteHeader(http.StatusBadGateway)
+			}
+		},
+		Secondary: func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+			hit = "secondary"
+		},
+		HalfLife: time.Minute,
+		Random:   func() float64 { return random },
+	}
+
+	router := New()
+	router.GET("/api/*path", f.Handle)
+
+	serve := func() {
+		hit = ""
+		r, _ := http.NewRequest(http.MethodGet, "/api/users", nil)
+		router.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	// Both healthy, the primary gets the request and fails
+	serve()
+	if hit != "primary" {
+		t.Errorf("healthy primary not used, got %q", hit)
+	}
+	primary, secondary := f.Health()
+	if primary >= 1 || secondary != 1 {
+		t.Errorf("wrong health after failure: primary=%f, secondary=%f", primary, secondary)
+	}
+
+	// Traffic is split by the ratio of the healths
+	serve()
+	if hit != "secondary" {
+		t.Errorf("traffic not shifted to secondary, got %q", hit)
+	}
+	random = 0
+	serve()
+	if hit != "primary" {
+		t.Errorf("primary not probed, got %q", hit)
+	}
+
+	// Repeated failures make the primary unavailable
+	random = 0.5
+	for i := 0; i < 10; i++ {
+		serve()
+	}
+	if hit != "secondary" {
+		t.Errorf("traffic not failed over, got %q", hit)
+	}
+
+	// The failure rate decays, the recovered primary gets the traffic back
+	failing = false
+	f.HalfLife = 5 * time.Millisecond
+	time.Sleep(200 * time.Millisecond)
+	serve()
+	if hit != "primary" {
+		t.Errorf("recovered primary not used, got %q", hit)
+	}
+	if primary, _ := f.Health(); primary < 0.99 {
+		t.Errorf("primary did not recover: health=%f", primary)
+	}
+}
+
+func TestFailoverLatencyAndPanic(t *testing.T) {
+	f := &Failover{
+		Primary: func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+			if req.URL.Path == "/panic" {
+				panic("upstream broken")
+			}
+			time.Sleep(5 * time.Millisecond)
+		},
+		Secondary: func(http.ResponseWriter, *http.Request, drouter.Params) {},
+		Latency:   time.Millisecond,
+	}
+
+	r, _ := http.NewRequest(http.MethodGet, "/slow", nil)
+	f.Handle(httptest.NewRecorder(), r, nil)
+	if primary, _ := f.Health(); primary >= 1 {
+		t.Error("slow response not counted as failure")
+	}
+
+	before, _ := f.Health()
+	r, _ = http.NewRequest(http.MethodGet, "/panic", nil)
+	f.Random = func() float64 { return 0 }
+	if recv := catchPanic(func() { f.Handle(httptest.NewRecorder(), r, nil) }); recv == nil {
+		t.Error("panic was swallowed")
+	}
+	if after, _ := f.Health(); after >= before {
+		t.Error("panic not counted as failure")
+	}
+}
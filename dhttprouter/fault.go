@@ -0,0 +1,93 @@
+package dhttprouter
+
+import (
+	"math/rand"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+// Fault describes faults injected into the requests of a route, to test the
+// retry and timeout behavior of clients against specific endpoints.
+type Fault struct {
+	// Fraction of eligible requests to inject the fault into, between 0 and 1.
+	Probability float64
+
+	// Latency added before the request is handled or failed.
+	Delay time.Duration
+
+	// If not zero, the request is answered with this status code instead of
+	// being passed to the handle.
+	Status int
+
+	// If enabled, the connection is closed without a response instead of
+	// passing the request to the handle. Takes priority over Status.
+	Reset bool
+
+	// If set, only requests carrying this header are eligible for faults.
+	Header string
+
+	// If set, faults are only injected if this environment variable is
+	// non-empty when the handle is wrapped. Otherwise Wrap returns the handle
+	// unchanged, so production builds carry no overhead.
+	Env string
+
+	// Source of randomness, mainly for testing. If it is not set,
+	// math/rand.Float64 is used.
+	Random func() float64
+}
+
+// Wrap returns a handle which injects the fault into requests before passing
+// them on to the given handle. It can be used as per-route Middleware, e.g.
+// router.GET(path, handle, fault.Wrap).
+func (f Fault) Wrap(handle HttpHandle) HttpHandle {
+	if f.Env != "" && os.Getenv(f.Env) == "" {
+		return handle
+	}
+
+	random := f.Random
+	if random == nil {
+		random = rand.Float64
+	}
+
+	return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		if (f.Header != "" && req.Header.Get(f.Header) == "") || random() >= f.Probability {
+			handle(w, req, ps)
+			return
+		}
+
+		if f.Delay > 0 {
+			timer := time.NewTimer(f.Delay)
+			select {
+			case <-timer.C:
+			case <-req.Context().Done():
+				timer.Stop()
+				return
+			}
+		}
+
+		switch {
+		case f.Reset:
+			resetConnection(w)
+		case f.Status != 0:
+			http.Error(w, http.StatusText(f.Status), f.Status)
+		default:
+			handle(w, req, ps)
+		}
+	}
+}
+
+// resetConnection closes the underlying connection without writing a
+// response, falling back to aborting the handler if the connection cannot be
+// hijacked.
+func resetConnection(w http.ResponseWriter) {
+	if hj, ok := w.(http.Hijacker); ok {
+		if conn, _, err := hj.Hijack(); err == nil {
+			conn.Close()
+			return
+		}
+	}
+	panic(http.ErrAbortHandler)
+}
@@ -0,0 +1,101 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestFault(t *testing.T) {
+	handled := false
+	handle := func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		handled = true
+	}
+	always := func() float64 { return 0 }
+
+	router := New()
+	router.GET("/error", Fault{Probability: 0.5, Status: http.StatusServiceUnavailable, Random: always}.Wrap(handle))
+	router.GET("/never", Fault{Probability: 0, Status: http.StatusServiceUnavailable}.Wrap(handle))
+	router.GET("/guarded", Fault{Probability: 1, Status: http.StatusTeapot, Header: "X-Chaos"}.Wrap(handle))
+	router.GET("/slow", Fault{Probability: 1, Delay: 10 * time.Millisecond}.Wrap(handle))
+
+	tests := []struct {
+		path    string
+		header  bool
+		code    int
+		handled bool
+	}{
+		{"/error", false, http.StatusServiceUnavailable, false},
+		{"/never", false, http.StatusOK, true},
+		{"/guarded", false, http.StatusOK, true},
+		{"/guarded", true, http.StatusTeapot, false},
+		{"/slow", false, http.StatusOK, true},
+	}
+	for _, tr := range tests {
+		handled = false
+		r, _ := http.NewRequest(http.MethodGet, tr.path, nil)
+		if tr.header {
+			r.Header.Set("X-Chaos", "1")
+		}
+		w := httptest.NewRecorder()
+		start := time.Now()
+		router.ServeHTTP(w, r)
+		if w.Code != tr.code || handled != tr.handled {
+			t.Errorf("fault for %s failed: Code=%d, handled=%t", tr.path, w.Code, handled)
+		}
+		if tr.path == "/slow" && time.Since(start) < 10*time.Millisecond {
+			t.Errorf("no latency injected for %s", tr.path)
+		}
+	}
+}
+
+func TestFaultEnv(t *testing.T) {
+	handle := func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
+
+	const env = "DROUTER_TEST_CHAOS"
+	os.Unsetenv(env)
+	router := New()
+	router.GET("/off", Fault{Probability: 1, Status: http.StatusTeapot, Env: env}.Wrap(handle))
+	os.Setenv(env, "1")
+	defer os.Unsetenv(env)
+	router.GET("/on", Fault{Probability: 1, Status: http.StatusTeapot, Env: env}.Wrap(handle))
+
+	for path, code := range map[string]int{"/off": http.StatusOK, "/on": http.StatusTeapot} {
+		r, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != code {
+			t.Errorf("unexpected response code %d for %s, want %d", w.Code, path, code)
+		}
+	}
+}
+
+func TestFaultReset(t *testing.T) {
+	router := New()
+	router.GET("/reset", Fault{Probability: 1, Reset: true}.Wrap(
+		func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+			t.Error("handle called despite reset")
+		},
+	))
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	if resp, err := http.Get(server.URL + "/reset"); err == nil {
+		resp.Body.Close()
+		t.Errorf("expected connection error, got status %d", resp.StatusCode)
+	}
+
+	// recorders cannot be hijacked, the handler is aborted instead
+	r, _ := http.NewRequest(http.MethodGet, "/reset", nil)
+	recv := catchPanic(func() {
+		router.ServeHTTP(httptest.NewRecorder(), r)
+	})
+	if recv != http.ErrAbortHandler {
+		t.Errorf("expected http.ErrAbortHandler panic, got %v", recv)
+	}
+}
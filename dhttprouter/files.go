@@ -0,0 +1,302 @@
+package dhttprouter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Files configures the serving of static files, see ServeFilesWith.
+type Files struct {
+	// File system the files are served from, e.g. http.Dir("/var/www")
+	Root http.FileSystem
+
+	// File system the files are served from if Root is not set, e.g. an
+	// embed.FS. If Dir is set, the files are served from this directory of
+	// FS, which strips the directory named by the go:embed directive:
+	//
+	//	//go:embed static
+	//	var static embed.FS
+	//
+	//	router.ServeFilesWith("/static/*filepath", dhttprouter.Files{
+	//		FS:  static,
+	//		Dir: "static",
+	//	})
+	FS  fs.FS
+	Dir string
+
+	// Optional handle called instead of http.FileServer's plain 404 for
+	// requests of files which do not exist, with the unmodified request and
+	// the Params of the route. It may fall back to the router's NotFound, an
+	// index page of a single-page application or an upstream origin.
+	// Not used for FileFallthrough, which only serves existing files.
+	OnMissing HttpHandle
+
+	// If enabled, requests of files which do not exist are answered like
+	// requests no route matches, i.e. by the NotFound handler of the router
+	// or the one set for the path with SetNotFound, and reported to
+	// OnNoMatch. OnMissing takes precedence.
+	RouterNotFound bool
+
+	// Cache-Control header of the served files, e.g. "public, max-age=3600".
+	// No header is set if empty.
+	CacheControl string
+
+	// Optional function reporting whether the file with the given name is
+	// fingerprinted, i.e. gets a new name whenever its content changes.
+	// Such files are served with the Cache-Control header
+	// "public, max-age=31536000, immutable" instead. See Fingerprinted.
+	Immutable func(name string) bool
+
+	// If enabled, files are served with a strong ETag derived from a hash of
+	// their content, so conditional requests are answered with 304 even if
+	// the modification times of the files are unreliable. Hashes are computed
+	// on first request and again whenever the size or modification time of a
+	// file changes.
+	ETag bool
+
+	// If enabled, the modification time of the files is neither sent as
+	// Last-Modified nor compared with If-Modified-Since, e.g. because
+	// deployments or embed.FS reset it.
+	NoLastModified bool
+}
+
+// cached reports whether any of the caching controls is set.
+func (f *Files) cached() bool {
+	return f.CacheControl != "" || f.Immutable != nil || f.ETag || f.NoLastModified
+}
+
+// ServeFilesWith serves files like ServeFiles, configured by files.
+//
+//	router.ServeFilesWith("/app/*filepath", dhttprouter.Files{
+//		Root: http.Dir("dist"),
+//		OnMissing: func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+//			http.ServeFile(w, req, "dist/index.html")
+//		},
+//	})
+func (r *HttpRouter) ServeFilesWith(path string, files Files) {
+	if files.Root == nil && files.FS != nil {
+		fsys := files.FS
+		if files.Dir != "" && files.Dir != "." {
+			sub, err := fs.Sub(fsys, files.Dir)
+			if err != nil {
+				panic("invalid directory '" + files.Dir + "' of file system in path '" + path + "': " + err.Error())
+			}
+			fsys = sub
+		}
+		files.Root = http.FS(fsys)
+	}
+	if !files.cached() {
+		r.serveFiles(path, files, http.FileServer(files.Root))
+		return
+	}
+
+	c := &cachedFiles{files: files}
+	root := files.Root
+	if files.NoLastModified {
+		root = noModTimeFS{root}
+	}
+	c.server = http.FileServer(root)
+	r.serveFiles(path, files, c)
+}
+
+// ServeFS serves the files of fsys, e.g. an embed.FS, like ServeFiles.
+// See Files.Dir for serving a directory of fsys.
+func (r *HttpRouter) ServeFS(path string, fsys fs.FS) {
+	r.ServeFilesWith(path, Files{FS: fsys})
+}
+
+// fileMount is a file system served by ServeFiles as fallthrough, see
+// FileFallthrough.
+type fileMount struct {
+	// Path prefix of the files, i.e. the route path without "*filepath"
+	prefix string
+	root   http.FileSystem
+	server http.Handler
+}
+
+// exists reports whether the file system has a file or directory with the
+// given name.
+func (m *fileMount) exists(name string) bool {
+	return fileExists(m.root, name)
+}
+
+// fileExists reports whether the file system has a file or directory with
+// the given name.
+func fileExists(root http.FileSystem, name string) bool {
+	f, err := root.Open(path.Clean("/" + name))
+	if err != nil {
+		return false
+	}
+	f.Close()
+	return true
+}
+
+// serveFiles serves the request from the first file mount having the
+// requested file, if any.
+func (t *routeTable) serveFiles(w http.ResponseWriter, req *http.Request) bool {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return false
+	}
+
+	for _, m := range t.files {
+		if !strings.HasPrefix(req.URL.Path, m.prefix) {
+			continue
+		}
+		name := req.URL.Path[len(m.prefix)-1:]
+		if !m.exists(name) {
+			continue
+		}
+
+		u := *req.URL
+		u.Path = name
+		u.RawPath = ""
+		sub := *req
+		sub.URL = &u
+		m.server.ServeHTTP(w, &sub)
+		return true
+	}
+	return false
+}
+
+// cachedFiles serves files with the caching controls of Files.
+type cachedFiles struct {
+	files  Files
+	server http.Handler
+
+	mu    sync.Mutex
+	etags map[string]fileETag
+}
+
+// fileETag is the ETag of a version of a file.
+type fileETag struct {
+	size    int64
+	modTime time.Time
+	etag    string
+}
+
+func (c *cachedFiles) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	name := path.Clean("/" + req.URL.Path)
+	h := w.Header()
+	if c.files.Immutable != nil && c.files.Immutable(name) {
+		h.Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else if c.files.CacheControl != "" {
+		h.Set("Cache-Control", c.files.CacheControl)
+	}
+	if c.files.ETag {
+		if etag := c.etag(name); etag != "" {
+			h.Set("ETag", etag)
+		}
+	}
+	c.server.ServeHTTP(w, req)
+}
+
+// etag returns the ETag of the named file, or an empty string if it is not a
+// readable file.
+func (c *cachedFiles) etag(name string) string {
+	f, err := c.files.Root.Open(name)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil || fi.IsDir() {
+		return ""
+	}
+
+	c.mu.Lock()
+	e, ok := c.etags[name]
+	c.mu.Unlock()
+	if ok && e.size == fi.Size() && e.modTime.Equal(fi.ModTime()) {
+		return e.etag
+	}
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return ""
+	}
+	e = fileETag{
+		size:    fi.Size(),
+		modTime: fi.ModTime(),
+		etag:    `"` + hex.EncodeToString(hash.Sum(nil))[:assetHashLen] + `"`,
+	}
+
+	c.mu.Lock()
+	if c.etags == nil {
+		c.etags = make(map[string]fileETag)
+	}
+	c.etags[name] = e
+	c.mu.Unlock()
+	return e.etag
+}
+
+// noModTimeFS hides the modification times of the files of a file system.
+type noModTimeFS struct {
+	http.FileSystem
+}
+
+func (fsys noModTimeFS) Open(name string) (http.File, error) {
+	f, err := fsys.FileSystem.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return noModTimeFile{f}, nil
+}
+
+type noModTimeFile struct {
+	http.File
+}
+
+func (f noModTimeFile) Stat() (os.FileInfo, error) {
+	fi, err := f.File.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return noModTimeInfo{fi}, nil
+}
+
+type noModTimeInfo struct {
+	os.FileInfo
+}
+
+func (noModTimeInfo) ModTime() time.Time {
+	return time.Time{}
+}
+
+// Fingerprinted reports whether the file name contains a content hash, i.e.
+// a dot or dash separated part of at least 8 hex digits containing both
+// digits and letters, like "app.3f2a1b9c.css" or "chunk-5d41402abc.js".
+// It can be used as Files.Immutable.
+func Fingerprinted(name string) bool {
+	base := path.Base(name)
+	for _, part := range strings.FieldsFunc(base, func(r rune) bool { return r == '.' || r == '-' }) {
+		if len(part) >= 8 && isHash(part) {
+			return true
+		}
+	}
+	return false
+}
+
+// isHash reports whether s consists of hex digits, both digits and letters.
+func isHash(s string) bool {
+	var digits, letters bool
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case '0' <= c && c <= '9':
+			digits = true
+		case 'a' <= c && c <= 'f', 'A' <= c && c <= 'F':
+			letters = true
+		default:
+			return false
+		}
+	}
+	return digits && letters
+}
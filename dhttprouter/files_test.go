@@ -0,0 +1,269 @@
+package dhttprouter
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterFileFallthrough(t *testing.T) {
+	dir, err := ioutil.TempDir("", "drouter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"index.html":      "spa",
+		"app.js":          "js",
+		"api/users/list":  "shadowed",
+		"assets/logo.svg": "dist logo",
+	}
+	for name, content := range files {
+		name = filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(name), 0755)
+		if err := ioutil.WriteFile(name, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assets, err := ioutil.TempDir("", "drouter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(assets)
+	ioutil.WriteFile(filepath.Join(assets, "logo.svg"), []byte("logo"), 0644)
+
+	handle := func(w http.ResponseWriter, _ *http.Request, ps drouter.Params) {
+		w.Write([]byte("user " + ps.ByName("id")))
+	}
+
+	router := New()
+	router.FileFallthrough = true
+	router.GET("/api/users/:id", handle)
+	router.POST("/app.js", handle)
+	router.ServeFiles("/assets/*filepath", http.Dir(assets))
+	router.ServeFiles("/*filepath", http.Dir(dir))
+
+	tests := []struct {
+		method, path string
+		code         int
+		body         string
+	}{
+		{http.MethodGet, "/api/users/list", http.StatusOK, "user list"},
+		{http.MethodGet, "/app.js", http.StatusOK, "js"},
+		{http.MethodHead, "/app.js", http.StatusOK, ""},
+		{http.MethodGet, "/", http.StatusOK, "spa"},
+		{http.MethodGet, "/assets/logo.svg", http.StatusOK, "logo"},
+		{http.MethodGet, "/missing.js", http.StatusNotFound, "404 page not found\n"},
+		{http.MethodPut, "/index.html", http.StatusNotFound, "404 page not found\n"},
+		{http.MethodPut, "/app.js", http.StatusMethodNotAllowed, "Method Not Allowed\n"},
+	}
+	for _, tr := range tests {
+		r, _ := http.NewRequest(tr.method, tr.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != tr.code || w.Body.String() != tr.body {
+			t.Errorf("%s %s: got %d %q, want %d %q", tr.method, tr.path, w.Code, w.Body.String(), tr.code, tr.body)
+		}
+	}
+
+	recv := catchPanic(func() {
+		router.ServeFiles("/:tenant/*filepath", http.Dir(dir))
+	})
+	if recv == nil {
+		t.Error("registering fallthrough files below a wildcard did not panic")
+	}
+}
+
+func TestRouterServeFilesOnMissing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "drouter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "app.js"), []byte("js"), 0644)
+
+	router := New()
+	router.ServeFilesWith("/app/*filepath", Files{
+		Root: http.Dir(dir),
+		OnMissing: func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+			w.Write([]byte("index for " + req.URL.Path + " " + ps.ByName("filepath")))
+		},
+	})
+
+	tests := []struct {
+		path, body string
+	}{
+		{"/app/app.js", "js"},
+		{"/app/users/42", "index for /app/users/42 /users/42"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK || w.Body.String() != tt.body {
+			t.Errorf("%s: wrong response: %d %q", tt.path, w.Code, w.Body.String())
+		}
+	}
+
+	if recv := catchPanic(func() { router.ServeFilesWith("/other/*filepath", Files{}) }); recv == nil {
+		t.Error("no panic for missing root")
+	}
+}
+
+func TestRouterServeFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"static/css/app.css": {Data: []byte("body{}")},
+		"static/index.html":  {Data: []byte("index")},
+		"secret.txt":         {Data: []byte("secret")},
+	}
+
+	router := New()
+	router.ServeFS("/all/*filepath", fsys)
+	router.ServeFilesWith("/static/*filepath", Files{FS: fsys, Dir: "static"})
+
+	tests := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/all/secret.txt", http.StatusOK, "secret"},
+		{"/all/static/css/app.css", http.StatusOK, "body{}"},
+		{"/static/css/app.css", http.StatusOK, "body{}"},
+		{"/static/", http.StatusOK, "index"},
+		{"/static/secret.txt", http.StatusNotFound, ""},
+		{"/static/../secret.txt", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+		router.ServeHTTP(w, req)
+		if w.Code != tt.code || (tt.body != "" && w.Body.String() != tt.body) {
+			t.Errorf("%s: wrong response: %d %q", tt.path, w.Code, w.Body.String())
+		}
+	}
+
+	if recv := catchPanic(func() {
+		router.ServeFilesWith("/bad/*filepath", Files{FS: fsys, Dir: "../static"})
+	}); recv == nil {
+		t.Error("no panic for invalid directory")
+	}
+}
+
+func TestRouterServeFilesRouterNotFound(t *testing.T) {
+	var misses []string
+	router := New()
+	router.NotFound = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("custom 404"))
+	})
+	router.OnNoMatch = func(method, path string, _ Decision) {
+		misses = append(misses, path)
+	}
+	router.ServeFilesWith("/static/*filepath", Files{
+		FS:             fstest.MapFS{"app.js": {Data: []byte("js")}},
+		RouterNotFound: true,
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/static/missing.js", nil)
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound || w.Body.String() != "custom 404" {
+		t.Errorf("wrong response for missing file: %d %q", w.Code, w.Body.String())
+	}
+	if len(misses) != 1 || misses[0] != "/static/missing.js" {
+		t.Errorf("wrong misses: %q", misses)
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/static/app.js", nil)
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Body.String() != "js" {
+		t.Errorf("wrong response for existing file: %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestRouterServeFilesCaching(t *testing.T) {
+	modTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"page.html":           {Data: []byte("page"), ModTime: modTime},
+		"app.3f2a1b9c0d4e.js": {Data: []byte("app"), ModTime: modTime},
+	}
+
+	router := New()
+	router.ServeFilesWith("/static/*filepath", Files{
+		FS:           fsys,
+		CacheControl: "public, max-age=60",
+		Immutable:    Fingerprinted,
+		ETag:         true,
+	})
+	router.ServeFilesWith("/plain/*filepath", Files{FS: fsys, NoLastModified: true})
+
+	serve := func(path string, header http.Header) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("/static/page.html", nil)
+	if got := w.Header().Get("Cache-Control"); got != "public, max-age=60" {
+		t.Errorf("wrong Cache-Control %q", got)
+	}
+	etag := w.Header().Get("ETag")
+	if len(etag) != assetHashLen+2 {
+		t.Fatalf("wrong ETag %q", etag)
+	}
+	if w.Header().Get("Last-Modified") == "" {
+		t.Error("Last-Modified is missing")
+	}
+	if w = serve("/static/page.html", http.Header{"If-None-Match": {etag}}); w.Code != http.StatusNotModified {
+		t.Errorf("wrong status code for matching ETag: %d", w.Code)
+	}
+	if w = serve("/static/page.html", http.Header{"If-None-Match": {`"other"`}}); w.Code != http.StatusOK {
+		t.Errorf("wrong status code for other ETag: %d", w.Code)
+	}
+
+	w = serve("/static/app.3f2a1b9c0d4e.js", nil)
+	if got := w.Header().Get("Cache-Control"); got != "public, max-age=31536000, immutable" {
+		t.Errorf("wrong Cache-Control for fingerprinted file %q", got)
+	}
+	if got := w.Header().Get("ETag"); got == etag || got == "" {
+		t.Errorf("wrong ETag for other file %q", got)
+	}
+
+	w = serve("/plain/page.html", http.Header{"If-Modified-Since": {modTime.Format(http.TimeFormat)}})
+	if w.Code != http.StatusOK || w.Header().Get("Last-Modified") != "" {
+		t.Errorf("modification time was used: Code=%d, Last-Modified=%q", w.Code, w.Header().Get("Last-Modified"))
+	}
+	if w.Header().Get("Cache-Control") != "" || w.Header().Get("ETag") != "" {
+		t.Errorf("unexpected caching headers: %v", w.Header())
+	}
+}
+
+func TestFingerprinted(t *testing.T) {
+	tests := map[string]bool{
+		"/css/app.3f2a1b9c0d4e.css": true,
+		"chunk-5d41402abc.js":       true,
+		"/dir.3f2a1b9c/app.css":     false,
+		"app.css":                   false,
+		"report-20240101.pdf":       false,
+		"deadbeefcafe.txt":          false,
+		"app.3f2a1b9.css":           false,
+	}
+	for name, want := range tests {
+		if got := Fingerprinted(name); got != want {
+			t.Errorf("Fingerprinted(%q): want %v, got %v", name, want, got)
+		}
+	}
+}
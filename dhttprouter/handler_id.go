@@ -0,0 +1,75 @@
+package dhttprouter
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// HandlerID identifies the handle or http.Handler a route was registered
+// with, before any middleware was applied.
+//
+// Functions are identified by their code, so closures created by the same
+// function literal share an identifier even if they capture different
+// values. Handlers implemented by pointer types are identified by their
+// type and address, other handlers only by their type name.
+type HandlerID struct {
+	// Name of the function or type, e.g. "main.getUser",
+	// "main.(*API).getUser" or "*main.userHandler"
+	Name string
+
+	// Entry point of the function or address of the handler.
+	// Zero if the handler has no stable address.
+	Pointer uintptr
+}
+
+// String returns the name and, if known, the pointer of the handler.
+func (id HandlerID) String() string {
+	if id.Pointer == 0 {
+		return id.Name
+	}
+	return fmt.Sprintf("%s@%#x", id.Name, id.Pointer)
+}
+
+// SameHandler reports whether both routes were registered with the same
+// handler, e.g. to find patterns which are aliases of each other.
+// Handlers without a stable address are never reported as the same.
+func SameHandler(a, b RouteInfo) bool {
+	return a.Handler.Pointer != 0 && a.Handler == b.Handler
+}
+
+// handlerID returns the identifier of a handle or handler.
+func handlerID(h interface{}) HandlerID {
+	switch fn := h.(type) {
+	case nil:
+		return HandlerID{}
+	case HttpHandle:
+		return funcID(reflect.ValueOf(fn))
+	case HttpHandleE:
+		return funcID(reflect.ValueOf(fn))
+	case http.HandlerFunc:
+		return funcID(reflect.ValueOf(fn))
+	}
+
+	v := reflect.ValueOf(h)
+	id := HandlerID{Name: v.Type().String()}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Chan, reflect.UnsafePointer:
+		id.Pointer = v.Pointer()
+	case reflect.Func:
+		return funcID(v)
+	}
+	return id
+}
+
+func funcID(v reflect.Value) HandlerID {
+	pc := v.Pointer()
+	id := HandlerID{Pointer: pc}
+	if f := runtime.FuncForPC(pc); f != nil {
+		// Method values are wrapped, e.g. main.(*API).getUser-fm
+		id.Name = strings.TrimSuffix(f.Name(), "-fm")
+	}
+	return id
+}
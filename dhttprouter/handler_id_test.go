@@ -0,0 +1,77 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func getUserHandle(http.ResponseWriter, *http.Request, drouter.Params) {}
+
+type userAPI struct{}
+
+func (*userAPI) list(http.ResponseWriter, *http.Request, drouter.Params) {}
+
+func (*userAPI) ServeHTTP(http.ResponseWriter, *http.Request) {}
+
+type valueHandler struct{}
+
+func (valueHandler) ServeHTTP(http.ResponseWriter, *http.Request) {}
+
+func TestRouterHandlerID(t *testing.T) {
+	api := &userAPI{}
+	mw := func(next HttpHandle) HttpHandle { return next }
+
+	router := New()
+	router.GET("/users/:id", getUserHandle)
+	router.GET("/members/:id", getUserHandle, mw)
+	router.GET("/users", api.list)
+	router.Handler(http.MethodPost, "/users", api)
+	router.Handler(http.MethodPut, "/users", api)
+	router.Handler(http.MethodDelete, "/users", valueHandler{})
+	router.Handler(http.MethodPatch, "/users", valueHandler{})
+
+	routes := make(map[string]RouteInfo)
+	for _, info := range router.Routes() {
+		routes[info.Method+" "+info.Path] = info
+	}
+
+	tests := []struct {
+		route, name string
+	}{
+		{"GET /users/:id", "dhttprouter.getUserHandle"},
+		{"GET /users", "dhttprouter.(*userAPI).list"},
+		{"POST /users", "*dhttprouter.userAPI"},
+		{"DELETE /users", "dhttprouter.valueHandler"},
+	}
+	for _, tr := range tests {
+		if name := routes[tr.route].Handler.Name; !strings.HasSuffix(name, tr.name) {
+			t.Errorf("%s: unexpected handler name %q, want suffix %q", tr.route, name, tr.name)
+		}
+	}
+
+	same := []struct {
+		a, b string
+		same bool
+	}{
+		{"GET /users/:id", "GET /members/:id", true},
+		{"POST /users", "PUT /users", true},
+		{"GET /users/:id", "GET /users", false},
+		{"GET /users", "POST /users", false},
+		{"DELETE /users", "PATCH /users", false},
+	}
+	for _, tr := range same {
+		if got := SameHandler(routes[tr.a], routes[tr.b]); got != tr.same {
+			t.Errorf("SameHandler(%s, %s) = %v, want %v", tr.a, tr.b, got, tr.same)
+		}
+	}
+
+	if id := routes["DELETE /users"].Handler; id.String() != id.Name {
+		t.Errorf("unexpected string of handler without pointer: %q", id.String())
+	}
+	if id := routes["POST /users"].Handler; !strings.HasPrefix(id.String(), id.Name+"@0x") {
+		t.Errorf("unexpected string of handler with pointer: %q", id.String())
+	}
+}
@@ -0,0 +1,80 @@
+package dhttprouter
+
+import (
+	"net/http"
+
+	"github.com/thekhanj/drouter"
+)
+
+// serveAutoHEAD serves a HEAD request with the GET route of the path, if
+// any, see AutoHEAD. It reports whether there was such a route.
+func (r *HttpRouter) serveAutoHEAD(t *routeTable, w http.ResponseWriter, req *http.Request, path string) bool {
+	router := t.routers[http.MethodGet]
+	if router == nil {
+		return false
+	}
+
+	ps := t.getParams()
+	rt, _ := router.Lookup(path, ps)
+	if rt == nil {
+		t.putParams(ps)
+		return false
+	}
+	if r.UseRawPath && r.UnescapePathValues {
+		unescapeParams(*ps)
+	}
+	r.serve(t, rt, w, req, *ps)
+	t.putParams(ps)
+	return true
+}
+
+// headWriter discards the body of a GET handle serving a HEAD request.
+type headWriter struct {
+	http.ResponseWriter
+}
+
+func (w headWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+// withHeadHook returns a handle passing HEAD requests served by the GET route
+// to its hook, if one is set, or to the given handle with the body
+// discarded.
+func (rt *route) withHeadHook(handle HttpHandle) HttpHandle {
+	return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		if req.Method != http.MethodHead {
+			handle(w, req, ps)
+		} else if rt.head != nil {
+			rt.head(w, req, ps)
+		} else {
+			handle(headWriter{w}, req, ps)
+		}
+	}
+}
+
+// SetHEAD sets a hook answering the HEAD requests which AutoHEAD passes to
+// the GET route with the given path, instead of running its GET handle just
+// to discard the body. The hook typically sets the headers of the GET
+// response which are cheap to compute, e.g.
+//
+//	router.GET("/reports/:id", renderReport)
+//	router.SetHEAD("/reports/:id", func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+//		meta, err := reports.Stat(ps.ByName("id"))
+//		if err != nil {
+//			w.WriteHeader(http.StatusNotFound)
+//			return
+//		}
+//		w.Header().Set("Content-Length", strconv.FormatInt(meta.Size, 10))
+//		w.Header().Set("ETag", meta.ETag)
+//	})
+//
+// The hook runs within the middleware of the GET route. SetHEAD panics if no
+// GET route with the path exists. Like the registration of routes, it must
+// not be called concurrently with ServeHTTP.
+func (r *HttpRouter) SetHEAD(path string, hook HttpHandle) {
+	rt := r.mutableTable().lookupRoute(http.MethodGet, path)
+	if rt == nil {
+		panic("no route registered for GET '" + path + "'")
+	}
+	rt.head = hook
+}
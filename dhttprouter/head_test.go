@@ -0,0 +1,78 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterAutoHEAD(t *testing.T) {
+	var getCalls, hookCalls, middlewareCalls int
+	logger := &testLogger{}
+	router := New()
+	router.Logger = logger
+	counting := func(next HttpHandle) HttpHandle {
+		return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+			middlewareCalls++
+			next(w, req, ps)
+		}
+	}
+	get := func(w http.ResponseWriter, _ *http.Request, ps drouter.Params) {
+		getCalls++
+		w.Header().Set("ETag", `"`+ps.ByName("id")+`"`)
+		w.Write([]byte("expensive"))
+	}
+	router.GET("/reports/:id", get, counting)
+	router.GET("/cheap/:id", get, counting)
+	router.SetHEAD("/cheap/:id", func(w http.ResponseWriter, _ *http.Request, ps drouter.Params) {
+		hookCalls++
+		w.Header().Set("ETag", `"`+ps.ByName("id")+`"`)
+	})
+
+	// Disabled by default
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/reports/1", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("want 405 without AutoHEAD, got %d", w.Code)
+	}
+
+	router.AutoHEAD = true
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/reports/1", nil))
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != `"1"` || w.Body.Len() != 0 || getCalls != 1 {
+		t.Errorf("wrong response %d %v %q, %d calls", w.Code, w.Header(), w.Body, getCalls)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/cheap/2", nil))
+	if w.Header().Get("ETag") != `"2"` || getCalls != 1 || hookCalls != 1 || middlewareCalls != 2 {
+		t.Errorf("hook was not used: %v, %d get calls, %d hook calls, %d middleware calls",
+			w.Header(), getCalls, hookCalls, middlewareCalls)
+	}
+
+	// The hook does not affect GET requests
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/cheap/3", nil))
+	if w.Body.String() != "expensive" || hookCalls != 1 {
+		t.Errorf("wrong GET response %q", w.Body)
+	}
+
+	// Explicit HEAD routes take priority
+	router.HEAD("/reports/:id", func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodHead, "/reports/4", nil))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("explicit HEAD route was not used: %d", w.Code)
+	}
+
+	if e := logger.entries[1]; e["route"] != "/reports/:id" || e["method"] != http.MethodHead {
+		t.Errorf("wrong access log entry %v", e)
+	}
+	if recv := catchPanic(func() { router.SetHEAD("/missing", nil) }); recv == nil {
+		t.Error("no panic for missing route")
+	}
+}
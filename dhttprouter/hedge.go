@@ -0,0 +1,160 @@
+package dhttprouter
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+// hedgeBurst is the maximum number of hedges which can be saved up.
+const hedgeBurst = 10
+
+// Hedge reduces the tail latency of a route by sending a hedge request to a
+// second handle, typically a reverse proxy to another backend, if the
+// primary handle did not respond within Delay. The response which is
+// complete first is sent to the client and the request of the other handle
+// is canceled via its context. Its Handle method is used as the handle of
+// the route, e.g.
+//
+//	h := &dhttprouter.Hedge{Primary: proxyA, Secondary: proxyB, Delay: 50 * time.Millisecond}
+//	router.GET("/search", h.Handle)
+//
+// Hedges also replace attempts that panic. To limit the extra load on the
+// backends, Budget caps the ratio of hedged requests. Only GET and HEAD
+// requests are hedged, requests with other methods are passed to the
+// primary handle as they are.
+// The responses are buffered, so Hedge is not suited for streaming handles.
+// The handles run in their own goroutines and may outlive the request if
+// they ignore its context.
+type Hedge struct {
+	Primary HttpHandle
+
+	// Handle receiving the hedge requests. Defaults to Primary.
+	Secondary HttpHandle
+
+	// Time to wait for the primary handle before the hedge is sent.
+	// Typically a high percentile of its latency, e.g. the 95th.
+	Delay time.Duration
+
+	// Maximum ratio of hedged requests, e.g. 0.05 for 5 percent.
+	// Defaults to 0.1.
+	Budget float64
+
+	mu     sync.Mutex
+	tokens float64
+	hedged uint64
+}
+
+type hedgeResult struct {
+	response *bufferedResponse
+	panicked interface{}
+}
+
+func (h *Hedge) budget() float64 {
+	if h.Budget > 0 {
+		return h.Budget
+	}
+	return 0.1
+}
+
+// deposit adds the share of a request to the hedge budget.
+func (h *Hedge) deposit() {
+	h.mu.Lock()
+	if h.tokens += h.budget(); h.tokens > hedgeBurst {
+		h.tokens = hedgeBurst
+	}
+	h.mu.Unlock()
+}
+
+// take reports whether the budget allows another hedge and spends it.
+func (h *Hedge) take() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.tokens < 1 {
+		return false
+	}
+	h.tokens--
+	h.hedged++
+	return true
+}
+
+// Hedged returns the number of hedge requests sent so far.
+func (h *Hedge) Hedged() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.hedged
+}
+
+// Handle serves the request with the primary handle and, if it is too slow,
+// the secondary one.
+func (h *Hedge) Handle(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		h.Primary(w, req, ps)
+		return
+	}
+	h.deposit()
+
+	secondary := h.Secondary
+	if secondary == nil {
+		secondary = h.Primary
+	}
+
+	// The attempts may outlive the request, so they get their own params
+	ps = ps.Clone()
+	ctx, cancel := context.WithCancel(req.Context())
+	defer cancel()
+	sub := req.WithContext(ctx)
+
+	results := make(chan hedgeResult, 2)
+	run := func(handle HttpHandle) {
+		go func() {
+			res := hedgeResult{response: &bufferedResponse{header: make(http.Header)}}
+			defer func() {
+				res.panicked = recover()
+				results <- res
+			}()
+			handle(res.response, sub, ps)
+		}()
+	}
+
+	run(h.Primary)
+	pending, hedged := 1, false
+
+	timer := time.NewTimer(h.Delay)
+	defer timer.Stop()
+	delay := timer.C
+
+	for {
+		select {
+		case res := <-results:
+			pending--
+			if res.panicked == nil {
+				res.response.writeTo(w)
+				return
+			}
+			if pending > 0 {
+				continue
+			}
+			if hedged || !h.take() {
+				panic(res.panicked)
+			}
+			// Replace the failed attempt right away
+			run(secondary)
+			pending, hedged = 1, true
+
+		case <-delay:
+			delay = nil
+			if !hedged && h.take() {
+				run(secondary)
+				pending++
+				hedged = true
+			}
+
+		case <-req.Context().Done():
+			return
+		}
+	}
+}
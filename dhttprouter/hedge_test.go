@@ -0,0 +1,140 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+func hedgeHandle(body string, delay time.Duration, canceled *int32) HttpHandle {
+	return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			atomic.AddInt32(canceled, 1)
+			return
+		}
+		w.Header().Set("X-Backend", body)
+		w.Write([]byte(body + ps.ByName("id")))
+	}
+}
+
+func TestHedge(t *testing.T) {
+	var canceled int32
+	h := &Hedge{
+		Primary:   hedgeHandle("primary", time.Second, &canceled),
+		Secondary: hedgeHandle("secondary", 0, &canceled),
+		Delay:     10 * time.Millisecond,
+		Budget:    1,
+	}
+	router := New()
+	router.GET("/items/:id", h.Handle)
+	router.POST("/items/:id", h.Handle)
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/items/1", nil)
+	router.ServeHTTP(w, r)
+	if w.Body.String() != "secondary1" || w.Header().Get("X-Backend") != "secondary" {
+		t.Errorf("unexpected response: %q %v", w.Body.String(), w.Header())
+	}
+	if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("hedged request took %v", d)
+	}
+	if n := h.Hedged(); n != 1 {
+		t.Errorf("Hedged() = %d, want 1", n)
+	}
+
+	// The slow primary is canceled
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&canceled) != 1 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if n := atomic.LoadInt32(&canceled); n != 1 {
+		t.Errorf("expected the primary to be canceled, got %d cancellations", n)
+	}
+
+	// Other methods are not hedged
+	h.Primary = hedgeHandle("primary", 20*time.Millisecond, &canceled)
+	w = httptest.NewRecorder()
+	r, _ = http.NewRequest(http.MethodPost, "/items/2", nil)
+	router.ServeHTTP(w, r)
+	if w.Body.String() != "primary2" || h.Hedged() != 1 {
+		t.Errorf("POST was hedged: %q, hedged=%d", w.Body.String(), h.Hedged())
+	}
+}
+
+func TestHedgeFastPrimary(t *testing.T) {
+	var calls int32
+	h := &Hedge{
+		Primary: func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusTeapot)
+		},
+		Delay:  time.Second,
+		Budget: 1,
+	}
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	h.Handle(w, r, nil)
+	if w.Code != http.StatusTeapot || atomic.LoadInt32(&calls) != 1 || h.Hedged() != 0 {
+		t.Errorf("unexpected result: code=%d, calls=%d, hedged=%d", w.Code, calls, h.Hedged())
+	}
+}
+
+func TestHedgeBudget(t *testing.T) {
+	var canceled int32
+	h := &Hedge{
+		Primary:   hedgeHandle("primary", 20*time.Millisecond, &canceled),
+		Secondary: hedgeHandle("secondary", 0, &canceled),
+		Budget:    0.25,
+	}
+
+	secondary := 0
+	for i := 0; i < 8; i++ {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, "/", nil)
+		h.Handle(w, r, nil)
+		if w.Body.String() == "secondary" {
+			secondary++
+		}
+	}
+	if secondary != 2 || h.Hedged() != 2 {
+		t.Errorf("expected 2 hedged requests, got %d (Hedged() = %d)", secondary, h.Hedged())
+	}
+}
+
+func TestHedgePanic(t *testing.T) {
+	var canceled int32
+	failing := func(http.ResponseWriter, *http.Request, drouter.Params) {
+		panic("oops")
+	}
+
+	// A failed primary is replaced by the hedge
+	h := &Hedge{
+		Primary:   failing,
+		Secondary: hedgeHandle("secondary", 0, &canceled),
+		Delay:     time.Second,
+		Budget:    1,
+	}
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	h.Handle(w, r, nil)
+	if w.Body.String() != "secondary" {
+		t.Errorf("unexpected response: %q", w.Body.String())
+	}
+
+	// Without a successful attempt, the panic is passed on
+	h.Secondary = failing
+	recv := catchPanic(func() {
+		h.Handle(httptest.NewRecorder(), r, nil)
+	})
+	if recv != "oops" {
+		t.Errorf("unexpected panic: %v", recv)
+	}
+}
@@ -0,0 +1,194 @@
+package dhttprouter
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/thekhanj/drouter"
+)
+
+// HostRouter dispatches requests to different handlers, typically one
+// HttpRouter per site, by the host of the request. Its zero value is ready
+// to use, e.g.
+//
+//	hosts := new(dhttprouter.HostRouter)
+//	hosts.Handle("example.com", site)
+//	hosts.Handle("api.example.com", api)
+//	hosts.Handle("*.example.com", tenants)
+//	log.Fatal(http.ListenAndServe(":8080", hosts))
+//
+// Host patterns are matched case-insensitively against the request host
+// without its port. Labels starting with ':' are parameters matching a
+// single label, e.g. ":tenant.api.example.com". Like path parameters they
+// may have a constraint, e.g. ":tenant|alnum". Their values are passed to
+// handles of HttpRouters alongside the path parameters and are available to
+// other handlers via HostParamsFromContext. A leading "*" label matches one
+// or more labels, so "*.example.com" matches "a.example.com" and
+// "a.b.example.com", but not "example.com". Exact hosts take precedence over
+// hosts with parameters, which take precedence over wildcards. Longer
+// wildcards take precedence over shorter ones.
+//
+// The hostnames are stored in radix trees with their labels reversed, e.g.
+// api.example.com as /com/example/api, so hosts sharing a domain share a
+// common prefix.
+type HostRouter struct {
+	// Configurable http.Handler which is called when no pattern matches the
+	// host of the request. If it is not set, http.NotFound is used.
+	NotFound http.Handler
+
+	// Exact hosts, hosts with parameters and wildcard suffixes, all keyed by
+	// their reversed labels
+	hosts     *drouter.Router[*hostRoute]
+	params    *drouter.Router[*hostRoute]
+	wildcards *drouter.Router[*hostRoute]
+
+	// Maximum number of parameters of any host pattern
+	maxParams int
+}
+
+type hostParamsKey struct{}
+
+// HostParamsKey is the request context key under which the parameters of
+// the host pattern matched by a HostRouter are stored.
+var HostParamsKey = hostParamsKey{}
+
+// HostParamsFromContext returns the parameters of the host pattern matched
+// by a HostRouter, or nil if there are none.
+func HostParamsFromContext(ctx context.Context) drouter.Params {
+	ps, _ := ctx.Value(HostParamsKey).(drouter.Params)
+	return ps
+}
+
+type hostRoute struct {
+	pattern string
+	handler http.Handler
+}
+
+// Handle registers the handler for the given host pattern, e.g.
+// "api.example.com" or "*.example.com".
+func (h *HostRouter) Handle(pattern string, handler http.Handler) {
+	if handler == nil {
+		panic("handler must not be nil")
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(pattern, "."))
+	wildcard := strings.HasPrefix(host, "*.")
+	if wildcard {
+		host = host[2:]
+	}
+	if host == "" || strings.ContainsAny(host, "*/") {
+		panic("host must be a hostname with an optional leading '*' label in pattern '" + pattern + "'")
+	}
+	params := 0
+	for _, label := range strings.Split(host, ".") {
+		if label == "" {
+			panic("host must not contain empty labels in pattern '" + pattern + "'")
+		}
+		if i := strings.IndexByte(label, ':'); i == 0 {
+			if len(label) < 2 {
+				panic("host parameters must be named with a non-empty name in pattern '" + pattern + "'")
+			}
+			params++
+		} else if i > 0 {
+			panic("host parameters must span a whole label in pattern '" + pattern + "'")
+		}
+	}
+
+	tree := &h.hosts
+	key := hostPath(host)
+	switch {
+	case wildcard && params > 0:
+		panic("host must not have both parameters and a wildcard in pattern '" + pattern + "'")
+	case wildcard:
+		tree = &h.wildcards
+		key += "/"
+	case params > 0:
+		tree = &h.params
+		if params > h.maxParams {
+			h.maxParams = params
+		}
+	}
+	if *tree == nil {
+		*tree = drouter.New[*hostRoute]()
+	}
+	if handle, _ := (*tree).Lookup(key, nil); handle != nil {
+		panic("a handler is already registered for host '" + pattern + "'")
+	}
+
+	err := (*tree).TryAddRoute(key, &hostRoute{pattern: pattern, handler: handler})
+	if rerr, ok := err.(*drouter.RouteError); ok {
+		// Report conflicts with the patterns instead of the reversed keys
+		if rerr.Kind == drouter.Conflict {
+			(*tree).Walk(func(key string, hr *hostRoute) bool {
+				if key == rerr.Existing {
+					panic("host pattern '" + pattern + "' conflicts with host pattern '" + hr.pattern + "'")
+				}
+				return true
+			})
+		}
+		panic("invalid host pattern '" + pattern + "': " + rerr.Message)
+	}
+}
+
+// Handler returns the handler for the given host, which may include a port,
+// and the pattern it was registered with. It returns a nil handler if no
+// pattern matches the host.
+func (h *HostRouter) Handler(host string) (handler http.Handler, pattern string) {
+	handler, pattern, _ = h.lookup(host)
+	return handler, pattern
+}
+
+// lookup returns the handler and pattern for the host together with the
+// values of the pattern's parameters.
+func (h *HostRouter) lookup(host string) (http.Handler, string, drouter.Params) {
+	host, _ = splitHostPort(host)
+	path := hostPath(strings.ToLower(strings.TrimSuffix(host, ".")))
+
+	if h.hosts != nil {
+		if hr, _ := h.hosts.Lookup(path, nil); hr != nil {
+			return hr.handler, hr.pattern, nil
+		}
+	}
+
+	if h.params != nil {
+		ps := make(drouter.Params, 0, h.maxParams)
+		if hr, _ := h.params.Lookup(path, &ps); hr != nil {
+			return hr.handler, hr.pattern, ps
+		}
+	}
+
+	if h.wildcards != nil {
+		// Try the suffixes of the host, longest first
+		for i := strings.LastIndexByte(path, '/'); i > 0; i = strings.LastIndexByte(path[:i], '/') {
+			if hr, _ := h.wildcards.Lookup(path[:i+1], nil); hr != nil {
+				return hr.handler, hr.pattern, nil
+			}
+		}
+	}
+	return nil, "", nil
+}
+
+// ServeHTTP makes the HostRouter implement the http.Handler interface.
+func (h *HostRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if handler, _, ps := h.lookup(req.Host); handler != nil {
+		if len(ps) > 0 {
+			req = req.WithContext(context.WithValue(req.Context(), HostParamsKey, ps))
+		}
+		handler.ServeHTTP(w, req)
+	} else if h.NotFound != nil {
+		h.NotFound.ServeHTTP(w, req)
+	} else {
+		http.NotFound(w, req)
+	}
+}
+
+// hostPath returns the labels of the host in reversed order as a path, e.g.
+// "/com/example/api" for "api.example.com".
+func hostPath(host string) string {
+	labels := strings.Split(host, ".")
+	for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+		labels[i], labels[j] = labels[j], labels[i]
+	}
+	return "/" + strings.Join(labels, "/")
+}
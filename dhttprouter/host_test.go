@@ -0,0 +1,151 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestHostRouter(t *testing.T) {
+	handler := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Write([]byte(name))
+		})
+	}
+
+	hosts := new(HostRouter)
+	hosts.Handle("example.com", handler("site"))
+	hosts.Handle("API.example.com", handler("api"))
+	hosts.Handle("*.example.com", handler("tenants"))
+	hosts.Handle("*.eu.example.com", handler("eu"))
+	hosts.Handle("example.org.", handler("org"))
+
+	tests := []struct {
+		host string
+		code int
+		body string
+	}{
+		{"example.com", http.StatusOK, "site"},
+		{"example.com:8080", http.StatusOK, "site"},
+		{"EXAMPLE.com.", http.StatusOK, "site"},
+		{"api.example.com", http.StatusOK, "api"},
+		{"acme.example.com", http.StatusOK, "tenants"},
+		{"a.b.example.com:443", http.StatusOK, "tenants"},
+		{"eu.example.com", http.StatusOK, "tenants"},
+		{"acme.eu.example.com", http.StatusOK, "eu"},
+		{"example.org", http.StatusOK, "org"},
+		{"www.example.org", http.StatusNotFound, "404 page not found\n"},
+		{"com", http.StatusNotFound, "404 page not found\n"},
+		{"", http.StatusNotFound, "404 page not found\n"},
+	}
+	for _, tr := range tests {
+		r, _ := http.NewRequest(http.MethodGet, "/", nil)
+		r.Host = tr.host
+		w := httptest.NewRecorder()
+		hosts.ServeHTTP(w, r)
+		if w.Code != tr.code || w.Body.String() != tr.body {
+			t.Errorf("host %q: got %d %q, want %d %q", tr.host, w.Code, w.Body.String(), tr.code, tr.body)
+		}
+	}
+
+	if _, pattern := hosts.Handler("x.eu.example.com"); pattern != "*.eu.example.com" {
+		t.Errorf("unexpected pattern: %q", pattern)
+	}
+
+	hosts.NotFound = handler("fallback")
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.Host = "unknown.net"
+	w := httptest.NewRecorder()
+	hosts.ServeHTTP(w, r)
+	if w.Body.String() != "fallback" {
+		t.Errorf("NotFound handler not used: %q", w.Body.String())
+	}
+}
+
+func TestHostRouterInvalid(t *testing.T) {
+	hosts := new(HostRouter)
+	hosts.Handle("example.com", http.NotFoundHandler())
+	hosts.Handle("*.example.com", http.NotFoundHandler())
+
+	for _, pattern := range []string{"", "*", "*.", "a.*.com", "a*.com", "example..com", "example.com:80", "example.com/x", "EXAMPLE.com", "*.Example.com"} {
+		recv := catchPanic(func() {
+			hosts.Handle(pattern, http.NotFoundHandler())
+		})
+		if recv == nil {
+			t.Errorf("registering host pattern %q did not panic", pattern)
+		}
+	}
+
+	recv := catchPanic(func() {
+		hosts.Handle("example.net", nil)
+	})
+	if recv == nil {
+		t.Error("registering nil handler did not panic")
+	}
+}
+
+func TestHostRouterParams(t *testing.T) {
+	var got string
+	router := New()
+	router.GET("/users/:id", func(w http.ResponseWriter, _ *http.Request, ps drouter.Params) {
+		got = ps.ByName("tenant") + "/" + ps.ByName("region") + "/" + ps.ByName("id")
+	})
+
+	hosts := new(HostRouter)
+	hosts.Handle("www.example.com", http.NotFoundHandler())
+	hosts.Handle(":tenant|alnum.example.com", router)
+	hosts.Handle(":tenant.:region.example.net", router)
+	hosts.Handle("*.example.com", http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		got = "wildcard"
+	}))
+	hosts.Handle(":app.example.org", http.HandlerFunc(func(_ http.ResponseWriter, req *http.Request) {
+		got = HostParamsFromContext(req.Context()).ByName("app")
+	}))
+
+	tests := []struct {
+		host, want string
+	}{
+		{"acme.example.com", "acme//42"},
+		{"ACME.example.com:8080", "acme//42"},
+		{"acme.eu.example.net", "acme/eu/42"},
+		{"ac-me.example.com", "wildcard"},
+		{"a.b.c.example.com", "wildcard"},
+		{"www.example.com", ""},
+		{"shop.example.org", "shop"},
+	}
+	for _, tr := range tests {
+		got = ""
+		r, _ := http.NewRequest(http.MethodGet, "/users/42", nil)
+		r.Host = tr.host
+		hosts.ServeHTTP(httptest.NewRecorder(), r)
+		if got != tr.want {
+			t.Errorf("host %q: got %q, want %q", tr.host, got, tr.want)
+		}
+	}
+
+	if _, pattern := hosts.Handler("x.eu.example.net"); pattern != ":tenant.:region.example.net" {
+		t.Errorf("unexpected pattern: %q", pattern)
+	}
+
+	for _, pattern := range []string{":.example.com", "a:b.example.com", "*.:tenant.example.com", ":tenant|nope.example.io"} {
+		recv := catchPanic(func() {
+			hosts.Handle(pattern, router)
+		})
+		if recv == nil {
+			t.Errorf("registering host pattern %q did not panic", pattern)
+		}
+	}
+
+	// Conflicts name the registered pattern
+	recv := catchPanic(func() {
+		hosts.Handle(":region.example.com", router)
+	})
+	if msg, _ := recv.(string); msg != "host pattern ':region.example.com' conflicts with host pattern ':tenant|alnum.example.com'" {
+		t.Errorf("unexpected panic: %v", recv)
+	}
+	if _, pattern := hosts.Handler("acme.example.com"); pattern != ":tenant|alnum.example.com" {
+		t.Errorf("failed registration changed the router: %q", pattern)
+	}
+}
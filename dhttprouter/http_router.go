@@ -3,31 +3,78 @@ package dhttprouter
 import (
 	"context"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/thekhanj/drouter"
 )
 
 // Handle is a function that can be registered to a route to handle HTTP
 // requests. Like http.HandlerFunc, but has a third parameter for the values of
-// wildcards (path variables).
+// wildcards (path variables). The Params are only valid until the handle
+// returns, use Params.Clone to keep them longer.
 type HttpHandle func(http.ResponseWriter, *http.Request, drouter.Params)
 
 // Router is a http.Handler which can be used to dispatch requests to different
 // handler functions via configurable routes
 type HttpRouter struct {
-	routers map[string]*drouter.Router
-
-	paramsPool sync.Pool
-	maxParams  uint16
-
-	// If enabled, adds the matched route path onto the http.Request context
-	// before invoking the handle.
-	// The matched route path is only added to handles of routes that were
-	// registered when this option was enabled.
+	// Version of the route table, incremented on every change.
+	// Accessed atomically, must stay the first field for 64-bit alignment.
+	version uint64
+
+	// Number of requests whose Params exceeded the pooled capacity.
+	// Accessed atomically, must stay next to version for 64-bit alignment.
+	paramsOverflows uint64
+
+	// Number of requests skipped by CheckCanceled.
+	// Accessed atomically, must stay next to version for 64-bit alignment.
+	canceled uint64
+
+	// The current *routeTable, replaced atomically by Swap
+	table atomic.Value
+
+	// Copy of the table changed during Update, which registration functions
+	// change instead of the current one
+	staged   *routeTable
+	updateMu sync.Mutex
+
+	// If enabled, adds the matched route path onto the Params before invoking
+	// the middleware and the handle, see drouter.Params.MatchedRoutePath.
+	// The option applies to all routes, including those registered before it
+	// was enabled. The path is stored in a slot the pooled Params reserve for
+	// it, so it does not count towards the params of a route.
 	SaveMatchedRoutePath bool
 
+	// If enabled, the router checks whether the context of the request is
+	// already done after the match and before each middleware and the handle
+	// run, e.g. because the client disconnected while the request was queued.
+	// Such requests are skipped and answered with StatusClientClosedRequest
+	// or, if their deadline passed, 503 Service Unavailable. Their number is
+	// available from Canceled.
+	// The check is only added to routes that were registered when this option
+	// was enabled.
+	CheckCanceled bool
+
+	// Fixed capacity of the Params pooled for lookups. If zero, the capacity
+	// grows with the registered routes to fit the params of every route, so
+	// lookups never allocate. A fixed capacity bounds the memory held by the
+	// pool, routes needing more params are handled as ParamsOverflow selects.
+	ParamsCapacity int
+
+	// Number of params reserved in the pooled Params of every route for
+	// middleware appending params, e.g. a request ID, and for the parameters
+	// of host patterns, see HostRouter.
+	ParamsReserve int
+
+	// Behavior for routes whose params may exceed the capacity of the pooled
+	// Params, i.e. their parameters, the matched route path and
+	// ParamsReserve, or whose params together with the host parameters do.
+	// The number of such requests is available from ParamsStats.
+	ParamsOverflow ParamsOverflow
+
 	// Enables automatic redirection if the current route can't be matched but a
 	// handle for the path with (without) the trailing slash exists. For example
 	// if /foo/ is requested but a route only exists for /foo, the client is
@@ -46,6 +93,13 @@ type HttpRouter struct {
 	// RedirectTrailingSlash is independent of this option.
 	RedirectFixedPath bool
 
+	// Methods for which RedirectTrailingSlash and RedirectFixedPath are
+	// disabled, e.g. methods of WebDAV or CalDAV clients which do not follow
+	// redirects, such as "PROPFIND" or "REPORT". Requests with these methods
+	// are passed to the MethodNotAllowed or NotFound handler instead.
+	// CONNECT requests are never redirected.
+	NoRedirectMethods []string
+
 	// If enabled, the router checks if another method is allowed for the
 	// current route, if the current request can not be routed.
 	// If this is the case, the request is answered with 'Method Not Allowed'
@@ -54,23 +108,107 @@ type HttpRouter struct {
 	// handle.
 	HandleMethodNotAllowed bool
 
+	// If enabled, POST requests are routed as PUT, PATCH or DELETE requests
+	// if they ask for it with the X-HTTP-Method-Override header or, for HTML
+	// forms, the _method form field. The method is overridden before the
+	// lookup, so 405 responses and the Allow header refer to the requested
+	// method. Handles see the overridden method, the original one is available
+	// via OriginalMethodFromContext. Reading the form field consumes the
+	// request body, which is parsed into the request's PostForm.
+	MethodOverride bool
+
+	// Name of the environment the router runs in, e.g. "production" or
+	// "staging", see WhenEnv.
+	Env string
+
+	// If enabled, routes are looked up with the escaped request path, see
+	// url.URL.EscapedPath, instead of the decoded one. An encoded slash "%2F"
+	// then belongs to the parameter value of its segment instead of separating
+	// two segments, e.g. "/repos/a%2Fb/issues" matches "/repos/:name/issues".
+	UseRawPath bool
+
+	// If enabled together with UseRawPath, the values of parameters are
+	// unescaped, so the handle of the above route sees name=a/b. Otherwise
+	// they are passed as they appear in the request path, e.g. "a%2Fb".
+	// Without UseRawPath, the values are always unescaped.
+	UnescapePathValues bool
+
+	// Optional hook canonicalizing the query string of requests before the
+	// lookup, e.g. to strip tracking parameters.
+	CanonicalQuery *CanonicalQuery
+
 	// If enabled, the router automatically replies to OPTIONS requests.
 	// Custom OPTIONS handles take priority over automatic replies.
 	HandleOPTIONS bool
 
+	// Optional CORS policy for all routes of the router. Preflight requests
+	// are answered by the automatic OPTIONS replies, with the methods
+	// registered for the path. Policies set with SetCORS take precedence.
+	CORS *CORSPolicy
+
+	// If enabled, HEAD requests without a HEAD route are served by the GET
+	// route of the path with the response body discarded. Routes whose GET
+	// handle is expensive can answer these requests cheaply, see SetHEAD.
+	AutoHEAD bool
+
 	// An optional http.Handler that is called on automatic OPTIONS requests.
 	// The handle is only called if HandleOPTIONS is true and no OPTIONS
 	// handle for the specific path was set.
 	// The "Allowed" header is set before calling the handle.
 	GlobalOPTIONS http.Handler
 
-	// Cached value of global (*) allowed methods
-	globalAllowed string
+	// An optional http.Handler that is called for server-wide "OPTIONS *"
+	// requests, which can not be matched by a route, instead of the
+	// automatic reply. It is called regardless of HandleOPTIONS, after the
+	// "Allow" header was set to the methods of all routes. Requests of other
+	// methods for "*" are answered with 400 Bad Request.
+	ServerOPTIONS http.Handler
+
+	// Maximum number of automatic OPTIONS responses cached per matched
+	// routes and allowed preflight request headers, so storms of preflight
+	// requests do not recompute the allowed methods and CORS headers every
+	// time. The cache is invalidated when the routes change, see Version, or
+	// the CORS policy is replaced. When full, the least recently used
+	// response is evicted. Disabled if zero.
+	PreflightCacheSize int
+
+	// Cached OPTIONS responses, see PreflightCacheSize
+	preflights preflightCache
 
 	// Configurable http.Handler which is called when no matching route is
 	// found. If it is not set, http.NotFound is used.
+	// See SetNotFound for handlers of path prefixes.
 	NotFound http.Handler
 
+	// Configurable http.Handler which is called instead of NotFound for
+	// CONNECT requests which match no route, e.g. those of clients mistaking
+	// the server for a proxy, whose target is a host instead of a path.
+	ConnectNotFound http.Handler
+
+	// If enabled, requests with a method which is neither defined by the
+	// HTTP specification nor registered for any route, e.g. "FOO", are
+	// answered with 501 Not Implemented instead of 405 Method Not Allowed or
+	// 404 Not Found.
+	RejectUnknownMethods bool
+
+	// What to do with requests whose path is empty, see EmptyPath. CONNECT
+	// requests, whose authority-form target has no path, are always passed
+	// to ConnectNotFound.
+	EmptyPath EmptyPath
+
+	// If enabled when ServeFiles is called, the files are not registered as
+	// a route but served as fallthrough: GET and HEAD requests which are not
+	// matched by any route or mount are attempted against the file systems,
+	// in the order of registration, before they are answered with 404.
+	// Only existing files and directories are served. As the files do not
+	// occupy the path in the route tree, they can be served from the root next
+	// to other routes, e.g. the assets of a single-page application:
+	//
+	//	router.FileFallthrough = true
+	//	router.GET("/api/users/:id", getUser)
+	//	router.ServeFiles("/*filepath", http.Dir("dist"))
+	FileFallthrough bool
+
 	// Configurable http.Handler which is called when a request
 	// cannot be routed and HandleMethodNotAllowed is true.
 	// If it is not set, http.Error with http.StatusMethodNotAllowed is used.
@@ -78,11 +216,118 @@ type HttpRouter struct {
 	// is called.
 	MethodNotAllowed http.Handler
 
+	// If greater than zero, the router looks for registered routes within this
+	// edit distance of an unmatched request path (see drouter.Router.Suggest).
+	// The suggestions are listed in the default NotFound response and are
+	// passed to a custom NotFound handler in the request context, from which
+	// they can be retrieved with SuggestionsFromContext.
+	SuggestDistance int
+
+	// Function which is called for every request answered with 404 or 405,
+	// after the NotFound or MethodNotAllowed handler ran, and for requests
+	// rejected with 400 or 501. The decision
+	// describes why the request could not be routed, which makes it suitable
+	// to feed dashboards about broken links and misbehaving clients.
+	OnNoMatch func(method, path string, decision Decision)
+
+	// Optional bus notified about added and removed routes, swapped route
+	// tables and requests which could not be routed.
+	Events *EventBus
+
+	// Optional resolver deriving the client IP of requests passing through
+	// trusted reverse proxies. The resolved address is stored in the request
+	// context, see ClientIP.
+	ProxyResolver *ProxyResolver
+
+	// If set, every response carries a header with this name, e.g.
+	// "X-Route-Version", holding the version of the route table which served
+	// the request. See Version.
+	VersionHeader string
+
+	// Optional budget disabling routes which panic repeatedly
+	PanicBudget *PanicBudget
+
+	// Optional debug headers naming the matched route pattern, see
+	// RouteHeaders
+	RouteHeaders *RouteHeaders
+
+	// Optional gzip compression of requests and responses, which counts the
+	// bytes per route, see CompressionStats.
+	Compression *Compression
+
+	// Optional sampler selecting a fraction of the requests per matched route,
+	// e.g. to attribute CPU profiles to route patterns.
+	Sampler *Sampler
+
+	// Optional recorder capturing the requests of selected routes in a
+	// replayable format.
+	Recorder *Recorder
+
+	// Library completing the WebSocket handshakes of the routes registered
+	// with WebSocket. It must be set before these routes are registered.
+	WebSocketUpgrader WebSocketUpgrader
+
+	// Optional tracing starting a span per request named after the matched
+	// route pattern, see Tracing.
+	Tracing *Tracing
+
+	// Optional logger receiving an access log entry after each request, with
+	// the method, path, matched route pattern, number of params, status,
+	// size of the response body and duration, followed by the values of the
+	// params as returned by LogFields, redacted as set with
+	// SetRedactedParams and the Redaction policy. The route is empty and the
+	// number of params zero for requests which were not routed to a handle.
+	Logger Logger
+
+	// Optional in-memory log of the last routing decisions, recording the
+	// same requests as Logger, see DecisionLog.
+	Decisions *DecisionLog
+
+	// Optional policy masking the values of sensitive params in the access
+	// log, the DecisionLog and traces, see RedactionPolicy.
+	Redaction *RedactionPolicy
+
+	// Path prefixes of a legacy URL scheme. Requests below one of these
+	// prefixes which end up in the NotFound handler are counted and reported
+	// to LegacyMissSink, so migrations can discover old URLs which still
+	// receive traffic and need a redirect.
+	LegacyPrefixes []string
+
+	// Function receiving the legacy misses recorded for LegacyPrefixes.
+	// Logging of legacy misses is disabled if it is not set.
+	LegacyMissSink func(LegacyMiss)
+
+	// Maximum number of distinct paths whose legacy misses are aggregated.
+	// If the limit is reached, the path with the fewest misses is dropped in
+	// favor of a new one, so clients scanning for paths can not grow the
+	// aggregation without bounds. Defaults to DefaultLegacyMissLimit if zero.
+	LegacyMissLimit int
+
+	// Aggregated legacy misses per path
+	legacyMisses legacyMissLog
+
+	// Translators mapping errors, which handles of this router report via
+	// Error or ErrorStatus, onto status codes. A router mounted into another
+	// one forms a group: its translators only apply to its own routes and
+	// take precedence over those of the parent router.
+	ErrorTranslators []ErrorTranslator
+
+	// Function handling the errors returned by handles registered with
+	// HandleE or adapted with E. If it is not set, Error is used, which
+	// replies with the status code ErrorStatus returns for the error.
+	ErrorHandler func(http.ResponseWriter, *http.Request, error)
+
+	// Registered plugins and the chain of their dispatch hooks, see Register
+	plugins    []Plugin
+	dispatcher http.Handler
+
 	// Function to handle panics recovered from http handlers.
 	// It should be used to generate a error page and return the http error code
 	// 500 (Internal Server Error).
 	// The handler can be used to keep your server from crashing because of
 	// unrecovered panics.
+	// Panics with http.ErrAbortHandler, which abort the response on purpose,
+	// are not passed to it.
 	PanicHandler func(http.ResponseWriter, *http.Request, interface{})
 }
 
@@ -104,92 +349,43 @@ func New() *HttpRouter {
 		RedirectFixedPath:      true,
 		HandleMethodNotAllowed: true,
 		HandleOPTIONS:          true,
+		UnescapePathValues:     true,
 	}
 }
 
-func (r *HttpRouter) getParams() *drouter.Params {
-	ps, _ := r.paramsPool.Get().(*drouter.Params)
-	*ps = (*ps)[0:0] // reset slice
-	return ps
+// GET is a shortcut for router.Handle(http.MethodGet, path, handle, middleware...)
+func (r *HttpRouter) GET(path string, handle HttpHandle, middleware ...Middleware) {
+	r.Handle(http.MethodGet, path, handle, middleware...)
 }
 
-func (r *HttpRouter) putParams(ps *drouter.Params) {
-	if ps != nil {
-		r.paramsPool.Put(ps)
-	}
+// HEAD is a shortcut for router.Handle(http.MethodHead, path, handle, middleware...)
+func (r *HttpRouter) HEAD(path string, handle HttpHandle, middleware ...Middleware) {
+	r.Handle(http.MethodHead, path, handle, middleware...)
 }
 
-func (r *HttpRouter) lazyInitParamsPool() {
-	if !(r.paramsPool.New == nil) {
-		return
-	}
-
-	r.paramsPool.New = func() interface{} {
-		ps := make(drouter.Params, 0, r.maxParams)
-		return &ps
-	}
+// OPTIONS is a shortcut for router.Handle(http.MethodOptions, path, handle, middleware...)
+func (r *HttpRouter) OPTIONS(path string, handle HttpHandle, middleware ...Middleware) {
+	r.Handle(http.MethodOptions, path, handle, middleware...)
 }
 
-func (r *HttpRouter) updateMaxParams(path string, varsCount uint16) {
-	if paramsCount := drouter.CountParams(path); paramsCount+varsCount > r.maxParams {
-		r.maxParams = paramsCount + varsCount
-	}
+// POST is a shortcut for router.Handle(http.MethodPost, path, handle, middleware...)
+func (r *HttpRouter) POST(path string, handle HttpHandle, middleware ...Middleware) {
+	r.Handle(http.MethodPost, path, handle, middleware...)
 }
 
-func (r *HttpRouter) saveMatchedRoutePath(path string, handle HttpHandle) HttpHandle {
-	return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
-		if ps == nil {
-			psp := r.getParams()
-			ps = (*psp)[0:1]
-			ps[0] = drouter.Param{
-				Key:   drouter.MatchedRoutePathParam,
-				Value: path,
-			}
-			handle(w, req, ps)
-			r.putParams(psp)
-		} else {
-			ps = append(ps, drouter.Param{
-				Key:   drouter.MatchedRoutePathParam,
-				Value: path,
-			})
-			handle(w, req, ps)
-		}
-	}
-}
-
-// GET is a shortcut for router.Handle(http.MethodGet, path, handle)
-func (r *HttpRouter) GET(path string, handle HttpHandle) {
-	r.Handle(http.MethodGet, path, handle)
-}
-
-// HEAD is a shortcut for router.Handle(http.MethodHead, path, handle)
-func (r *HttpRouter) HEAD(path string, handle HttpHandle) {
-	r.Handle(http.MethodHead, path, handle)
-}
-
-// OPTIONS is a shortcut for router.Handle(http.MethodOptions, path, handle)
-func (r *HttpRouter) OPTIONS(path string, handle HttpHandle) {
-	r.Handle(http.MethodOptions, path, handle)
-}
-
-// POST is a shortcut for router.Handle(http.MethodPost, path, handle)
-func (r *HttpRouter) POST(path string, handle HttpHandle) {
-	r.Handle(http.MethodPost, path, handle)
+// PUT is a shortcut for router.Handle(http.MethodPut, path, handle, middleware...)
+func (r *HttpRouter) PUT(path string, handle HttpHandle, middleware ...Middleware) {
+	r.Handle(http.MethodPut, path, handle, middleware...)
 }
 
-// PUT is a shortcut for router.Handle(http.MethodPut, path, handle)
-func (r *HttpRouter) PUT(path string, handle HttpHandle) {
-	r.Handle(http.MethodPut, path, handle)
+// PATCH is a shortcut for router.Handle(http.MethodPatch, path, handle, middleware...)
+func (r *HttpRouter) PATCH(path string, handle HttpHandle, middleware ...Middleware) {
+	r.Handle(http.MethodPatch, path, handle, middleware...)
 }
 
-// PATCH is a shortcut for router.Handle(http.MethodPatch, path, handle)
-func (r *HttpRouter) PATCH(path string, handle HttpHandle) {
-	r.Handle(http.MethodPatch, path, handle)
-}
-
-// DELETE is a shortcut for router.Handle(http.MethodDelete, path, handle)
-func (r *HttpRouter) DELETE(path string, handle HttpHandle) {
-	r.Handle(http.MethodDelete, path, handle)
+// DELETE is a shortcut for router.Handle(http.MethodDelete, path, handle, middleware...)
+func (r *HttpRouter) DELETE(path string, handle HttpHandle, middleware ...Middleware) {
+	r.Handle(http.MethodDelete, path, handle, middleware...)
 }
 
 // Handle registers a new request handle with the given path and method.
@@ -200,56 +396,137 @@ func (r *HttpRouter) DELETE(path string, handle HttpHandle) {
 // This function is intended for bulk loading and to allow the usage of less
 // frequently used, non-standardized or custom methods (e.g. for internal
 // communication with a proxy).
-func (r *HttpRouter) Handle(method, path string, handle HttpHandle) {
-	varsCount := uint16(0)
+//
+// The optional middleware only applies to this route. The first middleware
+// is the outermost one, i.e. it is invoked first.
+func (r *HttpRouter) Handle(method, path string, handle HttpHandle, middleware ...Middleware) {
+	if err := r.TryHandle(method, path, handle, middleware...); err != nil {
+		panic(err.Error())
+	}
+}
 
+// TryHandle is like Handle, but returns a *drouter.RouteError instead of
+// panicking if the route can not be registered, e.g. because the path
+// conflicts with a registered route. This allows to validate routes loaded
+// from configuration files gracefully. The router is left unchanged if an
+// error is returned.
+func (r *HttpRouter) TryHandle(method, path string, handle HttpHandle, middleware ...Middleware) error {
+	return r.tryHandle(method, path, handle, handle, middleware)
+}
+
+// tryHandle registers the route. The origin is the handle or handler as
+// passed by the user, which identifies the route's handler, see HandlerID.
+func (r *HttpRouter) tryHandle(method, path string, handle HttpHandle, origin interface{}, middleware []Middleware) error {
 	if method == "" {
-		panic("method must not be empty")
+		return &drouter.RouteError{Kind: drouter.InvalidMethod, Path: path, Message: "method must not be empty"}
 	}
 	if len(path) < 1 || path[0] != '/' {
-		panic("path must begin with '/' in path '" + path + "'")
+		return &drouter.RouteError{Kind: drouter.InvalidPath, Path: path, Message: "path must begin with '/' in path '" + path + "'"}
 	}
 	if handle == nil {
-		panic("handle must not be nil")
+		return &drouter.RouteError{Kind: drouter.InvalidHandle, Path: path, Message: "handle must not be nil"}
+	}
+
+	params := r.routeParams(path)
+	if r.ParamsOverflow == ParamsReject && r.ParamsCapacity > 0 && int(params) > r.ParamsCapacity {
+		return &drouter.RouteError{
+			Kind: drouter.TooManyParams,
+			Path: path,
+			Message: "route needs " + strconv.Itoa(int(params)) + " params, but the capacity is " +
+				strconv.Itoa(r.ParamsCapacity) + " in path '" + path + "'",
+		}
 	}
 
-	if r.SaveMatchedRoutePath {
-		varsCount++
-		handle = r.saveMatchedRoutePath(path, handle)
+	rt := &route{
+		method: method,
+		path:   path,
+		origin: origin,
+		params: params,
+	}
+	if method == http.MethodGet {
+		handle = rt.withHeadHook(handle)
+	}
+
+	if r.CheckCanceled {
+		handle = r.checkedChain(handle, middleware)
+	} else {
+		handle = chain(handle, middleware)
 	}
 
-	if r.routers == nil {
-		r.routers = make(map[string]*drouter.Router)
+	handle, err := r.pluginRoute(method, path, handle)
+	if err != nil {
+		return err
 	}
+	rt.handle = handle
 
-	router := r.routers[method]
+	t := r.mutableTable()
+	router := t.routers[method]
 	if router == nil {
-		router = drouter.New()
-		r.routers[method] = router
+		router = drouter.New[*route]()
+	}
+
+	if err = router.TryAddRoute(path, rt); err != nil {
+		return err
+	}
+
+	if t.routers[method] == nil {
+		t.setRouter(method, router)
+	}
 
-		r.globalAllowed = r.allowed("*", "")
+	t.updateMaxParams(params, r.ParamsCapacity)
+	t.lazyInitParamsPool()
+	r.bumpVersion()
+	r.emit(RouteAdded, method, path, nil)
+	return nil
+}
+
+// Remove removes the route registered with exactly the given method and
+// path, e.g. "/users/:id", and reports whether there was one.
+// Like the registration of routes, it must not be called concurrently with
+// ServeHTTP.
+func (r *HttpRouter) Remove(method, path string) bool {
+	t := r.mutableTable()
+	rt := t.lookupRoute(method, path)
+	if rt == nil {
+		return false
 	}
 
-	router.AddRoute(path, handle)
+	router := t.routers[method]
+	router.RemoveRoute(path)
+	if router.Len() == 0 {
+		t.setRouter(method, nil)
+	}
+
+	if rt.slo != nil {
+		for i := range t.slos {
+			if t.slos[i] == rt {
+				t.slos = append(t.slos[:i], t.slos[i+1:]...)
+				break
+			}
+		}
+	}
 
-	r.updateMaxParams(path, varsCount)
-	r.lazyInitParamsPool()
+	r.bumpVersion()
+	r.emit(RouteRemoved, method, path, nil)
+	return true
 }
 
 // Handler is an adapter which allows the usage of an http.Handler as a
 // request handle.
-// The Params are available in the request context under ParamsKey.
+// The Params are available in the request context under ParamsKey. They are
+// reused once the handler returned, see drouter.Params.
 func (r *HttpRouter) Handler(method, path string, handler http.Handler) {
-	r.Handle(method, path,
-		func(w http.ResponseWriter, req *http.Request, p drouter.Params) {
-			if len(p) > 0 {
-				ctx := req.Context()
-				ctx = context.WithValue(ctx, drouter.ParamsKey, p)
-				req = req.WithContext(ctx)
-			}
-			handler.ServeHTTP(w, req)
-		},
-	)
+	handle := func(w http.ResponseWriter, req *http.Request, p drouter.Params) {
+		if len(p) > 0 {
+			ctx := req.Context()
+			ctx = context.WithValue(ctx, drouter.ParamsKey, p)
+			req = req.WithContext(ctx)
+		}
+		handler.ServeHTTP(w, req)
+	}
+	if err := r.tryHandle(method, path, handle, handler, nil); err != nil {
+		panic(err.Error())
+	}
 }
 
 // HandlerFunc is an adapter which allows the usage of an http.HandlerFunc as a
@@ -264,53 +541,96 @@ func (r *HttpRouter) HandlerFunc(method, path string, handler http.HandlerFunc)
 // For example if root is "/etc" and *filepath is "passwd", the local file
 // "/etc/passwd" would be served.
 // Internally a http.FileServer is used, therefore http.NotFound is used instead
-// of the Router's NotFound handler.
+// of the Router's NotFound handler. See ServeFilesWith and Files.RouterNotFound
+// for handling missing files.
 // To use the operating system's file system implementation,
 // use http.Dir:
 // router.ServeFiles("/src/*filepath", http.Dir("/var/www"))
+// See FileFallthrough for serving files only if no route matches.
 func (r *HttpRouter) ServeFiles(path string, root http.FileSystem) {
+	r.ServeFilesWith(path, Files{Root: root})
+}
+
+// serveFiles registers the server for the files, see ServeFiles.
+func (r *HttpRouter) serveFiles(path string, files Files, fileServer http.Handler) {
+	if files.Root == nil {
+		panic("file system root must not be nil in path '" + path + "'")
+	}
 	if len(path) < 10 || path[len(path)-10:] != "/*filepath" {
 		panic("path must end with /*filepath in path '" + path + "'")
 	}
 
-	fileServer := http.FileServer(root)
+	if r.FileFallthrough {
+		if strings.ContainsAny(path[:len(path)-10], ":*") {
+			panic("fallthrough file path must not contain other wildcards in path '" + path + "'")
+		}
+		t := r.mutableTable()
+		t.files = append(t.files, &fileMount{
+			prefix: path[:len(path)-9],
+			root:   files.Root,
+			server: fileServer,
+		})
+		r.bumpVersion()
+		return
+	}
 
-	r.GET(path, func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
-		req.URL.Path = ps.ByName("filepath")
+	handle := func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		name := ps.ByName("filepath")
+		if (files.OnMissing != nil || files.RouterNotFound) && !fileExists(files.Root, name) {
+			if files.OnMissing != nil {
+				files.OnMissing(w, req, ps)
+			} else {
+				r.handleNotFound(r.loadTable(), w, req, Decision{
+					Status:      http.StatusNotFound,
+					KnownMethod: true,
+				})
+			}
+			return
+		}
+		req.URL.Path = name
 		fileServer.ServeHTTP(w, req)
-	})
+	}
+	if err := r.tryHandle(http.MethodGet, path, handle, fileServer, nil); err != nil {
+		panic(err.Error())
+	}
 }
 
 func (r *HttpRouter) recv(w http.ResponseWriter, req *http.Request) {
 	if rcv := recover(); rcv != nil {
+		// Aborting the response on purpose is not an error to handle
+		if rcv == http.ErrAbortHandler {
+			panic(rcv)
+		}
 		r.PanicHandler(w, req, rcv)
 	}
 }
 
-func (r *HttpRouter) allowed(path, reqMethod string) (allow string) {
+func (t *routeTable) allowed(path, reqMethod string) (allow string) {
 	allowed := make([]string, 0, 9)
 
 	if path == "*" { // server-wide
 		// empty method is used for internal calls to refresh the cache
 		if reqMethod == "" {
-			for method := range r.routers {
+			for method := range t.routers {
 				if method == http.MethodOptions {
 					continue
 				}
 				// Add request method to list of allowed methods
 				allowed = append(allowed, method)
 			}
+		} else if t.mounts != nil {
+			return t.mountsAllowed(t.globalAllowed)
 		} else {
-			return r.globalAllowed
+			return t.globalAllowed
 		}
 	} else { // specific path
-		for method := range r.routers {
+		for method := range t.routers {
 			// Skip the requested method - we already tried this one
 			if method == reqMethod || method == http.MethodOptions {
 				continue
 			}
 
-			handler, _ := r.routers[method].Lookup(path, nil)
+			handler, _ := t.routers[method].Lookup(path, nil)
 			if handler != nil {
 				// Add request method to list of allowed methods
 				allowed = append(allowed, method)
@@ -340,68 +660,159 @@ func (r *HttpRouter) allowed(path, reqMethod string) (allow string) {
 
 // ServeHTTP makes the router implement the http.Handler interface.
 func (r *HttpRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.Logger != nil || r.Decisions != nil {
+		aw := &accessWriter{statusWriter: statusWriter{ResponseWriter: w}}
+		defer r.logAccess(aw, req, time.Now())
+		w = aw
+	}
+
 	if r.PanicHandler != nil {
 		defer r.recv(w, req)
 	}
 
-	path := req.URL.Path
+	if r.dispatcher != nil {
+		r.dispatcher.ServeHTTP(w, req)
+		return
+	}
+	r.dispatch(w, req)
+}
+
+// dispatch routes the request to its handle.
+func (r *HttpRouter) dispatch(w http.ResponseWriter, req *http.Request) {
+	// All decisions for this request are made with the same route table
+	t := r.loadTable()
+
+	if r.VersionHeader != "" {
+		w.Header().Set(r.VersionHeader, strconv.FormatUint(t.version, 10))
+	}
+
+	if r.ProxyResolver != nil {
+		if ip := r.ProxyResolver.ClientIP(req); ip != nil {
+			req = req.WithContext(context.WithValue(req.Context(), ClientIPKey, ip))
+		}
+	}
+
+	if r.CanonicalQuery != nil {
+		r.CanonicalQuery.apply(req)
+	}
+
+	if r.MethodOverride && req.Method == http.MethodPost {
+		req = overrideMethod(req)
+	}
+
+	if len(t.rewrites) > 0 && t.applyRewrites(w, req) {
+		return
+	}
+
+	path := r.lookupPath(req)
+	if path == "" || path == "*" {
+		var done bool
+		if path, done = r.serveTarget(t, w, req, path); done {
+			return
+		}
+	}
+	router := t.routers[req.Method]
+	tsr := false
 
-	if router := r.routers[req.Method]; router != nil {
-		ps := r.getParams()
-		if handle, tsr := router.Lookup(path, ps); handle != nil {
+	if router != nil {
+		ps := t.getParams()
+		var rt *route
+		if rt, tsr = router.Lookup(path, ps); rt != nil {
 			if ps != nil {
-				handle.(HttpHandle)(w, req, *ps)
-				r.putParams(ps)
+				if r.UseRawPath && r.UnescapePathValues {
+					unescapeParams(*ps)
+				}
+				r.serve(t, rt, w, req, *ps)
+				t.putParams(ps)
 			} else {
-				handle.(HttpHandle)(w, req, nil)
+				r.serve(t, rt, w, req, nil)
 			}
 			return
-		} else if req.Method != http.MethodConnect && path != "/" {
-			// Moved Permanently, request with GET method
-			code := http.StatusMovedPermanently
-			if req.Method != http.MethodGet {
-				// Permanent Redirect, request with same method
-				code = http.StatusPermanentRedirect
-			}
+		}
+		t.putParams(ps)
+	}
 
-			if (bool)(tsr) && r.RedirectTrailingSlash {
-				if len(path) > 1 && path[len(path)-1] == '/' {
-					req.URL.Path = path[:len(path)-1]
-				} else {
-					req.URL.Path = path + "/"
-				}
-				http.Redirect(w, req, req.URL.String(), code)
-				return
+	if req.Method == http.MethodHead && r.AutoHEAD && r.serveAutoHEAD(t, w, req, path) {
+		return
+	}
+
+	// Requests not matched by a route of this router may belong to a mount
+	if t.mounts != nil && t.serveMount(w, req) {
+		return
+	}
+
+	if len(t.files) > 0 && t.serveFiles(w, req) {
+		return
+	}
+
+	if router != nil && path != "/" && r.redirects(req.Method) {
+		// Moved Permanently, request with GET method
+		code := http.StatusMovedPermanently
+		if req.Method != http.MethodGet {
+			// Permanent Redirect, request with same method, which includes
+			// non-standard methods like PROPFIND
+			code = http.StatusPermanentRedirect
+		}
+
+		if (bool)(tsr) && r.RedirectTrailingSlash {
+			if len(path) > 1 && path[len(path)-1] == '/' {
+				r.redirect(w, req, path[:len(path)-1], code)
+			} else {
+				r.redirect(w, req, path+"/", code)
 			}
+			return
+		}
 
-			// Try to fix the request path
-			if r.RedirectFixedPath {
-				fixedPath, found := router.FindCaseInsensitivePath(
-					drouter.CleanPath(path),
-					r.RedirectTrailingSlash,
-				)
-				if found {
-					req.URL.Path = fixedPath
-					http.Redirect(w, req, req.URL.String(), code)
-					return
-				}
+		// Try to fix the request path
+		if r.RedirectFixedPath {
+			fixedPath, found := router.FindCaseInsensitivePath(
+				drouter.CleanPath(path),
+				r.RedirectTrailingSlash,
+			)
+			if found {
+				r.redirect(w, req, fixedPath, code)
+				return
 			}
 		}
 	}
 
+	if router == nil && r.RejectUnknownMethods && !standardMethod(req.Method) {
+		http.Error(w,
+			http.StatusText(http.StatusNotImplemented),
+			http.StatusNotImplemented,
+		)
+		r.noMatch(req.Method, path, Decision{Status: http.StatusNotImplemented})
+		return
+	}
+
 	if req.Method == http.MethodOptions && r.HandleOPTIONS {
 		// Handle OPTIONS requests
-		if allow := r.allowed(path, http.MethodOptions); allow != "" {
+		key := r.preflightKey(t, req, path, r.CORS)
+		if r.cachedPreflight(w, t, key, r.CORS) {
+			return
+		}
+		if allow := t.allowed(path, http.MethodOptions); allow != "" {
 			w.Header().Set("Allow", allow)
+			if r.CORS != nil {
+				sw := &statusWriter{ResponseWriter: w}
+				if r.CORS.preflight(sw, req, allow) {
+					r.storePreflight(t, key, r.CORS, w.Header(), sw.status)
+					return
+				}
+			}
 			if r.GlobalOPTIONS != nil {
 				r.GlobalOPTIONS.ServeHTTP(w, req)
+			} else {
+				r.storePreflight(t, key, r.CORS, w.Header(), 0)
 			}
 			return
 		}
 	} else if r.HandleMethodNotAllowed { // Handle 405
-		if allow := r.allowed(path, req.Method); allow != "" {
+		if allow := t.allowed(path, req.Method); allow != "" {
 			w.Header().Set("Allow", allow)
-			if r.MethodNotAllowed != nil {
+			if h := t.methodNotAllowed(path); h != nil {
+				h.ServeHTTP(w, req)
+			} else if r.MethodNotAllowed != nil {
 				r.MethodNotAllowed.ServeHTTP(w, req)
 			} else {
 				http.Error(w,
@@ -409,14 +820,93 @@ func (r *HttpRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 					http.StatusMethodNotAllowed,
 				)
 			}
+			r.noMatch(req.Method, path, Decision{
+				Status:        http.StatusMethodNotAllowed,
+				Allow:         allow,
+				TrailingSlash: tsr,
+				KnownMethod:   router != nil,
+			})
 			return
 		}
 	}
 
 	// Handle 404
-	if r.NotFound != nil {
-		r.NotFound.ServeHTTP(w, req)
+	r.handleNotFound(t, w, req, Decision{
+		Status:        http.StatusNotFound,
+		TrailingSlash: tsr,
+		KnownMethod:   router != nil,
+	})
+}
+
+// standardMethod reports whether the method is defined by the HTTP
+// specification, see RejectUnknownMethods.
+func standardMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// redirects reports whether requests with the method may be redirected to
+// the path with (without) the trailing slash or to the fixed path.
+func (r *HttpRouter) redirects(method string) bool {
+	if method == http.MethodConnect {
+		return false
+	}
+	for _, m := range r.NoRedirectMethods {
+		if m == method {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *HttpRouter) handleNotFound(t *routeTable, w http.ResponseWriter, req *http.Request, decision Decision) {
+	if r.LegacyMissSink != nil && len(r.LegacyPrefixes) > 0 {
+		r.recordLegacyMiss(req)
+	}
+
+	var suggestions []string
+	if r.SuggestDistance > 0 {
+		suggestions = t.suggest("", req.URL.Path, r.SuggestDistance)
+	}
+
+	var notFound http.Handler
+	if req.Method == http.MethodConnect {
+		notFound = r.ConnectNotFound
+	}
+	if notFound == nil {
+		notFound = t.notFound(req.URL.Path)
+	}
+	if notFound == nil {
+		notFound = r.NotFound
+	}
+
+	if notFound != nil {
+		if len(suggestions) > 0 {
+			ctx := context.WithValue(req.Context(), SuggestionsKey, suggestions)
+			req = req.WithContext(ctx)
+		}
+		notFound.ServeHTTP(w, req)
+	} else if len(suggestions) > 0 {
+		notFoundWithSuggestions(w, suggestions)
 	} else {
 		http.NotFound(w, req)
 	}
+
+	decision.Suggestions = suggestions
+	r.noMatch(req.Method, req.URL.Path, decision)
+}
+
+// noMatch reports a request which could not be routed to OnNoMatch and the
+// event bus.
+func (r *HttpRouter) noMatch(method, path string, decision Decision) {
+	if r.OnNoMatch != nil {
+		r.OnNoMatch(method, path, decision)
+	}
+	if r.Events != nil {
+		r.emit(LookupMiss, method, path, &decision)
+	}
 }
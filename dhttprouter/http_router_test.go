@@ -11,6 +11,7 @@ import (
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/thekhanj/drouter"
 )
@@ -213,6 +214,52 @@ func TestRouterInvalidInput(t *testing.T) {
 	}
 }
 
+func TestRouterTryHandle(t *testing.T) {
+	router := New()
+
+	handle := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
+
+	if err := router.TryHandle(http.MethodGet, "/users/:id", handle); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	version := router.Version()
+
+	tests := []struct {
+		method, path string
+		handle       HttpHandle
+		kind         drouter.RouteErrorKind
+	}{
+		{"", "/", handle, drouter.InvalidMethod},
+		{http.MethodGet, "users", handle, drouter.InvalidPath},
+		{http.MethodGet, "/", nil, drouter.InvalidHandle},
+		{http.MethodGet, "/files/*path/x", handle, drouter.InvalidWildcard},
+		{http.MethodGet, "/users/:name", handle, drouter.Conflict},
+		{http.MethodGet, "/users/:id", handle, drouter.Conflict},
+	}
+	for _, tr := range tests {
+		err := router.TryHandle(tr.method, tr.path, tr.handle)
+		var rerr *drouter.RouteError
+		if !errors.As(err, &rerr) || rerr.Kind != tr.kind {
+			t.Errorf("%s %s: expected %v error, got %v", tr.method, tr.path, tr.kind, err)
+		}
+	}
+
+	if router.Version() != version {
+		t.Error("failed registrations changed the router version")
+	}
+	if routes := router.Routes(); len(routes) != 1 {
+		t.Errorf("unexpected routes after failed registrations: %v", routes)
+	}
+
+	// Handle panics with the message of the error
+	recv := catchPanic(func() {
+		router.GET("/users/:name", handle)
+	})
+	if msg, _ := recv.(string); msg == "" {
+		t.Errorf("unexpected panic value: %v", recv)
+	}
+}
+
 func TestRouterChaining(t *testing.T) {
 	router1 := New()
 	router2 := New()
@@ -261,17 +308,18 @@ func BenchmarkAllowed(b *testing.B) {
 	router := New()
 	router.POST("/path", handlerFunc)
 	router.GET("/path", handlerFunc)
+	table := router.loadTable()
 
 	b.Run("Global", func(b *testing.B) {
 		b.ReportAllocs()
 		for i := 0; i < b.N; i++ {
-			_ = router.allowed("*", http.MethodOptions)
+			_ = table.allowed("*", http.MethodOptions)
 		}
 	})
 	b.Run("Path", func(b *testing.B) {
 		b.ReportAllocs()
 		for i := 0; i < b.N; i++ {
-			_ = router.allowed("/path", http.MethodOptions)
+			_ = table.allowed("/path", http.MethodOptions)
 		}
 	})
 }
@@ -372,6 +420,56 @@ func TestRouterOPTIONS(t *testing.T) {
 	}
 }
 
+func TestRouterAllowCustomMethods(t *testing.T) {
+	handle := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
+
+	allow := func(router *HttpRouter, method, path string) string {
+		r, _ := http.NewRequest(method, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w.Header().Get("Allow")
+	}
+
+	router := New()
+	router.GET("/cache/:key", handle)
+	if got := allow(router, http.MethodOptions, "*"); got != "GET, OPTIONS" {
+		t.Errorf("unexpected server-wide Allow header: %q", got)
+	}
+
+	// Custom methods registered after the first requests
+	router.Handle("PURGE", "/cache/:key", handle)
+	router.Handle("LINK", "/cache/:key", handle)
+	tests := []struct {
+		method, path, allow string
+	}{
+		{http.MethodOptions, "*", "GET, LINK, OPTIONS, PURGE"},
+		{http.MethodOptions, "/cache/x", "GET, LINK, OPTIONS, PURGE"},
+		{http.MethodPost, "/cache/x", "GET, LINK, OPTIONS, PURGE"},
+		{"UNLINK", "/cache/x", "GET, LINK, OPTIONS, PURGE"},
+	}
+	for _, tr := range tests {
+		if got := allow(router, tr.method, tr.path); got != tr.allow {
+			t.Errorf("%s %s: unexpected Allow header: %q, want %q", tr.method, tr.path, got, tr.allow)
+		}
+	}
+
+	router.Remove("LINK", "/cache/:key")
+	if got := allow(router, http.MethodOptions, "*"); got != "GET, OPTIONS, PURGE" {
+		t.Errorf("unexpected server-wide Allow header after removal: %q", got)
+	}
+
+	// Methods of mounted routers, even if registered after mounting
+	admin := New()
+	router.Mount("/admin", admin)
+	admin.Handle("REPORT", "/stats", handle)
+	if got := allow(router, http.MethodOptions, "*"); got != "GET, OPTIONS, PURGE, REPORT" {
+		t.Errorf("unexpected server-wide Allow header with mount: %q", got)
+	}
+	if got := allow(router, http.MethodGet, "/admin/stats"); got != "OPTIONS, REPORT" {
+		t.Errorf("unexpected Allow header of mounted route: %q", got)
+	}
+}
+
 func TestRouterNotAllowed(t *testing.T) {
 	handlerFunc := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
 
@@ -421,6 +519,76 @@ func TestRouterNotAllowed(t *testing.T) {
 	}
 }
 
+func TestRouterUnknownMethods(t *testing.T) {
+	handlerFunc := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
+
+	router := New()
+	router.GET("/path", handlerFunc)
+	var decisions []Decision
+	router.OnNoMatch = func(_, _ string, d Decision) {
+		decisions = append(decisions, d)
+	}
+
+	// Methods without any route are answered like registered ones
+	for _, method := range []string{"FOO", http.MethodConnect, http.MethodPost} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(method, "/path", nil))
+		if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "GET, OPTIONS" {
+			t.Errorf("%s: NotAllowed handling failed: Code=%d, Header=%v", method, w.Code, w.Header())
+		}
+	}
+	router.HandleMethodNotAllowed = false
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("FOO", "/path", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unexpected response code %d want %d", w.Code, http.StatusNotFound)
+	}
+
+	// Unknown methods are not implemented
+	router.HandleMethodNotAllowed = true
+	router.RejectUnknownMethods = true
+	router.AutoHEAD = true
+	decisions = nil
+	tests := []struct {
+		method, path string
+		code         int
+	}{
+		{"FOO", "/path", http.StatusNotImplemented},
+		{"FOO", "/missing", http.StatusNotImplemented},
+		{http.MethodPost, "/path", http.StatusMethodNotAllowed},
+		{http.MethodTrace, "/missing", http.StatusNotFound},
+		{http.MethodOptions, "/path", http.StatusOK},
+		{http.MethodHead, "/path", http.StatusOK},
+		{http.MethodHead, "/missing", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.code {
+			t.Errorf("%s %s: unexpected response code %d want %d", tt.method, tt.path, w.Code, tt.code)
+		}
+	}
+	if len(decisions) != 5 || decisions[0].Status != http.StatusNotImplemented {
+		t.Errorf("wrong decisions %+v", decisions)
+	}
+
+	// Unmatched CONNECT requests have their own handler
+	router.RejectUnknownMethods = false
+	router.ConnectNotFound = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodConnect, "example.com:443", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("unexpected response code %d want %d", w.Code, http.StatusForbidden)
+	}
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unexpected response code %d want %d", w.Code, http.StatusNotFound)
+	}
+}
+
 func TestRouterNotFound(t *testing.T) {
 	handlerFunc := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
 
@@ -486,6 +654,36 @@ func TestRouterNotFound(t *testing.T) {
 	}
 }
 
+func TestRouterRedirectCustomMethods(t *testing.T) {
+	handlerFunc := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
+
+	router := New()
+	router.Handle("PROPFIND", "/calendars/", handlerFunc)
+	router.Handle("REPORT", "/calendars/:id", handlerFunc)
+	router.Handle("MKCOL", "/files/", handlerFunc)
+	router.NoRedirectMethods = []string{"MKCOL"}
+
+	tests := []struct {
+		method, path string
+		code         int
+		location     string
+	}{
+		{"PROPFIND", "/calendars", http.StatusPermanentRedirect, "/calendars/"},         // TSR +/
+		{"REPORT", "/calendars/home/", http.StatusPermanentRedirect, "/calendars/home"}, // TSR -/
+		{"PROPFIND", "/CALENDARS/", http.StatusPermanentRedirect, "/calendars/"},        // Fixed Case
+		{"MKCOL", "/files", http.StatusNotFound, ""},                                    // Opted out
+		{"MKCOL", "/FILES/", http.StatusNotFound, ""},                                   // Opted out
+	}
+	for _, tt := range tests {
+		r, _ := http.NewRequest(tt.method, tt.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != tt.code || w.Header().Get("Location") != tt.location {
+			t.Errorf("%s %s: want %d %q, got %d %q", tt.method, tt.path, tt.code, tt.location, w.Code, w.Header().Get("Location"))
+		}
+	}
+}
+
 func TestRouterPanicHandler(t *testing.T) {
 	router := New()
 	panicHandled := false
@@ -618,6 +816,30 @@ func TestRouterMatchedRoutePath(t *testing.T) {
 	}
 }
 
+func TestRouterMatchedRoutePathToggled(t *testing.T) {
+	var matched, id string
+	router := New()
+	router.ParamsCapacity = 1
+	router.GET("/users/:id", func(_ http.ResponseWriter, _ *http.Request, ps drouter.Params) {
+		matched, id = ps.MatchedRoutePath(), ps.ByName("id")
+	})
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	if matched != "" || id != "1" {
+		t.Errorf("wrong params while disabled: %q, %q", matched, id)
+	}
+
+	// Routes registered before the option was enabled are covered
+	router.SaveMatchedRoutePath = true
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/2", nil))
+	if matched != "/users/:id" || id != "2" {
+		t.Errorf("wrong params while enabled: %q, %q", matched, id)
+	}
+	if stats := router.ParamsStats(); stats.Overflows != 0 {
+		t.Errorf("matched route path overflowed the params: %+v", stats)
+	}
+}
+
 type mockFileSystem struct {
 	opened bool
 }
@@ -646,3 +868,48 @@ func TestRouterServeFiles(t *testing.T) {
 		t.Error("serving file failed")
 	}
 }
+
+func TestRouterRemove(t *testing.T) {
+	handle := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
+
+	router := New()
+	router.GET("/users/:id", handle)
+	router.PUT("/users/:id", handle)
+	router.DELETE("/users/:id", handle)
+	router.SetSLO(http.MethodDelete, "/users/:id", SLO{Threshold: time.Second, Target: 0.99})
+	version := router.Version()
+
+	if router.Remove(http.MethodGet, "/users/42") {
+		t.Error("removed route by request path")
+	}
+	if !router.Remove(http.MethodDelete, "/users/:id") {
+		t.Error("failed to remove route")
+	}
+	if router.Remove(http.MethodDelete, "/users/:id") {
+		t.Error("removed route twice")
+	}
+	if router.Version() == version {
+		t.Error("version not bumped")
+	}
+	if len(router.SLOStats()) != 0 {
+		t.Error("SLO of removed route still reported")
+	}
+
+	r, _ := http.NewRequest(http.MethodDelete, "/users/42", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("removed route still served: Code=%d", w.Code)
+	}
+	if allow := w.Header().Get("Allow"); allow != "GET, OPTIONS, PUT" {
+		t.Errorf("unexpected Allow header value: %q", allow)
+	}
+
+	// Server-wide Allow header no longer lists the method
+	r, _ = http.NewRequest(http.MethodOptions, "*", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if allow := w.Header().Get("Allow"); allow != "GET, OPTIONS, PUT" {
+		t.Errorf("unexpected global Allow header value: %q", allow)
+	}
+}
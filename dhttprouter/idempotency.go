@@ -0,0 +1,278 @@
+package dhttprouter
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+// StoredResponse is a response kept for replaying it to retried requests.
+type StoredResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+
+	// Fingerprint of the body of the request which produced the response
+	Fingerprint string
+}
+
+// IdempotencyStore keeps the responses of requests by their idempotency key.
+// Implementations must be safe for concurrent use. Stores shared between
+// several instances of a service, e.g. backed by Redis, make retries hitting
+// another instance replay the response as well.
+type IdempotencyStore interface {
+	// Get returns the response stored for the key, if it did not expire.
+	Get(key string) (*StoredResponse, bool)
+
+	// Set stores the response for the key for the given duration.
+	Set(key string, response *StoredResponse, ttl time.Duration)
+}
+
+// Idempotency honors the Idempotency-Key request header, which clients send
+// to retry unsafe requests like POST without performing them twice. It can be
+// used as per-route Middleware via its Wrap method, e.g.
+//
+//	idem := &dhttprouter.Idempotency{TTL: time.Hour}
+//	router.POST("/payments", handle, idem.Wrap)
+//
+// The first response for a key is stored and replayed, marked with the
+// Idempotent-Replayed header, for all requests with the same key, method,
+// path and scope within the TTL, so clients can not receive the responses of
+// other clients reusing their keys. Requests reusing a key with another body
+// are rejected with 422 (Unprocessable Entity). Responses with a 5xx status
+// are not stored, so the request can be retried. While the first request is
+// in progress, requests with the same key are rejected with 409 (Conflict).
+// Bodies of requests with a key are read into memory to fingerprint them,
+// requests with bodies larger than MaxBody are rejected with 413 (Request
+// Entity Too Large). Requests without the header are passed to the handle
+// as they are.
+type Idempotency struct {
+	// Store for the responses. Defaults to an in-memory store, which is
+	// shared by all routes using this Idempotency.
+	Store IdempotencyStore
+
+	// Time a response is replayed for. Defaults to 24 hours.
+	TTL time.Duration
+
+	// Name of the request header holding the key. Defaults to
+	// Idempotency-Key.
+	Header string
+
+	// If enabled, requests without key are rejected with 400 (Bad Request).
+	Required bool
+
+	// Maximum number of body bytes of requests with a key. Defaults to 1 MiB.
+	MaxBody int64
+
+	// Optional function returning the scope of the keys of a request, e.g.
+	// the ID of the authenticated user, so keys of different clients never
+	// collide. Defaults to the Authorization header of the request or, if it
+	// is missing, its ClientIP. Scopes are hashed before they are used in
+	// the keys of the Store.
+	Scope func(*http.Request) string
+
+	mu       sync.Mutex
+	store    IdempotencyStore
+	inflight map[string]struct{}
+}
+
+// Wrap returns a handle which applies the idempotency keys of the requests to
+// the given handle.
+func (i *Idempotency) Wrap(handle HttpHandle) HttpHandle {
+	header := i.Header
+	if header == "" {
+		header = "Idempotency-Key"
+	}
+	ttl := i.TTL
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	maxBody := i.MaxBody
+	if maxBody <= 0 {
+		maxBody = 1 << 20
+	}
+
+	return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		key := req.Header.Get(header)
+		if key == "" {
+			if i.Required {
+				http.Error(w, "Missing "+header+" header", http.StatusBadRequest)
+				return
+			}
+			handle(w, req, ps)
+			return
+		}
+		key = req.Method + " " + req.URL.Path + " " + fingerprint([]byte(i.scope(req))) + " " + key
+
+		var body []byte
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.ContentLength > maxBody {
+				http.Error(w,
+					http.StatusText(http.StatusRequestEntityTooLarge),
+					http.StatusRequestEntityTooLarge,
+				)
+				return
+			}
+			var err error
+			if body, err = ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxBody)); err != nil {
+				// The reader fails after maxBody bytes if there are more
+				code := http.StatusBadRequest
+				if int64(len(body)) >= maxBody {
+					code = http.StatusRequestEntityTooLarge
+				}
+				http.Error(w, http.StatusText(code), code)
+				return
+			}
+			req.Body = readCloser{bytes.NewReader(body), req.Body}
+		}
+		fp := fingerprint(body)
+
+		store := i.getStore()
+		if resp, ok := store.Get(key); ok {
+			replay(w, resp, fp, header)
+			return
+		}
+
+		i.mu.Lock()
+		if _, ok := i.inflight[key]; ok {
+			i.mu.Unlock()
+			http.Error(w, "A request with the same "+header+" is in progress", http.StatusConflict)
+			return
+		}
+		if i.inflight == nil {
+			i.inflight = make(map[string]struct{})
+		}
+		i.inflight[key] = struct{}{}
+		i.mu.Unlock()
+
+		defer func() {
+			i.mu.Lock()
+			delete(i.inflight, key)
+			i.mu.Unlock()
+		}()
+
+		// The first request may have completed in the meantime
+		if resp, ok := store.Get(key); ok {
+			replay(w, resp, fp, header)
+			return
+		}
+
+		buf := &bufferedResponse{header: make(http.Header)}
+		handle(buf, req, ps)
+		if buf.status < 500 {
+			store.Set(key, &StoredResponse{
+				Status:      buf.status,
+				Header:      cloneHeader(buf.header),
+				Body:        append([]byte(nil), buf.body.Bytes()...),
+				Fingerprint: fp,
+			}, ttl)
+		}
+		buf.writeTo(w)
+	}
+}
+
+func (i *Idempotency) scope(req *http.Request) string {
+	if i.Scope != nil {
+		return i.Scope(req)
+	}
+	if auth := req.Header.Get("Authorization"); auth != "" {
+		return auth
+	}
+	return ClientIP(req).String()
+}
+
+// fingerprint returns the hex encoded SHA-256 hash of data.
+func fingerprint(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (i *Idempotency) getStore() IdempotencyStore {
+	if i.Store != nil {
+		return i.Store
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.store == nil {
+		i.store = &MemoryIdempotencyStore{}
+	}
+	return i.store
+}
+
+// replay writes a stored response, unless it was produced by a request with
+// another body than the one with the fingerprint fp.
+func replay(w http.ResponseWriter, resp *StoredResponse, fp, header string) {
+	if resp.Fingerprint != fp {
+		http.Error(w, header+" was used with another request body", http.StatusUnprocessableEntity)
+		return
+	}
+
+	h := w.Header()
+	for k, v := range resp.Header {
+		h[k] = append([]string(nil), v...)
+	}
+	h.Set("Idempotent-Replayed", "true")
+	if resp.Status != 0 {
+		w.WriteHeader(resp.Status)
+	}
+	w.Write(resp.Body)
+}
+
+func cloneHeader(h http.Header) http.Header {
+	c := make(http.Header, len(h))
+	for k, v := range h {
+		c[k] = append([]string(nil), v...)
+	}
+	return c
+}
+
+// MemoryIdempotencyStore is an IdempotencyStore keeping the responses in
+// memory. Expired responses are removed once a minute. The zero value is
+// ready to use.
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryIdempotencyEntry
+	lastSweep time.Time
+}
+
+type memoryIdempotencyEntry struct {
+	response *StoredResponse
+	expires  time.Time
+}
+
+// Get implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Get(key string) (*StoredResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	if !ok || !time.Now().Before(e.expires) {
+		return nil, false
+	}
+	return e.response, true
+}
+
+// Set implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Set(key string, response *StoredResponse, ttl time.Duration) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.entries == nil {
+		s.entries = make(map[string]memoryIdempotencyEntry)
+	}
+	if now.Sub(s.lastSweep) > time.Minute {
+		for k, e := range s.entries {
+			if !now.Before(e.expires) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	s.entries[key] = memoryIdempotencyEntry{response, now.Add(ttl)}
+}
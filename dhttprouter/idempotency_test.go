@@ -0,0 +1,231 @@
+package dhttprouter
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestIdempotency(t *testing.T) {
+	payments := 0
+	failing := false
+	handle := func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		if failing {
+			http.Error(w, "database down", http.StatusServiceUnavailable)
+			return
+		}
+		payments++
+		w.Header().Set("Location", "/payments/"+strconv.Itoa(payments))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("payment " + strconv.Itoa(payments)))
+	}
+
+	idem := &Idempotency{TTL: time.Hour}
+	router := New()
+	router.POST("/payments", handle, idem.Wrap)
+	router.POST("/refunds", handle, idem.Wrap)
+
+	do := func(path, key string) *httptest.ResponseRecorder {
+		r, _ := http.NewRequest(http.MethodPost, path, nil)
+		if key != "" {
+			r.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	tests := []struct {
+		path, key string
+		body      string
+		replayed  bool
+	}{
+		{"/payments", "a", "payment 1", false},
+		{"/payments", "a", "payment 1", true},
+		{"/payments", "b", "payment 2", false},
+		{"/payments", "", "payment 3", false},
+		{"/payments", "", "payment 4", false},
+		{"/refunds", "a", "payment 5", false},
+		{"/payments", "a", "payment 1", true},
+	}
+	for _, tr := range tests {
+		w := do(tr.path, tr.key)
+		replayed := w.Header().Get("Idempotent-Replayed") == "true"
+		if w.Code != http.StatusCreated || w.Body.String() != tr.body || replayed != tr.replayed {
+			t.Errorf("%s with key %q: Code=%d, Body=%q, replayed=%t", tr.path, tr.key, w.Code, w.Body.String(), replayed)
+		}
+		if loc := w.Header().Get("Location"); loc != "/payments/"+tr.body[len("payment "):] {
+			t.Errorf("%s with key %q: wrong Location header %q", tr.path, tr.key, loc)
+		}
+	}
+
+	// Server errors are not stored
+	failing = true
+	if w := do("/payments", "c"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("unexpected status: %d", w.Code)
+	}
+	failing = false
+	if w := do("/payments", "c"); w.Code != http.StatusCreated || w.Header().Get("Idempotent-Replayed") != "" {
+		t.Errorf("server error was replayed: Code=%d", w.Code)
+	}
+
+	// Requests without key
+	idem.Required = true
+	if w := do("/payments", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("request without key not rejected: Code=%d", w.Code)
+	}
+}
+
+func TestIdempotencyInFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	idem := &Idempotency{Header: "X-Request-Id"}
+	handle := idem.Wrap(func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		close(started)
+		<-release
+	})
+
+	r, _ := http.NewRequest(http.MethodPost, "/payments", nil)
+	r.Header.Set("X-Request-Id", "a")
+	done := make(chan struct{})
+	go func() {
+		handle(httptest.NewRecorder(), r, nil)
+		close(done)
+	}()
+	<-started
+
+	w := httptest.NewRecorder()
+	handle(w, r, nil)
+	if w.Code != http.StatusConflict {
+		t.Errorf("concurrent retry not rejected: Code=%d", w.Code)
+	}
+	close(release)
+	<-done
+}
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	var s MemoryIdempotencyStore
+	if _, ok := s.Get("a"); ok {
+		t.Error("empty store returned a response")
+	}
+
+	s.Set("a", &StoredResponse{Status: http.StatusOK}, time.Hour)
+	s.Set("b", &StoredResponse{Status: http.StatusOK}, -time.Second)
+	if resp, ok := s.Get("a"); !ok || resp.Status != http.StatusOK {
+		t.Error("stored response not returned")
+	}
+	if _, ok := s.Get("b"); ok {
+		t.Error("expired response returned")
+	}
+
+	// Expired responses are swept
+	s.lastSweep = time.Time{}
+	s.Set("c", &StoredResponse{}, time.Hour)
+	if _, ok := s.entries["b"]; ok {
+		t.Error("expired response not removed")
+	}
+}
+
+func TestIdempotencyScope(t *testing.T) {
+	calls := 0
+	handle := func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		calls++
+		body, _ := ioutil.ReadAll(req.Body)
+		w.Write([]byte(req.Header.Get("Authorization") + " " + string(body)))
+	}
+
+	idem := &Idempotency{}
+	router := New()
+	router.POST("/payments", handle, idem.Wrap)
+	router.POST("/custom", handle, (&Idempotency{Scope: func(req *http.Request) string {
+		return req.Header.Get("X-Tenant")
+	}}).Wrap)
+
+	do := func(path, auth, tenant, body string) *httptest.ResponseRecorder {
+		r, _ := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		r.RemoteAddr = "1.2.3.4:1234"
+		r.Header.Set("Idempotency-Key", "k")
+		r.Header.Set("Authorization", auth)
+		r.Header.Set("X-Tenant", tenant)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		return w
+	}
+
+	tests := []struct {
+		path, auth, tenant, body string
+		code                     int
+		response                 string
+		calls                    int
+	}{
+		{"/payments", "alice", "", "10", http.StatusOK, "alice 10", 1},
+		{"/payments", "alice", "", "10", http.StatusOK, "alice 10", 1},
+		// Another client reusing the key
+		{"/payments", "bob", "", "10", http.StatusOK, "bob 10", 2},
+		// Clients without credentials are scoped by address
+		{"/payments", "", "", "10", http.StatusOK, " 10", 3},
+		// A retry with another body
+		{"/payments", "alice", "", "20", http.StatusUnprocessableEntity, "", 3},
+		{"/custom", "alice", "t1", "10", http.StatusOK, "alice 10", 4},
+		{"/custom", "bob", "t1", "10", http.StatusOK, "alice 10", 4},
+		{"/custom", "bob", "t2", "10", http.StatusOK, "bob 10", 5},
+	}
+	for _, tt := range tests {
+		w := do(tt.path, tt.auth, tt.tenant, tt.body)
+		if w.Code != tt.code || (tt.response != "" && w.Body.String() != tt.response) || calls != tt.calls {
+			t.Errorf("%s %q %q %q: Code=%d, Body=%q, calls=%d", tt.path, tt.auth, tt.tenant, tt.body, w.Code, w.Body, calls)
+		}
+	}
+}
+
+func TestIdempotencyMaxBody(t *testing.T) {
+	calls := 0
+	idem := &Idempotency{MaxBody: 4}
+	router := New()
+	router.POST("/payments", func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		calls++
+		body, _ := ioutil.ReadAll(req.Body)
+		w.Write(body)
+	}, idem.Wrap)
+
+	tests := []struct {
+		body          string
+		contentLength int64
+		code          int
+	}{
+		{"abcd", 4, http.StatusOK},
+		{"abcde", 5, http.StatusRequestEntityTooLarge},
+		// Without a Content-Length, the body is cut off while reading
+		{"abcde", -1, http.StatusRequestEntityTooLarge},
+	}
+	for i, tr := range tests {
+		r := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader(tr.body))
+		r.ContentLength = tr.contentLength
+		r.Header.Set("Idempotency-Key", strconv.Itoa(i))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != tr.code {
+			t.Errorf("%q (length %d): Code = %d; want %d", tr.body, tr.contentLength, w.Code, tr.code)
+		}
+		if tr.code == http.StatusOK && w.Body.String() != tr.body {
+			t.Errorf("%q: handle read %q", tr.body, w.Body.String())
+		}
+	}
+	if calls != 1 {
+		t.Errorf("handle called %d times; want 1", calls)
+	}
+
+	// Requests without key are not limited
+	r := httptest.NewRequest(http.MethodPost, "/payments", strings.NewReader("abcdefgh"))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || w.Body.String() != "abcdefgh" {
+		t.Errorf("request without key: %d %q", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,123 @@
+package dhttprouter
+
+import (
+	"container/heap"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// LegacyMiss describes an unroutable request below one of the configured
+// legacy prefixes.
+type LegacyMiss struct {
+	// Request path of the miss
+	Path string
+
+	// Referer header of the most recent request for Path
+	Referrer string
+
+	// Number of misses recorded for Path so far
+	Count uint64
+}
+
+// DefaultLegacyMissLimit is the default of HttpRouter.LegacyMissLimit.
+const DefaultLegacyMissLimit = 1000
+
+// legacyMissLog aggregates the legacy misses per path. The misses are kept
+// in a min-heap ordered by count, so the path with the fewest misses can be
+// dropped if the limit is reached.
+type legacyMissLog struct {
+	mu     sync.Mutex
+	misses map[string]*legacyMiss
+	heap   legacyMissHeap
+}
+
+type legacyMiss struct {
+	LegacyMiss
+	index int // in the heap
+}
+
+func (l *legacyMissLog) record(path, referrer string, limit int) LegacyMiss {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.misses == nil {
+		l.misses = make(map[string]*legacyMiss)
+	}
+	if limit <= 0 {
+		limit = DefaultLegacyMissLimit
+	}
+
+	miss := l.misses[path]
+	if miss == nil {
+		for len(l.heap) >= limit {
+			dropped := heap.Pop(&l.heap).(*legacyMiss)
+			delete(l.misses, dropped.Path)
+		}
+		miss = &legacyMiss{LegacyMiss: LegacyMiss{Path: path}}
+		l.misses[path] = miss
+		heap.Push(&l.heap, miss)
+	}
+	miss.Count++
+	heap.Fix(&l.heap, miss.index)
+	if referrer != "" {
+		miss.Referrer = referrer
+	}
+	return miss.LegacyMiss
+}
+
+// legacyMissHeap implements heap.Interface.
+type legacyMissHeap []*legacyMiss
+
+func (h legacyMissHeap) Len() int           { return len(h) }
+func (h legacyMissHeap) Less(i, j int) bool { return h[i].Count < h[j].Count }
+
+func (h legacyMissHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *legacyMissHeap) Push(x interface{}) {
+	miss := x.(*legacyMiss)
+	miss.index = len(*h)
+	*h = append(*h, miss)
+}
+
+func (h *legacyMissHeap) Pop() interface{} {
+	old := *h
+	miss := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return miss
+}
+
+func (r *HttpRouter) recordLegacyMiss(req *http.Request) {
+	path := req.URL.Path
+	for _, prefix := range r.LegacyPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			r.LegacyMissSink(r.legacyMisses.record(path, req.Referer(), r.LegacyMissLimit))
+			return
+		}
+	}
+}
+
+// LegacyMisses returns the legacy misses recorded so far, ordered by
+// descending count.
+func (r *HttpRouter) LegacyMisses() []LegacyMiss {
+	r.legacyMisses.mu.Lock()
+	misses := make([]LegacyMiss, 0, len(r.legacyMisses.misses))
+	for _, miss := range r.legacyMisses.misses {
+		misses = append(misses, miss.LegacyMiss)
+	}
+	r.legacyMisses.mu.Unlock()
+
+	sort.Slice(misses, func(i, j int) bool {
+		if misses[i].Count != misses[j].Count {
+			return misses[i].Count > misses[j].Count
+		}
+		return misses[i].Path < misses[j].Path
+	})
+	return misses
+}
@@ -0,0 +1,92 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterLegacyMisses(t *testing.T) {
+	handlerFunc := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
+
+	var sunk []LegacyMiss
+	router := New()
+	router.GET("/old/kept", handlerFunc)
+	router.LegacyPrefixes = []string{"/old/", "/cgi-bin/"}
+	router.LegacyMissSink = func(miss LegacyMiss) {
+		sunk = append(sunk, miss)
+	}
+
+	requests := []struct {
+		path     string
+		referrer string
+	}{
+		{"/old/kept", ""},                          // routed
+		{"/old/page.html", "http://example.com/a"}, // miss
+		{"/new/page", ""},                          // not a legacy path
+		{"/cgi-bin/form.pl", ""},                   // miss
+		{"/old/page.html", "http://example.com/b"}, // miss
+		{"/old/page.html", ""},                     // miss, keeps referrer
+	}
+	for _, tr := range requests {
+		r, _ := http.NewRequest(http.MethodGet, tr.path, nil)
+		if tr.referrer != "" {
+			r.Header.Set("Referer", tr.referrer)
+		}
+		router.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	if len(sunk) != 4 {
+		t.Fatalf("wrong number of recorded misses: want 4, got %d", len(sunk))
+	}
+	if want := (LegacyMiss{"/old/page.html", "http://example.com/b", 2}); sunk[2] != want {
+		t.Errorf("wrong miss: want %v, got %v", want, sunk[2])
+	}
+
+	want := []LegacyMiss{
+		{"/old/page.html", "http://example.com/b", 3},
+		{"/cgi-bin/form.pl", "", 1},
+	}
+	if got := router.LegacyMisses(); !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong legacy misses: want %v, got %v", want, got)
+	}
+}
+
+func TestRouterLegacyMissLimit(t *testing.T) {
+	router := New()
+	router.LegacyPrefixes = []string{"/old/"}
+	router.LegacyMissSink = func(LegacyMiss) {}
+	router.LegacyMissLimit = 10
+
+	serve := func(path string) {
+		r, _ := http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	for i := 0; i < 3; i++ {
+		serve("/old/popular")
+	}
+
+	// A scanner flooding unique paths
+	for i := 0; i < 10000; i++ {
+		serve("/old/scan/" + strconv.Itoa(i))
+	}
+
+	misses := router.LegacyMisses()
+	if len(misses) != 10 {
+		t.Fatalf("wrong number of legacy misses: want 10, got %d", len(misses))
+	}
+	if want := (LegacyMiss{"/old/popular", "", 3}); misses[0] != want {
+		t.Errorf("frequent miss was dropped: %v", misses)
+	}
+	found := false
+	for _, miss := range misses {
+		found = found || miss.Path == "/old/scan/9999"
+	}
+	if !found {
+		t.Errorf("latest miss was not recorded: %v", misses)
+	}
+}
@@ -0,0 +1,48 @@
+package dhttprouter
+
+import (
+	"context"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+type originalMethodKey struct{}
+
+// OriginalMethodKey is the request context key under which the method of a
+// request is stored, if it was overridden, see HttpRouter.MethodOverride.
+var OriginalMethodKey = originalMethodKey{}
+
+// OriginalMethodFromContext returns the method a request was sent with, if
+// the router overrode it, or an empty string otherwise.
+func OriginalMethodFromContext(ctx context.Context) string {
+	m, _ := ctx.Value(OriginalMethodKey).(string)
+	return m
+}
+
+// overrideMethod returns the request with the method requested by the
+// X-HTTP-Method-Override header or the _method form field, if any.
+// Only PUT, PATCH and DELETE can be requested.
+func overrideMethod(req *http.Request) *http.Request {
+	method := req.Header.Get("X-HTTP-Method-Override")
+	if method == "" && isForm(req) {
+		method = req.PostFormValue("_method")
+	}
+
+	switch method = strings.ToUpper(strings.TrimSpace(method)); method {
+	case http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return req
+	}
+
+	ctx := context.WithValue(req.Context(), OriginalMethodKey, req.Method)
+	req = req.WithContext(ctx)
+	req.Method = method
+	return req
+}
+
+// isForm reports whether the request body is an HTML form.
+func isForm(req *http.Request) bool {
+	ct, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	return err == nil && (ct == "application/x-www-form-urlencoded" || ct == "multipart/form-data")
+}
@@ -0,0 +1,74 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterMethodOverride(t *testing.T) {
+	var hit string
+	handle := func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		hit = req.Method + " " + OriginalMethodFromContext(req.Context()) + " " + req.PostFormValue("name")
+	}
+
+	router := New()
+	router.MethodOverride = true
+	router.POST("/users", handle)
+	router.PUT("/users/:id", handle)
+	router.DELETE("/users/:id", handle)
+	router.GET("/users/:id", handle)
+	router.GET("/posts/:id", handle)
+	router.POST("/posts/:id", handle)
+
+	tests := []struct {
+		method, path, override, form string
+		code                         int
+		hit, allow                   string
+	}{
+		{http.MethodPost, "/users/1", "PUT", "", http.StatusOK, "PUT POST ", ""},
+		{http.MethodPost, "/users/1", "delete", "", http.StatusOK, "DELETE POST ", ""},
+		{http.MethodPost, "/users/1", "", "_method=PUT&name=x", http.StatusOK, "PUT POST x", ""},
+		{http.MethodPost, "/users", "", "name=y", http.StatusOK, "POST  y", ""},
+
+		// 405 refers to the overridden method
+		{http.MethodPost, "/posts/1", "DELETE", "", http.StatusMethodNotAllowed, "", "GET, OPTIONS, POST"},
+		{http.MethodPost, "/users/1", "PATCH", "", http.StatusMethodNotAllowed, "", "DELETE, GET, OPTIONS, PUT"},
+
+		// Only PUT, PATCH and DELETE can be requested, only by POST requests
+		{http.MethodPost, "/posts/1", "GET", "", http.StatusOK, "POST  ", ""},
+		{http.MethodGet, "/users/1", "DELETE", "", http.StatusOK, "GET  ", ""},
+	}
+	for _, tr := range tests {
+		hit = ""
+		r, _ := http.NewRequest(tr.method, tr.path, strings.NewReader(tr.form))
+		if tr.override != "" {
+			r.Header.Set("X-HTTP-Method-Override", tr.override)
+		}
+		if tr.form != "" {
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != tr.code || hit != tr.hit || w.Header().Get("Allow") != tr.allow {
+			t.Errorf("%s %s (%q, %q): got %d %q Allow %q, want %d %q Allow %q", tr.method, tr.path, tr.override, tr.form,
+				w.Code, hit, w.Header().Get("Allow"), tr.code, tr.hit, tr.allow)
+		}
+		if tr.method != r.Method {
+			t.Errorf("%s %s: method of the original request changed to %s", tr.method, tr.path, r.Method)
+		}
+	}
+
+	// Disabled by default
+	router.MethodOverride = false
+	r, _ := http.NewRequest(http.MethodPost, "/users/1", nil)
+	r.Header.Set("X-HTTP-Method-Override", "PUT")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("method overridden while disabled: %d", w.Code)
+	}
+}
@@ -0,0 +1,23 @@
+package dhttprouter
+
+// Middleware wraps a handle to run code before and/or after it, e.g. for
+// authentication or logging. It may also decide not to call the handle.
+type Middleware func(HttpHandle) HttpHandle
+
+// Chain combines the given middleware into a single one. The first middleware
+// is the outermost one.
+func Chain(middleware ...Middleware) Middleware {
+	return func(handle HttpHandle) HttpHandle {
+		return chain(handle, middleware)
+	}
+}
+
+func chain(handle HttpHandle, middleware []Middleware) HttpHandle {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		if middleware[i] == nil {
+			panic("middleware must not be nil")
+		}
+		handle = middleware[i](handle)
+	}
+	return handle
+}
@@ -0,0 +1,84 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func traceMiddleware(trace *[]string, name string) Middleware {
+	return func(next HttpHandle) HttpHandle {
+		return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+			*trace = append(*trace, name)
+			next(w, req, ps)
+		}
+	}
+}
+
+func TestRouterMiddleware(t *testing.T) {
+	var trace []string
+	handle := func(_ http.ResponseWriter, _ *http.Request, ps drouter.Params) {
+		trace = append(trace, "handle:"+ps.ByName("id"))
+	}
+	deny := func(next HttpHandle) HttpHandle {
+		return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}
+
+	router := New()
+	router.GET("/a/:id", handle, traceMiddleware(&trace, "1"), traceMiddleware(&trace, "2"))
+	router.POST("/a/:id", handle)
+	router.Handle(http.MethodPut, "/a/:id", handle, Chain(traceMiddleware(&trace, "c1"), traceMiddleware(&trace, "c2")), traceMiddleware(&trace, "3"))
+	router.DELETE("/a/:id", handle, deny)
+
+	tests := []struct {
+		method string
+		trace  string
+		code   int
+	}{
+		{http.MethodGet, "1,2,handle:x", http.StatusOK},
+		{http.MethodPost, "handle:x", http.StatusOK},
+		{http.MethodPut, "c1,c2,3,handle:x", http.StatusOK},
+		{http.MethodDelete, "", http.StatusForbidden},
+	}
+	for _, tr := range tests {
+		trace = nil
+		r, _ := http.NewRequest(tr.method, "/a/x", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if got := strings.Join(trace, ","); got != tr.trace || w.Code != tr.code {
+			t.Errorf("wrong middleware execution for %s: trace=%q, Code=%d", tr.method, got, w.Code)
+		}
+	}
+
+	recv := catchPanic(func() {
+		router.GET("/b", handle, nil)
+	})
+	if recv == nil {
+		t.Error("registering nil middleware did not panic")
+	}
+}
+
+func TestRouterMiddlewareMatchedRoutePath(t *testing.T) {
+	var matched string
+	router := New()
+	router.SaveMatchedRoutePath = true
+	router.GET("/users/:id", func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {},
+		func(next HttpHandle) HttpHandle {
+			return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+				matched = ps.MatchedRoutePath()
+				next(w, req, ps)
+			}
+		},
+	)
+
+	r, _ := http.NewRequest(http.MethodGet, "/users/1", nil)
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	if matched != "/users/:id" {
+		t.Errorf("middleware got wrong matched route path %q", matched)
+	}
+}
@@ -0,0 +1,160 @@
+package dhttprouter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// RouteModule is a group of routes which is built independently of a
+// router and installed onto any HttpRouter later, so features like
+// authentication or billing can be shipped as libraries:
+//
+//	func Module() *dhttprouter.RouteModule {
+//		m := &dhttprouter.RouteModule{Name: "billing", Middleware: []dhttprouter.Middleware{requireAccount}}
+//		m.GET("/invoices", listInvoices)
+//		m.GET("/invoices/:id", getInvoice)
+//		return m
+//	}
+//
+//	router.Install("/billing", billing.Module(), auth.Wrap)
+//
+// Unlike a mounted HttpRouter, the routes become routes of the host router,
+// so its plugins, options and per-route settings apply to them.
+type RouteModule struct {
+	// Name of the module, e.g. "billing", which is reported as the Module of
+	// its routes. It must not be empty and must be unique per router.
+	Name string
+
+	// Optional description of the module for the host, e.g. its version or
+	// the owning team, see Modules
+	Metadata map[string]string
+
+	// Tags added to every route of the module, see TagRoute
+	Tags []string
+
+	// Middleware applied to every route of the module, outermost first
+	Middleware []Middleware
+
+	// Routes of the module, see Handle
+	Routes []ModuleRoute
+}
+
+// ModuleRoute is a route of a RouteModule. The path is relative to the
+// prefix the module is installed under.
+type ModuleRoute struct {
+	Method string
+	Path   string
+	Handle HttpHandle
+
+	// Middleware of the route, applied within the middleware of the module
+	Middleware []Middleware
+}
+
+// Handle adds a route to the module, see HttpRouter.Handle.
+func (m *RouteModule) Handle(method, path string, handle HttpHandle, middleware ...Middleware) {
+	m.Routes = append(m.Routes, ModuleRoute{Method: method, Path: path, Handle: handle, Middleware: middleware})
+}
+
+// GET is a shortcut for m.Handle(http.MethodGet, path, handle, middleware...)
+func (m *RouteModule) GET(path string, handle HttpHandle, middleware ...Middleware) {
+	m.Handle(http.MethodGet, path, handle, middleware...)
+}
+
+// POST is a shortcut for m.Handle(http.MethodPost, path, handle, middleware...)
+func (m *RouteModule) POST(path string, handle HttpHandle, middleware ...Middleware) {
+	m.Handle(http.MethodPost, path, handle, middleware...)
+}
+
+// PUT is a shortcut for m.Handle(http.MethodPut, path, handle, middleware...)
+func (m *RouteModule) PUT(path string, handle HttpHandle, middleware ...Middleware) {
+	m.Handle(http.MethodPut, path, handle, middleware...)
+}
+
+// PATCH is a shortcut for m.Handle(http.MethodPatch, path, handle, middleware...)
+func (m *RouteModule) PATCH(path string, handle HttpHandle, middleware ...Middleware) {
+	m.Handle(http.MethodPatch, path, handle, middleware...)
+}
+
+// DELETE is a shortcut for m.Handle(http.MethodDelete, path, handle, middleware...)
+func (m *RouteModule) DELETE(path string, handle HttpHandle, middleware ...Middleware) {
+	m.Handle(http.MethodDelete, path, handle, middleware...)
+}
+
+// ModuleInfo describes a RouteModule installed onto a router.
+type ModuleInfo struct {
+	Name     string
+	Prefix   string
+	Metadata map[string]string
+}
+
+// Modules returns the installed modules, in the order of installation.
+func (r *HttpRouter) Modules() []ModuleInfo {
+	return append([]ModuleInfo(nil), r.loadTable().modules...)
+}
+
+// Install registers the routes of the module onto r, with the prefix in
+// front of their paths, e.g. "/billing" for "/invoices". The prefix may be
+// empty, otherwise it must begin with '/' and must not end with '/'. The
+// optional middleware wraps the middleware of the module, e.g. to add the
+// authentication of the host application.
+// Install panics if a route can not be registered, see TryInstall.
+func (r *HttpRouter) Install(prefix string, m *RouteModule, middleware ...Middleware) {
+	if err := r.TryInstall(prefix, m, middleware...); err != nil {
+		panic(err.Error())
+	}
+}
+
+// TryInstall is like Install, but returns an error instead of panicking.
+// Either all routes of the module are registered or none, so a module can
+// be installed optionally, e.g. a plugin-like feature which conflicts with
+// existing routes.
+func (r *HttpRouter) TryInstall(prefix string, m *RouteModule, middleware ...Middleware) error {
+	if m == nil {
+		return errors.New("module must not be nil")
+	}
+	if m.Name == "" {
+		return errors.New("module must have a name")
+	}
+	if prefix != "" && (prefix[0] != '/' || prefix[len(prefix)-1] == '/') {
+		return errors.New("module prefix must begin with '/' and must not end with '/' in prefix '" + prefix + "'")
+	}
+	for _, info := range r.mutableTable().modules {
+		if info.Name == m.Name {
+			return errors.New("a module named '" + m.Name + "' is already installed")
+		}
+	}
+
+	outer := append(append([]Middleware(nil), middleware...), m.Middleware...)
+	for i, mr := range m.Routes {
+		path := prefix + mr.Path
+		err := r.TryHandle(mr.Method, path, mr.Handle, append(outer[:len(outer):len(outer)], mr.Middleware...)...)
+		if err != nil {
+			for _, added := range m.Routes[:i] {
+				r.Remove(added.Method, prefix+added.Path)
+			}
+			return fmt.Errorf("module %s: %s %s: %w", m.Name, mr.Method, path, err)
+		}
+
+		rt := r.mutableTable().lookupRoute(mr.Method, path)
+		rt.module = m.Name
+		if len(m.Tags) > 0 {
+			rt.tags = append(append([]string(nil), rt.tags...), m.Tags...)
+		}
+	}
+
+	t := r.mutableTable()
+	t.modules = append(t.modules, ModuleInfo{Name: m.Name, Prefix: prefix, Metadata: copyMetadata(m.Metadata)})
+	return nil
+}
+
+func copyMetadata(md map[string]string) map[string]string {
+	if md == nil {
+		return nil
+	}
+	c := make(map[string]string, len(md))
+	for k, v := range md {
+		c[k] = v
+	}
+	return c
+}
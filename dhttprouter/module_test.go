@@ -0,0 +1,107 @@
+package dhttprouter
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterInstall(t *testing.T) {
+	var calls []string
+	mark := func(name string) Middleware {
+		return func(next HttpHandle) HttpHandle {
+			return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+				calls = append(calls, name)
+				next(w, req, ps)
+			}
+		}
+	}
+	getInvoice := func(w http.ResponseWriter, _ *http.Request, ps drouter.Params) {
+		w.Write([]byte("invoice " + ps.ByName("id")))
+	}
+
+	billing := &RouteModule{
+		Name:       "billing",
+		Metadata:   map[string]string{"version": "1.2.0"},
+		Tags:       []string{"pii"},
+		Middleware: []Middleware{mark("module")},
+	}
+	billing.GET("/invoices/:id", getInvoice, mark("route"))
+	billing.POST("/invoices", func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	// A module can be installed onto several routers
+	for _, prefix := range []string{"/billing", ""} {
+		calls = nil
+		router := New()
+		router.Install(prefix, billing, mark("host"))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, prefix+"/invoices/7", nil))
+		if w.Code != http.StatusOK || w.Body.String() != "invoice 7" {
+			t.Errorf("%q: wrong response: %d %q", prefix, w.Code, w.Body)
+		}
+		if strings.Join(calls, ",") != "host,module,route" {
+			t.Errorf("%q: wrong middleware order %v", prefix, calls)
+		}
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, prefix+"/invoices", nil))
+		if w.Code != http.StatusCreated {
+			t.Errorf("%q: wrong status: %d", prefix, w.Code)
+		}
+
+		info, ok := router.Snapshot().Route(http.MethodGet, prefix+"/invoices/:id")
+		if !ok || info.Module != "billing" || len(info.Tags) != 1 || info.Tags[0] != "pii" || info.Handler != handlerID(getInvoice) {
+			t.Errorf("%q: wrong route %+v", prefix, info)
+		}
+		modules := router.Modules()
+		if len(modules) != 1 || modules[0].Name != "billing" || modules[0].Prefix != prefix || modules[0].Metadata["version"] != "1.2.0" {
+			t.Errorf("%q: wrong modules %+v", prefix, modules)
+		}
+	}
+	if len(billing.Middleware) != 1 || len(billing.Routes[0].Middleware) != 1 {
+		t.Errorf("module was changed: %+v", billing)
+	}
+
+	// Failed installations leave the router unchanged
+	router := New()
+	router.GET("/billing/invoices", func(http.ResponseWriter, *http.Request, drouter.Params) {})
+	conflicting := &RouteModule{Name: "conflicting"}
+	conflicting.GET("/billing/invoices/:id", getInvoice)
+	conflicting.DELETE("/billing/invoices/:id", getInvoice)
+	conflicting.GET("/billing/:section", getInvoice)
+	err := router.TryInstall("", conflicting)
+	var rerr *drouter.RouteError
+	if !errors.As(err, &rerr) || !strings.HasPrefix(err.Error(), "module conflicting: GET /billing/:section: ") {
+		t.Errorf("wrong error %v", err)
+	}
+	if routes := router.Routes(); len(routes) != 1 || len(router.Modules()) != 0 {
+		t.Errorf("module was partially installed: %+v", routes)
+	}
+
+	router.Install("/billing", billing)
+	tests := []struct {
+		prefix string
+		module *RouteModule
+		err    string
+	}{
+		{"", nil, "module must not be nil"},
+		{"", &RouteModule{}, "module must have a name"},
+		{"billing", &RouteModule{Name: "b"}, "module prefix must begin with '/' and must not end with '/' in prefix 'billing'"},
+		{"/b/", &RouteModule{Name: "b"}, "module prefix must begin with '/' and must not end with '/' in prefix '/b/'"},
+		{"/other", &RouteModule{Name: "billing"}, "a module named 'billing' is already installed"},
+	}
+	for _, tt := range tests {
+		if err := router.TryInstall(tt.prefix, tt.module); err == nil || err.Error() != tt.err {
+			t.Errorf("wrong error: want %q, got %v", tt.err, err)
+		}
+	}
+	if rcv := catchPanic(func() { router.Install("/billing", &RouteModule{Name: "copy", Routes: billing.Routes}) }); rcv == nil {
+		t.Error("conflicting module was installed")
+	}
+}
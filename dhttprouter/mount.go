@@ -0,0 +1,149 @@
+package dhttprouter
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/thekhanj/drouter"
+)
+
+type mountPrefixKey struct{}
+
+// MountPrefixKey is the request context key under which the path prefix of
+// the mount(s) a request was passed through is stored.
+var MountPrefixKey = mountPrefixKey{}
+
+// MountPrefixFromContext returns the path prefix under which the handler of
+// the request is mounted, or an empty string if it is not mounted.
+// Handlers of mounted routers can use it to build absolute paths.
+func MountPrefixFromContext(ctx context.Context) string {
+	p, _ := ctx.Value(MountPrefixKey).(string)
+	return p
+}
+
+type mount struct {
+	prefix  string
+	handler http.Handler
+}
+
+// Mount attaches an independently built handler, typically another
+// HttpRouter, under the given path prefix, e.g. router.Mount("/admin", admin).
+//
+// Requests for the prefix itself or paths below it, which are not matched by
+// a route of r, are passed to the handler with the prefix stripped from the
+// request path. The handler is responsible for all of them, including
+// trailing slash and fixed path redirects as well as 404 and 405 responses.
+// Redirects issued by a mounted HttpRouter keep the prefix, which is also
+// available to handlers via MountPrefixFromContext.
+//
+// The prefix must begin with '/', must not end with '/' and must not contain
+// wildcards. Mounts can not be nested, Mount panics if the prefix is below
+// or above the prefix of another mount.
+func (r *HttpRouter) Mount(prefix string, handler http.Handler) {
+	if len(prefix) < 2 || prefix[0] != '/' {
+		panic("mount prefix must begin with '/' and must not be the root in prefix '" + prefix + "'")
+	}
+	if prefix[len(prefix)-1] == '/' {
+		panic("mount prefix must not end with '/' in prefix '" + prefix + "'")
+	}
+	if strings.ContainsAny(prefix, ":*") {
+		panic("mount prefix must not contain wildcards in prefix '" + prefix + "'")
+	}
+	if handler == nil {
+		panic("handler must not be nil")
+	}
+
+	t := r.mutableTable()
+	if t.mounts == nil {
+		t.mounts = drouter.New[*mount]()
+	}
+
+	m := &mount{prefix: prefix, handler: handler}
+	err := t.mounts.TryAddRoute(prefix, m)
+	if err == nil {
+		if err = t.mounts.TryAddRoute(prefix+"/*mountpath", m); err != nil {
+			t.mounts.RemoveRoute(prefix)
+		}
+	}
+	if err != nil {
+		panic(mountConflict(t.mounts, prefix, err))
+	}
+	r.bumpVersion()
+}
+
+// mountConflict returns the message for a mount at the prefix which could not
+// be added to mounts due to err, naming the prefix of the existing mount.
+func mountConflict(mounts *drouter.Router[*mount], prefix string, err error) string {
+	rerr, ok := err.(*drouter.RouteError)
+	if !ok || rerr.Existing == "" {
+		return "mount prefix '" + prefix + "' conflicts with another mount: " + err.Error()
+	}
+	existing := strings.TrimSuffix(rerr.Existing, "/*mountpath")
+	if m, _ := mounts.Lookup(rerr.Existing, nil); m != nil {
+		existing = m.prefix
+	}
+	return "mount prefix '" + prefix + "' conflicts with the mount at prefix '" + existing + "'"
+}
+
+// serveMount passes the request to the matching mount, if any.
+func (t *routeTable) serveMount(w http.ResponseWriter, req *http.Request) bool {
+	m, _ := t.mounts.Lookup(req.URL.Path, nil)
+	if m == nil {
+		return false
+	}
+
+	u := *req.URL
+	u.Path = req.URL.Path[len(m.prefix):]
+	if strings.HasPrefix(u.RawPath, m.prefix) {
+		u.RawPath = u.RawPath[len(m.prefix):]
+	} else {
+		u.RawPath = ""
+	}
+
+	ctx := context.WithValue(req.Context(), MountPrefixKey, MountPrefixFromContext(req.Context())+m.prefix)
+	sub := req.WithContext(ctx)
+	sub.URL = &u
+
+	m.handler.ServeHTTP(w, sub)
+	return true
+}
+
+// mountsAllowed returns the server-wide Allow header of the given methods
+// merged with the methods of the mounted HttpRouters. It is computed for
+// every request, so methods registered with a mounted router after it was
+// mounted are included.
+func (t *routeTable) mountsAllowed(allow string) string {
+	methods := make(map[string]bool)
+	add := func(allow string) {
+		for _, method := range strings.Split(allow, ", ") {
+			if method != "" {
+				methods[method] = true
+			}
+		}
+	}
+	add(allow)
+
+	t.mounts.Walk(func(path string, m *mount) bool {
+		if sub, ok := m.handler.(*HttpRouter); ok && path == m.prefix {
+			add(sub.loadTable().allowed("*", http.MethodOptions))
+		}
+		return true
+	})
+
+	allowed := make([]string, 0, len(methods))
+	for method := range methods {
+		allowed = append(allowed, method)
+	}
+	sort.Strings(allowed)
+	return strings.Join(allowed, ", ")
+}
+
+// redirectPath redirects the client to the given path, keeping the query
+// string and the prefix of the mount the request was passed through.
+func redirectPath(w http.ResponseWriter, req *http.Request, path string, code int) {
+	req.URL.Path = MountPrefixFromContext(req.Context()) + path
+	req.URL.RawPath = ""
+	http.Redirect(w, req, req.URL.String(), code)
+}
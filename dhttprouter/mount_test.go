@@ -0,0 +1,125 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterMount(t *testing.T) {
+	var hit, prefix string
+	handle := func(name string) HttpHandle {
+		return func(_ http.ResponseWriter, req *http.Request, ps drouter.Params) {
+			hit = name + ":" + ps.ByName("id")
+			prefix = MountPrefixFromContext(req.Context())
+		}
+	}
+
+	users := New()
+	users.GET("/", handle("users.list"))
+	users.GET("/:id", handle("users.get"))
+	users.POST("/:id/settings/", handle("users.settings"))
+
+	admin := New()
+	admin.GET("/dashboard", handle("admin.dashboard"))
+	admin.Mount("/users", users)
+
+	router := New()
+	router.GET("/admin/override", handle("root.override"))
+	router.GET("/about", handle("root.about"))
+	router.Mount("/admin", admin)
+
+	tests := []struct {
+		method, path string
+		code         int
+		hit          string
+		prefix       string
+		location     string
+	}{
+		{http.MethodGet, "/about", http.StatusOK, "root.about:", "", ""},
+		{http.MethodGet, "/admin/override", http.StatusOK, "root.override:", "", ""},
+		{http.MethodGet, "/admin/dashboard", http.StatusOK, "admin.dashboard:", "/admin", ""},
+		{http.MethodGet, "/admin/users/", http.StatusOK, "users.list:", "/admin/users", ""},
+		{http.MethodGet, "/admin/users/42", http.StatusOK, "users.get:42", "/admin/users", ""},
+
+		// TSR and fixed path redirects keep the prefix
+		{http.MethodGet, "/admin/dashboard/", http.StatusMovedPermanently, "", "", "/admin/dashboard"},
+		{http.MethodGet, "/admin/users", http.StatusMovedPermanently, "", "", "/admin/users/"},
+		{http.MethodGet, "/admin/DASHBOARD", http.StatusMovedPermanently, "", "", "/admin/dashboard"},
+		{http.MethodPost, "/admin/users/42/settings", http.StatusPermanentRedirect, "", "", "/admin/users/42/settings/"},
+
+		// 405 and 404 are answered by the mounted router
+		{http.MethodDelete, "/admin/users/42", http.StatusMethodNotAllowed, "", "", ""},
+		{http.MethodGet, "/admin/nope", http.StatusNotFound, "", "", ""},
+		{http.MethodGet, "/administrator", http.StatusNotFound, "", "", ""},
+	}
+	for _, tr := range tests {
+		hit, prefix = "", ""
+		r, _ := http.NewRequest(tr.method, tr.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != tr.code || hit != tr.hit || prefix != tr.prefix || w.Header().Get("Location") != tr.location {
+			t.Errorf("%s %s failed: Code=%d, hit=%q, prefix=%q, Location=%q",
+				tr.method, tr.path, w.Code, hit, prefix, w.Header().Get("Location"))
+		}
+	}
+
+	// Allow header of the mounted router
+	r, _ := http.NewRequest(http.MethodDelete, "/admin/users/42", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if allow := w.Header().Get("Allow"); allow != "GET, OPTIONS" {
+		t.Errorf("unexpected Allow header value: %q", allow)
+	}
+}
+
+func TestRouterMountInvalid(t *testing.T) {
+	router := New()
+	for _, prefix := range []string{"", "/", "admin", "/admin/", "/:tenant"} {
+		recv := catchPanic(func() {
+			router.Mount(prefix, New())
+		})
+		if recv == nil {
+			t.Errorf("mounting at %q did not panic", prefix)
+		}
+	}
+
+	recv := catchPanic(func() {
+		router.Mount("/admin", nil)
+	})
+	if recv == nil {
+		t.Error("mounting nil handler did not panic")
+	}
+
+	router.Mount("/admin", New())
+	recv = catchPanic(func() {
+		router.Mount("/admin", New())
+	})
+	if recv == nil {
+		t.Error("mounting twice at the same prefix did not panic")
+	}
+
+	// Nested mounts are rejected without leaving a partial mount behind
+	for _, prefix := range []string{"/admin/users", "/api"} {
+		router := New()
+		router.Mount("/api/v2", New())
+		router.Mount("/admin", New())
+		recv := catchPanic(func() {
+			router.Mount(prefix, New())
+		})
+		msg, _ := recv.(string)
+		want := "/api/v2"
+		if prefix == "/admin/users" {
+			want = "/admin"
+		}
+		if !strings.Contains(msg, "'"+prefix+"'") || !strings.Contains(msg, "'"+want+"'") {
+			t.Errorf("mounting at %s: got panic %v; want it to name %s", prefix, recv, want)
+		}
+		if n := router.loadTable().mounts.Len(); n != 4 {
+			t.Errorf("mounting at %s: %d mount routes left; want 4", prefix, n)
+		}
+	}
+}
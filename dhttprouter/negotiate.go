@@ -0,0 +1,139 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/thekhanj/drouter"
+)
+
+// Representation is a handle serving a route in one media type, see
+// Negotiate.
+type Representation struct {
+	// Media type, e.g. "application/json"
+	MediaType string
+
+	Handle HttpHandle
+}
+
+// JSON returns a representation of the handle as application/json.
+func JSON(handle HttpHandle) Representation {
+	return Representation{"application/json", handle}
+}
+
+// HTML returns a representation of the handle as text/html.
+func HTML(handle HttpHandle) Representation {
+	return Representation{"text/html", handle}
+}
+
+// XML returns a representation of the handle as application/xml.
+func XML(handle HttpHandle) Representation {
+	return Representation{"application/xml", handle}
+}
+
+// Text returns a representation of the handle as text/plain.
+func Text(handle HttpHandle) Representation {
+	return Representation{"text/plain", handle}
+}
+
+// Negotiate returns a handle serving each request with the representation
+// preferred by its Accept header, e.g.
+//
+//	router.GET("/users/:id", dhttprouter.Negotiate(
+//		dhttprouter.JSON(getUserJSON),
+//		dhttprouter.HTML(getUserPage),
+//	))
+//
+// The representations are weighted by the q-value of the most specific
+// media range matching them. Among equally weighted ones, and for requests
+// without Accept header, the first one is used. If the client accepts none
+// of them, the request is answered with 406 Not Acceptable.
+// The Content-Type header is set to the media type of the representation
+// before its handle is called, which may change it, e.g. to add a charset.
+// It panics if no representation is given or one has no media type or
+// handle.
+func Negotiate(representations ...Representation) HttpHandle {
+	if len(representations) == 0 {
+		panic("at least one representation is required")
+	}
+	reps := make([]Representation, len(representations))
+	for i, rep := range representations {
+		if rep.Handle == nil {
+			panic("handle must not be nil for media type '" + rep.MediaType + "'")
+		}
+		mediaType := strings.ToLower(strings.TrimSpace(rep.MediaType))
+		if slash := strings.IndexByte(mediaType, '/'); slash <= 0 || slash == len(mediaType)-1 || strings.ContainsAny(mediaType, "*;, ") {
+			panic("invalid media type '" + rep.MediaType + "'")
+		}
+		reps[i] = Representation{mediaType, rep.Handle}
+	}
+
+	return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		w.Header().Add("Vary", "Accept")
+
+		rep := negotiate(reps, req.Header["Accept"])
+		if rep == nil {
+			http.Error(w, http.StatusText(http.StatusNotAcceptable), http.StatusNotAcceptable)
+			return
+		}
+		w.Header().Set("Content-Type", rep.MediaType)
+		rep.Handle(w, req, ps)
+	}
+}
+
+// negotiate returns the representation preferred by the given Accept
+// header values, or nil if none is acceptable.
+func negotiate(reps []Representation, accept []string) *Representation {
+	if len(accept) == 0 {
+		return &reps[0]
+	}
+
+	var best *Representation
+	bestQ := 0.0
+	for i := range reps {
+		if q := acceptQuality(reps[i].MediaType, accept); q > bestQ {
+			best, bestQ = &reps[i], q
+		}
+	}
+	return best
+}
+
+// acceptQuality returns the q-value of the most specific media range of the
+// Accept header values matching the media type, or 0 if none matches.
+func acceptQuality(mediaType string, accept []string) float64 {
+	typ := mediaType[:strings.IndexByte(mediaType, '/')]
+
+	q, specificity := 0.0, -1
+	for _, value := range accept {
+		for _, r := range strings.Split(value, ",") {
+			params := strings.Split(r, ";")
+			mediaRange := strings.ToLower(strings.TrimSpace(params[0]))
+
+			s := -1
+			switch {
+			case mediaRange == mediaType:
+				s = 2
+			case mediaRange == typ+"/*":
+				s = 1
+			case mediaRange == "*/*":
+				s = 0
+			}
+			if s <= specificity {
+				continue
+			}
+
+			rq := 1.0
+			for _, p := range params[1:] {
+				p = strings.TrimSpace(p)
+				if len(p) > 2 && (p[0] == 'q' || p[0] == 'Q') && p[1] == '=' {
+					if v, err := strconv.ParseFloat(p[2:], 64); err == nil && v >= 0 && v <= 1 {
+						rq = v
+					}
+				}
+			}
+			q, specificity = rq, s
+		}
+	}
+	return q
+}
@@ -0,0 +1,76 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestNegotiate(t *testing.T) {
+	represent := func(body string) HttpHandle {
+		return func(w http.ResponseWriter, _ *http.Request, ps drouter.Params) {
+			w.Write([]byte(body + ps.ByName("id")))
+		}
+	}
+
+	router := New()
+	router.GET("/users/:id", Negotiate(
+		JSON(represent("json")),
+		HTML(represent("html")),
+		Representation{"Text/CSV", represent("csv")},
+	))
+
+	tests := []struct {
+		accept      []string
+		code        int
+		body        string
+		contentType string
+	}{
+		{nil, http.StatusOK, "json1", "application/json"},
+		{[]string{"*/*"}, http.StatusOK, "json1", "application/json"},
+		{[]string{"text/html"}, http.StatusOK, "html1", "text/html"},
+		{[]string{"text/*"}, http.StatusOK, "html1", "text/html"},
+		{[]string{"text/*;q=0.5, text/csv"}, http.StatusOK, "csv1", "text/csv"},
+		{[]string{"application/json;q=0.8, text/html;level=1;q=0.9"}, http.StatusOK, "html1", "text/html"},
+		{[]string{"application/json;q=0.8", "text/html;q=0.2"}, http.StatusOK, "json1", "application/json"},
+		{[]string{"*/*;q=0.1, application/json;q=0"}, http.StatusOK, "html1", "text/html"},
+		{[]string{"TEXT/HTML"}, http.StatusOK, "html1", "text/html"},
+		{[]string{"image/png"}, http.StatusNotAcceptable, "Not Acceptable\n", "text/plain; charset=utf-8"},
+		{[]string{"text/html;q=0, application/*;q=0"}, http.StatusNotAcceptable, "Not Acceptable\n", "text/plain; charset=utf-8"},
+	}
+	for _, tr := range tests {
+		r, _ := http.NewRequest(http.MethodGet, "/users/1", nil)
+		r.Header["Accept"] = tr.accept
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != tr.code || w.Body.String() != tr.body || w.Header().Get("Content-Type") != tr.contentType {
+			t.Errorf("Accept %q: got %d %q %q, want %d %q %q", tr.accept,
+				w.Code, w.Body.String(), w.Header().Get("Content-Type"), tr.code, tr.body, tr.contentType)
+		}
+		if vary := w.Header().Get("Vary"); vary != "Accept" {
+			t.Errorf("Accept %q: unexpected Vary header %q", tr.accept, vary)
+		}
+	}
+}
+
+func TestNegotiateInvalid(t *testing.T) {
+	handle := func(http.ResponseWriter, *http.Request, drouter.Params) {}
+	invalid := [][]Representation{
+		nil,
+		{JSON(nil)},
+		{{"", handle}},
+		{{"json", handle}},
+		{{"text/*", handle}},
+		{{"text/html; charset=utf-8", handle}},
+	}
+	for _, reps := range invalid {
+		recv := catchPanic(func() {
+			Negotiate(reps...)
+		})
+		if recv == nil {
+			t.Errorf("Negotiate(%v) did not panic", reps)
+		}
+	}
+}
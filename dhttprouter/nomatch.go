@@ -0,0 +1,25 @@
+package dhttprouter
+
+// Decision describes why a request could not be routed to a handle.
+type Decision struct {
+	// Status code of the response, either http.StatusNotFound,
+	// http.StatusMethodNotAllowed, http.StatusNotImplemented, see
+	// HttpRouter.RejectUnknownMethods, or http.StatusBadRequest for requests
+	// of the target "*" with another method than OPTIONS.
+	Status int
+
+	// Comma-separated list of the methods allowed for the path.
+	// Only set for http.StatusMethodNotAllowed.
+	Allow string
+
+	// Whether a handle exists for the path with (without) a trailing slash.
+	// This is only reported if the client was not redirected, i.e. if
+	// RedirectTrailingSlash is disabled or the path is exempt from redirects.
+	TrailingSlash bool
+
+	// Whether any route is registered for the request method.
+	KnownMethod bool
+
+	// Route suggestions for the path, if SuggestDistance is set.
+	Suggestions []string
+}
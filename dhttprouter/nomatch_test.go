@@ -0,0 +1,54 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterOnNoMatch(t *testing.T) {
+	handlerFunc := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
+
+	type noMatch struct {
+		method, path string
+		decision     Decision
+	}
+	var got []noMatch
+
+	router := New()
+	router.RedirectTrailingSlash = false
+	router.SuggestDistance = 1
+	router.GET("/path", handlerFunc)
+	router.POST("/form", handlerFunc)
+	router.OnNoMatch = func(method, path string, decision Decision) {
+		got = append(got, noMatch{method, path, decision})
+	}
+
+	requests := []struct {
+		method, path string
+	}{
+		{http.MethodGet, "/path"},     // routed
+		{http.MethodGet, "/path/"},    // 404 with TSR
+		{http.MethodGet, "/form"},     // 405
+		{http.MethodPut, "/nope"},     // 404 for unknown method
+		{http.MethodGet, "/paths"},    // 404 with suggestion
+		{http.MethodOptions, "/path"}, // automatic OPTIONS
+	}
+	for _, tr := range requests {
+		r, _ := http.NewRequest(tr.method, tr.path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	want := []noMatch{
+		{http.MethodGet, "/path/", Decision{Status: http.StatusNotFound, TrailingSlash: true, KnownMethod: true, Suggestions: []string{"/path"}}},
+		{http.MethodGet, "/form", Decision{Status: http.StatusMethodNotAllowed, Allow: "OPTIONS, POST", KnownMethod: true}},
+		{http.MethodPut, "/nope", Decision{Status: http.StatusNotFound}},
+		{http.MethodGet, "/paths", Decision{Status: http.StatusNotFound, KnownMethod: true, Suggestions: []string{"/path"}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong no-match decisions:\nwant %+v\n got %+v", want, got)
+	}
+}
@@ -0,0 +1,244 @@
+package dhttprouter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// openAPIMethods maps the operation keys of a path item onto request methods,
+// in the order the operations are registered.
+var openAPIMethods = []struct{ key, method string }{
+	{"get", http.MethodGet},
+	{"head", http.MethodHead},
+	{"post", http.MethodPost},
+	{"put", http.MethodPut},
+	{"patch", http.MethodPatch},
+	{"delete", http.MethodDelete},
+	{"options", http.MethodOptions},
+	{"trace", http.MethodTrace},
+}
+
+type openAPIDocument struct {
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+
+	// OpenAPI 3
+	Components struct {
+		Parameters map[string]openAPIParameter `json:"parameters"`
+		Schemas    map[string]openAPISchema    `json:"schemas"`
+	} `json:"components"`
+
+	// Swagger 2
+	Parameters  map[string]openAPIParameter `json:"parameters"`
+	Definitions map[string]openAPISchema    `json:"definitions"`
+}
+
+type openAPIOperation struct {
+	OperationID string             `json:"operationId"`
+	Parameters  []openAPIParameter `json:"parameters"`
+}
+
+type openAPIParameter struct {
+	Ref    string         `json:"$ref"`
+	Name   string         `json:"name"`
+	In     string         `json:"in"`
+	Schema *openAPISchema `json:"schema"`
+
+	// Swagger 2 keeps the schema of non-body parameters inline
+	openAPISchema
+}
+
+type openAPISchema struct {
+	Ref     string          `json:"$ref"`
+	Type    json.RawMessage `json:"type"`
+	Format  string          `json:"format"`
+	Minimum *float64        `json:"minimum"`
+}
+
+// hasType reports whether the schema has the given type. Since OpenAPI 3.1 the
+// type may be a list, e.g. ["integer", "null"].
+func (s *openAPISchema) hasType(typ string) bool {
+	var one string
+	if json.Unmarshal(s.Type, &one) == nil {
+		return one == typ
+	}
+	var list []string
+	json.Unmarshal(s.Type, &list)
+	for _, t := range list {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// constraint returns the name of the parameter constraint matching the
+// schema, or an empty string if there is none.
+func (s *openAPISchema) constraint() string {
+	switch {
+	case s.hasType("integer") && s.Minimum != nil && *s.Minimum >= 0:
+		return "uint"
+	case s.hasType("integer"):
+		return "int"
+	case s.hasType("string") && s.Format == "uuid":
+		return "uuid"
+	}
+	return ""
+}
+
+func (d *openAPIDocument) parameter(p openAPIParameter) (openAPIParameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	var ref openAPIParameter
+	var ok bool
+	switch {
+	case strings.HasPrefix(p.Ref, "#/components/parameters/"):
+		ref, ok = d.Components.Parameters[p.Ref[len("#/components/parameters/"):]]
+	case strings.HasPrefix(p.Ref, "#/parameters/"):
+		ref, ok = d.Parameters[p.Ref[len("#/parameters/"):]]
+	}
+	if !ok {
+		return p, errors.New("unresolvable parameter reference '" + p.Ref + "'")
+	}
+	return ref, nil
+}
+
+func (d *openAPIDocument) schema(p openAPIParameter) (openAPISchema, error) {
+	s := p.openAPISchema
+	if p.Schema != nil {
+		s = *p.Schema
+	}
+	if s.Ref == "" {
+		return s, nil
+	}
+	var ref openAPISchema
+	var ok bool
+	switch {
+	case strings.HasPrefix(s.Ref, "#/components/schemas/"):
+		ref, ok = d.Components.Schemas[s.Ref[len("#/components/schemas/"):]]
+	case strings.HasPrefix(s.Ref, "#/definitions/"):
+		ref, ok = d.Definitions[s.Ref[len("#/definitions/"):]]
+	}
+	if !ok {
+		return s, errors.New("unresolvable schema reference '" + s.Ref + "'")
+	}
+	return ref, nil
+}
+
+// LoadOpenAPI registers a route for every operation of an OpenAPI 3 or
+// Swagger 2 document, read as JSON from rd. The handle of an operation is
+// looked up by its operationId in handles.
+//
+// Path templates are converted into route paths, e.g. /users/{id} into
+// /users/:id. Path parameters with an integer schema are constrained to int,
+// or uint if their minimum is not negative, strings with the uuid format to
+// uuid. The servers of the document are ignored; use Mount to serve the
+// routes below a base path.
+//
+// The document is validated before any route is registered: it returns an
+// error for operations without operationId or handle and for path templates
+// with parameters not spanning a whole segment. Routes conflicting with each
+// other or with existing routes result in an error as well, in which case the
+// operations registered before the failing one remain registered.
+func (r *HttpRouter) LoadOpenAPI(rd io.Reader, handles map[string]HttpHandle) error {
+	var doc openAPIDocument
+	if err := json.NewDecoder(rd).Decode(&doc); err != nil {
+		return fmt.Errorf("openapi: %v", err)
+	}
+
+	type operation struct {
+		method, path, id string
+	}
+	var ops []operation
+
+	templates := make([]string, 0, len(doc.Paths))
+	for template := range doc.Paths {
+		templates = append(templates, template)
+	}
+	sort.Strings(templates)
+
+	for _, template := range templates {
+		item := doc.Paths[template]
+
+		var shared []openAPIParameter
+		if raw, ok := item["parameters"]; ok {
+			if err := json.Unmarshal(raw, &shared); err != nil {
+				return fmt.Errorf("openapi: parameters of %s: %v", template, err)
+			}
+		}
+
+		for _, m := range openAPIMethods {
+			raw, ok := item[m.key]
+			if !ok {
+				continue
+			}
+			var op openAPIOperation
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return fmt.Errorf("openapi: %s %s: %v", m.method, template, err)
+			}
+			if op.OperationID == "" {
+				return fmt.Errorf("openapi: %s %s: operation has no operationId", m.method, template)
+			}
+			if handles[op.OperationID] == nil {
+				return fmt.Errorf("openapi: %s %s: no handle for operation '%s'", m.method, template, op.OperationID)
+			}
+
+			path, err := doc.routePath(template, append(append([]openAPIParameter(nil), shared...), op.Parameters...))
+			if err != nil {
+				return fmt.Errorf("openapi: %s %s: %v", m.method, template, err)
+			}
+			ops = append(ops, operation{m.method, path, op.OperationID})
+		}
+	}
+
+	for _, op := range ops {
+		if err := r.TryHandle(op.method, op.path, handles[op.id]); err != nil {
+			return fmt.Errorf("openapi: operation '%s' (%s %s): %v", op.id, op.method, op.path, err)
+		}
+	}
+	return nil
+}
+
+// routePath converts a path template into a route path. The parameters of
+// the operation take precedence over those of the path item, which come
+// first.
+func (d *openAPIDocument) routePath(template string, params []openAPIParameter) (string, error) {
+	constraints := make(map[string]string)
+	for _, p := range params {
+		p, err := d.parameter(p)
+		if err != nil {
+			return "", err
+		}
+		if p.In != "path" {
+			continue
+		}
+		s, err := d.schema(p)
+		if err != nil {
+			return "", err
+		}
+		constraints[p.Name] = s.constraint()
+	}
+
+	segments := strings.Split(template, "/")
+	for i, seg := range segments {
+		open := strings.IndexByte(seg, '{')
+		if open < 0 {
+			continue
+		}
+		if open != 0 || seg[len(seg)-1] != '}' || strings.Count(seg, "{") != 1 {
+			return "", errors.New("path parameters must span a whole segment in '" + template + "'")
+		}
+
+		name := seg[1 : len(seg)-1]
+		segments[i] = ":" + name
+		if c := constraints[name]; c != "" {
+			segments[i] += "|" + c
+		}
+	}
+	return strings.Join(segments, "/"), nil
+}
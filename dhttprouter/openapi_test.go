@@ -0,0 +1,158 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+const testOpenAPI = `{
+	"openapi": "3.1.0",
+	"servers": [{"url": "https://api.example.com/v1"}],
+	"paths": {
+		"/users": {
+			"get": {"operationId": "listUsers"},
+			"post": {"operationId": "createUser"}
+		},
+		"/users/{id}": {
+			"parameters": [{"$ref": "#/components/parameters/UserID"}],
+			"get": {"operationId": "getUser"},
+			"delete": {
+				"operationId": "deleteUser",
+				"parameters": [{"name": "id", "in": "path", "schema": {"type": "integer"}}]
+			}
+		},
+		"/objects/{key}/versions/{version}": {
+			"get": {
+				"operationId": "getObjectVersion",
+				"parameters": [
+					{"name": "key", "in": "path", "schema": {"$ref": "#/components/schemas/Key"}},
+					{"name": "version", "in": "path", "schema": {"type": ["integer", "null"]}},
+					{"name": "version", "in": "query", "schema": {"type": "string"}}
+				]
+			}
+		}
+	},
+	"components": {
+		"parameters": {
+			"UserID": {"name": "id", "in": "path", "schema": {"type": "integer", "minimum": 1}}
+		},
+		"schemas": {
+			"Key": {"type": "string", "format": "uuid"}
+		}
+	}
+}`
+
+func TestLoadOpenAPI(t *testing.T) {
+	var hit string
+	handle := func(name string) HttpHandle {
+		return func(_ http.ResponseWriter, _ *http.Request, ps drouter.Params) {
+			hit = name
+			for _, p := range ps {
+				hit += " " + p.Key + "=" + p.Value
+			}
+		}
+	}
+	handles := map[string]HttpHandle{
+		"listUsers":        handle("listUsers"),
+		"createUser":       handle("createUser"),
+		"getUser":          handle("getUser"),
+		"deleteUser":       handle("deleteUser"),
+		"getObjectVersion": handle("getObjectVersion"),
+	}
+
+	router := New()
+	if err := router.LoadOpenAPI(strings.NewReader(testOpenAPI), handles); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []RouteInfo{
+		{Method: http.MethodGet, Path: "/objects/:key|uuid/versions/:version|int"},
+		{Method: http.MethodGet, Path: "/users"},
+		{Method: http.MethodPost, Path: "/users"},
+		{Method: http.MethodDelete, Path: "/users/:id|int"},
+		{Method: http.MethodGet, Path: "/users/:id|uint"},
+	}
+	routes := router.Routes()
+	for i := range routes {
+		routes[i].Handler = HandlerID{}
+	}
+	if !reflect.DeepEqual(routes, want) {
+		t.Errorf("wrong routes:\nwant %v\n got %v", want, routes)
+	}
+
+	tests := []struct {
+		method, path string
+		hit          string
+	}{
+		{http.MethodGet, "/users", "listUsers"},
+		{http.MethodPost, "/users", "createUser"},
+		{http.MethodGet, "/users/42", "getUser id=42"},
+		{http.MethodGet, "/users/-1", ""},
+		{http.MethodDelete, "/users/-1", "deleteUser id=-1"},
+		{http.MethodGet, "/objects/123e4567-e89b-12d3-a456-426614174000/versions/3", "getObjectVersion key=123e4567-e89b-12d3-a456-426614174000 version=3"},
+		{http.MethodGet, "/objects/readme/versions/3", ""},
+	}
+	for _, tr := range tests {
+		hit = ""
+		r, _ := http.NewRequest(tr.method, tr.path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), r)
+		if hit != tr.hit {
+			t.Errorf("%s %s: want %q, got %q", tr.method, tr.path, tr.hit, hit)
+		}
+	}
+}
+
+func TestLoadOpenAPISwagger2(t *testing.T) {
+	doc := `{
+		"swagger": "2.0",
+		"paths": {
+			"/pets/{petId}": {
+				"get": {
+					"operationId": "getPet",
+					"parameters": [{"$ref": "#/parameters/PetID"}]
+				}
+			}
+		},
+		"parameters": {
+			"PetID": {"name": "petId", "in": "path", "type": "integer", "format": "int64"}
+		}
+	}`
+	handle := func(http.ResponseWriter, *http.Request, drouter.Params) {}
+
+	router := New()
+	if err := router.LoadOpenAPI(strings.NewReader(doc), map[string]HttpHandle{"getPet": handle}); err != nil {
+		t.Fatal(err)
+	}
+	if routes := router.Routes(); len(routes) != 1 || routes[0].Path != "/pets/:petId|int" {
+		t.Errorf("wrong routes: %v", routes)
+	}
+}
+
+func TestLoadOpenAPIInvalid(t *testing.T) {
+	handle := func(http.ResponseWriter, *http.Request, drouter.Params) {}
+	handles := map[string]HttpHandle{"a": handle, "b": handle}
+
+	tests := []struct {
+		doc string
+		err string
+	}{
+		{`{"paths": `, "unexpected EOF"},
+		{`{"paths": {"/a": {"get": {}}}}`, "no operationId"},
+		{`{"paths": {"/a": {"get": {"operationId": "c"}}}}`, "no handle for operation 'c'"},
+		{`{"paths": {"/a/{name}.json": {"get": {"operationId": "a"}}}}`, "whole segment"},
+		{`{"paths": {"/a/{id}": {"get": {"operationId": "a", "parameters": [{"$ref": "#/components/parameters/X"}]}}}}`, "unresolvable parameter reference"},
+		{`{"paths": {"/a/{id}": {"get": {"operationId": "a", "parameters": [{"name": "id", "in": "path", "schema": {"$ref": "#/x"}}]}}}}`, "unresolvable schema reference"},
+		{`{"paths": {"/a/{id}": {"get": {"operationId": "a"}}, "/a/{name}": {"get": {"operationId": "b"}}}}`, "conflicts"},
+	}
+	for _, test := range tests {
+		err := New().LoadOpenAPI(strings.NewReader(test.doc), handles)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("wrong error for %s: want %q, got %v", test.doc, test.err, err)
+		}
+	}
+}
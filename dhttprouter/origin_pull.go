@@ -0,0 +1,203 @@
+package dhttprouter
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+// Default for OriginPull.MaxSize
+const defaultOriginPullMaxSize = 10 << 20
+
+// OriginPull serves static files from a local directory, which it fills on
+// demand from an origin server, turning the router into a small pull-through
+// cache for assets. Its Handle method is used as the handle of a route whose
+// path ends with "/*filepath", e.g.
+//
+//	assets := &dhttprouter.OriginPull{
+//		Origin: "https://cdn.example.com/assets",
+//		Dir:    "/var/cache/assets",
+//		TTL:    24 * time.Hour,
+//	}
+//	router.GET("/assets/*filepath", assets.Handle)
+//
+// Files missing locally or older than TTL are fetched from the origin URL
+// followed by the file path, stored under Dir and served. If the origin
+// fails, a stale local copy is served. Responses of the origin other than
+// 200 are passed on to the client as their status without being stored.
+type OriginPull struct {
+	// Base URL of the origin, without trailing slash
+	Origin string
+
+	// Local directory the files are stored in
+	Dir string
+
+	// Time after which stored files are fetched again. If zero, stored files
+	// never expire.
+	TTL time.Duration
+
+	// Files larger than this are passed through without being stored.
+	// Defaults to 10 MiB.
+	MaxSize int64
+
+	// Client used to fetch the files. If not set, http.DefaultClient is used.
+	Client *http.Client
+}
+
+func (o *OriginPull) maxSize() int64 {
+	if o.MaxSize > 0 {
+		return o.MaxSize
+	}
+	return defaultOriginPullMaxSize
+}
+
+// Handle serves the file named by the filepath parameter, see OriginPull.
+func (o *OriginPull) Handle(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+	name := path.Clean("/" + ps.ByName("filepath"))
+	if name == "/" || strings.HasSuffix(ps.ByName("filepath"), "/") {
+		http.NotFound(w, req)
+		return
+	}
+	local := filepath.Join(o.Dir, filepath.FromSlash(name))
+
+	fi, err := os.Stat(local)
+	cached := err == nil && fi.Mode().IsRegular()
+	if cached && (o.TTL <= 0 || time.Since(fi.ModTime()) < o.TTL) {
+		o.serveStored(w, req, name, local)
+		return
+	}
+
+	resp, err := o.fetch(req, name)
+	if err != nil {
+		if cached {
+			// Better stale than nothing
+			o.serveStored(w, req, name, local)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if cached && resp.StatusCode >= 500 {
+			o.serveStored(w, req, name, local)
+			return
+		}
+		http.Error(w, http.StatusText(resp.StatusCode), resp.StatusCode)
+		return
+	}
+
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		w.Header().Set("Content-Type", ct)
+	}
+
+	max := o.maxSize()
+	if resp.ContentLength > max {
+		o.passThrough(w, req, resp, nil)
+		return
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, max+1))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+		return
+	}
+	if int64(len(body)) > max {
+		o.passThrough(w, req, resp, body)
+		return
+	}
+
+	// The file is served even if it can't be stored
+	o.store(local, body)
+	http.ServeContent(w, req, name, time.Now(), bytes.NewReader(body))
+}
+
+// serveStored serves the stored copy of the named file. Unlike
+// http.ServeFile, it serves files named index.html instead of redirecting
+// to their directory.
+func (o *OriginPull) serveStored(w http.ResponseWriter, req *http.Request, name, local string) {
+	f, err := os.Open(local)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	http.ServeContent(w, req, name, fi.ModTime(), f)
+}
+
+// fetch requests the named file from the origin. The name is escaped, so
+// the fetched resource is always the one stored under the name, e.g.
+// "/a%3Fv=1" for "/a?v=1".
+func (o *OriginPull) fetch(req *http.Request, name string) (*http.Response, error) {
+	u, err := url.Parse(o.Origin)
+	if err != nil {
+		return nil, err
+	}
+	u.RawPath = strings.TrimSuffix(u.EscapedPath(), "/") + (&url.URL{Path: name}).EscapedPath()
+	u.Path = strings.TrimSuffix(u.Path, "/") + name
+
+	oreq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	client := o.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(oreq)
+}
+
+// passThrough sends a file which is too large to be stored to the client,
+// starting with the part of the body which was already read.
+func (o *OriginPull) passThrough(w http.ResponseWriter, req *http.Request, resp *http.Response, head []byte) {
+	if resp.ContentLength >= 0 {
+		w.Header().Set("Content-Length", resp.Header.Get("Content-Length"))
+	}
+	w.WriteHeader(http.StatusOK)
+	if req.Method == http.MethodHead {
+		return
+	}
+	w.Write(head)
+	io.Copy(w, resp.Body)
+}
+
+// store writes the file atomically, so concurrent requests never see a
+// partial file.
+func (o *OriginPull) store(local string, body []byte) error {
+	dir := filepath.Dir(local)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(dir, ".pull-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), local)
+}
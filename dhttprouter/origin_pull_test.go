@@ -0,0 +1,156 @@
+package dhttprouter
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOriginPull(t *testing.T) {
+	fetches := map[string]int{}
+	down := false
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fetches[req.URL.Path]++
+		switch {
+		case down:
+			http.Error(w, "down", http.StatusServiceUnavailable)
+		case req.URL.Path == "/assets/app.css":
+			w.Header().Set("Content-Type", "text/css")
+			w.Write([]byte("body{}"))
+		case req.URL.Path == "/assets/video.mp4":
+			w.Write([]byte(strings.Repeat("v", 100)))
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer origin.Close()
+
+	dir, err := ioutil.TempDir("", "drouter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pull := &OriginPull{Origin: origin.URL + "/assets", Dir: dir, TTL: time.Hour, MaxSize: 50}
+	router := New()
+	router.GET("/static/*filepath", pull.Handle)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 2; i++ {
+		w := get("/static/app.css")
+		if w.Code != http.StatusOK || w.Body.String() != "body{}" || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/css") {
+			t.Errorf("wrong response: %d %q %q", w.Code, w.Body.String(), w.Header().Get("Content-Type"))
+		}
+	}
+	if fetches["/assets/app.css"] != 1 {
+		t.Errorf("cached file fetched %d times", fetches["/assets/app.css"])
+	}
+	if data, err := ioutil.ReadFile(filepath.Join(dir, "app.css")); err != nil || string(data) != "body{}" {
+		t.Errorf("file not stored: %q, %v", data, err)
+	}
+
+	// too large to be stored
+	if w := get("/static/video.mp4"); w.Code != http.StatusOK || w.Body.Len() != 100 {
+		t.Errorf("wrong response for large file: %d %d", w.Code, w.Body.Len())
+	}
+	if _, err := os.Stat(filepath.Join(dir, "video.mp4")); err == nil {
+		t.Error("large file stored")
+	}
+
+	if w := get("/static/missing.js"); w.Code != http.StatusNotFound {
+		t.Errorf("wrong status for missing file: %d", w.Code)
+	}
+
+	// expired copies are refreshed, or served stale if the origin fails
+	old := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(filepath.Join(dir, "app.css"), old, old)
+	down = true
+	if w := get("/static/app.css"); w.Code != http.StatusOK || w.Body.String() != "body{}" {
+		t.Errorf("stale copy not served: %d %q", w.Code, w.Body.String())
+	}
+	if fetches["/assets/app.css"] != 2 {
+		t.Errorf("expired file not fetched again")
+	}
+	if w := get("/static/other.css"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("wrong status for failing origin: %d", w.Code)
+	}
+}
+
+func TestOriginPullEscaping(t *testing.T) {
+	var fetched []string
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fetched = append(fetched, req.URL.EscapedPath()+"?"+req.URL.RawQuery)
+		w.Write([]byte(req.URL.Path))
+	}))
+	defer origin.Close()
+
+	dir, err := ioutil.TempDir("", "drouter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pull := &OriginPull{Origin: origin.URL + "/assets/", Dir: dir}
+	router := New()
+	router.GET("/static/*filepath", pull.Handle)
+
+	for _, tt := range []struct{ path, fetched, name string }{
+		{"/static/a%3Fv=1", "/assets/a%3Fv=1?", "a?v=1"},
+		{"/static/b%23c", "/assets/b%23c?", "b#c"},
+		{"/static/d%20e.css", "/assets/d%20e.css?", "d e.css"},
+	} {
+		fetched = nil
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != http.StatusOK || len(fetched) != 1 || fetched[0] != tt.fetched {
+			t.Errorf("%s: wrong fetch %d %v", tt.path, w.Code, fetched)
+		}
+		// The stored file holds the fetched resource
+		if data, err := ioutil.ReadFile(filepath.Join(dir, tt.name)); err != nil || string(data) != "/assets/"+tt.name {
+			t.Errorf("%s: wrong stored file %q, %v", tt.path, data, err)
+		}
+	}
+}
+
+func TestOriginPullIndex(t *testing.T) {
+	fetches := 0
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fetches++
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<h1>docs</h1>"))
+	}))
+	defer origin.Close()
+
+	dir, err := ioutil.TempDir("", "drouter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pull := &OriginPull{Origin: origin.URL, Dir: dir}
+	router := New()
+	router.GET("/static/*filepath", pull.Handle)
+
+	// Served from the origin first, then from the stored copy
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/static/docs/index.html", nil))
+		if w.Code != http.StatusOK || w.Body.String() != "<h1>docs</h1>" {
+			t.Errorf("request %d: wrong response %d %q %v", i, w.Code, w.Body, w.Header())
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("stored file fetched %d times", fetches)
+	}
+}
@@ -0,0 +1,159 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// PanicBudget isolates routes which panic repeatedly. If a route panics more
+// than Budget times within Window, it is disabled and answered with 503
+// Service Unavailable, so a crashing endpoint can not eat the capacity of
+// the server. The panics themselves are passed on to the PanicHandler as
+// usual.
+type PanicBudget struct {
+	// Number of panics a route may have within Window. The next panic
+	// disables the route. Defaults to 5.
+	Budget int
+
+	// Time window in which panics are counted. Defaults to one minute.
+	Window time.Duration
+
+	// Time after which a disabled route is enabled again. If zero, the route
+	// stays disabled until EnableRoute is called.
+	Cooldown time.Duration
+
+	// Optional function which is called when a route is disabled, e.g. to
+	// alert an operator. It is called in the goroutine of the request with
+	// the last panic value.
+	OnDisable func(method, path string, rcv interface{})
+}
+
+// panicState tracks the recent panics of a route.
+type panicState struct {
+	// Set while the route is disabled. Accessed atomically.
+	disabled uint32
+
+	mu    sync.Mutex
+	times []time.Time
+	until time.Time
+}
+
+func (b *PanicBudget) budget() int {
+	if b.Budget > 0 {
+		return b.Budget
+	}
+	return 5
+}
+
+func (b *PanicBudget) window() time.Duration {
+	if b.Window > 0 {
+		return b.Window
+	}
+	return time.Minute
+}
+
+// available reports whether the route may serve requests, enabling it again
+// if its cooldown has passed.
+func (s *panicState) available() bool {
+	if atomic.LoadUint32(&s.disabled) == 0 {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.until.IsZero() || time.Now().Before(s.until) {
+		return false
+	}
+	s.enable()
+	return true
+}
+
+// enable enables the route and forgets its panics. s.mu must be held.
+func (s *panicState) enable() {
+	s.times = s.times[:0]
+	s.until = time.Time{}
+	atomic.StoreUint32(&s.disabled, 0)
+}
+
+// observe records a panic and reports whether it disabled the route.
+func (s *panicState) observe(b *PanicBudget, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if atomic.LoadUint32(&s.disabled) != 0 {
+		return false
+	}
+
+	// Drop panics which left the window
+	start := now.Add(-b.window())
+	i := 0
+	for i < len(s.times) && !s.times[i].After(start) {
+		i++
+	}
+	s.times = append(s.times[:0], s.times[i:]...)
+
+	if len(s.times) < b.budget() {
+		s.times = append(s.times, now)
+		return false
+	}
+
+	if b.Cooldown > 0 {
+		s.until = now.Add(b.Cooldown)
+	}
+	atomic.StoreUint32(&s.disabled, 1)
+	return true
+}
+
+// serveDisabled answers requests of a disabled route.
+func (b *PanicBudget) serveDisabled(w http.ResponseWriter) {
+	if b.Cooldown > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int((b.Cooldown+time.Second-1)/time.Second)))
+	}
+	http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+}
+
+// recover counts a panic of the route and passes it on. It must be deferred
+// directly.
+func (b *PanicBudget) recover(rt *route) {
+	rcv := recover()
+	if rcv == nil {
+		return
+	}
+	// Aborting the response on purpose is not a crash
+	if rcv != http.ErrAbortHandler && rt.panics.observe(b, time.Now()) && b.OnDisable != nil {
+		b.OnDisable(rt.method, rt.path, rcv)
+	}
+	panic(rcv)
+}
+
+// EnableRoute enables the route registered with the given method and path
+// again after it was disabled by the PanicBudget or DisableRoute, and reports
+// whether it was disabled.
+func (r *HttpRouter) EnableRoute(method, path string) bool {
+	rt := r.loadTable().lookupRoute(method, path)
+	if rt == nil {
+		return false
+	}
+
+	rt.panics.mu.Lock()
+	defer rt.panics.mu.Unlock()
+	disabled := atomic.SwapUint32(&rt.disabled, 0) != 0
+	disabled = atomic.LoadUint32(&rt.panics.disabled) != 0 || disabled
+	rt.panics.enable()
+	return disabled
+}
+
+// DisableRoute disables the route registered with exactly the given method
+// and path, which is then answered with 503 Service Unavailable until
+// EnableRoute is called, and reports whether there is such a route. Unlike
+// Remove, it may be called while r serves requests.
+func (r *HttpRouter) DisableRoute(method, path string) bool {
+	rt := r.loadTable().lookupRoute(method, path)
+	if rt == nil {
+		return false
+	}
+	atomic.StoreUint32(&rt.disabled, 1)
+	return true
+}
@@ -0,0 +1,127 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterPanicBudget(t *testing.T) {
+	type disabled struct {
+		method, path string
+		rcv          interface{}
+	}
+	var alerts []disabled
+	crash := true
+
+	router := New()
+	router.PanicHandler = func(w http.ResponseWriter, _ *http.Request, _ interface{}) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	router.PanicBudget = &PanicBudget{
+		Budget: 2,
+		Window: time.Minute,
+		OnDisable: func(method, path string, rcv interface{}) {
+			alerts = append(alerts, disabled{method, path, rcv})
+		},
+	}
+	router.GET("/crash/:id", func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		if crash {
+			panic("boom")
+		}
+	})
+	router.GET("/ok", func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {})
+
+	serve := func(path string) int {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	for i, want := range []int{500, 500, 500, 503, 503} {
+		if code := serve("/crash/1"); code != want {
+			t.Errorf("request %d: got status %d, want %d", i, code, want)
+		}
+	}
+	if len(alerts) != 1 || alerts[0] != (disabled{http.MethodGet, "/crash/:id", "boom"}) {
+		t.Errorf("unexpected alerts: %v", alerts)
+	}
+
+	// Other routes are not affected
+	if code := serve("/ok"); code != http.StatusOK {
+		t.Errorf("unaffected route: got status %d", code)
+	}
+
+	crash = false
+	if !router.EnableRoute(http.MethodGet, "/crash/:id") {
+		t.Error("route was not reported as disabled")
+	}
+	if router.EnableRoute(http.MethodGet, "/crash/:id") {
+		t.Error("enabled route was reported as disabled")
+	}
+	if code := serve("/crash/1"); code != http.StatusOK {
+		t.Errorf("enabled route: got status %d", code)
+	}
+}
+
+func TestRouterPanicBudgetCooldown(t *testing.T) {
+	router := New()
+	router.PanicHandler = func(w http.ResponseWriter, _ *http.Request, _ interface{}) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	router.PanicBudget = &PanicBudget{
+		Budget:   1,
+		Window:   50 * time.Millisecond,
+		Cooldown: 50 * time.Millisecond,
+	}
+	crash := true
+	router.GET("/", func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		if crash {
+			panic("boom")
+		}
+	})
+	router.GET("/abort", func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		panic(http.ErrAbortHandler)
+	})
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, path, nil)
+		func() {
+			defer func() { recover() }()
+			router.ServeHTTP(w, r)
+		}()
+		return w
+	}
+
+	// Panics outside of the window are forgotten
+	serve("/")
+	time.Sleep(60 * time.Millisecond)
+	serve("/")
+	if w := serve("/"); w.Code != http.StatusInternalServerError {
+		t.Fatalf("route disabled too early: status %d", w.Code)
+	}
+
+	w := serve("/")
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") != "1" {
+		t.Errorf("expected 503 with Retry-After, got %d %v", w.Code, w.Header())
+	}
+
+	crash = false
+	time.Sleep(60 * time.Millisecond)
+	if w := serve("/"); w.Code != http.StatusOK {
+		t.Errorf("route not enabled after cooldown: status %d", w.Code)
+	}
+
+	// Aborted responses do not count
+	for i := 0; i < 3; i++ {
+		serve("/abort")
+	}
+	if router.EnableRoute(http.MethodGet, "/abort") {
+		t.Error("route disabled by aborted responses")
+	}
+}
@@ -0,0 +1,81 @@
+package dhttprouter
+
+import (
+	"strconv"
+	"sync/atomic"
+
+	"github.com/thekhanj/drouter"
+)
+
+// ParamsOverflow selects what a HttpRouter does with routes whose Params may
+// exceed the capacity of its pooled Params, see HttpRouter.ParamsCapacity.
+type ParamsOverflow int
+
+const (
+	// ParamsRealloc grows the Params of such routes transparently, which
+	// allocates on every request. It is the default.
+	ParamsRealloc ParamsOverflow = iota
+
+	// ParamsReject refuses to register such routes. Handle panics and
+	// TryHandle returns a *drouter.RouteError of kind drouter.TooManyParams.
+	ParamsReject
+
+	// ParamsPanic panics on requests of such routes, meant for tests and
+	// debug builds to find routes which allocate.
+	ParamsPanic
+)
+
+var paramsOverflowNames = [...]string{
+	ParamsRealloc: "ParamsRealloc",
+	ParamsReject:  "ParamsReject",
+	ParamsPanic:   "ParamsPanic",
+}
+
+func (o ParamsOverflow) String() string {
+	if o >= 0 && int(o) < len(paramsOverflowNames) {
+		return paramsOverflowNames[o]
+	}
+	return "ParamsOverflow(" + strconv.Itoa(int(o)) + ")"
+}
+
+// ParamsStats describes the pooled Params of a HttpRouter.
+type ParamsStats struct {
+	// Capacity of the pooled Params
+	Capacity int
+
+	// The configured behavior for exceeding the capacity
+	Overflow ParamsOverflow
+
+	// Number of requests whose Params exceeded the capacity
+	Overflows uint64
+}
+
+// ParamsStats returns the capacity of the pooled Params and how often it was
+// exceeded.
+func (r *HttpRouter) ParamsStats() ParamsStats {
+	return r.paramsStats(r.loadTable())
+}
+
+func (r *HttpRouter) paramsStats(t *routeTable) ParamsStats {
+	return ParamsStats{
+		Capacity:  int(t.maxParams),
+		Overflow:  r.ParamsOverflow,
+		Overflows: atomic.LoadUint64(&r.paramsOverflows),
+	}
+}
+
+// routeParams returns the number of params the route with the given path may
+// need: its parameters and the reserve for params appended by middleware.
+func (r *HttpRouter) routeParams(path string) uint16 {
+	return uint16(int(drouter.CountParams(path)) + r.ParamsReserve)
+}
+
+// overflowParams records a request of the route needing n params, which
+// exceed the capacity.
+func (r *HttpRouter) overflowParams(rt *route, n int, capacity uint16) {
+	atomic.AddUint64(&r.paramsOverflows, 1)
+	if r.ParamsOverflow == ParamsPanic {
+		panic("route " + rt.method + " " + rt.path + " needs " + strconv.Itoa(n) +
+			" params, but the capacity is " + strconv.Itoa(int(capacity)))
+	}
+}
@@ -0,0 +1,90 @@
+package dhttprouter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterParamsCapacity(t *testing.T) {
+	var got drouter.Params
+	handle := func(_ http.ResponseWriter, _ *http.Request, ps drouter.Params) {
+		got = append(got[:0], ps...)
+	}
+
+	// The capacity grows with the routes, the matched route path does not
+	// count
+	router := New()
+	router.SaveMatchedRoutePath = true
+	router.ParamsReserve = 1
+	router.GET("/a/:b/:c", handle)
+	if stats := router.ParamsStats(); stats.Capacity != 3 || stats.Overflow != ParamsRealloc {
+		t.Errorf("wrong stats %+v", stats)
+	}
+
+	// A fixed capacity reallocates transparently
+	router = New()
+	router.ParamsCapacity = 1
+	router.GET("/a/:b/:c", handle)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/a/1/2", nil))
+	if len(got) != 2 || got.ByName("c") != "2" {
+		t.Errorf("wrong params %v", got)
+	}
+	if stats := router.ParamsStats(); stats.Capacity != 1 || stats.Overflows != 1 {
+		t.Errorf("wrong stats %+v", stats)
+	}
+	if s := router.Snapshot(); s.Params.Overflows != 1 {
+		t.Errorf("wrong snapshot stats %+v", s.Params)
+	}
+
+	// Host params count as well
+	router.GET("/x/:y", handle)
+	req := httptest.NewRequest(http.MethodGet, "/x/1", nil)
+	req = req.WithContext(context.WithValue(req.Context(), HostParamsKey, drouter.Params{{Key: "tenant", Value: "t"}}))
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	if got.ByName("tenant") != "t" || router.ParamsStats().Overflows != 2 {
+		t.Errorf("wrong params %v, stats %+v", got, router.ParamsStats())
+	}
+
+	// Rejected at registration
+	router = New()
+	router.ParamsCapacity = 2
+	router.ParamsReserve = 1
+	router.ParamsOverflow = ParamsReject
+	router.GET("/a/:b", handle)
+	err := router.TryHandle(http.MethodGet, "/a/:b/:c", handle)
+	var rerr *drouter.RouteError
+	if !errors.As(err, &rerr) || rerr.Kind != drouter.TooManyParams {
+		t.Errorf("wrong error %v", err)
+	}
+	if len(router.Routes()) != 1 {
+		t.Error("rejected route was registered")
+	}
+
+	// Panic on request
+	router = New()
+	router.ParamsCapacity = 1
+	router.ParamsOverflow = ParamsPanic
+	router.GET("/a/:b", handle)
+	router.GET("/a/:b/:c", handle)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/a/1", nil))
+	recv := catchPanic(func() {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/a/1/2", nil))
+	})
+	if recv == nil {
+		t.Error("overflow did not panic")
+	}
+}
+
+func TestParamsOverflowString(t *testing.T) {
+	if s := ParamsReject.String(); s != "ParamsReject" {
+		t.Errorf("wrong name %q", s)
+	}
+	if s := ParamsOverflow(9).String(); s != "ParamsOverflow(9)" {
+		t.Errorf("wrong name %q", s)
+	}
+}
@@ -0,0 +1,114 @@
+package dhttprouter
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Plugin is an extension of a HttpRouter, e.g. for metrics, tracing or an API
+// gateway, which is added with Register. Besides naming itself, a plugin
+// implements any of RoutePlugin, CompilePlugin and DispatchPlugin to hook
+// into the respective phase of the router.
+type Plugin interface {
+	// Name identifies the plugin, it must be unique per router.
+	Name() string
+}
+
+// RoutePlugin hooks into the registration of routes.
+type RoutePlugin interface {
+	Plugin
+
+	// OnRoute is called for every route registered after the plugin, before
+	// the route is added to the tree. It returns the handle to register, e.g.
+	// the given one wrapped into instrumentation, or an error rejecting the
+	// route, which is then returned by TryHandle or panicked by Handle.
+	OnRoute(method, path string, handle HttpHandle) (HttpHandle, error)
+}
+
+// CompilePlugin hooks into the completion of the route table, see Compile.
+type CompilePlugin interface {
+	Plugin
+
+	// OnCompile is called by Compile with all routes of the router, e.g. to
+	// generate documentation or to validate the routes.
+	OnCompile(routes []RouteInfo) error
+}
+
+// DispatchPlugin hooks into the dispatching of requests.
+type DispatchPlugin interface {
+	Plugin
+
+	// Dispatch returns a handler wrapping next, which dispatches the request
+	// to its route or answers it with a redirect, 404 or 405. The handler is
+	// called for every request, also for those which are not routed.
+	Dispatch(next http.Handler) http.Handler
+}
+
+// Register adds the plugin to the router. The hooks of a RoutePlugin apply to
+// the routes registered afterwards, so plugins should be registered before
+// the routes. Dispatch hooks run in the order of registration, the first
+// plugin's handler being the outermost one.
+// Register panics if the plugin is nil or a plugin with the same name is
+// already registered. Like the registration of routes, it must not be called
+// concurrently with ServeHTTP.
+func (r *HttpRouter) Register(p Plugin) {
+	if p == nil {
+		panic("plugin must not be nil")
+	}
+	for _, registered := range r.plugins {
+		if registered.Name() == p.Name() {
+			panic("a plugin named '" + p.Name() + "' is already registered")
+		}
+	}
+	r.plugins = append(r.plugins, p)
+
+	if _, ok := p.(DispatchPlugin); !ok {
+		return
+	}
+	var h http.Handler = http.HandlerFunc(r.dispatch)
+	for i := len(r.plugins) - 1; i >= 0; i-- {
+		if dp, ok := r.plugins[i].(DispatchPlugin); ok {
+			h = dp.Dispatch(h)
+		}
+	}
+	r.dispatcher = h
+}
+
+// Compile calls the compile hooks of the plugins with the routes of the
+// router. It should be called once all routes are registered, before the
+// router serves requests, and returns the first error of a hook.
+func (r *HttpRouter) Compile() error {
+	var routes []RouteInfo
+	for _, p := range r.plugins {
+		cp, ok := p.(CompilePlugin)
+		if !ok {
+			continue
+		}
+		if routes == nil {
+			routes = r.Routes()
+		}
+		if err := cp.OnCompile(routes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pluginRoute passes the handle of a new route through the route hooks of the
+// plugins.
+func (r *HttpRouter) pluginRoute(method, path string, handle HttpHandle) (HttpHandle, error) {
+	for _, p := range r.plugins {
+		rp, ok := p.(RoutePlugin)
+		if !ok {
+			continue
+		}
+		var err error
+		if handle, err = rp.OnRoute(method, path, handle); err != nil {
+			return nil, err
+		}
+		if handle == nil {
+			return nil, errors.New("plugin '" + p.Name() + "' returned a nil handle for path '" + path + "'")
+		}
+	}
+	return handle, nil
+}
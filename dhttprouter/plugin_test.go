@@ -0,0 +1,97 @@
+package dhttprouter
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+type testPlugin struct {
+	name     string
+	log      *[]string
+	compiled []RouteInfo
+}
+
+func (p *testPlugin) Name() string { return p.name }
+
+func (p *testPlugin) OnRoute(method, path string, handle HttpHandle) (HttpHandle, error) {
+	if strings.HasPrefix(path, "/internal") {
+		return nil, errors.New("internal routes are not allowed")
+	}
+	return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		*p.log = append(*p.log, p.name+" route "+method+" "+path)
+		handle(w, req, ps)
+	}, nil
+}
+
+func (p *testPlugin) OnCompile(routes []RouteInfo) error {
+	p.compiled = routes
+	return nil
+}
+
+func (p *testPlugin) Dispatch(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		*p.log = append(*p.log, p.name+" dispatch "+req.URL.Path)
+		next.ServeHTTP(w, req)
+	})
+}
+
+type namedPlugin string
+
+func (p namedPlugin) Name() string { return string(p) }
+
+func TestRouterRegister(t *testing.T) {
+	var log []string
+	handle := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		log = append(log, "handle")
+	}
+
+	router := New()
+	router.GET("/before", handle)
+	metrics := &testPlugin{name: "metrics", log: &log}
+	router.Register(metrics)
+	router.Register(&testPlugin{name: "tracing", log: &log})
+	router.Register(namedPlugin("noop"))
+	router.GET("/users/:id", handle)
+
+	if err := router.TryHandle(http.MethodGet, "/internal/debug", handle); err == nil {
+		t.Error("route hook did not reject route")
+	}
+	if recv := catchPanic(func() { router.Register(namedPlugin("metrics")) }); recv == nil {
+		t.Error("no panic for duplicate plugin name")
+	}
+
+	for _, path := range []string{"/users/1", "/before", "/nope"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(w, req)
+	}
+
+	want := []string{
+		"metrics dispatch /users/1",
+		"tracing dispatch /users/1",
+		"tracing route GET /users/:id",
+		"metrics route GET /users/:id",
+		"handle",
+		"metrics dispatch /before",
+		"tracing dispatch /before",
+		"handle",
+		"metrics dispatch /nope",
+		"tracing dispatch /nope",
+	}
+	if !reflect.DeepEqual(log, want) {
+		t.Errorf("wrong hook calls:\nwant %q\n got %q", want, log)
+	}
+
+	if err := router.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	if len(metrics.compiled) != 2 {
+		t.Errorf("wrong routes passed to compile hook: %v", metrics.compiled)
+	}
+}
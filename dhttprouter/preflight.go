@@ -0,0 +1,161 @@
+package dhttprouter
+
+import (
+	"container/list"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// preflightCache holds complete OPTIONS responses, see
+// HttpRouter.PreflightCacheSize. The least recently used entry is evicted
+// when the cache is full.
+type preflightCache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     list.List
+}
+
+type preflightEntry struct {
+	key string
+
+	// Version of the routes and CORS policy the response was computed with
+	version uint64
+	policy  *CORSPolicy
+
+	header http.Header
+
+	// Status code written, or zero if the status was left to the server
+	status int
+}
+
+// preflightKey returns the key of the OPTIONS response for the path, or an
+// empty string if the response must not be cached. Instead of the path
+// itself, the key holds the patterns of the routes matching it, which
+// determine the allowed methods, and only the preflight request headers the
+// response depends on. So the number of keys is bounded by the routes and
+// the allowed origins, methods and headers. Preflight requests of rejected
+// origins are never cached.
+func (r *HttpRouter) preflightKey(t *routeTable, req *http.Request, path string, policy *CORSPolicy) string {
+	if r.PreflightCacheSize <= 0 {
+		return ""
+	}
+
+	var origin, method, headers string
+	if policy != nil {
+		method = req.Header.Get("Access-Control-Request-Method")
+		if origin = req.Header.Get("Origin"); origin == "" || method == "" {
+			// Not a preflight request, which only depends on the path
+			origin, method = "", ""
+		} else if origin = policy.allowOrigin(origin); origin == "" {
+			return ""
+		} else if len(policy.AllowedHeaders) == 1 && policy.AllowedHeaders[0] == "*" {
+			// Only echoed headers vary the response
+			headers = normalizeHeaderList(req.Header.Get("Access-Control-Request-Headers"))
+		}
+	}
+	return t.routePatterns(path) + "\x00" + origin + "\x00" + method + "\x00" + headers
+}
+
+// routePatterns returns the patterns of the routes matching the path, with
+// their methods in lexical order.
+func (t *routeTable) routePatterns(path string) string {
+	if path == "*" {
+		return path
+	}
+
+	patterns := make([]string, 0, 9)
+	for method, router := range t.routers {
+		if rt, _ := router.Lookup(path, nil); rt != nil {
+			patterns = append(patterns, method+" "+rt.path)
+		}
+	}
+	for i, l := 1, len(patterns); i < l; i++ {
+		for j := i; j > 0 && patterns[j] < patterns[j-1]; j-- {
+			patterns[j], patterns[j-1] = patterns[j-1], patterns[j]
+		}
+	}
+	return strings.Join(patterns, "\x00")
+}
+
+// normalizeHeaderList returns a comma-separated list of header names in
+// lower case without optional whitespace.
+func normalizeHeaderList(list string) string {
+	names := strings.Split(list, ",")
+	for i, name := range names {
+		names[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+	return strings.Join(names, ",")
+}
+
+// cachedPreflight writes the OPTIONS response cached with the given key,
+// computed with the routes of t and the given CORS policy, and reports
+// whether there was one.
+func (r *HttpRouter) cachedPreflight(w http.ResponseWriter, t *routeTable, key string, policy *CORSPolicy) bool {
+	if key == "" {
+		return false
+	}
+	c := &r.preflights
+	c.mu.Lock()
+	elem := c.entries[key]
+	if elem == nil {
+		c.mu.Unlock()
+		return false
+	}
+	e := elem.Value.(*preflightEntry)
+	if e.version != t.version || e.policy != policy {
+		c.mu.Unlock()
+		return false
+	}
+	c.lru.MoveToFront(elem)
+	c.mu.Unlock()
+
+	h := w.Header()
+	for k, v := range e.header {
+		h[k] = append([]string(nil), v...)
+	}
+	if e.status != 0 {
+		w.WriteHeader(e.status)
+	}
+	return true
+}
+
+// storePreflight caches the OPTIONS response computed with the routes of t
+// under the given key, with the given header and status code. Only the
+// Allow, Vary and CORS headers are kept. Rejected preflight requests are
+// not cached.
+func (r *HttpRouter) storePreflight(t *routeTable, key string, policy *CORSPolicy, header http.Header, status int) {
+	if key == "" || (status != 0 && status != http.StatusNoContent) {
+		return
+	}
+	e := &preflightEntry{
+		key:     key,
+		version: t.version,
+		policy:  policy,
+		header:  make(http.Header),
+		status:  status,
+	}
+	for k, v := range header {
+		if k == "Allow" || k == "Vary" || strings.HasPrefix(k, "Access-Control-") {
+			e.header[k] = append([]string(nil), v...)
+		}
+	}
+
+	c := &r.preflights
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]*list.Element)
+	}
+	if elem := c.entries[key]; elem != nil {
+		elem.Value = e
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(e)
+	for c.lru.Len() > r.PreflightCacheSize {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*preflightEntry).key)
+	}
+}
@@ -0,0 +1,126 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterPreflightCache(t *testing.T) {
+	handle := func(http.ResponseWriter, *http.Request, drouter.Params) {}
+	router := New()
+	router.PreflightCacheSize = 2
+	router.CORS = &CORSPolicy{AllowedOrigins: []string{"https://app.example.com"}}
+	router.GET("/items/:id", handle)
+
+	preflight := func(path, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, path, nil)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+			req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := preflight("/items/1", "https://app.example.com")
+	second := preflight("/items/1", "https://app.example.com")
+	if len(router.preflights.entries) != 1 {
+		t.Fatalf("response was not cached: %v", router.preflights.entries)
+	}
+	for _, w := range []*httptest.ResponseRecorder{first, second} {
+		if w.Code != http.StatusNoContent || w.Header().Get("Allow") != "GET, OPTIONS" ||
+			w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" ||
+			len(w.Header()["Vary"]) != 3 {
+			t.Errorf("wrong response %d %v", w.Code, w.Header())
+		}
+	}
+
+	// Plain OPTIONS requests are cached, rejected preflights are not
+	if w := preflight("/items/2", ""); w.Code != http.StatusOK || w.Header().Get("Allow") != "GET, OPTIONS" {
+		t.Errorf("wrong response %d %v", w.Code, w.Header())
+	}
+	if w := preflight("/items/1", "https://evil.example.com"); w.Code != http.StatusForbidden {
+		t.Errorf("wrong response for rejected origin %d", w.Code)
+	}
+	if len(router.preflights.entries) != 2 {
+		t.Errorf("wrong cache entries %v", router.preflights.entries)
+	}
+
+	// New routes invalidate the cache
+	router.DELETE("/items/:id", handle)
+	if w := preflight("/items/1", "https://app.example.com"); w.Header().Get("Allow") != "DELETE, GET, OPTIONS" {
+		t.Errorf("stale response %v", w.Header())
+	}
+
+	// So does a new policy
+	router.CORS = &CORSPolicy{AllowedOrigins: []string{"*"}}
+	if w := preflight("/items/1", "https://app.example.com"); w.Header().Get("Access-Control-Allow-Origin") != "*" {
+		t.Errorf("stale response for new policy %v", w.Header())
+	}
+
+	// Paths matching the same routes and origins allowed by a wildcard share
+	// their entries
+	router.PreflightCacheSize = 10
+	before := len(router.preflights.entries)
+	for i := 0; i < 20; i++ {
+		preflight("/items/"+strconv.Itoa(i), "https://"+strconv.Itoa(i)+".example.com")
+	}
+	if n := len(router.preflights.entries); n != before {
+		t.Errorf("%d cache entries after preflights of one route; want %d", n, before)
+	}
+}
+
+func TestRouterPreflightCacheEviction(t *testing.T) {
+	handle := func(http.ResponseWriter, *http.Request, drouter.Params) {}
+	router := New()
+	router.PreflightCacheSize = 2
+	router.GET("/a", handle)
+	router.GET("/b", handle)
+	router.GET("/c", handle)
+
+	options := func(path string) {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodOptions, path, nil))
+	}
+	cached := func(path string) bool {
+		_, ok := router.preflights.entries[router.preflightKey(router.loadTable(), httptest.NewRequest(http.MethodOptions, path, nil), path, nil)]
+		return ok
+	}
+
+	options("/a")
+	options("/b")
+	options("/a") // most recently used
+	options("/c")
+	if len(router.preflights.entries) != 2 || router.preflights.lru.Len() != 2 {
+		t.Fatalf("wrong cache entries %v", router.preflights.entries)
+	}
+	if !cached("/a") || cached("/b") || !cached("/c") {
+		t.Errorf("least recently used entry was not evicted: a=%v b=%v c=%v", cached("/a"), cached("/b"), cached("/c"))
+	}
+}
+
+func TestRouterPreflightCacheSetCORS(t *testing.T) {
+	router := New()
+	router.PreflightCacheSize = 10
+	router.PUT("/doc", func(http.ResponseWriter, *http.Request, drouter.Params) {})
+	router.SetCORS("/doc", CORSPolicy{AllowedOrigins: []string{"*"}, AllowedHeaders: []string{"*"}})
+
+	for _, headers := range []string{"X-A", "X-A", "X-B"} {
+		req := httptest.NewRequest(http.MethodOptions, "/doc", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+		req.Header.Set("Access-Control-Request-Headers", headers)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Headers") != headers {
+			t.Errorf("wrong response %d %v", w.Code, w.Header())
+		}
+	}
+	if len(router.preflights.entries) != 2 {
+		t.Errorf("wrong cache entries %v", router.preflights.entries)
+	}
+}
@@ -0,0 +1,180 @@
+package dhttprouter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"github.com/thekhanj/drouter"
+)
+
+// ProxyRoute configures a route forwarding requests to an upstream server,
+// see HttpRouter.ProxyWith.
+type ProxyRoute struct {
+	// Base URL of the upstream, e.g. "http://users.internal:8080/v1". The
+	// path of the upstream request is appended to its path.
+	Target *url.URL
+
+	// Path of the upstream requests, which may reference the parameters of
+	// the route by name, e.g. "/:rest" or "/users/*path". The values are
+	// escaped, so they can not add a query string. If empty, the request path
+	// is forwarded unchanged. The query string of the request is retained.
+	// Requests whose path or values contain dot segments, e.g. "..", are
+	// answered with 400 Bad Request, so they can not leave the path of the
+	// Target.
+	Path string
+
+	// Headers set on the upstream requests, replacing those of the client
+	Header http.Header
+
+	// Headers of the client which are not forwarded, e.g. "Cookie"
+	RemoveHeaders []string
+
+	// If enabled, the Host header of the client is forwarded. Otherwise the
+	// host of the Target is used.
+	PreserveHost bool
+
+	// Transport of the upstream requests. If it is not set, a
+	// DeadlineTransport passing the deadline of the request on to the
+	// upstream is used.
+	Transport http.RoundTripper
+
+	// Optional function modifying the responses of the upstream.
+	ModifyResponse func(*http.Response) error
+
+	// Optional function handling the errors of upstream requests. If it is
+	// not set, the client receives 504 Gateway Timeout if the deadline of the
+	// request passed and 502 Bad Gateway otherwise.
+	ErrorHandler func(w http.ResponseWriter, req *http.Request, err error)
+}
+
+// Proxy registers a route forwarding the requests to the target with the
+// request path appended to the path of the target, see ProxyWith.
+func (r *HttpRouter) Proxy(method, path string, target *url.URL, middleware ...Middleware) {
+	r.ProxyWith(method, path, ProxyRoute{Target: target}, middleware...)
+}
+
+// ProxyWith registers a route forwarding the requests as configured by p,
+// e.g. to strip a prefix:
+//
+//	users, _ := url.Parse("http://users.internal:8080")
+//	router.ProxyWith(http.MethodGet, "/svc/users/*rest", dhttprouter.ProxyRoute{
+//		Target:        users,
+//		Path:          "/*rest",
+//		RemoveHeaders: []string{"Cookie"},
+//	})
+//
+// The X-Forwarded-For, X-Forwarded-Host and X-Forwarded-Proto headers are set
+// on the upstream requests. ProxyWith panics if p has no Target or its Path
+// references unknown parameters or is not escaped correctly.
+func (r *HttpRouter) ProxyWith(method, path string, p ProxyRoute, middleware ...Middleware) {
+	if p.Target == nil {
+		panic("proxy target must not be nil for path '" + path + "'")
+	}
+
+	var upstream *redirectTarget
+	if p.Path != "" {
+		if _, err := url.PathUnescape(p.Path); err != nil {
+			panic("invalid proxy path '" + p.Path + "' for path '" + path + "': " + err.Error())
+		}
+		t := parseRedirectTarget(path, p.Path)
+		upstream = &t
+	}
+	transport := p.Transport
+	if transport == nil {
+		transport = &DeadlineTransport{}
+	}
+	errorHandler := p.ErrorHandler
+	if errorHandler == nil {
+		errorHandler = proxyError
+	}
+	base := strings.TrimSuffix(p.Target.Path, "/")
+	rawBase := strings.TrimSuffix(p.Target.EscapedPath(), "/")
+
+	handle := func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		target, rawTarget, query := req.URL.Path, req.URL.EscapedPath(), req.URL.RawQuery
+		if upstream != nil {
+			built, err := upstream.build(req, ps, r.UseRawPath && !r.UnescapePathValues)
+			if err != nil {
+				http.Error(w,
+					http.StatusText(http.StatusBadRequest),
+					http.StatusBadRequest,
+				)
+				return
+			}
+			// The values are escaped, so a '?' starts the query string
+			rawTarget, query = built, ""
+			if i := strings.IndexByte(built, '?'); i >= 0 {
+				rawTarget, query = built[:i], built[i+1:]
+			}
+			if target, err = url.PathUnescape(rawTarget); err != nil {
+				errorHandler(w, req, err)
+				return
+			}
+		} else if hasDotSegment(target) {
+			http.Error(w,
+				http.StatusText(http.StatusBadRequest),
+				http.StatusBadRequest,
+			)
+			return
+		}
+		if !strings.HasPrefix(target, "/") {
+			target, rawTarget = "/"+target, "/"+rawTarget
+		}
+
+		proxy := &httputil.ReverseProxy{
+			Director: func(out *http.Request) {
+				out.URL.Scheme = p.Target.Scheme
+				out.URL.Host = p.Target.Host
+				out.URL.Path = base + target
+				out.URL.RawPath = rawBase + rawTarget
+				out.URL.RawQuery = query
+				if !p.PreserveHost {
+					out.Host = p.Target.Host
+				}
+
+				for _, h := range p.RemoveHeaders {
+					out.Header.Del(h)
+				}
+				for h, v := range p.Header {
+					out.Header[http.CanonicalHeaderKey(h)] = v
+				}
+				out.Header.Set("X-Forwarded-Host", req.Host)
+				if req.TLS != nil {
+					out.Header.Set("X-Forwarded-Proto", "https")
+				} else {
+					out.Header.Set("X-Forwarded-Proto", "http")
+				}
+			},
+			Transport:      transport,
+			ModifyResponse: p.ModifyResponse,
+			ErrorHandler:   errorHandler,
+		}
+		proxy.ServeHTTP(w, req)
+	}
+	if err := r.tryHandle(method, path, handle, p.Target, middleware); err != nil {
+		panic(err.Error())
+	}
+}
+
+// hasDotSegment reports whether the path contains a "." or ".." segment.
+func hasDotSegment(path string) bool {
+	for _, seg := range strings.Split(path, "/") {
+		if isDotSegment(seg) {
+			return true
+		}
+	}
+	return false
+}
+
+// proxyError is the default ProxyRoute.ErrorHandler.
+func proxyError(w http.ResponseWriter, _ *http.Request, err error) {
+	code := http.StatusBadGateway
+	if errors.Is(err, context.DeadlineExceeded) {
+		code = http.StatusGatewayTimeout
+	}
+	http.Error(w, http.StatusText(code), code)
+}
@@ -0,0 +1,156 @@
+package dhttprouter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestRouterProxy(t *testing.T) {
+	var got *http.Request
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got = req
+		w.Header().Set("X-Upstream", "yes")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL + "/v1/")
+
+	router := New()
+	router.Proxy(http.MethodGet, "/plain/*rest", target)
+	router.ProxyWith(http.MethodPost, "/svc/users/*rest", ProxyRoute{
+		Target:        target,
+		Path:          "/users/*rest",
+		Header:        http.Header{"X-Api-Key": {"internal"}},
+		RemoveHeaders: []string{"Cookie"},
+	})
+
+	tests := []struct {
+		method, path string
+		upstream     string
+		query        string
+	}{
+		{http.MethodGet, "/plain/a/b?x=1", "/v1/plain/a/b", "x=1"},
+		{http.MethodPost, "/svc/users/42/orders?page=2", "/v1/users/42/orders", "page=2"},
+	}
+	for _, tt := range tests {
+		got = nil
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req.Header.Set("Cookie", "session=secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusCreated || w.Header().Get("X-Upstream") != "yes" {
+			t.Errorf("%s: wrong response %d %v", tt.path, w.Code, w.Header())
+		}
+		if got == nil {
+			t.Errorf("%s: upstream was not called", tt.path)
+			continue
+		}
+		if got.URL.Path != tt.upstream || got.URL.RawQuery != tt.query || got.Method != tt.method {
+			t.Errorf("%s: wrong upstream request %s %s?%s", tt.path, got.Method, got.URL.Path, got.URL.RawQuery)
+		}
+		if got.Header.Get("X-Forwarded-Host") != "example.com" || got.Header.Get("X-Forwarded-For") == "" {
+			t.Errorf("%s: forwarding headers are missing: %v", tt.path, got.Header)
+		}
+		if got.Host != target.Host {
+			t.Errorf("%s: wrong host %q", tt.path, got.Host)
+		}
+	}
+	if got.Header.Get("Cookie") != "" || got.Header.Get("X-Api-Key") != "internal" {
+		t.Errorf("headers were not rewritten: %v", got.Header)
+	}
+
+	if recv := catchPanic(func() { router.Proxy(http.MethodGet, "/nil", nil) }); recv == nil {
+		t.Error("no panic for nil target")
+	}
+}
+
+func TestRouterProxyErrors(t *testing.T) {
+	target, _ := url.Parse("http://upstream.invalid")
+	failing := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if _, ok := req.Context().Deadline(); ok {
+			return nil, context.DeadlineExceeded
+		}
+		return nil, errors.New("connection refused")
+	})
+
+	router := New()
+	router.ProxyWith(http.MethodGet, "/a", ProxyRoute{Target: target, Transport: failing})
+	router.ProxyWith(http.MethodGet, "/b", ProxyRoute{Target: target, Transport: failing})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/a", nil))
+	if w.Code != http.StatusBadGateway {
+		t.Errorf("want 502, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/b", nil).WithContext(ctx))
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("want 504, got %d", w.Code)
+	}
+}
+
+func TestRouterProxyEscaping(t *testing.T) {
+	var got *http.Request
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got = req
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL + "/v1")
+
+	router := New()
+	router.ProxyWith(http.MethodGet, "/svc/users/*rest", ProxyRoute{Target: target, Path: "/users/*rest"})
+	router.ProxyWith(http.MethodGet, "/svc/items/:id", ProxyRoute{Target: target, Path: "/items/:id?v=2"})
+	router.Proxy(http.MethodGet, "/plain/*rest", target)
+
+	tests := []struct {
+		path     string
+		code     int
+		upstream string
+		query    string
+	}{
+		{"/svc/users/1%3Fadmin=true", http.StatusOK, "/v1/users/1%3Fadmin=true", ""},
+		{"/svc/users/a%2Fb/c?x=1", http.StatusOK, "/v1/users/a/b/c", "x=1"},
+		{"/svc/users/..%2f..%2fadmin", http.StatusBadRequest, "", ""},
+		{"/svc/users/./a", http.StatusBadRequest, "", ""},
+		{"/svc/items/a%3Fb%23c", http.StatusOK, "/v1/items/a%3Fb%23c", "v=2"},
+		{"/svc/items/..", http.StatusBadRequest, "", ""},
+		{"/plain/a%2Fb", http.StatusOK, "/v1/plain/a%2Fb", ""},
+		{"/plain/..%2f..%2fadmin", http.StatusBadRequest, "", ""},
+	}
+	for _, tt := range tests {
+		got = nil
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.code {
+			t.Errorf("%s: wrong status %d", tt.path, w.Code)
+		}
+		if tt.upstream == "" {
+			if got != nil {
+				t.Errorf("%s: upstream was called with %s", tt.path, got.URL)
+			}
+			continue
+		}
+		if got == nil {
+			t.Errorf("%s: upstream was not called", tt.path)
+			continue
+		}
+		if got.URL.EscapedPath() != tt.upstream || got.URL.RawQuery != tt.query {
+			t.Errorf("%s: wrong upstream request %s?%s", tt.path, got.URL.EscapedPath(), got.URL.RawQuery)
+		}
+	}
+
+	if recv := catchPanic(func() {
+		router.ProxyWith(http.MethodGet, "/bad", ProxyRoute{Target: target, Path: "/a%zz"})
+	}); recv == nil {
+		t.Error("no panic for invalid path")
+	}
+}
@@ -0,0 +1,55 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// CanonicalQuery canonicalizes the query string of requests before the
+// lookup, so handles, caches and coalescing see the same URL for equivalent
+// requests. The parameters are sorted by name, keeping the order of the
+// values of each parameter, and tracking parameters can be stripped.
+type CanonicalQuery struct {
+	// Names of parameters removed from the query, e.g. "fbclid".
+	// A trailing '*' matches all names with the prefix, e.g. "utm_*".
+	Strip []string
+
+	// Optional function which can modify the parameters after stripping,
+	// e.g. to lower-case values or drop empty ones.
+	Rewrite func(query url.Values)
+}
+
+// stripped reports whether the parameter is to be removed.
+func (c *CanonicalQuery) stripped(name string) bool {
+	for _, s := range c.Strip {
+		if s == name || (strings.HasSuffix(s, "*") && strings.HasPrefix(name, s[:len(s)-1])) {
+			return true
+		}
+	}
+	return false
+}
+
+// apply canonicalizes the query of the request URL. Malformed queries are
+// left as they are.
+func (c *CanonicalQuery) apply(req *http.Request) {
+	if req.URL.RawQuery == "" {
+		return
+	}
+	query, err := url.ParseQuery(req.URL.RawQuery)
+	if err != nil {
+		return
+	}
+
+	for name := range query {
+		if c.stripped(name) {
+			delete(query, name)
+		}
+	}
+	if c.Rewrite != nil {
+		c.Rewrite(query)
+	}
+
+	// Encode sorts by name
+	req.URL.RawQuery = query.Encode()
+}
@@ -0,0 +1,48 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterCanonicalQuery(t *testing.T) {
+	var uri string
+	router := New()
+	router.CanonicalQuery = &CanonicalQuery{
+		Strip: []string{"utm_*", "fbclid"},
+		Rewrite: func(query url.Values) {
+			for name, values := range query {
+				if len(values) == 1 && values[0] == "" {
+					delete(query, name)
+				}
+			}
+		},
+	}
+	router.GET("/search", func(_ http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		uri = req.URL.RequestURI()
+	})
+
+	tests := []struct {
+		target, want string
+	}{
+		{"/search", "/search"},
+		{"/search?q=go&page=2", "/search?page=2&q=go"},
+		{"/search?tag=b&q=go&tag=a", "/search?q=go&tag=b&tag=a"},
+		{"/search?utm_source=x&q=go&utm_medium=y&fbclid=z", "/search?q=go"},
+		{"/search?utm_source=x", "/search"},
+		{"/search?q=a+b&empty=", "/search?q=a+b"},
+		{"/search?q=%zz&b=1", "/search?q=%zz&b=1"},
+	}
+	for _, tr := range tests {
+		uri = ""
+		r, _ := http.NewRequest(http.MethodGet, tr.target, nil)
+		router.ServeHTTP(httptest.NewRecorder(), r)
+		if uri != tr.want {
+			t.Errorf("%s: got %q, want %q", tr.target, uri, tr.want)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package dhttprouter
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+// Rate is a number of requests per time window.
+type Rate struct {
+	N   int
+	Per time.Duration
+}
+
+// RateLimitStore holds the token buckets of a RateLimiter. Stores shared by
+// several servers, e.g. backed by Redis, make the limits global.
+type RateLimitStore interface {
+	// Take takes a token from the bucket with the given key, which holds up
+	// to rate.N tokens and is refilled by rate.N tokens per rate.Per. It
+	// reports whether a token was available and, if not, how long it takes
+	// until the next one is.
+	Take(key string, rate Rate, now time.Time) (ok bool, retryAfter time.Duration)
+}
+
+// RateLimiter enforces request rate limits per client, declared per route
+// with the middleware returned by Limit, e.g.
+//
+//	limiter := &dhttprouter.RateLimiter{}
+//	router.POST("/login", login, limiter.Limit(10, time.Minute))
+//
+//	api := limiter.Limit(100, time.Minute)
+//	router.GET("/api/users", listUsers, api)
+//	router.GET("/api/posts", listPosts, api)
+//
+// Each call of Limit declares a limit of its own, routes sharing the returned
+// middleware form a group sharing the limit. Limits are enforced before the
+// handle runs, requests exceeding them are answered with 429 Too Many
+// Requests and a Retry-After header.
+type RateLimiter struct {
+	// Accessed atomically, must stay the first fields for 64-bit alignment
+	limited uint64
+	limits  uint64
+
+	// Function deriving the client a request is counted for. If it is not
+	// set, requests are counted per ClientIP.
+	Key func(req *http.Request) string
+
+	// Store of the token buckets. If it is not set, an in-memory store is
+	// used, which limits each server on its own.
+	Store RateLimitStore
+
+	// Configurable http.Handler which is called for requests exceeding their
+	// limit, after the Retry-After header was set. If it is not set,
+	// http.Error with http.StatusTooManyRequests is used.
+	TooManyRequests http.Handler
+
+	once  sync.Once
+	store RateLimitStore
+}
+
+// Limit returns a middleware limiting the routes it is applied to to n
+// requests per client within the given window. Short bursts of up to n
+// requests are allowed, as long as the average rate is kept.
+func (l *RateLimiter) Limit(n int, per time.Duration) Middleware {
+	if n <= 0 || per <= 0 {
+		panic("rate limit must allow at least one request per positive window")
+	}
+	rate := Rate{N: n, Per: per}
+	prefix := strconv.FormatUint(atomic.AddUint64(&l.limits, 1), 10) + ":"
+
+	return func(handle HttpHandle) HttpHandle {
+		return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+			ok, retryAfter := l.getStore().Take(prefix+l.key(req), rate, time.Now())
+			if !ok {
+				atomic.AddUint64(&l.limited, 1)
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+				if l.TooManyRequests != nil {
+					l.TooManyRequests.ServeHTTP(w, req)
+				} else {
+					http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				}
+				return
+			}
+			handle(w, req, ps)
+		}
+	}
+}
+
+// Limited returns the number of requests rejected so far.
+func (l *RateLimiter) Limited() uint64 {
+	return atomic.LoadUint64(&l.limited)
+}
+
+func (l *RateLimiter) key(req *http.Request) string {
+	if l.Key != nil {
+		return l.Key(req)
+	}
+	return ClientIP(req).String()
+}
+
+func (l *RateLimiter) getStore() RateLimitStore {
+	l.once.Do(func() {
+		l.store = l.Store
+		if l.store == nil {
+			l.store = NewMemoryRateLimitStore()
+		}
+	})
+	return l.store
+}
+
+// MemoryRateLimitStore is the in-memory RateLimitStore used by default. Full
+// buckets are dropped, so its size is bounded by the number of clients
+// recently seen.
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	sweepAt int
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+	rate   Rate
+}
+
+// NewMemoryRateLimitStore returns an empty in-memory store.
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: make(map[string]*tokenBucket),
+		sweepAt: 1024,
+	}
+}
+
+// refill adds the tokens accumulated since the last request.
+func (b *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last)
+	if elapsed <= 0 {
+		return
+	}
+	b.tokens += float64(b.rate.N) * float64(elapsed) / float64(b.rate.Per)
+	if b.tokens > float64(b.rate.N) {
+		b.tokens = float64(b.rate.N)
+	}
+	b.last = now
+}
+
+// Take implements RateLimitStore.
+func (s *MemoryRateLimitStore) Take(key string, rate Rate, now time.Time) (bool, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := s.buckets[key]
+	if b == nil {
+		if len(s.buckets) >= s.sweepAt {
+			s.sweep(now)
+		}
+		b = &tokenBucket{tokens: float64(rate.N), last: now, rate: rate}
+		s.buckets[key] = b
+	}
+	b.refill(now)
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	missing := 1 - b.tokens
+	return false, time.Duration(missing * float64(rate.Per) / float64(rate.N))
+}
+
+// sweep drops the buckets which are full again, as they are equivalent to
+// new ones, and adjusts the size at which the next sweep happens.
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		b.refill(now)
+		if b.tokens >= float64(b.rate.N) {
+			delete(s.buckets, key)
+		}
+	}
+	if n := 2 * len(s.buckets); n > s.sweepAt {
+		s.sweepAt = n
+	}
+}
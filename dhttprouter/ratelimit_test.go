@@ -0,0 +1,97 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRateLimiter(t *testing.T) {
+	handle := func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
+
+	limiter := &RateLimiter{}
+	api := limiter.Limit(2, time.Hour)
+
+	router := New()
+	router.POST("/login", handle, limiter.Limit(1, time.Hour))
+	router.GET("/api/users", handle, api)
+	router.GET("/api/posts", handle, api)
+
+	serve := func(method, path, addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = addr
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		method, path, addr string
+		code               int
+	}{
+		{http.MethodPost, "/login", "10.0.0.1:1234", http.StatusOK},
+		{http.MethodPost, "/login", "10.0.0.1:5678", http.StatusTooManyRequests},
+		{http.MethodPost, "/login", "10.0.0.2:1234", http.StatusOK},
+
+		// The group shares its limit, which is independent of /login
+		{http.MethodGet, "/api/users", "10.0.0.1:1234", http.StatusOK},
+		{http.MethodGet, "/api/posts", "10.0.0.1:1234", http.StatusOK},
+		{http.MethodGet, "/api/users", "10.0.0.1:1234", http.StatusTooManyRequests},
+	}
+	for i, tt := range tests {
+		w := serve(tt.method, tt.path, tt.addr)
+		if w.Code != tt.code {
+			t.Errorf("%d: %s %s from %s: want %d, got %d", i, tt.method, tt.path, tt.addr, tt.code, w.Code)
+		}
+		if tt.code == http.StatusTooManyRequests {
+			if s, _ := strconv.Atoi(w.Header().Get("Retry-After")); s <= 0 || s > 3600 {
+				t.Errorf("%d: wrong Retry-After %q", i, w.Header().Get("Retry-After"))
+			}
+		}
+	}
+	if n := limiter.Limited(); n != 2 {
+		t.Errorf("wrong number of limited requests: %d", n)
+	}
+
+	limiter.TooManyRequests = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	if w := serve(http.MethodPost, "/login", "10.0.0.1:1"); w.Code != http.StatusTeapot {
+		t.Errorf("custom handler was not used: %d", w.Code)
+	}
+
+	if recv := catchPanic(func() { limiter.Limit(0, time.Second) }); recv == nil {
+		t.Error("no panic for empty limit")
+	}
+}
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	s := NewMemoryRateLimitStore()
+	rate := Rate{N: 2, Per: time.Second}
+	now := time.Now()
+
+	for i, want := range []bool{true, true, false} {
+		if ok, _ := s.Take("a", rate, now); ok != want {
+			t.Errorf("take %d: want %v, got %v", i, want, ok)
+		}
+	}
+	if _, retryAfter := s.Take("a", rate, now); retryAfter != 500*time.Millisecond {
+		t.Errorf("wrong retry after %v", retryAfter)
+	}
+
+	// Tokens are refilled over time
+	if ok, _ := s.Take("a", rate, now.Add(500*time.Millisecond)); !ok {
+		t.Error("token was not refilled")
+	}
+
+	// Full buckets are dropped
+	s.sweepAt = 1
+	s.Take("b", rate, now.Add(time.Hour))
+	if _, ok := s.buckets["a"]; ok || len(s.buckets) != 1 {
+		t.Errorf("full bucket was not dropped: %v", s.buckets)
+	}
+}
@@ -0,0 +1,46 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/thekhanj/drouter"
+)
+
+// lookupPath returns the path used to look up the route of the request, see
+// UseRawPath.
+func (r *HttpRouter) lookupPath(req *http.Request) string {
+	if r.UseRawPath {
+		return req.URL.EscapedPath()
+	}
+	return req.URL.Path
+}
+
+// unescapeParams unescapes the values of the params looked up in an escaped
+// path. Values which are no valid escapes are kept as they are.
+func unescapeParams(ps drouter.Params) {
+	for i := range ps {
+		if !strings.Contains(ps[i].Value, "%") {
+			continue
+		}
+		if value, err := url.PathUnescape(ps[i].Value); err == nil {
+			ps[i].Value = value
+		}
+	}
+}
+
+// redirect redirects the client to the given lookup path, which is escaped
+// if UseRawPath is enabled.
+func (r *HttpRouter) redirect(w http.ResponseWriter, req *http.Request, path string, code int) {
+	if r.UseRawPath {
+		if unescaped, err := url.PathUnescape(path); err == nil {
+			prefix := MountPrefixFromContext(req.Context())
+			req.URL.Path = prefix + unescaped
+			req.URL.RawPath = prefix + path
+			http.Redirect(w, req, req.URL.String(), code)
+			return
+		}
+	}
+	redirectPath(w, req, path, code)
+}
@@ -0,0 +1,49 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterUseRawPath(t *testing.T) {
+	handle := func(w http.ResponseWriter, _ *http.Request, ps drouter.Params) {
+		w.Write([]byte(ps.ByName("name")))
+	}
+
+	tests := []struct {
+		useRaw, unescape bool
+		path             string
+		code             int
+		body, location   string
+	}{
+		{false, true, "/repos/a%2Fb/issues", http.StatusNotFound, "", ""},
+		{false, true, "/repos/a%20b/issues", http.StatusOK, "a b", ""},
+		{true, true, "/repos/a%2Fb/issues", http.StatusOK, "a/b", ""},
+		{true, true, "/repos/a%20b/issues", http.StatusOK, "a b", ""},
+		{true, false, "/repos/a%2Fb/issues", http.StatusOK, "a%2Fb", ""},
+		{true, true, "/repos/a%2Fb/issues/", http.StatusMovedPermanently, "", "/repos/a%2Fb/issues"},
+	}
+	for _, tt := range tests {
+		router := New()
+		router.UseRawPath = tt.useRaw
+		router.UnescapePathValues = tt.unescape
+		router.GET("/repos/:name/issues", handle)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+		router.ServeHTTP(w, req)
+		if w.Code != tt.code {
+			t.Errorf("%s (raw %v): wrong status: want %d, got %d", tt.path, tt.useRaw, tt.code, w.Code)
+			continue
+		}
+		if tt.code == http.StatusOK && w.Body.String() != tt.body {
+			t.Errorf("%s (raw %v, unescape %v): wrong value: want %q, got %q", tt.path, tt.useRaw, tt.unescape, tt.body, w.Body.String())
+		}
+		if loc := w.Header().Get("Location"); loc != tt.location {
+			t.Errorf("%s: wrong location: want %q, got %q", tt.path, tt.location, loc)
+		}
+	}
+}
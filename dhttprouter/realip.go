@@ -0,0 +1,166 @@
+package dhttprouter
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ProxyResolver derives the client IP of requests passing through trusted
+// reverse proxies from the Forwarded, X-Forwarded-For and X-Real-IP headers.
+// Headers are only honored if the request comes from a trusted proxy, and
+// forwarded addresses are only followed through trusted proxies, so clients
+// can't spoof their address by sending the headers themselves.
+type ProxyResolver struct {
+	trusted []*net.IPNet
+}
+
+// NewProxyResolver returns a resolver trusting proxies within the given
+// networks in CIDR notation, e.g. "10.0.0.0/8". Single IP addresses are
+// accepted as well.
+func NewProxyResolver(trustedProxies ...string) (*ProxyResolver, error) {
+	trusted, err := parseNetworks(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	return &ProxyResolver{trusted: trusted}, nil
+}
+
+// parseNetworks parses networks in CIDR notation and single IP addresses.
+func parseNetworks(networks []string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, s := range networks {
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, &net.ParseError{Type: "IP address", Text: s}
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// Trusted reports whether the given address belongs to a trusted proxy.
+func (p *ProxyResolver) Trusted(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, n := range p.trusted {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the IP address of the client which sent the request.
+// The forwarded addresses are walked from the closest proxy towards the
+// client, and the first address which is not a trusted proxy is returned.
+// It returns nil if the remote address of the request is not an IP address.
+func (p *ProxyResolver) ClientIP(req *http.Request) net.IP {
+	ip := remoteIP(req.RemoteAddr)
+	if !p.Trusted(ip) {
+		return ip
+	}
+
+	hops := forwardedFor(req.Header)
+	if hops == nil {
+		if real := parseIP(req.Header.Get("X-Real-Ip")); real != nil {
+			return real
+		}
+		return ip
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := parseIP(hops[i])
+		if hop == nil {
+			// Obfuscated or unknown address, the last known hop is the
+			// best information available
+			return ip
+		}
+		ip = hop
+		if !p.Trusted(ip) {
+			return ip
+		}
+	}
+	return ip
+}
+
+// forwardedFor returns the addresses of the proxy chain, client first, taken
+// from the Forwarded header or, if it is missing, the X-Forwarded-For header.
+func forwardedFor(h http.Header) []string {
+	var hops []string
+	if values := h["Forwarded"]; len(values) > 0 {
+		for _, v := range values {
+			for _, elem := range strings.Split(v, ",") {
+				for _, pair := range strings.Split(elem, ";") {
+					pair = strings.TrimSpace(pair)
+					if len(pair) > 4 && strings.EqualFold(pair[:4], "for=") {
+						hops = append(hops, strings.Trim(pair[4:], `"`))
+					}
+				}
+			}
+		}
+		return hops
+	}
+
+	for _, v := range h["X-Forwarded-For"] {
+		for _, hop := range strings.Split(v, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+	return hops
+}
+
+// parseIP parses an address which may carry a port and IPv6 brackets.
+func parseIP(s string) net.IP {
+	if ip := net.ParseIP(s); ip != nil {
+		return ip
+	}
+	return remoteIP(s)
+}
+
+func remoteIP(addr string) net.IP {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = strings.Trim(addr, "[]")
+	}
+	return net.ParseIP(host)
+}
+
+type clientIPKey struct{}
+
+// ClientIPKey is the request context key under which the client IP resolved
+// by the router's ProxyResolver is stored.
+var ClientIPKey = clientIPKey{}
+
+// ClientIPFromContext pulls the client IP resolved by the router's
+// ProxyResolver from a request context, or returns nil if none is present.
+func ClientIPFromContext(ctx context.Context) net.IP {
+	ip, _ := ctx.Value(ClientIPKey).(net.IP)
+	return ip
+}
+
+// ClientIP returns the client IP of the request as resolved by the router's
+// ProxyResolver, falling back to the remote address of the request.
+// All modules deriving per-client behavior use it, so they agree on the
+// client address.
+func ClientIP(req *http.Request) net.IP {
+	if ip := ClientIPFromContext(req.Context()); ip != nil {
+		return ip
+	}
+	return remoteIP(req.RemoteAddr)
+}
@@ -0,0 +1,121 @@
+package dhttprouter
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestProxyResolver(t *testing.T) {
+	p, err := NewProxyResolver("10.0.0.0/8", "192.168.1.1", "fd00::/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		remote string
+		header [2]string
+		want   string
+	}{
+		{"1.2.3.4:1234", [2]string{}, "1.2.3.4"},
+		{"1.2.3.4:1234", [2]string{"X-Forwarded-For", "5.6.7.8"}, "1.2.3.4"}, // untrusted remote
+		{"10.0.0.1:1234", [2]string{}, "10.0.0.1"},
+		{"10.0.0.1:1234", [2]string{"X-Forwarded-For", "5.6.7.8"}, "5.6.7.8"},
+		{"10.0.0.1:1234", [2]string{"X-Forwarded-For", "6.6.6.6, 5.6.7.8, 10.0.0.2"}, "5.6.7.8"}, // spoofed first entry
+		{"10.0.0.1:1234", [2]string{"X-Forwarded-For", "10.0.0.3, 10.0.0.2"}, "10.0.0.3"},
+		{"192.168.1.1:1234", [2]string{"X-Real-Ip", "5.6.7.8"}, "5.6.7.8"},
+		{"192.168.1.2:1234", [2]string{"X-Real-Ip", "5.6.7.8"}, "192.168.1.2"},
+		{"10.0.0.1:1234", [2]string{"Forwarded", `for=5.6.7.8;proto=https, for="[fd00::1]:4711"`}, "5.6.7.8"},
+		{"10.0.0.1:1234", [2]string{"Forwarded", `for="[2001:db8::17]:4711"`}, "2001:db8::17"},
+		{"10.0.0.1:1234", [2]string{"Forwarded", "for=unknown"}, "10.0.0.1"},
+		{"[fd00::2]:80", [2]string{"X-Forwarded-For", "5.6.7.8"}, "5.6.7.8"},
+	}
+	for _, tr := range tests {
+		r, _ := http.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = tr.remote
+		if tr.header[0] != "" {
+			r.Header.Set(tr.header[0], tr.header[1])
+		}
+		if got := p.ClientIP(r); got.String() != tr.want {
+			t.Errorf("wrong client IP for %s %v: want %s, got %s", tr.remote, tr.header, tr.want, got)
+		}
+	}
+
+	for _, invalid := range []string{"10.0.0.0/33", "nope"} {
+		if _, err := NewProxyResolver(invalid); err == nil {
+			t.Errorf("invalid proxy %q accepted", invalid)
+		}
+	}
+}
+
+func TestRouterClientIP(t *testing.T) {
+	var got net.IP
+	router := New()
+	router.GET("/", func(_ http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		got = ClientIP(req)
+	})
+
+	r, _ := http.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	r.Header.Set("X-Forwarded-For", "5.6.7.8")
+
+	// without resolver the remote address is used
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	if got.String() != "10.0.0.1" {
+		t.Errorf("wrong client IP: want 10.0.0.1, got %s", got)
+	}
+
+	router.ProxyResolver, _ = NewProxyResolver("10.0.0.0/8")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	if got.String() != "5.6.7.8" {
+		t.Errorf("wrong client IP: want 5.6.7.8, got %s", got)
+	}
+}
+
+func TestHTTPSOnlyTrustedProxies(t *testing.T) {
+	proxies, _ := NewProxyResolver("10.0.0.0/8")
+	router := New()
+	router.GET("/", func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {}, HTTPSOnly{Proxies: proxies}.Wrap)
+
+	for remote, code := range map[string]int{
+		"10.0.0.1:1234": http.StatusOK,
+		"1.2.3.4:1234":  http.StatusForbidden,
+	} {
+		r, _ := http.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = remote
+		r.Header.Set("X-Forwarded-Proto", "https")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != code {
+			t.Errorf("unexpected response code %d for %s, want %d", w.Code, remote, code)
+		}
+	}
+
+	// Entries are followed through trusted proxies only
+	tests := []struct {
+		header map[string]string
+		code   int
+	}{
+		{map[string]string{"X-Forwarded-Proto": "https, http", "X-Forwarded-For": "1.2.3.4, 5.6.7.8"}, http.StatusForbidden},
+		{map[string]string{"X-Forwarded-Proto": "https, http", "X-Forwarded-For": "1.2.3.4, 10.0.0.2"}, http.StatusOK},
+		{map[string]string{"X-Forwarded-Proto": "https, http, https", "X-Forwarded-For": "1.2.3.4, 5.6.7.8, 10.0.0.2"}, http.StatusForbidden},
+		{map[string]string{"X-Forwarded-Proto": "https, http"}, http.StatusForbidden},
+		{map[string]string{"Forwarded": "for=1.2.3.4;proto=https, for=10.0.0.2;proto=http"}, http.StatusOK},
+		{map[string]string{"Forwarded": "for=1.2.3.4;proto=https, for=5.6.7.8;proto=http"}, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		r, _ := http.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = "10.0.0.1:1234"
+		for k, v := range tt.header {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != tt.code {
+			t.Errorf("%v: unexpected response code %d, want %d", tt.header, w.Code, tt.code)
+		}
+	}
+}
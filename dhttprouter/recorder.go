@@ -0,0 +1,148 @@
+package dhttprouter
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RecordedRequest is a request captured by a Recorder.
+type RecordedRequest struct {
+	Time   time.Time   `json:"time"`
+	Method string      `json:"method"`
+	Route  string      `json:"route"`
+	URL    string      `json:"url"`
+	Host   string      `json:"host,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   []byte      `json:"body,omitempty"`
+
+	// Whether the body was longer than the captured part
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// NewRequest builds a request replaying the recorded one against the given
+// base URL, e.g. "http://localhost:8080".
+func (rr *RecordedRequest) NewRequest(base string) (*http.Request, error) {
+	req, err := http.NewRequest(rr.Method, base+rr.URL, bytes.NewReader(rr.Body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range rr.Header {
+		req.Header[k] = append([]string(nil), v...)
+	}
+	if rr.Host != "" {
+		req.Host = rr.Host
+	}
+	return req, nil
+}
+
+// Recorder captures the requests of selected routes in a replayable format,
+// e.g. to generate load-test corpora from production traffic.
+// Requests are written to Writer as JSON-encoded RecordedRequest objects, one
+// per line.
+type Recorder struct {
+	// Destination of the recorded requests. Writes are serialized.
+	Writer io.Writer
+
+	// Paths of the routes to record, e.g. "/users/:id". If empty, the
+	// requests of all routes are recorded.
+	Routes []string
+
+	// Maximum number of body bytes to capture. Bodies are not captured if it
+	// is zero.
+	MaxBody int64
+
+	// Headers whose values are replaced by Redacted in the recordings, in
+	// addition to the credential headers Authorization, Proxy-Authorization,
+	// Cookie, X-Api-Key and X-Auth-Token, which are always redacted
+	RedactHeaders []string
+
+	// Optional function which is called if writing a recorded request fails.
+	ErrorHandler func(error)
+
+	mu sync.Mutex
+}
+
+func (rec *Recorder) records(path string) bool {
+	if len(rec.Routes) == 0 {
+		return true
+	}
+	for _, p := range rec.Routes {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+func (rec *Recorder) record(rt *route, req *http.Request) {
+	rr := RecordedRequest{
+		Time:   time.Now(),
+		Method: req.Method,
+		Route:  rt.path,
+		URL:    req.URL.RequestURI(),
+		Host:   req.Host,
+		Header: redactHeader(req.Header, credentialHeaders, rec.RedactHeaders),
+	}
+
+	if rec.MaxBody > 0 && req.Body != nil && req.Body != http.NoBody {
+		// Read one byte more than captured to detect truncation, then hand
+		// the complete body on to the handle
+		body, err := ioutil.ReadAll(io.LimitReader(req.Body, rec.MaxBody+1))
+		req.Body = readCloser{io.MultiReader(bytes.NewReader(body), req.Body), req.Body}
+		if err != nil {
+			rec.fail(err)
+			return
+		}
+		if int64(len(body)) > rec.MaxBody {
+			body = body[:rec.MaxBody]
+			rr.Truncated = true
+		}
+		rr.Body = body
+	}
+
+	data, err := json.Marshal(&rr)
+	if err != nil {
+		rec.fail(err)
+		return
+	}
+	data = append(data, '\n')
+
+	rec.mu.Lock()
+	_, err = rec.Writer.Write(data)
+	rec.mu.Unlock()
+	if err != nil {
+		rec.fail(err)
+	}
+}
+
+func (rec *Recorder) fail(err error) {
+	if rec.ErrorHandler != nil {
+		rec.ErrorHandler(err)
+	}
+}
+
+// readCloser combines a reader with the closer of the original body.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// ReadRecordedRequests decodes all requests written by a Recorder.
+func ReadRecordedRequests(rd io.Reader) ([]RecordedRequest, error) {
+	var requests []RecordedRequest
+	dec := json.NewDecoder(rd)
+	for {
+		var rr RecordedRequest
+		if err := dec.Decode(&rr); err == io.EOF {
+			return requests, nil
+		} else if err != nil {
+			return requests, err
+		}
+		requests = append(requests, rr)
+	}
+}
@@ -0,0 +1,110 @@
+package dhttprouter
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterRecorder(t *testing.T) {
+	var bodies []string
+	handle := func(_ http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		body, _ := ioutil.ReadAll(req.Body)
+		bodies = append(bodies, string(body))
+	}
+
+	var buf bytes.Buffer
+	router := New()
+	router.POST("/users/:id", handle)
+	router.POST("/ignored", handle)
+	router.Recorder = &Recorder{
+		Writer:  &buf,
+		Routes:  []string{"/users/:id"},
+		MaxBody: 4,
+	}
+
+	for _, tr := range []struct{ path, body string }{
+		{"/users/1?x=y", "abc"},
+		{"/users/2", "abcdefgh"},
+		{"/ignored", "abc"},
+	} {
+		r, _ := http.NewRequest(http.MethodPost, tr.path, strings.NewReader(tr.body))
+		r.Header.Set("X-Test", "1")
+		router.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	// handles still receive the complete body
+	if want := []string{"abc", "abcdefgh", "abc"}; strings.Join(bodies, ",") != strings.Join(want, ",") {
+		t.Errorf("wrong bodies passed to handle: want %v, got %v", want, bodies)
+	}
+
+	requests, err := ReadRecordedRequests(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("wrong number of recorded requests: want 2, got %d", len(requests))
+	}
+
+	rr := requests[0]
+	if rr.Method != http.MethodPost || rr.Route != "/users/:id" || rr.URL != "/users/1?x=y" ||
+		string(rr.Body) != "abc" || rr.Truncated || rr.Header.Get("X-Test") != "1" {
+		t.Errorf("wrong recorded request: %+v", rr)
+	}
+	if rr := requests[1]; string(rr.Body) != "abcd" || !rr.Truncated {
+		t.Errorf("wrong truncated request: %+v", rr)
+	}
+
+	// replay
+	req, err := rr.NewRequest("http://localhost:8080")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(req.Body)
+	if req.URL.String() != "http://localhost:8080/users/1?x=y" || string(body) != "abc" || req.Header.Get("X-Test") != "1" {
+		t.Errorf("wrong replayed request: %s %q", req.URL, body)
+	}
+}
+
+func TestRecorderRedactsHeaders(t *testing.T) {
+	var buf bytes.Buffer
+	router := New()
+	router.GET("/users/:id", func(_ http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		// The handle may still change the header of the request
+		req.Header.Set("X-Test", "changed")
+	})
+	router.Recorder = &Recorder{Writer: &buf, RedactHeaders: []string{"x-session"}}
+
+	r, _ := http.NewRequest(http.MethodGet, "/users/1", nil)
+	r.Header.Set("Authorization", "Bearer secret")
+	r.Header.Add("Cookie", "a=1")
+	r.Header.Add("Cookie", "b=2")
+	r.Header.Set("X-Api-Key", "key")
+	r.Header.Set("X-Session", "session")
+	r.Header.Set("X-Test", "1")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	requests, err := ReadRecordedRequests(&buf)
+	if err != nil || len(requests) != 1 {
+		t.Fatalf("wrong recorded requests: %v %v", requests, err)
+	}
+	h := requests[0].Header
+	for _, name := range []string{"Authorization", "Cookie", "X-Api-Key", "X-Session"} {
+		for _, v := range h.Values(name) {
+			if v != Redacted {
+				t.Errorf("%s was not redacted: %q", name, v)
+			}
+		}
+	}
+	if len(h.Values("Cookie")) != 2 || h.Get("X-Test") != "1" {
+		t.Errorf("wrong recorded header %v", h)
+	}
+	if r.Header.Get("Authorization") != "Bearer secret" {
+		t.Errorf("request header was changed: %v", r.Header)
+	}
+}
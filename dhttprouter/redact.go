@@ -0,0 +1,191 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/thekhanj/drouter"
+)
+
+// Redacted replaces the values of redacted params in log fields, request
+// paths and span attributes.
+const Redacted = "[REDACTED]"
+
+// RedactionPolicy selects params whose values must not leak into the
+// observability data of the router, e.g. tokens or email addresses embedded
+// in paths:
+//
+//	router.Redaction = &dhttprouter.RedactionPolicy{
+//		Params: []string{"token", "email"},
+//		Tags:   []string{"pii"},
+//	}
+//	router.GET("/reset/:token", reset)
+//	router.GET("/patients/:name/records/:id", records)
+//	router.TagRoute(http.MethodGet, "/patients/:name/records/:id", "pii")
+//
+// The values are replaced by Redacted in the path and the param fields of
+// the access log entries, the records of the DecisionLog and the "url.path"
+// attribute of the spans started by Tracing. The params named with
+// SetRedactedParams are redacted as well.
+type RedactionPolicy struct {
+	// Names of the params redacted on all routes
+	Params []string
+
+	// Tags of the routes whose params are all redacted, see TagRoute
+	Tags []string
+}
+
+// LogFields returns structured log fields for the matched route pattern and
+// its params, as alternating keys and values which can be passed to a
+// Logger: "route" with the pattern, followed by "param.<name>" with the value
+// of each param, in the order of the pattern. The values of the params named
+// in redacted are replaced by Redacted, so personal data does not leak into
+// logs. The matched route path, see HttpRouter.SaveMatchedRoutePath, is
+// omitted.
+//
+// The access log of the router includes these fields, redacted as set with
+// SetRedactedParams and the Redaction policy.
+func LogFields(route string, ps drouter.Params, redacted ...string) []interface{} {
+	return paramFields([]interface{}{"route", route}, ps, func(key string) bool {
+		return containsString(redacted, key)
+	})
+}
+
+// paramFields appends the log fields of the params to fields, redacting the
+// values of the params for which redacts returns true.
+func paramFields(fields []interface{}, ps drouter.Params, redacts func(key string) bool) []interface{} {
+	for _, p := range ps {
+		if p.Key == drouter.MatchedRoutePathParam {
+			continue
+		}
+		value := p.Value
+		if redacts(p.Key) {
+			value = Redacted
+		}
+		fields = append(fields, "param."+p.Key, value)
+	}
+	return fields
+}
+
+// SetRedactedParams sets the names of the params whose values are redacted
+// in the access log entries of the route registered with the given method
+// and path, see LogFields. It panics if no such route exists. Like the
+// registration of routes, it must not be called concurrently with
+// ServeHTTP.
+func (r *HttpRouter) SetRedactedParams(method, path string, names ...string) {
+	rt := r.mutableTable().lookupRoute(method, path)
+	if rt == nil {
+		panic("no route registered for " + method + " '" + path + "'")
+	}
+	rt.redacted = append([]string(nil), names...)
+}
+
+// TagRoute adds tags to the route registered with the given method and path,
+// which are listed in its RouteInfo and matched against the Tags of the
+// RedactionPolicy. It panics if no such route exists. Like the registration
+// of routes, it must not be called concurrently with ServeHTTP.
+func (r *HttpRouter) TagRoute(method, path string, tags ...string) {
+	rt := r.mutableTable().lookupRoute(method, path)
+	if rt == nil {
+		panic("no route registered for " + method + " '" + path + "'")
+	}
+	rt.tags = append(append([]string(nil), rt.tags...), tags...)
+}
+
+// redacts reports whether the value of the param with the given key is
+// redacted in requests matched to the route.
+func (r *HttpRouter) redacts(rt *route, key string) bool {
+	if containsString(rt.redacted, key) {
+		return true
+	}
+	p := r.Redaction
+	if p == nil {
+		return false
+	}
+	if containsString(p.Params, key) {
+		return true
+	}
+	for _, tag := range rt.tags {
+		if containsString(p.Tags, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactPath returns the path of the request matched to the route with the
+// values of redacted params replaced by Redacted, rebuilt from the route
+// pattern and the mount prefix. It returns an empty string if no param is
+// redacted, in which case the path of the request can be reported as is.
+func (r *HttpRouter) redactPath(rt *route, req *http.Request, ps drouter.Params) string {
+	redacted := false
+	for _, p := range ps {
+		if r.redacts(rt, p.Key) {
+			redacted = true
+			break
+		}
+	}
+	if !redacted {
+		return ""
+	}
+
+	segments, err := drouter.ParsePattern(rt.path)
+	if err != nil {
+		// Unreachable, the pattern was parsed when the route was registered
+		return Redacted
+	}
+	var b strings.Builder
+	b.WriteString(MountPrefixFromContext(req.Context()))
+	for _, s := range segments {
+		switch s.Kind {
+		case drouter.StaticSegment:
+			b.WriteString(s.Value)
+		case drouter.ParamSegment:
+			if r.redacts(rt, s.Value) {
+				b.WriteString(Redacted)
+			} else {
+				b.WriteString(ps.ByName(s.Value))
+			}
+		case drouter.CatchAllSegment:
+			if r.redacts(rt, s.Value) {
+				b.WriteString("/" + Redacted)
+			} else {
+				b.WriteString(ps.ByName(s.Value))
+			}
+		}
+	}
+	return b.String()
+}
+
+// credentialHeaders carry the credentials of requests. They are always
+// redacted in recorded requests, see Recorder, and distinguish coalesced
+// requests, see Coalesce.
+var credentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "X-Api-Key", "X-Auth-Token"}
+
+// redactHeader returns a copy of the header with the values of the named
+// headers replaced by Redacted.
+func redactHeader(h http.Header, names ...[]string) http.Header {
+	c := h.Clone()
+	for _, list := range names {
+		for _, name := range list {
+			key := http.CanonicalHeaderKey(name)
+			if values := c[key]; len(values) > 0 {
+				redacted := make([]string, len(values))
+				for i := range redacted {
+					redacted[i] = Redacted
+				}
+				c[key] = redacted
+			}
+		}
+	}
+	return c
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterLoggerRedactedParams(t *testing.T) {
+	logger := &testLogger{}
+	router := New()
+	router.Logger = logger
+	router.SaveMatchedRoutePath = true
+	handle := func(http.ResponseWriter, *http.Request, drouter.Params) {}
+	router.GET("/users/:email/tokens/:token", handle)
+	router.SetRedactedParams(http.MethodGet, "/users/:email/tokens/:token", "email", "token")
+	router.GET("/posts/:id", handle)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/a@example.com/tokens/secret", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/posts/42", nil))
+
+	if e := logger.entries[0]; e["param.email"] != Redacted || e["param.token"] != Redacted {
+		t.Errorf("params were not redacted: %v", e)
+	}
+	if e := logger.entries[1]; e["param.id"] != "42" {
+		t.Errorf("param is missing: %v", e)
+	}
+	for _, e := range logger.entries {
+		if _, ok := e["param."+drouter.MatchedRoutePathParam]; ok {
+			t.Errorf("matched route path was logged: %v", e)
+		}
+	}
+
+	if recv := catchPanic(func() { router.SetRedactedParams(http.MethodGet, "/missing", "id") }); recv == nil {
+		t.Error("no panic for missing route")
+	}
+}
+
+func TestLogFields(t *testing.T) {
+	ps := drouter.Params{{Key: "user", Value: "alice"}, {Key: "id", Value: "7"}}
+	fields := LogFields("/users/:user/orders/:id", ps, "user")
+	want := []interface{}{"route", "/users/:user/orders/:id", "param.user", Redacted, "param.id", "7"}
+	if len(fields) != len(want) {
+		t.Fatalf("wrong fields %v", fields)
+	}
+	for i := range want {
+		if fields[i] != want[i] {
+			t.Errorf("wrong fields %v", fields)
+		}
+	}
+}
+
+func TestRouterRedactionPolicy(t *testing.T) {
+	logger := &testLogger{}
+	tracer := &testTracer{}
+	decisions := NewDecisionLog(10)
+	router := New()
+	router.Logger = logger
+	router.Decisions = decisions
+	router.Tracing = &Tracing{Tracer: tracer}
+	router.Redaction = &RedactionPolicy{Params: []string{"token"}, Tags: []string{"pii"}}
+
+	handle := func(http.ResponseWriter, *http.Request, drouter.Params) {}
+	router.GET("/reset/:token", handle)
+	router.GET("/patients/:name/files/*path", handle)
+	router.TagRoute(http.MethodGet, "/patients/:name/files/*path", "pii")
+	router.GET("/posts/:id", handle)
+
+	admin := New()
+	admin.Redaction = &RedactionPolicy{Params: []string{"token"}}
+	admin.GET("/keys/:token", handle)
+	router.Mount("/admin", admin)
+
+	tests := []struct {
+		path, want string
+	}{
+		{"/reset/abc", "/reset/" + Redacted},
+		{"/patients/alice/files/a/b.pdf", "/patients/" + Redacted + "/files/" + Redacted},
+		{"/posts/42", "/posts/42"},
+		{"/admin/keys/abc", "/admin/keys/" + Redacted},
+	}
+	for i, tt := range tests {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if e := logger.entries[i]; e["path"] != tt.want {
+			t.Errorf("%s: wrong logged path %v", tt.path, e["path"])
+		}
+		if got := decisions.Records()[0].Path; got != tt.want {
+			t.Errorf("%s: wrong recorded path %q", tt.path, got)
+		}
+	}
+
+	if e := logger.entries[1]; e["param.name"] != Redacted || e["param.path"] != Redacted {
+		t.Errorf("params of tagged route were not redacted: %v", e)
+	}
+	if e := logger.entries[2]; e["param.id"] != "42" {
+		t.Errorf("param was redacted: %v", e)
+	}
+	for i, span := range tracer.spans[:3] {
+		if span.attrs["url.path"] != tests[i].want {
+			t.Errorf("%s: wrong traced path %q", tests[i].path, span.attrs["url.path"])
+		}
+	}
+
+	var tags []string
+	for _, info := range router.Routes() {
+		if info.Path == "/patients/:name/files/*path" {
+			tags = info.Tags
+		}
+	}
+	if len(tags) != 1 || tags[0] != "pii" {
+		t.Errorf("wrong tags %v", tags)
+	}
+	if recv := catchPanic(func() { router.TagRoute(http.MethodGet, "/missing", "pii") }); recv == nil {
+		t.Error("no panic for missing route")
+	}
+}
@@ -0,0 +1,309 @@
+package dhttprouter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/thekhanj/drouter"
+)
+
+// RedirectRule maps an old URL pattern onto a new target.
+// From is a route pattern and may contain named parameters and a catch-all,
+// which can be referenced by name in To, e.g. "/blog/:slug" → "/posts/:slug".
+// Code defaults to http.StatusMovedPermanently if zero.
+type RedirectRule struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Code int    `json:"code,omitempty"`
+}
+
+// redirectTarget is a target URL split into static parts and parameter
+// references, so it can be built without re-parsing it on every request.
+type redirectTarget struct {
+	parts    []string        // static parts; odd indices hold parameter names
+	catchAll map[string]bool // parameters which are catch-alls of the source
+	query    bool            // target carries its own query string
+	abs      bool            // target is an absolute or protocol-relative URL
+}
+
+// Errors of redirectTarget.build for values which would change the meaning
+// of the target
+var (
+	errDotSegment  = errors.New("parameter value contains a dot segment")
+	errAbsoluteURL = errors.New("target with parameter values is an absolute URL")
+)
+
+func parseRedirectTarget(source, target string) redirectTarget {
+	segments, err := drouter.ParsePattern(source)
+	if err != nil {
+		panic(err.Error())
+	}
+	// Whether each parameter of the source is a catch-all
+	params := make(map[string]bool)
+	for _, seg := range segments {
+		if seg.Kind != drouter.StaticSegment {
+			params[seg.Value] = seg.Kind == drouter.CatchAllSegment
+		}
+	}
+
+	t := redirectTarget{
+		catchAll: make(map[string]bool),
+		query:    strings.IndexByte(target, '?') >= 0,
+		abs:      strings.HasPrefix(target, "//") || hasScheme(target),
+	}
+
+	for {
+		start := strings.IndexAny(target, ":*")
+		// Skip the scheme separator of absolute targets and ports
+		for start >= 0 && target[start] == ':' &&
+			(start+1 == len(target) || target[start+1] == '/' ||
+				(target[start+1] >= '0' && target[start+1] <= '9')) {
+			next := strings.IndexAny(target[start+1:], ":*")
+			if next < 0 {
+				start = -1
+				break
+			}
+			start += next + 1
+		}
+		if start < 0 {
+			t.parts = append(t.parts, target)
+			return t
+		}
+
+		end := start + 1
+		for end < len(target) && target[end] != '/' && target[end] != '?' {
+			end++
+		}
+
+		name := target[start+1 : end]
+		if name == "" {
+			panic("redirect target '" + target + "' contains a wildcard without name")
+		}
+		catchAll, ok := params[name]
+		if !ok {
+			panic("redirect target references unknown parameter '" + name +
+				"' of source '" + source + "'")
+		}
+		if catchAll {
+			t.catchAll[name] = true
+		}
+
+		prefix := target[:start]
+		// A catch-all value already begins with a '/'
+		if target[start] == '*' && strings.HasSuffix(prefix, "/") {
+			prefix = prefix[:len(prefix)-1]
+		}
+		t.parts = append(t.parts, prefix, name)
+		target = target[end:]
+	}
+}
+
+// hasScheme reports whether the URL begins with a scheme, e.g. "https:".
+func hasScheme(u string) bool {
+	i := strings.IndexAny(u, ":/?#")
+	return i > 0 && u[i] == ':'
+}
+
+// build returns the target for the request. The values of the parameters
+// are escaped, so they can neither add a query string to the target nor
+// turn a relative target into an absolute one. If escaped is set, the values
+// are taken from the escaped request path, see UseRawPath, and are unescaped
+// first.
+func (t redirectTarget) build(req *http.Request, ps drouter.Params, escaped bool) (string, error) {
+	var b bytes.Buffer
+	for i, part := range t.parts {
+		if i%2 == 0 {
+			b.WriteString(part)
+			continue
+		}
+
+		value := ps.ByName(part)
+		if !t.catchAll[part] {
+			if err := writeSegment(&b, value, escaped); err != nil {
+				return "", err
+			}
+			continue
+		}
+		for j, seg := range strings.Split(value, "/") {
+			if j > 0 {
+				b.WriteByte('/')
+			}
+			if err := writeSegment(&b, seg, escaped); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	target := b.String()
+	if !t.abs {
+		// Clients take "//host/path" for a protocol-relative URL
+		if strings.HasPrefix(target, "//") {
+			target = "/" + strings.TrimLeft(target, "/")
+		}
+		if hasScheme(target) {
+			return "", errAbsoluteURL
+		}
+	}
+	if !t.query && req.URL.RawQuery != "" {
+		target += "?" + req.URL.RawQuery
+	}
+	return target, nil
+}
+
+// writeSegment writes the escaped path segment to b. It rejects dot
+// segments, which would move the target out of its path once resolved.
+func writeSegment(b *bytes.Buffer, seg string, escaped bool) error {
+	if escaped {
+		if s, err := url.PathUnescape(seg); err == nil {
+			seg = s
+		}
+	}
+	if isDotSegment(seg) {
+		return errDotSegment
+	}
+	b.WriteString(url.PathEscape(seg))
+	return nil
+}
+
+func isDotSegment(seg string) bool {
+	return seg == "." || seg == ".."
+}
+
+// Redirect registers GET and HEAD routes for the given source pattern which
+// redirect the client to the target with the given status code.
+// Parameters captured by the source can be referenced by name in the target,
+// e.g. router.Redirect("/docs/*page", "https://docs.example.com/*page", 301).
+// The values are escaped and requests whose values contain dot segments, or
+// would turn a relative target into an absolute URL, are answered with
+// 400 Bad Request.
+// The query string of the request is retained, unless the target has its own.
+// A code of 0 defaults to http.StatusMovedPermanently.
+// Redirect panics if either route can not be registered, in which case
+// neither is.
+func (r *HttpRouter) Redirect(source, target string, code int) {
+	if code == 0 {
+		code = http.StatusMovedPermanently
+	}
+	if code < 300 || code > 399 {
+		panic("invalid redirect status code " + strconv.Itoa(code) +
+			" for path '" + source + "'")
+	}
+
+	t := parseRedirectTarget(source, target)
+	handle := func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		location, err := t.build(req, ps, r.UseRawPath && !r.UnescapePathValues)
+		if err != nil {
+			http.Error(w,
+				http.StatusText(http.StatusBadRequest),
+				http.StatusBadRequest,
+			)
+			return
+		}
+		http.Redirect(w, req, location, code)
+	}
+
+	if err := r.tryHandle(http.MethodGet, source, handle, handle, nil); err != nil {
+		panic(err.Error())
+	}
+	if err := r.tryHandle(http.MethodHead, source, handle, handle, nil); err != nil {
+		r.Remove(http.MethodGet, source)
+		panic(err.Error())
+	}
+}
+
+// LoadRedirects registers all given redirect rules.
+// In contrast to Redirect it does not panic on invalid or conflicting rules,
+// but returns an error naming the offending rule. Rules registered before the
+// failing one remain registered.
+func (r *HttpRouter) LoadRedirects(rules []RedirectRule) error {
+	for i, rule := range rules {
+		if err := r.loadRedirect(rule); err != nil {
+			return fmt.Errorf("redirect rule %d (%s): %v", i+1, rule.From, err)
+		}
+	}
+	return nil
+}
+
+func (r *HttpRouter) loadRedirect(rule RedirectRule) (err error) {
+	defer func() {
+		if rcv := recover(); rcv != nil {
+			err = fmt.Errorf("%v", rcv)
+		}
+	}()
+
+	r.Redirect(rule.From, rule.To, rule.Code)
+	return nil
+}
+
+// LoadRedirectsCSV reads redirect rules from CSV records of the form
+// "from,to[,code]" and registers them. Empty lines and lines starting with '#'
+// are ignored.
+func (r *HttpRouter) LoadRedirectsCSV(rd io.Reader) error {
+	cr := csv.NewReader(rd)
+	cr.Comment = '#'
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	var rules []RedirectRule
+	for n := 1; ; n++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(record) < 2 || len(record) > 3 {
+			return fmt.Errorf("record %d: expected 2 or 3 fields, got %d", n, len(record))
+		}
+
+		rule := RedirectRule{From: record[0], To: record[1]}
+		if len(record) == 3 && record[2] != "" {
+			if rule.Code, err = strconv.Atoi(record[2]); err != nil {
+				return fmt.Errorf("record %d: invalid status code %q", n, record[2])
+			}
+		}
+		rules = append(rules, rule)
+	}
+
+	return r.LoadRedirects(rules)
+}
+
+// LoadRedirectsJSON reads redirect rules from JSON and registers them.
+// The document is either an array of RedirectRule objects or an object
+// mapping old paths to new ones, e.g. {"/old": "/new"}.
+// The status code of object entries is always http.StatusMovedPermanently.
+func (r *HttpRouter) LoadRedirectsJSON(rd io.Reader) error {
+	data, err := ioutil.ReadAll(rd)
+	if err != nil {
+		return err
+	}
+
+	var rules []RedirectRule
+	if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '{' {
+		var m map[string]string
+		if err := json.Unmarshal(data, &m); err != nil {
+			return err
+		}
+		for from, to := range m {
+			rules = append(rules, RedirectRule{From: from, To: to})
+		}
+		// Register in a stable order, so errors are reproducible
+		sort.Slice(rules, func(i, j int) bool { return rules[i].From < rules[j].From })
+	} else if err := json.Unmarshal(data, &rules); err != nil {
+		return err
+	}
+
+	return r.LoadRedirects(rules)
+}
@@ -0,0 +1,188 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func checkRedirects(t *testing.T, router *HttpRouter, tests []struct {
+	route    string
+	code     int
+	location string
+}) {
+	for _, tr := range tests {
+		r, _ := http.NewRequest(http.MethodGet, tr.route, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != tr.code || w.Header().Get("Location") != tr.location {
+			t.Errorf("redirect for %s failed: Code=%d, Location=%q", tr.route, w.Code, w.Header().Get("Location"))
+		}
+	}
+}
+
+func TestRouterRedirect(t *testing.T) {
+	router := New()
+	router.Redirect("/blog/:slug", "/posts/:slug", 0)
+	router.Redirect("/docs/*page", "https://docs.example.com:8080/v2/*page", http.StatusFound)
+	router.Redirect("/search", "/find?src=legacy", 0)
+
+	checkRedirects(t, router, []struct {
+		route    string
+		code     int
+		location string
+	}{
+		{"/blog/hello", http.StatusMovedPermanently, "/posts/hello"},
+		{"/blog/hello?page=2", http.StatusMovedPermanently, "/posts/hello?page=2"},
+		{"/docs/a/b.html", http.StatusFound, "https://docs.example.com:8080/v2/a/b.html"},
+		{"/search?q=go", http.StatusMovedPermanently, "/find?src=legacy"},
+	})
+
+	r, _ := http.NewRequest(http.MethodHead, "/blog/hello", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusMovedPermanently {
+		t.Errorf("HEAD redirect failed: Code=%d", w.Code)
+	}
+
+	recv := catchPanic(func() {
+		router.Redirect("/a/:id", "/b/:name", 0)
+	})
+	if recv == nil {
+		t.Error("registering target with unknown parameter did not panic")
+	}
+
+	// Parameters are matched by their exact names, not as prefixes
+	for _, tr := range [][2]string{
+		{"/a/:identifier", "/b/:id"},
+		{"/files/*path", "/f/*p"},
+	} {
+		if catchPanic(func() { router.Redirect(tr[0], tr[1], 0) }) == nil {
+			t.Errorf("registering target %s of %s did not panic", tr[1], tr[0])
+		}
+	}
+	router.Redirect("/users/:id|int", "/people/:id", 0)
+	checkRedirects(t, router, []struct {
+		route    string
+		code     int
+		location string
+	}{
+		{"/users/42", http.StatusMovedPermanently, "/people/42"},
+	})
+
+	recv = catchPanic(func() {
+		router.Redirect("/c", "/d", http.StatusOK)
+	})
+	if recv == nil {
+		t.Error("registering non-redirect status code did not panic")
+	}
+}
+
+func TestRouterLoadRedirectsCSV(t *testing.T) {
+	router := New()
+	err := router.LoadRedirectsCSV(strings.NewReader(
+		"# legacy site\n" +
+			"/about.html,/about\n" +
+			"/user/:id/profile.php, /users/:id, 302\n",
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkRedirects(t, router, []struct {
+		route    string
+		code     int
+		location string
+	}{
+		{"/about.html", http.StatusMovedPermanently, "/about"},
+		{"/user/42/profile.php", http.StatusFound, "/users/42"},
+	})
+
+	if err := router.LoadRedirectsCSV(strings.NewReader("/x\n")); err == nil {
+		t.Error("loading record with too few fields did not fail")
+	}
+	if err := router.LoadRedirectsCSV(strings.NewReader("/x,/y,abc\n")); err == nil {
+		t.Error("loading record with invalid status code did not fail")
+	}
+	if err := router.LoadRedirectsCSV(strings.NewReader("/about.html,/other\n")); err == nil {
+		t.Error("loading conflicting record did not fail")
+	}
+}
+
+func TestRouterLoadRedirectsJSON(t *testing.T) {
+	router := New()
+	err := router.LoadRedirectsJSON(strings.NewReader(`{"/old": "/new", "/a/*rest": "/b/*rest"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = router.LoadRedirectsJSON(strings.NewReader(`[{"from": "/tmp/:x", "to": "/t/:x", "code": 307}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkRedirects(t, router, []struct {
+		route    string
+		code     int
+		location string
+	}{
+		{"/old", http.StatusMovedPermanently, "/new"},
+		{"/a/c/d", http.StatusMovedPermanently, "/b/c/d"},
+		{"/tmp/1", http.StatusTemporaryRedirect, "/t/1"},
+	})
+
+	if err := router.LoadRedirectsJSON(strings.NewReader(`[{"from": "nope", "to": "/"}]`)); err == nil {
+		t.Error("loading invalid source path did not fail")
+	}
+	if err := router.LoadRedirectsJSON(strings.NewReader(`{`)); err == nil {
+		t.Error("loading malformed JSON did not fail")
+	}
+}
+
+func TestRouterRedirectEscaping(t *testing.T) {
+	router := New()
+	router.Redirect("/go/*rest", "/*rest", 0)
+	router.Redirect("/blog/:slug", "/posts/:slug", 0)
+	router.Redirect("/rel/:page", ":page", 0)
+	router.Redirect("/cdn/*file", "//cdn.example.com/*file", 0)
+
+	checkRedirects(t, router, []struct {
+		route    string
+		code     int
+		location string
+	}{
+		{"/go//evil.com", http.StatusMovedPermanently, "/evil.com"},
+		{"/go/a/b%3Fadmin=1", http.StatusMovedPermanently, "/a/b%3Fadmin=1"},
+		{"/go/a%2F..%2F..%2Fadmin", http.StatusBadRequest, ""},
+		{"/blog/a%0d%0aSet-Cookie:x", http.StatusMovedPermanently, "/posts/a%0D%0ASet-Cookie:x"},
+		{"/blog/a%3Fb?page=2", http.StatusMovedPermanently, "/posts/a%3Fb?page=2"},
+		{"/blog/..", http.StatusBadRequest, ""},
+		{"/rel/javascript:alert(1)", http.StatusBadRequest, ""},
+		{"/cdn/a.js", http.StatusMovedPermanently, "//cdn.example.com/a.js"},
+	})
+
+	// Values of escaped paths are not escaped twice
+	router = New()
+	router.UseRawPath = true
+	router.Redirect("/blog/:slug", "/posts/:slug", 0)
+	checkRedirects(t, router, []struct {
+		route    string
+		code     int
+		location string
+	}{
+		{"/blog/a%2Fb", http.StatusMovedPermanently, "/posts/a%2Fb"},
+	})
+}
+
+func TestRouterRedirectConflict(t *testing.T) {
+	router := New()
+	router.HEAD("/old", func(http.ResponseWriter, *http.Request, drouter.Params) {})
+	if recv := catchPanic(func() { router.Redirect("/old", "/new", 0) }); recv == nil {
+		t.Fatal("registering conflicting redirect did not panic")
+	}
+	if routes := router.Routes(); len(routes) != 1 || routes[0].Method != http.MethodHead {
+		t.Errorf("redirect was partially registered: %+v", routes)
+	}
+}
@@ -0,0 +1,140 @@
+package dhttprouter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ConfigWatcher keeps the routes of a HttpRouter in sync with a JSON
+// configuration file, see LoadRoutes. When the file changes, the routes are
+// loaded into a new HttpRouter, validated and swapped in atomically, see
+// Swap. Configurations which fail to load or validate are rejected and the
+// router keeps serving its current routes:
+//
+//	w := &dhttprouter.ConfigWatcher{Router: router, Path: "routes.json", Registry: reg,
+//		OnReload: func(err error) {
+//			if err != nil {
+//				log.Printf("routes.json rejected: %v", err)
+//			}
+//		},
+//	}
+//	if err := w.Reload(); err != nil {
+//		log.Fatal(err)
+//	}
+//	go w.Watch(ctx)
+//
+// The file is polled, as the module has no file system notification
+// dependency. Only the routes are replaced, the options of the Router are
+// kept.
+type ConfigWatcher struct {
+	Router   *HttpRouter
+	Path     string
+	Registry Registry
+
+	// Optional function returning the router the routes are loaded into,
+	// e.g. to register routes which are not part of the configuration.
+	// Defaults to New.
+	New func() *HttpRouter
+
+	// If enabled, the loaded routes must pass SelfCheck, which serves
+	// synthesized requests to the GET routes, before they are swapped in.
+	SelfCheck bool
+
+	// Interval between checks of the file for changes. Defaults to 1 second.
+	Interval time.Duration
+
+	// Optional function which is called after each reload triggered by a
+	// change of the file, with the error which rejected it, if any.
+	OnReload func(err error)
+
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+}
+
+// Reload loads the routes from the file and swaps them in, unless loading or
+// validating them fails. Changes of the file found by Watch trigger Reload.
+func (cw *ConfigWatcher) Reload() error {
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+
+	info, err := os.Stat(cw.Path)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(cw.Path)
+	if err != nil {
+		return err
+	}
+	// Remember the version even if it is rejected, so it is not retried
+	cw.modTime, cw.size = info.ModTime(), info.Size()
+
+	next := New()
+	if cw.New != nil {
+		next = cw.New()
+	}
+	if err := next.LoadRoutes(bytes.NewReader(data), cw.Registry); err != nil {
+		return fmt.Errorf("%s: %w", cw.Path, err)
+	}
+	if cw.SelfCheck {
+		if problems := next.SelfCheck(); len(problems) > 0 {
+			msgs := make([]string, len(problems))
+			for i, p := range problems {
+				msgs[i] = p.Error()
+			}
+			return fmt.Errorf("%s: self-check failed: %s", cw.Path, strings.Join(msgs, "; "))
+		}
+	}
+
+	cw.Router.Swap(next)
+	return nil
+}
+
+// changed reports whether the file changed since it was loaded last.
+func (cw *ConfigWatcher) changed() bool {
+	info, err := os.Stat(cw.Path)
+	if err != nil {
+		return false
+	}
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	return !info.ModTime().Equal(cw.modTime) || info.Size() != cw.size
+}
+
+// Watch checks the file for changes every Interval and reloads the routes
+// when it changed, until the context is done. Files which cannot be read,
+// e.g. while they are replaced, are checked again at the next interval.
+// Watch returns the error of the context.
+func (cw *ConfigWatcher) Watch(ctx context.Context) error {
+	if cw.Router == nil {
+		return errors.New("dhttprouter: config watcher has no router")
+	}
+	interval := cw.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if !cw.changed() {
+				continue
+			}
+			err := cw.Reload()
+			if cw.OnReload != nil {
+				cw.OnReload(err)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package dhttprouter
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestConfigWatcher(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	// Replace the file atomically, so the watcher never reads a partial one
+	write := func(config string) {
+		if err := ioutil.WriteFile(path+".tmp", []byte(config), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(path+".tmp", path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	status := func(router *HttpRouter, path string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code
+	}
+
+	reg := Registry{Handlers: map[string]HttpHandle{
+		"ok": func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+			w.WriteHeader(http.StatusNoContent)
+		},
+	}}
+	reloads := make(chan error, 10)
+	router := New()
+	cw := &ConfigWatcher{
+		Router:   router,
+		Path:     path,
+		Registry: reg,
+		Interval: time.Millisecond,
+		OnReload: func(err error) { reloads <- err },
+	}
+
+	write(`{"routes": [{"method": "GET", "path": "/a", "handler": "ok"}]}`)
+	if err := cw.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if status(router, "/a") != http.StatusNoContent {
+		t.Error("routes were not loaded")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- cw.Watch(ctx) }()
+
+	// Invalid configurations are rejected, the current routes stay
+	write(`{"routes": [{"method": "GET", "path": "/b", "handler": "missing"}]}`)
+	var errs ConfigErrors
+	if err := <-reloads; !errors.As(err, &errs) {
+		t.Errorf("wrong error %v", err)
+	}
+	if status(router, "/a") != http.StatusNoContent || status(router, "/b") != http.StatusNotFound {
+		t.Error("rejected configuration was swapped in")
+	}
+
+	write(`{"routes": [{"method": "GET", "path": "/b", "handler": "ok"}, {"method": "GET", "path": "/c", "handler": "ok"}]}`)
+	if err := <-reloads; err != nil {
+		t.Errorf("reload failed: %v", err)
+	}
+	if status(router, "/a") != http.StatusNotFound || status(router, "/c") != http.StatusNoContent {
+		t.Error("new configuration was not swapped in")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("wrong error %v", err)
+	}
+}
+
+func TestConfigWatcherSelfCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	ioutil.WriteFile(path, []byte(`{"routes": [{"method": "GET", "path": "/fail", "handler": "fail"}]}`), 0o644)
+
+	router := New()
+	cw := &ConfigWatcher{
+		Router: router,
+		Path:   path,
+		Registry: Registry{Handlers: map[string]HttpHandle{
+			"fail": func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+		}},
+		SelfCheck: true,
+	}
+	if err := cw.Reload(); err == nil {
+		t.Error("no error for failing self-check")
+	}
+	if len(router.Routes()) != 0 {
+		t.Error("routes failing the self-check were swapped in")
+	}
+}
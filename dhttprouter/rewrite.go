@@ -0,0 +1,90 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"regexp"
+)
+
+// RewriteFlag modifies how a matching rewrite rule is applied.
+type RewriteFlag uint8
+
+const (
+	// RewriteLast stops processing of further rules once this rule matched.
+	RewriteLast RewriteFlag = 1 << iota
+
+	// RewriteRedirect makes the client follow the rewritten path instead of
+	// rewriting it internally. The status code is 301 for GET requests and 308
+	// for all other request methods.
+	RewriteRedirect
+
+	// RewriteTemporaryRedirect is like RewriteRedirect, but uses the status
+	// codes 302 and 307 respectively.
+	RewriteTemporaryRedirect
+)
+
+type rewriteRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+	flags       RewriteFlag
+}
+
+// Rewrite appends a rule to the ordered list of rewrite rules, which are
+// applied to the request path before the route lookup.
+// The replacement may reference capture groups of the pattern, e.g.
+//
+//	router.Rewrite("^/v1/(.*)$", "/api/v1/$1", RewriteLast)
+//
+// Rules are evaluated in the order they were added. Each matching rule
+// operates on the result of the previous one, unless it stops processing with
+// RewriteLast or redirects the client.
+// Rewrite panics if the pattern is not a valid regular expression.
+func (r *HttpRouter) Rewrite(pattern, replacement string, flags RewriteFlag) {
+	t := r.mutableTable()
+	t.rewrites = append(t.rewrites, rewriteRule{
+		pattern:     regexp.MustCompile(pattern),
+		replacement: replacement,
+		flags:       flags,
+	})
+}
+
+// applyRewrites rewrites the request path according to the configured rules.
+// It returns true if the request was answered with a redirect.
+func (t *routeTable) applyRewrites(w http.ResponseWriter, req *http.Request) bool {
+	path := req.URL.Path
+	rewritten := false
+
+	for _, rule := range t.rewrites {
+		if !rule.pattern.MatchString(path) {
+			continue
+		}
+		path = rule.pattern.ReplaceAllString(path, rule.replacement)
+		rewritten = true
+
+		if rule.flags&(RewriteRedirect|RewriteTemporaryRedirect) != 0 {
+			code := http.StatusMovedPermanently
+			if rule.flags&RewriteTemporaryRedirect != 0 {
+				code = http.StatusFound
+			}
+			if req.Method != http.MethodGet {
+				if code == http.StatusFound {
+					code = http.StatusTemporaryRedirect
+				} else {
+					code = http.StatusPermanentRedirect
+				}
+			}
+
+			redirectPath(w, req, path, code)
+			return true
+		}
+
+		if rule.flags&RewriteLast != 0 {
+			break
+		}
+	}
+
+	if rewritten {
+		req.URL.Path = path
+		req.URL.RawPath = ""
+	}
+	return false
+}
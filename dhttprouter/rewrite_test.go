@@ -0,0 +1,79 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterRewrite(t *testing.T) {
+	var gotPath, gotParam string
+	handle := func(_ http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		gotPath = req.URL.Path
+		gotParam = ps.ByName("rest")
+	}
+
+	router := New()
+	router.GET("/api/v1/*rest", handle)
+	router.GET("/api/v2/*rest", handle)
+	router.Rewrite("^/v1/(.*)$", "/api/v1/$1", RewriteLast)
+	router.Rewrite("^/api/v1/(.*)$", "/api/v2/$1", 0)
+	router.Rewrite("^/old/(.*)$", "/api/v1/$1", RewriteRedirect)
+	router.Rewrite("^/tmp/(.*)$", "/api/v1/$1", RewriteTemporaryRedirect)
+
+	// internal rewrite stopped by RewriteLast
+	r, _ := http.NewRequest(http.MethodGet, "/v1/users", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if gotPath != "/api/v1/users" || gotParam != "/users" {
+		t.Errorf("rewrite failed: path=%q param=%q", gotPath, gotParam)
+	}
+
+	// rules without RewriteLast are chained
+	r, _ = http.NewRequest(http.MethodGet, "/api/v1/users", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if gotPath != "/api/v2/users" {
+		t.Errorf("chained rewrite failed: path=%q", gotPath)
+	}
+
+	testRedirects := []struct {
+		method   string
+		route    string
+		code     int
+		location string
+	}{
+		{http.MethodGet, "/old/a", http.StatusMovedPermanently, "/api/v1/a"},
+		{http.MethodPost, "/old/a", http.StatusPermanentRedirect, "/api/v1/a"},
+		{http.MethodGet, "/tmp/a", http.StatusFound, "/api/v1/a"},
+		{http.MethodPost, "/tmp/a", http.StatusTemporaryRedirect, "/api/v1/a"},
+	}
+	for _, tr := range testRedirects {
+		r, _ := http.NewRequest(tr.method, tr.route, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != tr.code || w.Header().Get("Location") != tr.location {
+			t.Errorf("rewrite redirect %s %s failed: Code=%d, Location=%q", tr.method, tr.route, w.Code, w.Header().Get("Location"))
+		}
+	}
+
+	// no matching rule leaves the path untouched
+	r, _ = http.NewRequest(http.MethodGet, "/nope", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unexpected response code %d want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestRouterRewriteInvalidPattern(t *testing.T) {
+	router := New()
+	recv := catchPanic(func() {
+		router.Rewrite("(", "/", 0)
+	})
+	if recv == nil {
+		t.Fatal("registering invalid rewrite pattern did not panic")
+	}
+}
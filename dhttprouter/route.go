@@ -0,0 +1,274 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+// route is the handle stored in the per-method trees.
+// Keeping the registered method and path next to the handle allows to
+// attribute a request to its route pattern at dispatch time.
+type route struct {
+	// Bytes passed through Compression, must stay the first field for
+	// 64-bit alignment
+	bytes byteCounters
+
+	method string
+	path   string
+	handle HttpHandle
+
+	// The handle or http.Handler as registered, without middleware
+	origin interface{}
+
+	// Number of params the route may need, see HttpRouter.routeParams
+	params uint16
+
+	// Latency objective tracking, if an SLO was set for the route
+	slo *sloTracker
+
+	// Recent panics, see PanicBudget
+	panics panicState
+
+	// Set while the route is disabled with DisableRoute. Accessed atomically.
+	disabled uint32
+
+	// Names of the params redacted in the access log, see SetRedactedParams
+	redacted []string
+
+	// Tags of the route, see TagRoute
+	tags []string
+
+	// Name of the RouteModule the route was installed with, if any
+	module string
+
+	// Handles of a weighted route, see HandleWeighted
+	canary *canary
+
+	// Hook answering HEAD requests served by a GET route, see SetHEAD
+	head HttpHandle
+}
+
+// RouteInfo describes a registered route.
+type RouteInfo struct {
+	Method string
+	Path   string
+
+	// Identifies the handle or http.Handler the route was registered with
+	Handler HandlerID
+
+	// Tags added with TagRoute
+	Tags []string
+
+	// Name of the RouteModule the route was installed with, if any
+	Module string
+
+	// Whether the route is answered with 503, see DisableRoute and
+	// PanicBudget
+	Disabled bool
+}
+
+// Routes returns all registered routes, ordered by path and method.
+// Routes of mounted HttpRouters are included with their mount prefix.
+func (r *HttpRouter) Routes() []RouteInfo {
+	return r.loadTable().routes()
+}
+
+func (t *routeTable) routes() []RouteInfo {
+	var routes []RouteInfo
+	t.appendRoutes(&routes, "")
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
+}
+
+func (t *routeTable) appendRoutes(routes *[]RouteInfo, prefix string) {
+	for method, router := range t.routers {
+		router.Walk(func(path string, rt *route) bool {
+			*routes = append(*routes, RouteInfo{
+				Method:   method,
+				Path:     prefix + path,
+				Handler:  handlerID(rt.origin),
+				Tags:     rt.tags,
+				Module:   rt.module,
+				Disabled: atomic.LoadUint32(&rt.disabled) != 0 || atomic.LoadUint32(&rt.panics.disabled) != 0,
+			})
+			return true
+		})
+	}
+
+	if t.mounts == nil {
+		return
+	}
+	t.mounts.Walk(func(path string, m *mount) bool {
+		// Each mount is registered twice, for its prefix and the paths below
+		if sub, ok := m.handler.(*HttpRouter); ok && path == m.prefix {
+			sub.loadTable().appendRoutes(routes, prefix+m.prefix)
+		}
+		return true
+	})
+}
+
+// CheckConflicts reports routes shadowing each other, see
+// drouter.Router.CheckConflicts. In addition, routes of mounted HttpRouters
+// are reported if a route of the parent router with the same method matches
+// their path, so requests never reach them. Existing is then the route of
+// the parent router. The paths of mounted routes include the mount prefix.
+func (r *HttpRouter) CheckConflicts() []*drouter.RouteError {
+	var conflicts []*drouter.RouteError
+	r.loadTable().appendConflicts(&conflicts, "")
+
+	sort.SliceStable(conflicts, func(i, j int) bool {
+		return conflicts[i].Path < conflicts[j].Path
+	})
+	return conflicts
+}
+
+func (t *routeTable) appendConflicts(conflicts *[]*drouter.RouteError, prefix string) {
+	for method, router := range t.routers {
+		for _, c := range router.CheckConflicts() {
+			*conflicts = append(*conflicts, &drouter.RouteError{
+				Kind:     c.Kind,
+				Path:     prefix + c.Path,
+				Existing: prefix + c.Existing,
+				Message:  method + " " + prefix + c.Path + ": " + c.Message,
+			})
+		}
+	}
+
+	if t.mounts == nil {
+		return
+	}
+	t.mounts.Walk(func(path string, m *mount) bool {
+		sub, ok := m.handler.(*HttpRouter)
+		if !ok || path != m.prefix {
+			return true
+		}
+
+		st := sub.loadTable()
+		var routes []RouteInfo
+		st.appendRoutes(&routes, m.prefix)
+		for _, info := range routes {
+			if rt := t.matchRoute(info.Method, info.Path); rt != nil {
+				*conflicts = append(*conflicts, &drouter.RouteError{
+					Kind:     drouter.Conflict,
+					Path:     prefix + info.Path,
+					Existing: prefix + rt.path,
+					Message: info.Method + " " + prefix + info.Path +
+						": mounted route is shadowed by route '" + prefix + rt.path + "'",
+				})
+			}
+		}
+		st.appendConflicts(conflicts, prefix+m.prefix)
+		return true
+	})
+}
+
+// matchRoute returns the route which a request with the given method and
+// path would be dispatched to, or nil if there is none. The path may be a
+// route pattern, whose wildcards are matched like values.
+func (t *routeTable) matchRoute(method, path string) *route {
+	router := t.routers[method]
+	if router == nil {
+		return nil
+	}
+	rt, _ := router.Lookup(path, nil)
+	return rt
+}
+
+// lookupRoute returns the route registered with exactly the given method and
+// path, or nil if there is none.
+func (t *routeTable) lookupRoute(method, path string) *route {
+	router := t.routers[method]
+	if router == nil {
+		return nil
+	}
+
+	// Route paths match themselves, as wildcards match any segment
+	if rt, _ := router.Lookup(path, nil); rt != nil && rt.path == path {
+		return rt
+	}
+	return nil
+}
+
+// serve invokes the handle of the matched route.
+func (r *HttpRouter) serve(t *routeTable, rt *route, w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+	if rt.slo != nil {
+		defer rt.slo.observe(time.Now())
+	}
+
+	hps := HostParamsFromContext(req.Context())
+	if n := int(rt.params) + len(hps); n > int(t.maxParams) {
+		r.overflowParams(rt, n, t.maxParams)
+	}
+	if len(hps) > 0 {
+		ps = append(ps, hps...)
+	}
+	if r.SaveMatchedRoutePath {
+		ps = append(ps, drouter.Param{Key: drouter.MatchedRoutePathParam, Value: rt.path})
+	}
+	r.matched(w, req, rt, ps)
+
+	if r.Tracing != nil {
+		var end func()
+		w, req, end = r.Tracing.start(rt, w, req, r.redactPath(rt, req, ps))
+		defer end()
+	}
+
+	if len(r.ErrorTranslators) > 0 {
+		req = withErrorTranslators(req, r.ErrorTranslators)
+	}
+
+	if rt.method != http.MethodOptions {
+		if p := t.corsPolicies[rt.path]; p != nil {
+			p.setHeaders(w, req)
+		} else if r.CORS != nil {
+			r.CORS.setHeaders(w, req)
+		}
+	}
+
+	if r.RouteHeaders != nil && r.RouteHeaders.allowed(req) {
+		r.RouteHeaders.stamp(w, req, rt, t.version)
+	}
+
+	if atomic.LoadUint32(&rt.disabled) != 0 {
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	if b := r.PanicBudget; b != nil {
+		if !rt.panics.available() {
+			b.serveDisabled(w)
+			return
+		}
+		defer b.recover(rt)
+	}
+
+	if c := r.Compression; c != nil {
+		cw, creq, ok := c.wrap(rt, w, req)
+		if !ok {
+			return
+		}
+		defer cw.close()
+		w, req = cw, creq
+	}
+
+	if r.Recorder != nil && r.Recorder.records(rt.path) {
+		r.Recorder.record(rt, req)
+	}
+
+	if r.Sampler != nil && r.Sampler.sample(rt.path) {
+		r.Sampler.serve(rt, w, req, ps)
+		return
+	}
+
+	rt.handle(w, req, ps)
+}
@@ -0,0 +1,77 @@
+package dhttprouter
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+)
+
+// RouteHeaders stamps responses with debug headers naming the matched route
+// pattern (X-Route-Pattern) and the version of the route table
+// (X-Route-Version), to diagnose routing in production. The headers can be
+// restricted to clients from given networks and toggled at runtime.
+//
+// The pattern includes the prefix of the mounts the request passed through.
+// Mounted routers stamp the headers of their routes only if RouteHeaders is
+// set for them as well.
+type RouteHeaders struct {
+	clients []*net.IPNet
+
+	// Whether the headers are stamped. Accessed atomically.
+	disabled uint32
+}
+
+// NewRouteHeaders returns enabled RouteHeaders for clients within the given
+// networks in CIDR notation, e.g. "10.0.0.0/8", or single IP addresses.
+// If no network is given, the headers are sent to all clients.
+// Client addresses are determined by ClientIP.
+func NewRouteHeaders(clients ...string) (*RouteHeaders, error) {
+	nets, err := parseNetworks(clients)
+	if err != nil {
+		return nil, err
+	}
+	return &RouteHeaders{clients: nets}, nil
+}
+
+// SetEnabled enables or disables the headers. It may be called while the
+// router serves requests.
+func (h *RouteHeaders) SetEnabled(enabled bool) {
+	var disabled uint32
+	if !enabled {
+		disabled = 1
+	}
+	atomic.StoreUint32(&h.disabled, disabled)
+}
+
+// Enabled reports whether the headers are stamped.
+func (h *RouteHeaders) Enabled() bool {
+	return atomic.LoadUint32(&h.disabled) == 0
+}
+
+// allowed reports whether the headers are sent in the response to req.
+func (h *RouteHeaders) allowed(req *http.Request) bool {
+	if !h.Enabled() {
+		return false
+	}
+	if len(h.clients) == 0 {
+		return true
+	}
+	ip := ClientIP(req)
+	if ip == nil {
+		return false
+	}
+	for _, n := range h.clients {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// stamp sets the headers for the matched route.
+func (h *RouteHeaders) stamp(w http.ResponseWriter, req *http.Request, rt *route, version uint64) {
+	header := w.Header()
+	header.Set("X-Route-Pattern", MountPrefixFromContext(req.Context())+rt.path)
+	header.Set("X-Route-Version", strconv.FormatUint(version, 10))
+}
@@ -0,0 +1,97 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterRouteHeaders(t *testing.T) {
+	handle := func(http.ResponseWriter, *http.Request, drouter.Params) {}
+
+	headers, err := NewRouteHeaders("10.0.0.0/8", "192.168.1.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	admin := New()
+	admin.RouteHeaders = headers
+	admin.GET("/users/:id", handle)
+
+	router := New()
+	router.RouteHeaders = headers
+	router.GET("/items/:id", handle)
+	router.Mount("/admin", admin)
+
+	tests := []struct {
+		path, remote, pattern, version string
+	}{
+		{"/items/1", "10.1.2.3:1234", "/items/:id", strconv.FormatUint(router.Version(), 10)},
+		{"/admin/users/1", "192.168.1.1:1234", "/admin/users/:id", strconv.FormatUint(admin.Version(), 10)},
+		{"/items/1", "192.168.1.2:1234", "", ""},
+		{"/nope", "10.1.2.3:1234", "", ""},
+	}
+	for _, tr := range tests {
+		r, _ := http.NewRequest(http.MethodGet, tr.path, nil)
+		r.RemoteAddr = tr.remote
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if p, v := w.Header().Get("X-Route-Pattern"), w.Header().Get("X-Route-Version"); p != tr.pattern || v != tr.version {
+			t.Errorf("%s from %s: got pattern %q version %q, want %q %q", tr.path, tr.remote, p, v, tr.pattern, tr.version)
+		}
+	}
+
+	headers.SetEnabled(false)
+	if headers.Enabled() {
+		t.Error("headers still enabled")
+	}
+	r, _ := http.NewRequest(http.MethodGet, "/items/1", nil)
+	r.RemoteAddr = "10.1.2.3:1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if p := w.Header().Get("X-Route-Pattern"); p != "" {
+		t.Errorf("disabled headers stamped: %q", p)
+	}
+
+	// Without networks all clients get the headers
+	all, _ := NewRouteHeaders()
+	router.RouteHeaders = all
+	r.RemoteAddr = "203.0.113.7:1234"
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if p := w.Header().Get("X-Route-Pattern"); p != "/items/:id" {
+		t.Errorf("unexpected pattern header: %q", p)
+	}
+
+	if _, err := NewRouteHeaders("not-an-ip"); err == nil {
+		t.Error("invalid network accepted")
+	}
+}
+
+func TestRouterRouteHeadersSwap(t *testing.T) {
+	handle := func(http.ResponseWriter, *http.Request, drouter.Params) {}
+	headers, _ := NewRouteHeaders()
+
+	router := New()
+	router.RouteHeaders = headers
+	router.VersionHeader = "X-Version"
+	router.GET("/items/:id", handle)
+
+	// The table is swapped after the request arrived, before its route is
+	// looked up
+	router.CanonicalQuery = &CanonicalQuery{Rewrite: func(url.Values) {
+		next := New()
+		next.GET("/items/:name", handle)
+		router.Swap(next)
+	}}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/1?x=1", nil))
+	p, v := w.Header().Get("X-Route-Pattern"), w.Header().Get("X-Route-Version")
+	if p != "/items/:id" || v != "1" || v != w.Header().Get("X-Version") {
+		t.Errorf("got pattern %q version %q, version header %q", p, v, w.Header().Get("X-Version"))
+	}
+}
@@ -0,0 +1,81 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterRoutes(t *testing.T) {
+	handle := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
+
+	router := New()
+	if routes := router.Routes(); len(routes) != 0 {
+		t.Errorf("empty router has routes: %v", routes)
+	}
+
+	users := New()
+	users.GET("/", handle)
+	users.DELETE("/:id", handle)
+
+	router.GET("/", handle)
+	router.GET("/users/:id", handle)
+	router.PUT("/users/:id", handle)
+	router.ServeFiles("/static/*filepath", http.Dir("."))
+	router.Mount("/admin/users", users)
+	router.Mount("/legacy", http.NotFoundHandler())
+
+	want := []RouteInfo{
+		{Method: http.MethodGet, Path: "/"},
+		{Method: http.MethodGet, Path: "/admin/users/"},
+		{Method: http.MethodDelete, Path: "/admin/users/:id"},
+		{Method: http.MethodGet, Path: "/static/*filepath"},
+		{Method: http.MethodGet, Path: "/users/:id"},
+		{Method: http.MethodPut, Path: "/users/:id"},
+	}
+	routes := router.Routes()
+	for i := range routes {
+		routes[i].Handler = HandlerID{}
+	}
+	if !reflect.DeepEqual(routes, want) {
+		t.Errorf("wrong routes:\nwant %v\n got %v", want, routes)
+	}
+}
+
+func TestRouterCheckConflicts(t *testing.T) {
+	handle := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
+
+	reports := New()
+	reports.GET("/Daily", handle)
+	reports.GET("/daily", handle)
+
+	admin := New()
+	admin.GET("/dashboard", handle)
+	admin.POST("/dashboard", handle)
+	admin.GET("/users/:id", handle)
+	admin.Mount("/reports", reports)
+
+	router := New()
+	router.GET("/admin/:page", handle)
+	router.GET("/About", handle)
+	router.GET("/about", handle)
+	router.Mount("/admin", admin)
+
+	var got []string
+	for _, c := range router.CheckConflicts() {
+		if c.Kind != drouter.Conflict {
+			t.Errorf("unexpected kind of conflict: %v", c.Kind)
+		}
+		got = append(got, c.Path+" <- "+c.Existing)
+	}
+	want := []string{
+		"/About <- /about",
+		"/admin/dashboard <- /admin/:page",
+		"/admin/reports/daily <- /admin/reports/Daily",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unexpected conflicts:\n got: %q\nwant: %q", got, want)
+	}
+}
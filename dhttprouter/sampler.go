@@ -0,0 +1,70 @@
+package dhttprouter
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"runtime/pprof"
+
+	"github.com/thekhanj/drouter"
+)
+
+// Sampler selects a configurable fraction of the requests per matched route.
+// Sampled requests are passed to Func and optionally run with pprof labels,
+// so CPU profiles taken in production can be attributed to route patterns.
+type Sampler struct {
+	// Fraction of requests to sample, between 0 and 1, for routes without an
+	// entry in Rates.
+	Rate float64
+
+	// Per-route fractions, keyed by the registered route path, e.g.
+	// "/users/:id". They take priority over Rate.
+	Rates map[string]float64
+
+	// Optional function which is called for every sampled request before the
+	// handle is invoked, with the method and path of the matched route.
+	Func func(req *http.Request, method, path string)
+
+	// If enabled, the handle of sampled requests is run with the pprof labels
+	// "http.method" and "http.route" set to the method and path of the matched
+	// route.
+	Labels bool
+
+	// Source of randomness, mainly for testing. If it is not set,
+	// math/rand.Float64 is used.
+	Random func() float64
+}
+
+func (s *Sampler) sample(path string) bool {
+	rate, ok := s.Rates[path]
+	if !ok {
+		rate = s.Rate
+	}
+
+	switch {
+	case rate <= 0:
+		return false
+	case rate >= 1:
+		return true
+	case s.Random != nil:
+		return s.Random() < rate
+	default:
+		return rand.Float64() < rate
+	}
+}
+
+func (s *Sampler) serve(rt *route, w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+	if s.Func != nil {
+		s.Func(req, rt.method, rt.path)
+	}
+
+	if !s.Labels {
+		rt.handle(w, req, ps)
+		return
+	}
+
+	labels := pprof.Labels("http.method", rt.method, "http.route", rt.path)
+	pprof.Do(req.Context(), labels, func(ctx context.Context) {
+		rt.handle(w, req.WithContext(ctx), ps)
+	})
+}
@@ -0,0 +1,61 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterSampler(t *testing.T) {
+	var label string
+	handle := func(_ http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		label, _ = pprof.Label(req.Context(), "http.route")
+	}
+
+	sampled := map[string]int{}
+	router := New()
+	router.GET("/users/:id", handle)
+	router.GET("/health", handle)
+	router.GET("/static/*filepath", handle)
+	router.Sampler = &Sampler{
+		Rate: 0.5,
+		Rates: map[string]float64{
+			"/health":           0,
+			"/static/*filepath": 1,
+		},
+		Func: func(req *http.Request, method, path string) {
+			sampled[method+" "+path]++
+		},
+		Labels: true,
+		Random: func() float64 { return 0.25 },
+	}
+
+	for _, path := range []string{"/users/1", "/users/2", "/health", "/static/app.js"} {
+		label = ""
+		r, _ := http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), r)
+
+		if path == "/health" {
+			if label != "" {
+				t.Errorf("unsampled request %s has route label %q", path, label)
+			}
+		} else if label == "" {
+			t.Errorf("sampled request %s has no route label", path)
+		}
+	}
+
+	if sampled["GET /users/:id"] != 2 || sampled["GET /static/*filepath"] != 1 || sampled["GET /health"] != 0 {
+		t.Errorf("wrong samples: %v", sampled)
+	}
+
+	// requests above the rate are not sampled
+	router.Sampler.Random = func() float64 { return 0.75 }
+	r, _ := http.NewRequest(http.MethodGet, "/users/3", nil)
+	router.ServeHTTP(httptest.NewRecorder(), r)
+	if sampled["GET /users/:id"] != 2 {
+		t.Errorf("request above sampling rate was sampled")
+	}
+}
@@ -0,0 +1,242 @@
+package dhttprouter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/thekhanj/drouter"
+)
+
+// ScaffoldStore is the storage of a resource served by Scaffold. Items are
+// the values returned by ScaffoldSpec.New, typically pointers to structs.
+// Errors are passed to the router's ErrorHandler, so stores report missing
+// items with an *HTTPError with code 404 or an error known to the router's
+// ErrorTranslators, e.g. ErrorIs(sql.ErrNoRows, http.StatusNotFound).
+type ScaffoldStore interface {
+	List(ctx context.Context, page Page) ([]interface{}, error)
+	Get(ctx context.Context, id string) (interface{}, error)
+
+	// Create stores a new item and returns its id.
+	Create(ctx context.Context, item interface{}) (id string, err error)
+
+	Update(ctx context.Context, id string, item interface{}) error
+	Delete(ctx context.Context, id string) error
+}
+
+// Page selects a part of a list, parsed from the query parameters "offset"
+// and "limit".
+type Page struct {
+	Offset int
+	Limit  int
+}
+
+// ScaffoldSpec declares a resource served by Scaffold.
+type ScaffoldSpec struct {
+	// Storage of the items
+	Store ScaffoldStore
+
+	// Function returning a new item to decode request bodies into, e.g.
+	//
+	//	func() interface{} { return new(User) }
+	New func() interface{}
+
+	// Optional function validating decoded items before they are created or
+	// updated. Its errors are answered with 422 Unprocessable Entity and the
+	// error message.
+	Validate func(item interface{}) error
+
+	// Name of the id parameter. Defaults to "id".
+	IDParam string
+
+	// Optional constraint of the id parameter, e.g. "int" or "uuid", see
+	// drouter.RegisterConstraint.
+	IDConstraint string
+
+	// Number of items listed if the request has no limit. Defaults to 20.
+	DefaultLimit int
+
+	// Maximum number of items listed per request. Defaults to 100.
+	MaxLimit int
+
+	// Maximum size of request bodies. Defaults to 1 MiB.
+	MaxBodyBytes int64
+
+	// If enabled, only the list and get routes are registered.
+	ReadOnly bool
+
+	// Middleware applied to all routes of the resource.
+	Middleware []Middleware
+}
+
+// Scaffold registers the routes of a JSON API for the resource declared by
+// spec under the given prefix, e.g. for "/users":
+//
+//	GET    /users      list, paginated by ?offset=&limit=
+//	POST   /users      create, 201 with Location header
+//	GET    /users/:id  get
+//	PUT    /users/:id  update
+//	DELETE /users/:id  delete, 204
+//
+// Request bodies are decoded into items returned by spec.New, rejecting
+// unknown fields, and validated by spec.Validate. Lists are answered with an
+// object holding the items and the page, e.g.
+// {"items":[...],"offset":0,"limit":20}.
+// Scaffold panics if spec has no Store or New function, or if a route can
+// not be registered.
+func (r *HttpRouter) Scaffold(prefix string, spec ScaffoldSpec) {
+	if spec.Store == nil {
+		panic("scaffold store must not be nil for prefix '" + prefix + "'")
+	}
+	if spec.New == nil && !spec.ReadOnly {
+		panic("scaffold must have a New function for prefix '" + prefix + "'")
+	}
+	if spec.IDParam == "" {
+		spec.IDParam = "id"
+	}
+	if spec.DefaultLimit <= 0 {
+		spec.DefaultLimit = 20
+	}
+	if spec.MaxLimit <= 0 {
+		spec.MaxLimit = 100
+	}
+	if spec.MaxBodyBytes <= 0 {
+		spec.MaxBodyBytes = 1 << 20
+	}
+
+	s := &scaffold{spec: &spec, prefix: prefix}
+	item := prefix + "/:" + spec.IDParam
+	if spec.IDConstraint != "" {
+		item += "|" + spec.IDConstraint
+	}
+	if prefix == "/" {
+		s.prefix = ""
+		item = item[1:]
+	}
+
+	handle := func(method, path string, h HttpHandleE) {
+		if err := r.tryHandle(method, path, r.E(h), h, spec.Middleware); err != nil {
+			panic(err.Error())
+		}
+	}
+	handle(http.MethodGet, prefix, s.list)
+	handle(http.MethodGet, item, s.get)
+	if !spec.ReadOnly {
+		handle(http.MethodPost, prefix, s.create)
+		handle(http.MethodPut, item, s.update)
+		handle(http.MethodDelete, item, s.delete)
+	}
+}
+
+// scaffold serves a resource declared by a ScaffoldSpec.
+type scaffold struct {
+	spec   *ScaffoldSpec
+	prefix string
+}
+
+func (s *scaffold) list(w http.ResponseWriter, req *http.Request, _ drouter.Params) error {
+	page, err := s.page(req)
+	if err != nil {
+		return err
+	}
+	items, err := s.spec.Store.List(req.Context(), page)
+	if err != nil {
+		return err
+	}
+	if items == nil {
+		items = []interface{}{}
+	}
+	return writeJSON(w, http.StatusOK, struct {
+		Items  []interface{} `json:"items"`
+		Offset int           `json:"offset"`
+		Limit  int           `json:"limit"`
+	}{items, page.Offset, page.Limit})
+}
+
+// page parses the pagination parameters of the request.
+func (s *scaffold) page(req *http.Request) (Page, error) {
+	page := Page{Limit: s.spec.DefaultLimit}
+	q := req.URL.Query()
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return page, &HTTPError{Code: http.StatusBadRequest, Message: "invalid offset '" + v + "'"}
+		}
+		page.Offset = n
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return page, &HTTPError{Code: http.StatusBadRequest, Message: "invalid limit '" + v + "'"}
+		}
+		page.Limit = n
+	}
+	if page.Limit > s.spec.MaxLimit {
+		page.Limit = s.spec.MaxLimit
+	}
+	return page, nil
+}
+
+func (s *scaffold) get(w http.ResponseWriter, req *http.Request, ps drouter.Params) error {
+	item, err := s.spec.Store.Get(req.Context(), ps.ByName(s.spec.IDParam))
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, item)
+}
+
+func (s *scaffold) create(w http.ResponseWriter, req *http.Request, _ drouter.Params) error {
+	item, err := s.decode(w, req)
+	if err != nil {
+		return err
+	}
+	id, err := s.spec.Store.Create(req.Context(), item)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Location", s.prefix+"/"+id)
+	return writeJSON(w, http.StatusCreated, item)
+}
+
+func (s *scaffold) update(w http.ResponseWriter, req *http.Request, ps drouter.Params) error {
+	item, err := s.decode(w, req)
+	if err != nil {
+		return err
+	}
+	if err := s.spec.Store.Update(req.Context(), ps.ByName(s.spec.IDParam), item); err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, item)
+}
+
+func (s *scaffold) delete(w http.ResponseWriter, req *http.Request, ps drouter.Params) error {
+	if err := s.spec.Store.Delete(req.Context(), ps.ByName(s.spec.IDParam)); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// decode decodes and validates the item in the request body.
+func (s *scaffold) decode(w http.ResponseWriter, req *http.Request) (interface{}, error) {
+	item := s.spec.New()
+	dec := json.NewDecoder(http.MaxBytesReader(w, req.Body, s.spec.MaxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(item); err != nil {
+		return nil, &HTTPError{Code: http.StatusBadRequest, Message: "invalid body: " + err.Error()}
+	}
+	if s.spec.Validate != nil {
+		if err := s.spec.Validate(item); err != nil {
+			return nil, &HTTPError{Code: http.StatusUnprocessableEntity, Message: err.Error()}
+		}
+	}
+	return item, nil
+}
+
+// writeJSON writes v as JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, code int, v interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	return json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,135 @@
+package dhttprouter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type scaffoldUser struct {
+	Name string `json:"name"`
+}
+
+type scaffoldStore struct {
+	items map[string]interface{}
+	next  int
+}
+
+func (s *scaffoldStore) List(_ context.Context, page Page) ([]interface{}, error) {
+	var items []interface{}
+	for i := page.Offset + 1; i <= s.next && len(items) < page.Limit; i++ {
+		if item, ok := s.items[strconv.Itoa(i)]; ok {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (s *scaffoldStore) Get(_ context.Context, id string) (interface{}, error) {
+	item, ok := s.items[id]
+	if !ok {
+		return nil, &HTTPError{Code: http.StatusNotFound}
+	}
+	return item, nil
+}
+
+func (s *scaffoldStore) Create(_ context.Context, item interface{}) (string, error) {
+	s.next++
+	id := strconv.Itoa(s.next)
+	s.items[id] = item
+	return id, nil
+}
+
+func (s *scaffoldStore) Update(ctx context.Context, id string, item interface{}) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	s.items[id] = item
+	return nil
+}
+
+func (s *scaffoldStore) Delete(ctx context.Context, id string) error {
+	if _, err := s.Get(ctx, id); err != nil {
+		return err
+	}
+	delete(s.items, id)
+	return nil
+}
+
+func TestRouterScaffold(t *testing.T) {
+	router := New()
+	router.Scaffold("/users", ScaffoldSpec{
+		Store:        &scaffoldStore{items: make(map[string]interface{})},
+		New:          func() interface{} { return new(scaffoldUser) },
+		IDConstraint: "int",
+		MaxLimit:     2,
+		Validate: func(item interface{}) error {
+			if item.(*scaffoldUser).Name == "" {
+				return errors.New("name is required")
+			}
+			return nil
+		},
+	})
+
+	tests := []struct {
+		method, path, body string
+		code               int
+		resp               string
+	}{
+		{http.MethodPost, "/users", `{"name":"a"}`, http.StatusCreated, `{"name":"a"}`},
+		{http.MethodPost, "/users", `{"name":"b"}`, http.StatusCreated, `{"name":"b"}`},
+		{http.MethodPost, "/users", `{"name":"c"}`, http.StatusCreated, `{"name":"c"}`},
+		{http.MethodPost, "/users", `{"name":""}`, http.StatusUnprocessableEntity, "name is required"},
+		{http.MethodPost, "/users", `{"email":"x"}`, http.StatusBadRequest, "invalid body"},
+		{http.MethodGet, "/users/2", "", http.StatusOK, `{"name":"b"}`},
+		{http.MethodGet, "/users/9", "", http.StatusNotFound, ""},
+		{http.MethodGet, "/users/x", "", http.StatusNotFound, ""},
+		{http.MethodGet, "/users", "", http.StatusOK, `{"items":[{"name":"a"},{"name":"b"}],"offset":0,"limit":2}`},
+		{http.MethodGet, "/users?offset=2&limit=1", "", http.StatusOK, `{"items":[{"name":"c"}],"offset":2,"limit":1}`},
+		{http.MethodGet, "/users?limit=-1", "", http.StatusBadRequest, "invalid limit"},
+		{http.MethodPut, "/users/1", `{"name":"z"}`, http.StatusOK, `{"name":"z"}`},
+		{http.MethodGet, "/users/1", "", http.StatusOK, `{"name":"z"}`},
+		{http.MethodDelete, "/users/1", "", http.StatusNoContent, ""},
+		{http.MethodDelete, "/users/1", "", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tt.code {
+			t.Errorf("%s %s: want %d, got %d: %s", tt.method, tt.path, tt.code, w.Code, w.Body)
+		}
+		if !strings.Contains(w.Body.String(), tt.resp) {
+			t.Errorf("%s %s: wrong body %q", tt.method, tt.path, w.Body)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"d"}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if loc := w.Header().Get("Location"); loc != "/users/4" {
+		t.Errorf("wrong location %q", loc)
+	}
+}
+
+func TestRouterScaffoldReadOnly(t *testing.T) {
+	router := New()
+	router.Scaffold("/users", ScaffoldSpec{
+		Store:    &scaffoldStore{items: make(map[string]interface{})},
+		ReadOnly: true,
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("{}")))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("create route of read-only scaffold was registered: %d", w.Code)
+	}
+
+	if recv := catchPanic(func() { router.Scaffold("/posts", ScaffoldSpec{}) }); recv == nil {
+		t.Error("no panic for missing store")
+	}
+}
@@ -0,0 +1,130 @@
+package dhttprouter
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/thekhanj/drouter"
+)
+
+// HTTPSOnly restricts routes to requests made over https. It can be used as
+// per-route Middleware via its Wrap method.
+type HTTPSOnly struct {
+	// If enabled, the scheme reported by a TLS-terminating proxy in the
+	// X-Forwarded-Proto or Forwarded header is trusted. Only enable this if
+	// all requests pass such a proxy, since clients can set the headers
+	// themselves.
+	TrustForwardedProto bool
+
+	// If set, the forwarded scheme is only trusted if the request comes from
+	// one of its trusted proxies. This implies TrustForwardedProto.
+	Proxies *ProxyResolver
+
+	// If enabled, insecure requests are redirected to the https URL with
+	// status code 301 for GET and HEAD requests and 308 for all other
+	// request methods. Otherwise they are rejected.
+	Redirect bool
+
+	// Configurable http.Handler which is called for rejected requests.
+	// If it is not set, http.Error with http.StatusForbidden is used.
+	Rejected http.Handler
+}
+
+// Wrap returns a handle which passes secure requests on to the given handle
+// and redirects or rejects all others.
+func (p HTTPSOnly) Wrap(handle HttpHandle) HttpHandle {
+	return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		trust := p.TrustForwardedProto
+		if p.Proxies != nil {
+			trust = p.Proxies.Trusted(remoteIP(req.RemoteAddr))
+		}
+
+		if requestScheme(req, trust, p.Proxies) == "https" {
+			handle(w, req, ps)
+			return
+		}
+
+		if p.Redirect {
+			code := http.StatusMovedPermanently
+			if req.Method != http.MethodGet && req.Method != http.MethodHead {
+				code = http.StatusPermanentRedirect
+			}
+
+			host := req.Host
+			if h, _, err := net.SplitHostPort(host); err == nil {
+				host = h
+			}
+			u := *req.URL
+			u.Scheme = "https"
+			u.Host = host
+			u.Path = MountPrefixFromContext(req.Context()) + u.Path
+			u.RawPath = ""
+			http.Redirect(w, req, u.String(), code)
+			return
+		}
+
+		if p.Rejected != nil {
+			p.Rejected.ServeHTTP(w, req)
+		} else {
+			http.Error(w, "HTTPS required", http.StatusForbidden)
+		}
+	}
+}
+
+// requestScheme returns the scheme ("http" or "https") the client used for
+// the request, optionally taking the headers of a proxy into account.
+// Clients can add entries to the headers themselves, so the last entry, which
+// was added by the proxy the request came from, is used. If proxies is set,
+// entries are walked towards the client as long as they were added by trusted
+// proxies, like ProxyResolver.ClientIP does.
+func requestScheme(req *http.Request, trustForwarded bool, proxies *ProxyResolver) string {
+	if req.TLS != nil {
+		return "https"
+	}
+	if !trustForwarded {
+		return "http"
+	}
+
+	// protos[i] is the scheme hops[i] used to connect to the proxy which
+	// added the entry
+	var protos, hops []string
+	if values := req.Header.Values("X-Forwarded-Proto"); len(values) > 0 {
+		for _, v := range values {
+			for _, proto := range strings.Split(v, ",") {
+				protos = append(protos, strings.TrimSpace(proto))
+			}
+		}
+		hops = forwardedFor(http.Header{"X-Forwarded-For": req.Header.Values("X-Forwarded-For")})
+	} else {
+		for _, v := range req.Header.Values("Forwarded") {
+			for _, elem := range strings.Split(v, ",") {
+				var proto, hop string
+				for _, pair := range strings.Split(elem, ";") {
+					pair = strings.TrimSpace(pair)
+					if len(pair) > 6 && strings.EqualFold(pair[:6], "proto=") {
+						proto = strings.Trim(pair[6:], `"`)
+					} else if len(pair) > 4 && strings.EqualFold(pair[:4], "for=") {
+						hop = strings.Trim(pair[4:], `"`)
+					}
+				}
+				protos = append(protos, proto)
+				hops = append(hops, hop)
+			}
+		}
+	}
+	if len(protos) == 0 {
+		return "http"
+	}
+
+	i := len(protos) - 1
+	if proxies != nil && len(hops) == len(protos) {
+		for i > 0 && proxies.Trusted(parseIP(hops[i])) {
+			i--
+		}
+	}
+	if proto := strings.ToLower(protos[i]); proto != "" {
+		return proto
+	}
+	return "http"
+}
@@ -0,0 +1,72 @@
+package dhttprouter
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestHTTPSOnly(t *testing.T) {
+	handled := false
+	handle := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		handled = true
+	}
+
+	router := New()
+	router.GET("/reject", handle, HTTPSOnly{}.Wrap)
+	router.GET("/redirect", handle, HTTPSOnly{Redirect: true}.Wrap)
+	router.POST("/redirect", handle, HTTPSOnly{Redirect: true}.Wrap)
+	router.GET("/proxy", handle, HTTPSOnly{TrustForwardedProto: true}.Wrap)
+
+	tests := []struct {
+		method, url string
+		tls         bool
+		header      [2]string
+		code        int
+		location    string
+		handled     bool
+	}{
+		{http.MethodGet, "http://example.com/reject", false, [2]string{}, http.StatusForbidden, "", false},
+		{http.MethodGet, "https://example.com/reject", true, [2]string{}, http.StatusOK, "", true},
+		{http.MethodGet, "http://example.com/reject", false, [2]string{"X-Forwarded-Proto", "https"}, http.StatusForbidden, "", false},
+		{http.MethodGet, "http://example.com:8080/redirect?a=b", false, [2]string{}, http.StatusMovedPermanently, "https://example.com/redirect?a=b", false},
+		{http.MethodPost, "http://example.com/redirect", false, [2]string{}, http.StatusPermanentRedirect, "https://example.com/redirect", false},
+		// The client can add the first entries itself
+		{http.MethodGet, "http://example.com/proxy", false, [2]string{"X-Forwarded-Proto", "https, http"}, http.StatusForbidden, "", false},
+		{http.MethodGet, "http://example.com/proxy", false, [2]string{"X-Forwarded-Proto", "http, https"}, http.StatusOK, "", true},
+		{http.MethodGet, "http://example.com/proxy", false, [2]string{"Forwarded", `proto=https, for=1.2.3.4;proto=http`}, http.StatusForbidden, "", false},
+		{http.MethodGet, "http://example.com/proxy", false, [2]string{"X-Forwarded-Proto", "http"}, http.StatusForbidden, "", false},
+		{http.MethodGet, "http://example.com/proxy", false, [2]string{"Forwarded", `for=1.2.3.4;Proto="HTTPS"`}, http.StatusOK, "", true},
+		{http.MethodGet, "http://example.com/proxy", false, [2]string{"Forwarded", "for=1.2.3.4"}, http.StatusForbidden, "", false},
+	}
+	for _, tr := range tests {
+		handled = false
+		r, _ := http.NewRequest(tr.method, tr.url, nil)
+		if tr.tls {
+			r.TLS = &tls.ConnectionState{}
+		}
+		if tr.header[0] != "" {
+			r.Header.Set(tr.header[0], tr.header[1])
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != tr.code || w.Header().Get("Location") != tr.location || handled != tr.handled {
+			t.Errorf("%s %s %v failed: Code=%d, Location=%q, handled=%t", tr.method, tr.url, tr.header, w.Code, w.Header().Get("Location"), handled)
+		}
+	}
+
+	// custom rejection handler
+	router = New()
+	router.GET("/x", handle, HTTPSOnly{Rejected: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUpgradeRequired)
+	})}.Wrap)
+	r, _ := http.NewRequest(http.MethodGet, "/x", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusUpgradeRequired {
+		t.Errorf("custom rejection handler not called: Code=%d", w.Code)
+	}
+}
@@ -0,0 +1,185 @@
+package dhttprouter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"github.com/thekhanj/drouter"
+)
+
+// SelfCheckError is a problem of a route found by SelfCheck.
+type SelfCheckError struct {
+	Method string
+	Path   string
+
+	// Request path synthesized for the route, if any
+	Sample string
+
+	Message string
+}
+
+func (e *SelfCheckError) Error() string {
+	return e.Method + " " + e.Path + ": " + e.Message
+}
+
+type selfCheckKey struct{}
+
+// IsSelfCheck reports whether the request was synthesized by SelfCheck, so
+// handles can skip expensive or irrelevant work.
+func IsSelfCheck(ctx context.Context) bool {
+	return ctx.Value(selfCheckKey{}) != nil
+}
+
+// sampleValues are tried in order as values of named parameters, until one
+// satisfies the constraint of the parameter.
+var sampleValues = []string{
+	"1", "a", "a1", "f", "00000000-0000-4000-8000-000000000000", "selfcheck",
+}
+
+// SelfCheck verifies the wiring of all routes before the router receives
+// traffic, e.g. at startup. It synthesizes a request path for every route,
+// filling in parameter values which satisfy the constraints, and checks that
+// a request for it is dispatched to the route. GET routes are warmed up by
+// serving the synthesized request, whose context is marked by IsSelfCheck,
+// to a discarded response, which must not fail with a 5xx status or a panic.
+// Routes of mounted HttpRouters are checked as well.
+//
+// It returns the problems found, ordered by path and method.
+func (r *HttpRouter) SelfCheck() []*SelfCheckError {
+	var problems []*SelfCheckError
+	r.selfCheck("", &problems)
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Path != problems[j].Path {
+			return problems[i].Path < problems[j].Path
+		}
+		return problems[i].Method < problems[j].Method
+	})
+	return problems
+}
+
+func (r *HttpRouter) selfCheck(prefix string, problems *[]*SelfCheckError) {
+	t := r.loadTable()
+	for _, router := range t.routers {
+		router.Walk(func(_ string, rt *route) bool {
+			if err := r.checkRoute(t, rt); err != nil {
+				err.Path = prefix + err.Path
+				*problems = append(*problems, err)
+			}
+			return true
+		})
+	}
+
+	if t.mounts == nil {
+		return
+	}
+	t.mounts.Walk(func(path string, m *mount) bool {
+		if sub, ok := m.handler.(*HttpRouter); ok && path == m.prefix {
+			sub.selfCheck(prefix+m.prefix, problems)
+		}
+		return true
+	})
+}
+
+// checkRoute checks a single route, see SelfCheck.
+func (r *HttpRouter) checkRoute(t *routeTable, rt *route) *SelfCheckError {
+	fail := func(sample, format string, args ...interface{}) *SelfCheckError {
+		return &SelfCheckError{Method: rt.method, Path: rt.path, Sample: sample, Message: fmt.Sprintf(format, args...)}
+	}
+
+	sample, err := samplePath(rt.path)
+	if err != nil {
+		return fail("", "%v", err)
+	}
+
+	varsCount := drouter.CountParams(rt.path)
+	if varsCount > t.maxParams {
+		return fail(sample, "params pool holds %d params, route needs %d", t.maxParams, varsCount)
+	}
+
+	if matched := t.matchRoute(rt.method, sample); matched != rt {
+		if matched == nil {
+			return fail(sample, "sample request '%s' is not routed", sample)
+		}
+		return fail(sample, "sample request '%s' is routed to '%s'", sample, matched.path)
+	}
+
+	if rt.method != http.MethodGet {
+		return nil
+	}
+	return r.warmup(rt, sample)
+}
+
+// warmup serves a GET request for the sample path of the route to a
+// discarded response.
+func (r *HttpRouter) warmup(rt *route, sample string) (problem *SelfCheckError) {
+	u := &url.URL{Path: sample}
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return &SelfCheckError{Method: rt.method, Path: rt.path, Sample: sample, Message: err.Error()}
+	}
+	req = req.WithContext(context.WithValue(req.Context(), selfCheckKey{}, true))
+
+	defer func() {
+		if rcv := recover(); rcv != nil {
+			problem = &SelfCheckError{Method: rt.method, Path: rt.path, Sample: sample,
+				Message: fmt.Sprintf("handle panicked: %v", rcv)}
+		}
+	}()
+
+	w := &bufferedResponse{header: make(http.Header)}
+	r.dispatch(w, req)
+	if w.status >= 500 {
+		return &SelfCheckError{Method: rt.method, Path: rt.path, Sample: sample,
+			Message: fmt.Sprintf("sample request '%s' failed with status %d", sample, w.status)}
+	}
+	return nil
+}
+
+// samplePath builds a request path for the route pattern, choosing parameter
+// values which satisfy their constraints.
+func samplePath(pattern string) (string, error) {
+	segments, err := drouter.ParsePattern(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	var ps drouter.Params
+	for _, seg := range segments {
+		switch seg.Kind {
+		case drouter.ParamSegment:
+			value := sampleValue(seg)
+			if value == "" {
+				return "", errors.New("no sample value satisfies constraint '" + seg.Constraint +
+					"' of parameter '" + seg.Value + "'")
+			}
+			ps = append(ps, drouter.Param{Key: seg.Value, Value: value})
+		case drouter.CatchAllSegment:
+			ps = append(ps, drouter.Param{Key: seg.Value, Value: "/selfcheck"})
+		}
+	}
+	return drouter.BuildPath(pattern, ps)
+}
+
+// sampleValue returns a parameter value satisfying the constraint of the
+// segment once its transforms are applied, or an empty string if none of the
+// sample values does.
+func sampleValue(seg drouter.Segment) string {
+	if seg.Constraint == "" {
+		return sampleValues[0]
+	}
+	check := drouter.Constraint(seg.Constraint)
+	for _, value := range sampleValues {
+		transformed := value
+		for _, name := range seg.Transforms {
+			transformed = drouter.Transform(name)(transformed)
+		}
+		if check(transformed) {
+			return value
+		}
+	}
+	return ""
+}
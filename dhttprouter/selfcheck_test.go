@@ -0,0 +1,56 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterSelfCheck(t *testing.T) {
+	drouter.RegisterConstraint("test-never", func(string) bool { return false })
+
+	var warmed []string
+	ok := func(_ http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		if !IsSelfCheck(req.Context()) {
+			t.Errorf("request not marked as self-check: %s", req.URL.Path)
+		}
+		warmed = append(warmed, req.URL.Path)
+	}
+	fail := func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		w.WriteHeader(http.StatusBadGateway)
+	}
+	boom := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		panic("not wired")
+	}
+
+	admin := New()
+	admin.GET("/reports/:id|uuid", ok)
+	admin.GET("/broken", boom)
+
+	router := New()
+	router.GET("/users/:id|int/files/*path", ok)
+	router.POST("/users", boom)
+	router.GET("/items/:id|test-never", ok)
+	router.GET("/upstream", fail)
+	router.Mount("/admin", admin)
+
+	var got []string
+	for _, p := range router.SelfCheck() {
+		got = append(got, p.Error())
+	}
+	want := []string{
+		"GET /admin/broken: handle panicked: not wired",
+		"GET /items/:id|test-never: no sample value satisfies constraint 'test-never' of parameter 'id'",
+		"GET /upstream: sample request '/upstream' failed with status 502",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong problems:\nwant %q\n got %q", want, got)
+	}
+
+	wantWarmed := []string{"/reports/00000000-0000-4000-8000-000000000000", "/users/1/files/selfcheck"}
+	if len(warmed) != 2 || !(warmed[0] == wantWarmed[0] || warmed[1] == wantWarmed[0]) {
+		t.Errorf("wrong warmed up paths: %q", warmed)
+	}
+}
@@ -0,0 +1,204 @@
+package dhttprouter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+// Shadow mirrors a copy of the requests of a route to a secondary handle or
+// upstream, e.g. to test a new implementation against production traffic.
+// The copies are sent asynchronously and their responses are discarded, so
+// the responses of the route are not affected. It can be used as per-route
+// Middleware via its Wrap method:
+//
+//	shadow := &dhttprouter.Shadow{Target: searchV2, MaxBody: 64 << 10}
+//	router.POST("/search", search, shadow.Wrap)
+//
+// Request bodies are buffered up to MaxBody, requests with larger bodies are
+// not mirrored.
+type Shadow struct {
+	// Counters, must stay the first fields for 64-bit alignment
+	shadowed uint64
+	skipped  uint64
+	inFlight int64
+
+	// Handle receiving the copies. The response it writes is discarded.
+	Handle HttpHandle
+
+	// Base URL of the upstream receiving the copies, if Handle is not set.
+	// The path of the request is appended to its path and the header
+	// X-Shadow-Request is set, so the upstream can tell copies apart.
+	Target *url.URL
+
+	// Transport of the requests to Target. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// Maximum number of body bytes buffered for the copy. Requests with
+	// larger bodies are not mirrored. Defaults to 1 MiB.
+	MaxBody int64
+
+	// Maximum number of copies in flight. Requests exceeding it are not
+	// mirrored, so a slow secondary does not pile up goroutines. Unlimited
+	// if zero.
+	MaxInFlight int
+
+	// Timeout of the copies, which are not canceled with the original
+	// request. Defaults to 10 seconds.
+	Timeout time.Duration
+
+	// Optional function which is called once a copy was served, with its
+	// status code or the error of the upstream request. Copies whose handle
+	// panicked are reported with 500 and an error.
+	Observe func(req *http.Request, code int, err error)
+}
+
+// Shadowed returns the number of requests mirrored so far.
+func (s *Shadow) Shadowed() uint64 {
+	return atomic.LoadUint64(&s.shadowed)
+}
+
+// Skipped returns the number of requests which were not mirrored, as their
+// body exceeded MaxBody or too many copies were in flight.
+func (s *Shadow) Skipped() uint64 {
+	return atomic.LoadUint64(&s.skipped)
+}
+
+// Wrap returns a handle which mirrors the requests before passing them on to
+// the given handle. It panics if neither Handle nor Target is set.
+func (s *Shadow) Wrap(handle HttpHandle) HttpHandle {
+	if s.Handle == nil && s.Target == nil {
+		panic("shadow needs a Handle or Target")
+	}
+	return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		s.mirror(req, ps)
+		handle(w, req, ps)
+	}
+}
+
+// mirror starts sending a copy of the request, restoring its body for the
+// handle of the route.
+func (s *Shadow) mirror(req *http.Request, ps drouter.Params) {
+	maxBody := s.MaxBody
+	if maxBody <= 0 {
+		maxBody = 1 << 20
+	}
+	if req.ContentLength > maxBody {
+		atomic.AddUint64(&s.skipped, 1)
+		return
+	}
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		// Read one byte more than buffered to detect larger bodies, then hand
+		// the complete body on to the handle
+		b, err := ioutil.ReadAll(io.LimitReader(req.Body, maxBody+1))
+		req.Body = readCloser{io.MultiReader(bytes.NewReader(b), req.Body), req.Body}
+		if err != nil || int64(len(b)) > maxBody {
+			atomic.AddUint64(&s.skipped, 1)
+			return
+		}
+		body = b
+	}
+
+	if n := atomic.AddInt64(&s.inFlight, 1); s.MaxInFlight > 0 && n > int64(s.MaxInFlight) {
+		atomic.AddInt64(&s.inFlight, -1)
+		atomic.AddUint64(&s.skipped, 1)
+		return
+	}
+	atomic.AddUint64(&s.shadowed, 1)
+
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	copied := req.Clone(ctx)
+	copied.Body = http.NoBody
+	if body != nil {
+		copied.Body = ioutil.NopCloser(bytes.NewReader(body))
+		copied.ContentLength = int64(len(body))
+	}
+
+	// The copy may outlive the request, so it gets its own params
+	ps = ps.Clone()
+	go func() {
+		defer atomic.AddInt64(&s.inFlight, -1)
+		defer cancel()
+		code, err := s.send(copied, ps)
+		if s.Observe != nil {
+			s.Observe(copied, code, err)
+		}
+	}()
+}
+
+// send serves the copy with the Handle or sends it to the Target.
+func (s *Shadow) send(req *http.Request, ps drouter.Params) (code int, err error) {
+	if s.Handle != nil {
+		defer func() {
+			if p := recover(); p != nil {
+				code, err = http.StatusInternalServerError, fmt.Errorf("shadow handle panicked: %v", p)
+			}
+		}()
+		w := &discardResponse{header: make(http.Header)}
+		s.Handle(w, req, ps)
+		if w.status == 0 {
+			return http.StatusOK, nil
+		}
+		return w.status, nil
+	}
+
+	out := req.Clone(req.Context())
+	out.RequestURI = ""
+	out.URL.Scheme = s.Target.Scheme
+	out.URL.Host = s.Target.Host
+	out.URL.Path = strings.TrimSuffix(s.Target.Path, "/") + req.URL.Path
+	out.URL.RawPath = ""
+	out.Host = s.Target.Host
+	out.Header.Set("X-Shadow-Request", "1")
+
+	transport := s.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	res, err := transport.RoundTrip(out)
+	if err != nil {
+		return 0, err
+	}
+	io.Copy(ioutil.Discard, res.Body)
+	res.Body.Close()
+	return res.StatusCode, nil
+}
+
+// discardResponse records the status of a shadow response and discards its
+// body.
+type discardResponse struct {
+	header http.Header
+	status int
+}
+
+func (d *discardResponse) Header() http.Header {
+	return d.header
+}
+
+func (d *discardResponse) WriteHeader(code int) {
+	if d.status == 0 {
+		d.status = code
+	}
+}
+
+func (d *discardResponse) Write(p []byte) (int, error) {
+	if d.status == 0 {
+		d.status = http.StatusOK
+	}
+	return len(p), nil
+}
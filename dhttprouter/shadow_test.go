@@ -0,0 +1,118 @@
+package dhttprouter
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+type shadowResult struct {
+	path, body string
+	code       int
+	err        error
+}
+
+func TestShadow(t *testing.T) {
+	results := make(chan shadowResult, 10)
+	shadow := &Shadow{
+		Handle: func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+			if ps.ByName("id") == "panic" {
+				panic("boom")
+			}
+			body, _ := ioutil.ReadAll(req.Body)
+			w.WriteHeader(http.StatusTeapot)
+			results <- shadowResult{path: req.URL.Path, body: string(body)}
+		},
+		MaxBody: 8,
+		Observe: func(req *http.Request, code int, err error) {
+			results <- shadowResult{code: code, err: err}
+		},
+	}
+
+	var primaryBody string
+	router := New()
+	router.POST("/items/:id", func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		body, _ := ioutil.ReadAll(req.Body)
+		primaryBody = string(body)
+		w.WriteHeader(http.StatusCreated)
+	}, shadow.Wrap)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/items/1", strings.NewReader("payload")))
+	if w.Code != http.StatusCreated || primaryBody != "payload" {
+		t.Errorf("primary response was affected: %d, %q", w.Code, primaryBody)
+	}
+	if got := <-results; got.path != "/items/1" || got.body != "payload" {
+		t.Errorf("wrong copy %+v", got)
+	}
+	if got := <-results; got.code != http.StatusTeapot || got.err != nil {
+		t.Errorf("wrong observation %+v", got)
+	}
+
+	// Panics of the shadow are reported, not propagated
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/items/panic", nil))
+	if got := <-results; got.code != http.StatusInternalServerError || got.err == nil {
+		t.Errorf("wrong observation of panic %+v", got)
+	}
+
+	// Larger bodies are passed on, but not mirrored
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/items/2", strings.NewReader("too large payload")))
+	if primaryBody != "too large payload" {
+		t.Errorf("body was not restored: %q", primaryBody)
+	}
+	if shadow.Shadowed() != 2 || shadow.Skipped() != 1 {
+		t.Errorf("wrong counters %d, %d", shadow.Shadowed(), shadow.Skipped())
+	}
+
+	if recv := catchPanic(func() { (&Shadow{}).Wrap(nil) }); recv == nil {
+		t.Error("no panic without Handle and Target")
+	}
+}
+
+func TestShadowTarget(t *testing.T) {
+	got := make(chan *http.Request, 1)
+	release := make(chan struct{})
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		<-release
+		got <- req
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL + "/v2")
+
+	observed := make(chan int, 1)
+	shadow := &Shadow{
+		Target:      target,
+		MaxInFlight: 1,
+		Observe: func(_ *http.Request, code int, _ error) {
+			observed <- code
+		},
+	}
+	router := New()
+	router.GET("/search", func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {}, shadow.Wrap)
+
+	start := time.Now()
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/search?q=go", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/search?q=go", nil))
+	if time.Since(start) > time.Second {
+		t.Error("requests waited for the shadow")
+	}
+	close(release)
+
+	req := <-got
+	if req.URL.Path != "/v2/search" || req.URL.RawQuery != "q=go" || req.Header.Get("X-Shadow-Request") != "1" {
+		t.Errorf("wrong upstream request %s?%s %v", req.URL.Path, req.URL.RawQuery, req.Header)
+	}
+	if code := <-observed; code != http.StatusOK {
+		t.Errorf("wrong status %d", code)
+	}
+	if shadow.Shadowed() != 1 || shadow.Skipped() != 1 {
+		t.Errorf("copies in flight were not limited: %d, %d", shadow.Shadowed(), shadow.Skipped())
+	}
+}
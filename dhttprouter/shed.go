@@ -0,0 +1,110 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/thekhanj/drouter"
+)
+
+// Priority is the importance of a route for load shedding, see LoadShedder.
+type Priority int
+
+// Priorities of routes, from the first to the last to be shed.
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+
+	// Critical routes are never shed
+	PriorityCritical
+)
+
+// defaultShedThresholds are the loads at which routes are shed by priority.
+var defaultShedThresholds = map[Priority]float64{
+	PriorityLow:    0.6,
+	PriorityNormal: 0.8,
+	PriorityHigh:   0.95,
+}
+
+// LoadShedder rejects requests of low-priority routes with 503 Service
+// Unavailable when the server is overloaded, protecting critical endpoints.
+// Routes are tagged with a priority using the middleware returned by
+// Priority, e.g.
+//
+//	shedder := &dhttprouter.LoadShedder{MaxInFlight: 500}
+//	router.GET("/reports", reports, shedder.Priority(dhttprouter.PriorityLow))
+//	router.POST("/checkout", checkout, shedder.Priority(dhttprouter.PriorityCritical))
+//
+// The load is a fraction where 1 means fully loaded. It is the number of
+// requests in flight through the shedder relative to MaxInFlight or the value
+// of the Load signal, whichever is higher. A request is shed if the load is
+// at least the threshold of its route's priority.
+type LoadShedder struct {
+	// Accessed atomically, must stay the first fields for 64-bit alignment
+	inFlight int64
+	shed     uint64
+
+	// Number of requests in flight at which the load is 1. Disabled if zero.
+	MaxInFlight int
+
+	// Optional load signal, e.g. the CPU utilization between 0 and 1.
+	Load func() float64
+
+	// Load at which the routes of a priority are shed. Priorities without
+	// threshold are never shed. Defaults to 0.6 for PriorityLow, 0.8 for
+	// PriorityNormal and 0.95 for PriorityHigh.
+	Thresholds map[Priority]float64
+}
+
+// Priority returns a middleware tagging a route with the given priority.
+func (s *LoadShedder) Priority(p Priority) Middleware {
+	return func(handle HttpHandle) HttpHandle {
+		return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+			inFlight := atomic.AddInt64(&s.inFlight, 1)
+			defer atomic.AddInt64(&s.inFlight, -1)
+
+			if threshold, ok := s.threshold(p); ok && s.load(inFlight-1) >= threshold {
+				atomic.AddUint64(&s.shed, 1)
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+				return
+			}
+			handle(w, req, ps)
+		}
+	}
+}
+
+func (s *LoadShedder) threshold(p Priority) (float64, bool) {
+	thresholds := s.Thresholds
+	if thresholds == nil {
+		thresholds = defaultShedThresholds
+	}
+	threshold, ok := thresholds[p]
+	return threshold, ok
+}
+
+// load returns the current load, given the number of other requests in
+// flight.
+func (s *LoadShedder) load(inFlight int64) float64 {
+	var load float64
+	if s.MaxInFlight > 0 {
+		load = float64(inFlight) / float64(s.MaxInFlight)
+	}
+	if s.Load != nil {
+		if l := s.Load(); l > load {
+			load = l
+		}
+	}
+	return load
+}
+
+// InFlight returns the number of requests in flight through the shedder.
+func (s *LoadShedder) InFlight() int {
+	return int(atomic.LoadInt64(&s.inFlight))
+}
+
+// Shed returns the number of requests shed so far.
+func (s *LoadShedder) Shed() uint64 {
+	return atomic.LoadUint64(&s.shed)
+}
@@ -0,0 +1,86 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestLoadShedder(t *testing.T) {
+	load := 0.0
+	shedder := &LoadShedder{Load: func() float64 { return load }}
+
+	handle := func(http.ResponseWriter, *http.Request, drouter.Params) {}
+	router := New()
+	router.GET("/low", handle, shedder.Priority(PriorityLow))
+	router.GET("/normal", handle, shedder.Priority(PriorityNormal))
+	router.GET("/high", handle, shedder.Priority(PriorityHigh))
+	router.GET("/critical", handle, shedder.Priority(PriorityCritical))
+
+	serve := func(path string) int {
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, path, nil)
+		router.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	tests := []struct {
+		load                        float64
+		low, normal, high, critical int
+	}{
+		{0, 200, 200, 200, 200},
+		{0.6, 503, 200, 200, 200},
+		{0.85, 503, 503, 200, 200},
+		{1, 503, 503, 503, 200},
+	}
+	for _, tr := range tests {
+		load = tr.load
+		got := [4]int{serve("/low"), serve("/normal"), serve("/high"), serve("/critical")}
+		if got != [4]int{tr.low, tr.normal, tr.high, tr.critical} {
+			t.Errorf("load %v: unexpected status codes %v", tr.load, got)
+		}
+	}
+	if n := shedder.Shed(); n != 6 {
+		t.Errorf("Shed() = %d, want 6", n)
+	}
+}
+
+func TestLoadShedderInFlight(t *testing.T) {
+	shedder := &LoadShedder{
+		MaxInFlight: 2,
+		Thresholds:  map[Priority]float64{PriorityLow: 0.5},
+	}
+
+	router := New()
+	var codes []int
+	router.GET("/low", func(http.ResponseWriter, *http.Request, drouter.Params) {}, shedder.Priority(PriorityLow))
+	router.GET("/outer", func(http.ResponseWriter, *http.Request, drouter.Params) {
+		if n := shedder.InFlight(); n != 1 {
+			t.Errorf("InFlight() = %d, want 1", n)
+		}
+		// A nested request sees one request in flight, a load of 0.5
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, "/low", nil)
+		router.ServeHTTP(w, r)
+		codes = append(codes, w.Code)
+	}, shedder.Priority(PriorityNormal))
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/outer", nil)
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || len(codes) != 1 || codes[0] != http.StatusServiceUnavailable {
+		t.Errorf("unexpected status codes: outer %d, inner %v", w.Code, codes)
+	}
+	if n := shedder.InFlight(); n != 0 {
+		t.Errorf("InFlight() = %d after requests", n)
+	}
+
+	w = httptest.NewRecorder()
+	r, _ = http.NewRequest(http.MethodGet, "/low", nil)
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Errorf("request shed without load: %d", w.Code)
+	}
+}
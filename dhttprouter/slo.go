@@ -0,0 +1,108 @@
+package dhttprouter
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// SLO is a latency objective for a route: the fraction Target of all requests
+// (e.g. 0.99 for the 99th percentile) should complete within Threshold.
+type SLO struct {
+	Threshold time.Duration
+	Target    float64
+}
+
+// SLOStats holds the counters of a route's latency objective.
+type SLOStats struct {
+	Method    string
+	Path      string
+	Objective SLO
+
+	// Number of requests served by the route
+	Total uint64
+
+	// Number of requests which took longer than the threshold
+	Slow uint64
+}
+
+// BurnRate returns the rate at which the error budget of the objective is
+// consumed: the observed fraction of slow requests divided by the allowed
+// fraction 1-Target. A burn rate above 1 means the objective is missed if the
+// current behavior continues.
+func (s SLOStats) BurnRate() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	budget := 1 - s.Objective.Target
+	slow := float64(s.Slow) / float64(s.Total)
+	if budget <= 0 {
+		if slow > 0 {
+			return float64(s.Slow)
+		}
+		return 0
+	}
+	return slow / budget
+}
+
+type sloTracker struct {
+	// Accessed atomically, keep 64-bit aligned
+	total uint64
+	slow  uint64
+
+	objective SLO
+}
+
+func (t *sloTracker) observe(start time.Time) {
+	atomic.AddUint64(&t.total, 1)
+	if time.Since(start) > t.objective.Threshold {
+		atomic.AddUint64(&t.slow, 1)
+	}
+}
+
+// SetSLO sets the latency objective for the route registered with the given
+// method and path, resetting its counters. It panics if no such route exists.
+// Like the registration of routes, it must not be called concurrently with
+// ServeHTTP.
+func (r *HttpRouter) SetSLO(method, path string, objective SLO) {
+	t := r.mutableTable()
+	rt := t.lookupRoute(method, path)
+	if rt == nil {
+		panic("no route registered for " + method + " '" + path + "'")
+	}
+	if objective.Target < 0 || objective.Target > 1 {
+		panic("SLO target must be between 0 and 1 for path '" + path + "'")
+	}
+
+	if rt.slo == nil {
+		t.slos = append(t.slos, rt)
+	}
+	rt.slo = &sloTracker{objective: objective}
+}
+
+// SLOStats returns the current counters of all routes with a latency
+// objective, ordered by path and method.
+func (r *HttpRouter) SLOStats() []SLOStats {
+	return r.loadTable().sloStats()
+}
+
+func (t *routeTable) sloStats() []SLOStats {
+	stats := make([]SLOStats, 0, len(t.slos))
+	for _, rt := range t.slos {
+		stats = append(stats, SLOStats{
+			Method:    rt.method,
+			Path:      rt.path,
+			Objective: rt.slo.objective,
+			Total:     atomic.LoadUint64(&rt.slo.total),
+			Slow:      atomic.LoadUint64(&rt.slo.slow),
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Path != stats[j].Path {
+			return stats[i].Path < stats[j].Path
+		}
+		return stats[i].Method < stats[j].Method
+	})
+	return stats
+}
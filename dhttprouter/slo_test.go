@@ -0,0 +1,66 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterSLO(t *testing.T) {
+	delay := time.Duration(0)
+	handle := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		time.Sleep(delay)
+	}
+
+	router := New()
+	router.GET("/users/:id", handle)
+	router.POST("/users/:id", handle)
+	router.GET("/fast", handle)
+
+	router.SetSLO(http.MethodGet, "/users/:id", SLO{Threshold: 5 * time.Millisecond, Target: 0.9})
+	router.SetSLO(http.MethodGet, "/fast", SLO{Threshold: time.Hour, Target: 0.99})
+
+	serve := func(method, path string) {
+		r, _ := http.NewRequest(method, path, nil)
+		router.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	for i := 0; i < 3; i++ {
+		serve(http.MethodGet, "/users/1")
+		serve(http.MethodPost, "/users/1")
+	}
+	delay = 10 * time.Millisecond
+	serve(http.MethodGet, "/users/2")
+	delay = 0
+	serve(http.MethodGet, "/fast")
+
+	stats := router.SLOStats()
+	if len(stats) != 2 {
+		t.Fatalf("wrong number of SLO stats: want 2, got %d", len(stats))
+	}
+	if s := stats[0]; s.Path != "/fast" || s.Total != 1 || s.Slow != 0 || s.BurnRate() != 0 {
+		t.Errorf("wrong stats for /fast: %+v", s)
+	}
+	s := stats[1]
+	if s.Method != http.MethodGet || s.Path != "/users/:id" || s.Total != 4 || s.Slow != 1 {
+		t.Errorf("wrong stats for /users/:id: %+v", s)
+	}
+	if burn := s.BurnRate(); burn < 2.49 || burn > 2.51 {
+		t.Errorf("wrong burn rate: want 2.5, got %f", burn)
+	}
+
+	recv := catchPanic(func() {
+		router.SetSLO(http.MethodGet, "/users/:name", SLO{Threshold: time.Second, Target: 0.9})
+	})
+	if recv == nil {
+		t.Error("setting SLO for unknown route did not panic")
+	}
+	recv = catchPanic(func() {
+		router.SetSLO(http.MethodGet, "/fast", SLO{Threshold: time.Second, Target: 2})
+	})
+	if recv == nil {
+		t.Error("setting SLO with invalid target did not panic")
+	}
+}
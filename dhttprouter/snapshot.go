@@ -0,0 +1,115 @@
+package dhttprouter
+
+// Snapshot is a read-only view of a HttpRouter at one point in time. It
+// shares no memory with the router, so it may be read from any goroutine,
+// e.g. by an admin API or a documentation generator, while the router keeps
+// serving requests and changing its routes.
+type Snapshot struct {
+	// Version of the route table the snapshot was taken from, see Version
+	Version uint64
+
+	// Registered routes, see Routes
+	Routes []RouteInfo
+
+	// Installed route modules, see Modules
+	Modules []ModuleInfo
+
+	// Byte counters of the routes using Compression, see CompressionStats
+	Compression []CompressionStats
+
+	// Counters of the routes with a latency objective, see SLOStats
+	SLOs []SLOStats
+
+	// Legacy misses recorded so far, see LegacyMisses
+	LegacyMisses []LegacyMiss
+
+	// Capacity and overflows of the pooled Params, see ParamsStats
+	Params ParamsStats
+
+	Settings Settings
+}
+
+// Settings are the options of a HttpRouter in a Snapshot, see the fields of
+// HttpRouter for their meaning.
+type Settings struct {
+	Env string
+
+	SaveMatchedRoutePath   bool
+	RedirectTrailingSlash  bool
+	RedirectFixedPath      bool
+	HandleMethodNotAllowed bool
+	HandleOPTIONS          bool
+	AutoHEAD               bool
+	RejectUnknownMethods   bool
+	MethodOverride         bool
+	UseRawPath             bool
+	UnescapePathValues     bool
+	FileFallthrough        bool
+	CheckCanceled          bool
+
+	EmptyPath          EmptyPath
+	SuggestDistance    int
+	PreflightCacheSize int
+	VersionHeader      string
+	LegacyPrefixes     []string
+
+	NoRedirectMethods []string
+
+	// Names of the registered plugins, in the order of registration
+	Plugins []string
+}
+
+// Snapshot returns a snapshot of the routes, statistics and settings of the
+// router. The routes and statistics are taken from the route table current at
+// the call, which is replaced atomically by Swap, so Snapshot may be called
+// concurrently with Swap and ServeHTTP. Like Routes, it must not be called
+// concurrently with the registration of routes or changes of the settings,
+// which modify the router in place.
+func (r *HttpRouter) Snapshot() *Snapshot {
+	t := r.loadTable()
+	s := &Snapshot{
+		Version:      r.Version(),
+		Routes:       t.routes(),
+		Modules:      append([]ModuleInfo(nil), t.modules...),
+		Compression:  t.compressionStats(),
+		SLOs:         t.sloStats(),
+		LegacyMisses: r.LegacyMisses(),
+		Params:       r.paramsStats(t),
+		Settings: Settings{
+			Env:                    r.Env,
+			SaveMatchedRoutePath:   r.SaveMatchedRoutePath,
+			RedirectTrailingSlash:  r.RedirectTrailingSlash,
+			RedirectFixedPath:      r.RedirectFixedPath,
+			HandleMethodNotAllowed: r.HandleMethodNotAllowed,
+			HandleOPTIONS:          r.HandleOPTIONS,
+			AutoHEAD:               r.AutoHEAD,
+			RejectUnknownMethods:   r.RejectUnknownMethods,
+			MethodOverride:         r.MethodOverride,
+			UseRawPath:             r.UseRawPath,
+			UnescapePathValues:     r.UnescapePathValues,
+			FileFallthrough:        r.FileFallthrough,
+			CheckCanceled:          r.CheckCanceled,
+			EmptyPath:              r.EmptyPath,
+			SuggestDistance:        r.SuggestDistance,
+			PreflightCacheSize:     r.PreflightCacheSize,
+			VersionHeader:          r.VersionHeader,
+			LegacyPrefixes:         append([]string(nil), r.LegacyPrefixes...),
+			NoRedirectMethods:      append([]string(nil), r.NoRedirectMethods...),
+		},
+	}
+	for _, p := range r.plugins {
+		s.Settings.Plugins = append(s.Settings.Plugins, p.Name())
+	}
+	return s
+}
+
+// Route returns the route with the given method and path, and whether the
+// snapshot has one.
+func (s *Snapshot) Route(method, path string) (RouteInfo, bool) {
+	for _, info := range s.Routes {
+		if info.Method == method && info.Path == path {
+			return info, true
+		}
+	}
+	return RouteInfo{}, false
+}
@@ -0,0 +1,66 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterSnapshot(t *testing.T) {
+	handle := func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
+
+	router := New()
+	router.Env = "staging"
+	router.LegacyPrefixes = []string{"/old/"}
+	router.Register(namedPlugin("audit"))
+	router.GET("/users/:id", handle)
+	router.SetSLO(http.MethodGet, "/users/:id", SLO{Target: 0.9})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	s := router.Snapshot()
+	if s.Version != router.Version() {
+		t.Errorf("wrong version: want %d, got %d", router.Version(), s.Version)
+	}
+	if info, ok := s.Route(http.MethodGet, "/users/:id"); !ok || info.Handler != handlerID(handle) {
+		t.Errorf("route is missing: %+v", info)
+	}
+	if _, ok := s.Route(http.MethodPost, "/users/:id"); ok {
+		t.Error("unexpected route")
+	}
+	if len(s.SLOs) != 1 || s.SLOs[0].Total != 1 {
+		t.Errorf("wrong SLO stats %+v", s.SLOs)
+	}
+	if s.Settings.Env != "staging" || !s.Settings.RedirectTrailingSlash || len(s.Settings.Plugins) != 1 || s.Settings.Plugins[0] != "audit" {
+		t.Errorf("wrong settings %+v", s.Settings)
+	}
+
+	// The snapshot does not change with the router
+	router.LegacyPrefixes[0] = "/legacy/"
+	next := New()
+	next.GET("/posts/:id", handle)
+	router.Swap(next)
+	if len(s.Routes) != 1 || s.Routes[0].Path != "/users/:id" || s.Settings.LegacyPrefixes[0] != "/old/" {
+		t.Errorf("snapshot changed: %+v", s)
+	}
+
+	// Snapshots are taken while the table is swapped
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			if routes := router.Snapshot().Routes; len(routes) != 1 {
+				t.Errorf("inconsistent routes %v", routes)
+			}
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		next := New()
+		next.GET("/posts/:id", handle)
+		router.Swap(next)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,122 @@
+package dhttprouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+// ServerEvent is a Server-Sent Event.
+type ServerEvent struct {
+	// Optional id, which the client sends back in the Last-Event-ID header
+	// when it reconnects
+	ID string
+
+	// Optional type of the event. Events without type are "message" events.
+	Event string
+
+	// Payload of the event, which may span several lines
+	Data string
+
+	// Optional reconnection time the client should use
+	Retry time.Duration
+}
+
+// ServerEventHandle is a handle sending Server-Sent Events, see HttpRouter.SSE.
+type ServerEventHandle func(ew *EventWriter, req *http.Request, ps drouter.Params)
+
+// EventWriter sends Server-Sent Events to a client. Each event is flushed
+// immediately. Its methods may be called concurrently and fail with the
+// error of the request context once the client disconnected.
+type EventWriter struct {
+	sw  *StreamWriter
+	req *http.Request
+}
+
+var errEventField = errors.New("dhttprouter: event id and type must not contain line breaks")
+
+// Send sends the event.
+func (ew *EventWriter) Send(e ServerEvent) error {
+	if strings.ContainsAny(e.ID, "\r\n") || strings.ContainsAny(e.Event, "\r\n") {
+		return errEventField
+	}
+
+	var b strings.Builder
+	if e.ID != "" {
+		b.WriteString("id: " + e.ID + "\n")
+	}
+	if e.Event != "" {
+		b.WriteString("event: " + e.Event + "\n")
+	}
+	if e.Retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(e.Retry.Milliseconds(), 10) + "\n")
+	}
+	data := strings.ReplaceAll(e.Data, "\r\n", "\n")
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteByte('\n')
+
+	_, err := ew.sw.Write([]byte(b.String()))
+	return err
+}
+
+// SendJSON sends an event of the given type with v encoded as JSON as data.
+func (ew *EventWriter) SendJSON(event string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return ew.Send(ServerEvent{Event: event, Data: string(data)})
+}
+
+// Comment sends a comment, which clients ignore. Sending comments
+// periodically keeps idle connections open through proxies.
+func (ew *EventWriter) Comment(text string) error {
+	_, err := ew.sw.Write([]byte(": " + strings.ReplaceAll(text, "\n", " ") + "\n\n"))
+	return err
+}
+
+// LastEventID returns the id of the last event the client received before
+// it reconnected, or an empty string.
+func (ew *EventWriter) LastEventID() string {
+	return ew.req.Header.Get("Last-Event-ID")
+}
+
+// Context returns the context of the request, which is done once the client
+// disconnected.
+func (ew *EventWriter) Context() context.Context {
+	return ew.sw.Context()
+}
+
+// SSE registers a GET route sending Server-Sent Events with the given
+// handle, e.g.
+//
+//	router.SSE("/jobs/:id/events", func(ew *dhttprouter.EventWriter, req *http.Request, ps drouter.Params) {
+//		for p := range job(ps.ByName("id")).Progress(ew.Context()) {
+//			if err := ew.SendJSON("progress", p); err != nil {
+//				return // client disconnected
+//			}
+//		}
+//	})
+//
+// The response has the content type text/event-stream and is neither cached
+// nor buffered by proxies. The stream ends when the handle returns.
+func (r *HttpRouter) SSE(path string, handle ServerEventHandle, middleware ...Middleware) {
+	if handle == nil {
+		panic("handle must not be nil")
+	}
+	stream := Stream{ContentType: "text/event-stream"}.Handle(func(sw *StreamWriter, req *http.Request, ps drouter.Params) {
+		sw.Header().Set("X-Accel-Buffering", "no")
+		handle(&EventWriter{sw: sw, req: req}, req, ps)
+	})
+	if err := r.tryHandle(http.MethodGet, path, stream, handle, middleware); err != nil {
+		panic(err.Error())
+	}
+}
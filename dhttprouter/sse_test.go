@@ -0,0 +1,59 @@
+package dhttprouter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterSSE(t *testing.T) {
+	var lastID string
+	var badField error
+	router := New()
+	router.SSE("/jobs/:id/events", func(ew *EventWriter, _ *http.Request, ps drouter.Params) {
+		lastID = ew.LastEventID()
+		ew.Send(ServerEvent{ID: "1", Event: "start", Data: "job " + ps.ByName("id"), Retry: 3 * time.Second})
+		ew.Send(ServerEvent{Data: "line 1\nline 2"})
+		ew.SendJSON("progress", map[string]int{"done": 50})
+		ew.Comment("keep-alive")
+		badField = ew.Send(ServerEvent{Event: "a\nb"})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/7/events", nil)
+	req.Header.Set("Last-Event-ID", "0")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	want := "id: 1\nevent: start\nretry: 3000\ndata: job 7\n\n" +
+		"data: line 1\ndata: line 2\n\n" +
+		"event: progress\ndata: {\"done\":50}\n\n" +
+		": keep-alive\n\n"
+	if w.Body.String() != want {
+		t.Errorf("wrong body %q", w.Body)
+	}
+	if w.Header().Get("Content-Type") != "text/event-stream" || !w.Flushed {
+		t.Errorf("wrong headers %v or not flushed", w.Header())
+	}
+	if lastID != "0" {
+		t.Errorf("wrong last event id %q", lastID)
+	}
+	if badField == nil {
+		t.Error("no error for line break in event type")
+	}
+
+	// Sending fails once the client disconnected
+	var sendErr error
+	router.SSE("/closed", func(ew *EventWriter, _ *http.Request, _ drouter.Params) {
+		sendErr = ew.Send(ServerEvent{Data: "x"})
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/closed", nil).WithContext(ctx))
+	if sendErr != context.Canceled {
+		t.Errorf("wrong error %v", sendErr)
+	}
+}
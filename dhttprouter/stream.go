@@ -0,0 +1,174 @@
+package dhttprouter
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+// StreamHandle is a handle writing a streamed response, see Stream.
+type StreamHandle func(sw *StreamWriter, req *http.Request, ps drouter.Params)
+
+// Stream configures the streamed responses of a route, e.g. of long-poll or
+// progress endpoints. Its Handle method adapts a StreamHandle to a handle:
+//
+//	progress := dhttprouter.Stream{FlushInterval: time.Second, ContentType: "text/plain"}
+//	router.GET("/jobs/:id/progress", progress.Handle(func(sw *dhttprouter.StreamWriter, req *http.Request, ps drouter.Params) {
+//		for p := range job(ps.ByName("id")).Progress(req.Context()) {
+//			if _, err := fmt.Fprintf(sw, "%d%%\n", p); err != nil {
+//				return // client disconnected
+//			}
+//		}
+//	}))
+//
+// The response is written through the writer passed to the handle, so the
+// access log and the other features of the router record its status and
+// size as for any other response.
+type Stream struct {
+	// Interval in which written data is flushed to the client. If zero, the
+	// data is flushed after every write.
+	FlushInterval time.Duration
+
+	// Content type of the response, e.g. "application/x-ndjson". If empty,
+	// it is left to the handle.
+	ContentType string
+}
+
+// Handle returns a handle which streams the response written by the given
+// handle. Responses are marked as not cacheable, unless the handle sets a
+// Cache-Control header itself before the first write.
+func (s Stream) Handle(handle StreamHandle) HttpHandle {
+	return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		sw := &StreamWriter{w: w, ctx: req.Context()}
+		sw.flusher, _ = w.(http.Flusher)
+		if s.ContentType != "" {
+			w.Header().Set("Content-Type", s.ContentType)
+		}
+		w.Header().Set("Cache-Control", "no-cache")
+
+		// Deferred calls run in reverse order: the periodic flushes stop
+		// before the final one
+		defer sw.flushPending()
+		if s.FlushInterval > 0 {
+			stop := sw.flushEvery(s.FlushInterval)
+			defer stop()
+		} else {
+			sw.immediate = true
+		}
+
+		handle(sw, req, ps)
+	}
+}
+
+// StreamWriter is the http.ResponseWriter of streamed responses. Its methods
+// may be called concurrently, e.g. by goroutines producing the data. Writes
+// fail with the error of the request context once it is done, e.g. because
+// the client disconnected.
+type StreamWriter struct {
+	mu        sync.Mutex
+	w         http.ResponseWriter
+	ctx       context.Context
+	flusher   http.Flusher
+	immediate bool
+
+	// Whether data was written since the last flush
+	pending bool
+}
+
+// Header returns the header of the response, which may be changed until the
+// first write.
+func (sw *StreamWriter) Header() http.Header {
+	return sw.w.Header()
+}
+
+// WriteHeader sends the response header with the given status code.
+func (sw *StreamWriter) WriteHeader(code int) {
+	sw.mu.Lock()
+	sw.w.WriteHeader(code)
+	sw.pending = true
+	sw.mu.Unlock()
+}
+
+// Write writes the data to the response, which is flushed immediately or
+// with the next periodic flush.
+func (sw *StreamWriter) Write(p []byte) (int, error) {
+	if err := sw.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	n, err := sw.w.Write(p)
+	sw.pending = true
+	if sw.immediate {
+		sw.flush()
+	}
+	return n, err
+}
+
+// Flush flushes the written data to the client. It returns the error of the
+// request context if it is done.
+func (sw *StreamWriter) Flush() error {
+	if err := sw.ctx.Err(); err != nil {
+		return err
+	}
+
+	sw.mu.Lock()
+	sw.flush()
+	sw.mu.Unlock()
+	return nil
+}
+
+// Context returns the context of the request, which is done once the client
+// disconnected.
+func (sw *StreamWriter) Context() context.Context {
+	return sw.ctx
+}
+
+// flush flushes the underlying writer. sw.mu must be held.
+func (sw *StreamWriter) flush() {
+	if sw.flusher != nil {
+		sw.flusher.Flush()
+	}
+	sw.pending = false
+}
+
+// flushPending flushes the data written since the last flush, if any. The
+// header is not sent if nothing was written, so a PanicHandler can still
+// respond.
+func (sw *StreamWriter) flushPending() {
+	sw.mu.Lock()
+	if sw.pending && sw.ctx.Err() == nil {
+		sw.flush()
+	}
+	sw.mu.Unlock()
+}
+
+// flushEvery flushes pending data in the given interval until the returned
+// function is called, which waits for the last flush to complete.
+func (sw *StreamWriter) flushEvery(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				sw.flushPending()
+			case <-done:
+				return
+			case <-sw.ctx.Done():
+				return
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
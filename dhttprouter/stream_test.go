@@ -0,0 +1,84 @@
+package dhttprouter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestStream(t *testing.T) {
+	logger := &testLogger{}
+	router := New()
+	router.Logger = logger
+
+	var flushedBefore, flushedAfter bool
+	periodic := Stream{FlushInterval: 5 * time.Millisecond, ContentType: "application/x-ndjson"}
+	router.GET("/periodic", periodic.Handle(func(sw *StreamWriter, _ *http.Request, _ drouter.Params) {
+		rec := sw.w.(*accessWriter).ResponseWriter.(*httptest.ResponseRecorder)
+		fmt.Fprintln(sw, `{"progress":50}`)
+
+		sw.mu.Lock()
+		flushedBefore = rec.Flushed
+		sw.mu.Unlock()
+
+		deadline := time.Now().Add(time.Second)
+		for !flushedAfter && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+			sw.mu.Lock()
+			flushedAfter = rec.Flushed
+			sw.mu.Unlock()
+		}
+	}))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/periodic", nil))
+	if flushedBefore || !flushedAfter {
+		t.Errorf("data was not flushed periodically: before %v, after %v", flushedBefore, flushedAfter)
+	}
+	if w.Header().Get("Content-Type") != "application/x-ndjson" || w.Header().Get("Cache-Control") != "no-cache" {
+		t.Errorf("wrong headers %v", w.Header())
+	}
+	if w.Body.String() != "{\"progress\":50}\n" {
+		t.Errorf("wrong body %q", w.Body)
+	}
+	if e := logger.entries[0]; e["status"] != http.StatusOK || e["size"] != int64(16) || e["route"] != "/periodic" {
+		t.Errorf("wrong access log entry %v", e)
+	}
+
+	// Without interval, every write is flushed
+	var flushed bool
+	immediate := Stream{}.Handle(func(sw *StreamWriter, _ *http.Request, _ drouter.Params) {
+		sw.WriteHeader(http.StatusAccepted)
+		sw.Write([]byte("a"))
+		flushed = sw.w.(*httptest.ResponseRecorder).Flushed
+	})
+	w = httptest.NewRecorder()
+	immediate(w, httptest.NewRequest(http.MethodGet, "/", nil), nil)
+	if !flushed || w.Code != http.StatusAccepted {
+		t.Errorf("write was not flushed: %v, %d", flushed, w.Code)
+	}
+}
+
+func TestStreamCanceled(t *testing.T) {
+	var writeErr, flushErr error
+	handle := Stream{}.Handle(func(sw *StreamWriter, req *http.Request, _ drouter.Params) {
+		_, writeErr = sw.Write([]byte("a"))
+		flushErr = sw.Flush()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	w := httptest.NewRecorder()
+	handle(w, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx), nil)
+	if writeErr != context.Canceled || flushErr != context.Canceled {
+		t.Errorf("wrong errors: %v, %v", writeErr, flushErr)
+	}
+	if w.Flushed || w.Body.Len() != 0 {
+		t.Error("canceled stream was written")
+	}
+}
@@ -0,0 +1,95 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// subtree holds the handlers for unmatched requests below a path prefix, see
+// SetNotFound.
+type subtree struct {
+	prefix           string
+	notFound         http.Handler
+	methodNotAllowed http.Handler
+}
+
+// SetNotFound sets the handler called instead of NotFound for unmatched
+// requests for the given path prefix and the paths below it, e.g. to answer
+// requests below "/api" with JSON errors while the rest of the site gets an
+// HTML page. If the prefixes of several handlers match, the longest one wins.
+// The prefix must begin with '/', must not end with '/' unless it is the root
+// and must not contain wildcards.
+//
+// Mounted HttpRouters answer the requests passed to them with their own
+// NotFound handler anyway.
+func (r *HttpRouter) SetNotFound(prefix string, handler http.Handler) {
+	r.mutableTable().subtree(prefix).notFound = handler
+	r.bumpVersion()
+}
+
+// SetMethodNotAllowed sets the handler called instead of MethodNotAllowed
+// for requests for the given path prefix and the paths below it which are
+// answered with 405, see SetNotFound.
+func (r *HttpRouter) SetMethodNotAllowed(prefix string, handler http.Handler) {
+	r.mutableTable().subtree(prefix).methodNotAllowed = handler
+	r.bumpVersion()
+}
+
+// subtree returns the subtree with the given prefix, adding it if necessary.
+func (t *routeTable) subtree(prefix string) *subtree {
+	if len(prefix) < 1 || prefix[0] != '/' {
+		panic("prefix must begin with '/' in prefix '" + prefix + "'")
+	}
+	if len(prefix) > 1 && prefix[len(prefix)-1] == '/' {
+		panic("prefix must not end with '/' in prefix '" + prefix + "'")
+	}
+	if strings.ContainsAny(prefix, ":*") {
+		panic("prefix must not contain wildcards in prefix '" + prefix + "'")
+	}
+
+	for _, s := range t.subtrees {
+		if s.prefix == prefix {
+			return s
+		}
+	}
+	s := &subtree{prefix: prefix}
+	t.subtrees = append(t.subtrees, s)
+
+	// Longest prefixes first
+	sort.SliceStable(t.subtrees, func(i, j int) bool {
+		return len(t.subtrees[i].prefix) > len(t.subtrees[j].prefix)
+	})
+	return s
+}
+
+// notFound returns the NotFound handler for the path, or nil to use the one
+// of the router.
+func (t *routeTable) notFound(path string) http.Handler {
+	for _, s := range t.subtrees {
+		if s.notFound != nil && hasPathPrefix(path, s.prefix) {
+			return s.notFound
+		}
+	}
+	return nil
+}
+
+// methodNotAllowed returns the MethodNotAllowed handler for the path, or nil
+// to use the one of the router.
+func (t *routeTable) methodNotAllowed(path string) http.Handler {
+	for _, s := range t.subtrees {
+		if s.methodNotAllowed != nil && hasPathPrefix(path, s.prefix) {
+			return s.methodNotAllowed
+		}
+	}
+	return nil
+}
+
+// hasPathPrefix reports whether the path is the prefix or a path below it.
+func hasPathPrefix(path, prefix string) bool {
+	if prefix == "/" {
+		return true
+	}
+	return strings.HasPrefix(path, prefix) &&
+		(len(path) == len(prefix) || path[len(prefix)] == '/')
+}
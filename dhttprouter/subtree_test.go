@@ -0,0 +1,55 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterSetNotFound(t *testing.T) {
+	handle := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
+	text := func(body string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+			w.Write([]byte(body))
+		})
+	}
+
+	router := New()
+	router.NotFound = text("html")
+	router.GET("/api/users/:id", handle)
+	router.GET("/apidocs", handle)
+	router.SetNotFound("/api", text("json"))
+	router.SetNotFound("/api/v2", text("json v2"))
+	router.SetMethodNotAllowed("/api", text("json 405"))
+
+	if recv := catchPanic(func() { router.SetNotFound("/api/", nil) }); recv == nil {
+		t.Error("no panic for prefix with trailing slash")
+	}
+	if recv := catchPanic(func() { router.SetNotFound("/:tenant", nil) }); recv == nil {
+		t.Error("no panic for prefix with wildcard")
+	}
+
+	tests := []struct {
+		method, path, body string
+	}{
+		{http.MethodGet, "/api", "json"},
+		{http.MethodGet, "/api/nope", "json"},
+		{http.MethodGet, "/api/v2/nope", "json v2"},
+		{http.MethodGet, "/api/v2x", "json"},
+		{http.MethodGet, "/apinope", "html"},
+		{http.MethodGet, "/nope", "html"},
+		{http.MethodPost, "/api/users/1", "json 405"},
+		{http.MethodPost, "/apidocs", "Method Not Allowed\n"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(tt.method, tt.path, nil)
+		router.ServeHTTP(w, req)
+		if w.Body.String() != tt.body {
+			t.Errorf("%s %s: wrong response: want %q, got %q", tt.method, tt.path, tt.body, w.Body.String())
+		}
+	}
+}
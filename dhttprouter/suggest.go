@@ -0,0 +1,74 @@
+package dhttprouter
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/thekhanj/drouter"
+)
+
+type suggestionsKey struct{}
+
+// SuggestionsKey is the request context key under which the route
+// suggestions for an unmatched request are stored.
+var SuggestionsKey = suggestionsKey{}
+
+// SuggestionsFromContext pulls the route suggestions for an unmatched request
+// from a request context, or returns nil if none are present.
+func SuggestionsFromContext(ctx context.Context) []string {
+	s, _ := ctx.Value(SuggestionsKey).([]string)
+	return s
+}
+
+// Suggest returns the registered route paths for the given method which are
+// closest to the given path, ordered by ascending distance.
+// If method is empty, the routes of all methods are considered.
+// See drouter.Router.Suggest for the distance metric.
+func (r *HttpRouter) Suggest(method, path string, maxDistance int) []string {
+	return r.loadTable().suggest(method, path, maxDistance)
+}
+
+func (t *routeTable) suggest(method, path string, maxDistance int) []string {
+	if method != "" {
+		if router := t.routers[method]; router != nil {
+			return router.Suggest(path, maxDistance)
+		}
+		return nil
+	}
+
+	// Merge the suggestions of all methods
+	distance := make(map[string]int)
+	for _, router := range t.routers {
+		for _, s := range router.Suggest(path, maxDistance) {
+			if _, ok := distance[s]; !ok {
+				distance[s] = drouter.PathDistance(path, s, maxDistance)
+			}
+		}
+	}
+	if len(distance) == 0 {
+		return nil
+	}
+
+	suggestions := make([]string, 0, len(distance))
+	for s := range distance {
+		suggestions = append(suggestions, s)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if distance[suggestions[i]] != distance[suggestions[j]] {
+			return distance[suggestions[i]] < distance[suggestions[j]]
+		}
+		return suggestions[i] < suggestions[j]
+	})
+	return suggestions
+}
+
+// notFoundWithSuggestions replies like http.NotFound, but lists the given
+// suggestions in the body.
+func notFoundWithSuggestions(w http.ResponseWriter, suggestions []string) {
+	http.Error(w,
+		"404 page not found\n\nDid you mean:\n  "+strings.Join(suggestions, "\n  "),
+		http.StatusNotFound,
+	)
+}
@@ -0,0 +1,68 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterSuggest(t *testing.T) {
+	handlerFunc := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
+
+	router := New()
+	router.GET("/users/:id", handlerFunc)
+	router.POST("/users", handlerFunc)
+	router.DELETE("/users/:id", handlerFunc)
+
+	if got, want := router.Suggest("", "/usrs/1", 1), []string{"/users/:id"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong suggestions: want %v, got %v", want, got)
+	}
+	if got, want := router.Suggest("", "/user", 2), []string{"/users", "/users/:id"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong suggestions: want %v, got %v", want, got)
+	}
+	if got, want := router.Suggest(http.MethodPost, "/user", 2), []string{"/users"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong suggestions: want %v, got %v", want, got)
+	}
+	if got := router.Suggest(http.MethodPut, "/user", 2); got != nil {
+		t.Errorf("unexpected suggestions for unknown method: %v", got)
+	}
+}
+
+func TestRouterNotFoundSuggestions(t *testing.T) {
+	handlerFunc := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
+
+	router := New()
+	router.GET("/users/:id", handlerFunc)
+
+	// disabled by default
+	r, _ := http.NewRequest(http.MethodGet, "/usr/1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if strings.Contains(w.Body.String(), "/users/:id") {
+		t.Errorf("unexpected suggestions in response: %q", w.Body.String())
+	}
+
+	// default NotFound response
+	router.SuggestDistance = 2
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusNotFound || !strings.Contains(w.Body.String(), "Did you mean:\n  /users/:id") {
+		t.Errorf("suggestions missing in response: Code=%d, Body=%q", w.Code, w.Body.String())
+	}
+
+	// custom NotFound handler
+	var got []string
+	router.NotFound = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got = SuggestionsFromContext(req.Context())
+		w.WriteHeader(http.StatusNotFound)
+	})
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if want := []string{"/users/:id"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong suggestions in context: want %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,203 @@
+package dhttprouter
+
+import (
+	"sync"
+
+	"github.com/thekhanj/drouter"
+)
+
+// routeTable holds the routes of a HttpRouter and everything attached to
+// them. A request is dispatched entirely with the table current at its
+// arrival, which Swap replaces atomically.
+type routeTable struct {
+	routers map[string]*drouter.Router[*route]
+
+	// Version of the router when the table was last changed, see Version
+	version uint64
+
+	// Cached value of the server-wide Allow header for the methods of
+	// routers, kept up to date by setRouter
+	globalAllowed string
+
+	paramsPool sync.Pool
+	maxParams  uint16
+
+	// Sub-routers attached under a path prefix, see Mount
+	mounts *drouter.Router[*mount]
+
+	// File systems served for unmatched requests, see FileFallthrough
+	files []*fileMount
+
+	// Ordered rewrite rules applied to the request path before the lookup
+	rewrites []rewriteRule
+
+	// CORS policies by route path, see SetCORS
+	corsPolicies map[string]*CORSPolicy
+
+	// Routes with a latency objective, see SetSLO
+	slos []*route
+
+	// Handlers for unmatched requests by path prefix, longest first, see
+	// SetNotFound
+	subtrees []*subtree
+
+	// Installed route modules, see Install
+	modules []ModuleInfo
+}
+
+// setRouter sets the tree of the given method, or removes it if router is
+// nil, and refreshes the cached server-wide Allow header. All changes of the
+// set of methods must go through it, so late registered custom methods are
+// allowed as well.
+func (t *routeTable) setRouter(method string, router *drouter.Router[*route]) {
+	if router == nil {
+		delete(t.routers, method)
+	} else {
+		if t.routers == nil {
+			t.routers = make(map[string]*drouter.Router[*route])
+		}
+		t.routers[method] = router
+	}
+	t.globalAllowed = t.allowed("*", "")
+}
+
+// emptyTable is served by routers without any route.
+var emptyTable = &routeTable{}
+
+// loadTable returns the current route table for dispatching a request.
+func (r *HttpRouter) loadTable() *routeTable {
+	if t, _ := r.table.Load().(*routeTable); t != nil {
+		return t
+	}
+	return emptyTable
+}
+
+// mutableTable returns the current route table for registering routes,
+// creating it if necessary, or its copy during Update.
+func (r *HttpRouter) mutableTable() *routeTable {
+	if r.staged != nil {
+		return r.staged
+	}
+	t, _ := r.table.Load().(*routeTable)
+	if t == nil {
+		t = &routeTable{}
+		r.table.Store(t)
+	}
+	return t
+}
+
+// Swap atomically replaces the route table of r by the one of next, which
+// can be built and validated off-line, e.g. when reloading the configuration.
+// The table includes the routes, mounts, rewrite rules and the per-route
+// settings like CORS policies and SLOs. The options of r, e.g. its NotFound
+// or PanicHandler, are kept for dispatching requests. Handles which next
+// created itself, e.g. by ServeFilesWith, SetCORS or Redirect, are bound to
+// next though and keep using its options, like NotFound, UseRawPath or
+// PreflightCacheSize, so these should be set alike on both routers.
+//
+// Requests being served keep using the previous table until they complete,
+// new requests use the new one. Afterwards r and next share the table, so
+// next must not be used anymore. Like the registration of routes, Swap itself
+// must not be called concurrently with other changes of r, but it may be
+// called while r serves requests.
+func (r *HttpRouter) Swap(next *HttpRouter) {
+	if next == nil {
+		panic("router must not be nil")
+	}
+	if next == r {
+		return
+	}
+
+	r.storeTable(next.mutableTable())
+	r.emit(TableSwapped, "", "", nil)
+}
+
+// Update changes the routes of r while it serves requests. The registration
+// functions called by fn, e.g. TryHandle or Remove, change a copy of the
+// route table, which replaces the current one atomically once fn returns
+// without an error, like with Swap. If fn returns an error, the copy is
+// discarded and r is left unchanged. Events are emitted while fn runs, before
+// the changes take effect.
+//
+// Only the routes are copied, fn must not change other parts of the table,
+// e.g. mounts, nor the per-route settings of existing routes, e.g. with
+// SetSLO. Calls of Update are serialized, but must not be made concurrently
+// with other changes of r.
+func (r *HttpRouter) Update(fn func() error) error {
+	r.updateMu.Lock()
+	defer r.updateMu.Unlock()
+
+	r.staged = r.loadTable().clone()
+	err := fn()
+	t := r.staged
+	r.staged = nil
+	if err != nil {
+		return err
+	}
+
+	r.storeTable(t)
+	return nil
+}
+
+// clone returns a copy of the table whose routes can be changed without
+// affecting t. The routes themselves and all other parts are shared.
+func (t *routeTable) clone() *routeTable {
+	c := &routeTable{
+		globalAllowed: t.globalAllowed,
+		maxParams:     t.maxParams,
+		mounts:        t.mounts,
+		files:         t.files[:len(t.files):len(t.files)],
+		rewrites:      t.rewrites[:len(t.rewrites):len(t.rewrites)],
+		corsPolicies:  t.corsPolicies,
+		slos:          append([]*route(nil), t.slos...),
+		subtrees:      t.subtrees[:len(t.subtrees):len(t.subtrees)],
+		modules:       t.modules[:len(t.modules):len(t.modules)],
+	}
+	for method, router := range t.routers {
+		cr := drouter.New[*route]()
+		router.Walk(func(path string, rt *route) bool {
+			cr.AddRoute(path, rt)
+			return true
+		})
+		if c.routers == nil {
+			c.routers = make(map[string]*drouter.Router[*route])
+		}
+		c.routers[method] = cr
+	}
+	c.lazyInitParamsPool()
+	return c
+}
+
+func (t *routeTable) getParams() *drouter.Params {
+	ps, _ := t.paramsPool.Get().(*drouter.Params)
+	*ps = (*ps)[0:0] // reset slice
+	return ps
+}
+
+func (t *routeTable) putParams(ps *drouter.Params) {
+	if ps != nil {
+		t.paramsPool.Put(ps)
+	}
+}
+
+func (t *routeTable) lazyInitParamsPool() {
+	if !(t.paramsPool.New == nil) {
+		return
+	}
+
+	t.paramsPool.New = func() interface{} {
+		// One more slot for the matched route path, see SaveMatchedRoutePath
+		ps := make(drouter.Params, 0, t.maxParams+1)
+		return &ps
+	}
+}
+
+// updateMaxParams sets the capacity of the pooled Params to the fixed
+// capacity, if greater than zero, or grows it to the given number of params.
+func (t *routeTable) updateMaxParams(params uint16, capacity int) {
+	if capacity > 0 {
+		t.maxParams = uint16(capacity)
+	} else if params > t.maxParams {
+		t.maxParams = params
+	}
+}
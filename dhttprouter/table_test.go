@@ -0,0 +1,217 @@
+package dhttprouter
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterSwap(t *testing.T) {
+	respond := func(body string) HttpHandle {
+		return func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+			w.Write([]byte(body))
+		}
+	}
+
+	router := New()
+	router.NotFound = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+	router.GET("/old", respond("old"))
+	router.GET("/users/:id", respond("v1"))
+
+	next := New()
+	next.GET("/users/:id", respond("v2"))
+	next.GET("/new/:a/:b/:c", respond("new"))
+	next.Rewrite("^/legacy$", "/users/1", 0)
+	next.SetSLO(http.MethodGet, "/users/:id", SLO{Target: 0.9})
+
+	// Requests are served while the table is swapped
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				r, _ := http.NewRequest(http.MethodGet, "/users/42", nil)
+				w := httptest.NewRecorder()
+				router.ServeHTTP(w, r)
+				if body := w.Body.String(); body != "v1" && body != "v2" {
+					t.Errorf("unexpected body during swap: %q", body)
+				}
+			}
+		}()
+	}
+	version := router.Version()
+	router.Swap(next)
+	wg.Wait()
+
+	if router.Version() == version {
+		t.Error("version not bumped")
+	}
+
+	tests := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/users/42", http.StatusOK, "v2"},
+		{"/new/1/2/3", http.StatusOK, "new"},
+		{"/legacy", http.StatusOK, "v2"},
+		{"/old", http.StatusTeapot, ""},
+	}
+	for _, tr := range tests {
+		r, _ := http.NewRequest(http.MethodGet, tr.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != tr.code || w.Body.String() != tr.body {
+			t.Errorf("%s after swap: Code=%d, Body=%q", tr.path, w.Code, w.Body.String())
+		}
+	}
+
+	if stats := router.SLOStats(); len(stats) != 1 || stats[0].Path != "/users/:id" {
+		t.Errorf("wrong SLO stats after swap: %+v", stats)
+	}
+
+	// Swapping a router without routes
+	router.Swap(New())
+	r, _ := http.NewRequest(http.MethodGet, "/users/42", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusTeapot {
+		t.Errorf("route served after swapping an empty table: Code=%d", w.Code)
+	}
+
+	if recv := catchPanic(func() { router.Swap(nil) }); recv == nil {
+		t.Error("swapping nil router did not panic")
+	}
+}
+
+func TestRouterSwapOptions(t *testing.T) {
+	notFound := func(code int) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(code)
+		})
+	}
+
+	router := New()
+	router.NotFound = notFound(http.StatusTeapot)
+	router.RedirectTrailingSlash = false
+
+	next := New()
+	next.NotFound = notFound(http.StatusGone)
+	next.GET("/users/", func(http.ResponseWriter, *http.Request, drouter.Params) {})
+	next.ServeFilesWith("/files/*filepath", Files{Root: http.Dir(t.TempDir()), RouterNotFound: true})
+
+	router.Swap(next)
+
+	tests := []struct {
+		path string
+		code int
+	}{
+		// Dispatched with the options of router
+		{"/missing", http.StatusTeapot},
+		{"/users", http.StatusTeapot},
+		// Served by a handle bound to next
+		{"/files/missing.txt", http.StatusGone},
+	}
+	for _, tr := range tests {
+		r, _ := http.NewRequest(http.MethodGet, tr.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != tr.code {
+			t.Errorf("%s after swap: Code=%d, want %d", tr.path, w.Code, tr.code)
+		}
+	}
+}
+
+func TestRouterUpdate(t *testing.T) {
+	respond := func(body string) HttpHandle {
+		return func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+			w.Write([]byte(body))
+		}
+	}
+	get := func(router *HttpRouter, path string) (int, string) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code, w.Body.String()
+	}
+
+	router := New()
+	router.GET("/users/:id", respond("user"))
+	router.GET("/posts", respond("posts"))
+	router.SetSLO(http.MethodGet, "/posts", SLO{Target: 0.9})
+
+	// Requests are served while the routes change
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			if code, body := get(router, "/users/1"); code != http.StatusOK || body != "user" {
+				t.Errorf("wrong response while updating: %d %q", code, body)
+				return
+			}
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		err := router.Update(func() error {
+			if !router.Remove(http.MethodGet, "/posts") {
+				t.Error("route to remove is missing")
+			}
+			return router.TryHandle(http.MethodGet, "/posts", respond("posts"))
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(stop)
+	wg.Wait()
+
+	// Failed updates leave the router unchanged
+	version := router.Version()
+	err := router.Update(func() error {
+		router.Remove(http.MethodGet, "/users/:id")
+		router.GET("/comments", respond("comments"))
+		return errors.New("rejected")
+	})
+	if err == nil || err.Error() != "rejected" {
+		t.Fatalf("wrong error: %v", err)
+	}
+	if code, body := get(router, "/users/1"); code != http.StatusOK || body != "user" {
+		t.Errorf("removed route is gone: %d %q", code, body)
+	}
+	if code, _ := get(router, "/comments"); code != http.StatusNotFound {
+		t.Errorf("added route is served: %d", code)
+	}
+
+	if router.Version() <= version {
+		t.Errorf("version was not bumped: %d", router.Version())
+	}
+	version = router.Version()
+	if err := router.Update(func() error {
+		router.GET("/comments", respond("comments"))
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if code, body := get(router, "/comments"); code != http.StatusOK || body != "comments" {
+		t.Errorf("wrong response: %d %q", code, body)
+	}
+	if router.Version() <= version {
+		t.Errorf("version was not bumped: %d", router.Version())
+	}
+	if stats := router.SLOStats(); len(stats) != 0 {
+		t.Errorf("SLO of the removed route is left: %+v", stats)
+	}
+}
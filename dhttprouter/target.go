@@ -0,0 +1,89 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// EmptyPath selects what a HttpRouter does with requests whose path is
+// empty, e.g. requests for the prefix of a Mount itself, which the mounted
+// router receives with the prefix stripped, or absolute-form targets like
+// "http://example.com".
+type EmptyPath int
+
+const (
+	// EmptyPathRedirect redirects to "/", unless the method is exempt from
+	// redirects, see NoRedirectMethods. It is the default.
+	EmptyPathRedirect EmptyPath = iota
+
+	// EmptyPathRoot serves the requests like requests for "/".
+	EmptyPathRoot
+
+	// EmptyPathNotFound answers the requests with the NotFound handler.
+	EmptyPathNotFound
+)
+
+var emptyPathNames = [...]string{
+	EmptyPathRedirect: "EmptyPathRedirect",
+	EmptyPathRoot:     "EmptyPathRoot",
+	EmptyPathNotFound: "EmptyPathNotFound",
+}
+
+func (p EmptyPath) String() string {
+	if p >= 0 && int(p) < len(emptyPathNames) {
+		return emptyPathNames[p]
+	}
+	return "EmptyPath(" + strconv.Itoa(int(p)) + ")"
+}
+
+// serveTarget handles requests with an empty path or the asterisk-form
+// target "*" and reports whether it answered the request. Otherwise it
+// returns the path to look up.
+func (r *HttpRouter) serveTarget(t *routeTable, w http.ResponseWriter, req *http.Request, path string) (string, bool) {
+	if path == "*" {
+		// Only OPTIONS requests may target the server as a whole
+		if req.Method != http.MethodOptions {
+			http.Error(w,
+				http.StatusText(http.StatusBadRequest),
+				http.StatusBadRequest,
+			)
+			r.noMatch(req.Method, path, Decision{Status: http.StatusBadRequest})
+			return path, true
+		}
+		if r.ServerOPTIONS == nil {
+			return path, false
+		}
+		if allow := t.allowed(path, http.MethodOptions); allow != "" {
+			w.Header().Set("Allow", allow)
+		}
+		r.ServerOPTIONS.ServeHTTP(w, req)
+		return path, true
+	}
+
+	notFound := Decision{Status: http.StatusNotFound, KnownMethod: t.routers[req.Method] != nil}
+
+	// Authority-form targets of CONNECT requests, e.g. "example.com:443",
+	// have no path
+	if req.Method == http.MethodConnect {
+		r.handleNotFound(t, w, req, notFound)
+		return path, true
+	}
+
+	switch r.EmptyPath {
+	case EmptyPathRoot:
+		return "/", false
+	case EmptyPathNotFound:
+		r.handleNotFound(t, w, req, notFound)
+		return path, true
+	}
+
+	if !r.redirects(req.Method) {
+		return path, false
+	}
+	code := http.StatusMovedPermanently
+	if req.Method != http.MethodGet {
+		code = http.StatusPermanentRedirect
+	}
+	r.redirect(w, req, "/", code)
+	return path, true
+}
@@ -0,0 +1,145 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+// targetRequest returns a request whose URL has the given path, which
+// httptest.NewRequest does not allow to be empty or "*".
+func targetRequest(method, path string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Method = method
+	req.URL = &url.URL{Path: path}
+	req.RequestURI = path
+	return req
+}
+
+func TestRouterEmptyPath(t *testing.T) {
+	root := func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		w.Write([]byte("root"))
+	}
+
+	tests := []struct {
+		mode     EmptyPath
+		method   string
+		code     int
+		body     string
+		location string
+	}{
+		{EmptyPathRedirect, http.MethodGet, http.StatusMovedPermanently, "", "/"},
+		{EmptyPathRedirect, http.MethodPost, http.StatusPermanentRedirect, "", "/"},
+		{EmptyPathRoot, http.MethodGet, http.StatusOK, "root", ""},
+		{EmptyPathRoot, http.MethodPost, http.StatusMethodNotAllowed, "", ""},
+		{EmptyPathNotFound, http.MethodGet, http.StatusNotFound, "", ""},
+		// CONNECT requests have an authority-form target
+		{EmptyPathRoot, http.MethodConnect, http.StatusTeapot, "", ""},
+	}
+	for _, tt := range tests {
+		var decisions []Decision
+		router := New()
+		router.EmptyPath = tt.mode
+		router.GET("/", root)
+		router.Handle(http.MethodConnect, "/", root)
+		router.ConnectNotFound = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})
+		router.OnNoMatch = func(_, _ string, d Decision) {
+			decisions = append(decisions, d)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, targetRequest(tt.method, ""))
+		if w.Code != tt.code || (tt.body != "" && w.Body.String() != tt.body) || w.Header().Get("Location") != tt.location {
+			t.Errorf("%v %s: wrong response: %d %q %q", tt.mode, tt.method, w.Code, w.Body, w.Header().Get("Location"))
+		}
+		if tt.code == http.StatusNotFound && (len(decisions) != 1 || decisions[0].Status != http.StatusNotFound || !decisions[0].KnownMethod) {
+			t.Errorf("%v %s: wrong decisions %+v", tt.mode, tt.method, decisions)
+		}
+	}
+
+	// Redirects keep the mount prefix
+	router := New()
+	admin := New()
+	admin.GET("/", root)
+	router.Mount("/admin", admin)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin", nil))
+	if w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != "/admin/" {
+		t.Errorf("wrong redirect: %d %q", w.Code, w.Header().Get("Location"))
+	}
+
+	// Methods exempt from redirects are looked up with the empty path
+	router = New()
+	router.NoRedirectMethods = []string{http.MethodPost}
+	router.POST("/", root)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, targetRequest(http.MethodPost, ""))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("wrong status: %d", w.Code)
+	}
+
+	if s := EmptyPathNotFound.String(); s != "EmptyPathNotFound" {
+		t.Errorf("wrong name %q", s)
+	}
+	if s := EmptyPath(7).String(); s != "EmptyPath(7)" {
+		t.Errorf("wrong name %q", s)
+	}
+}
+
+func TestRouterServerOPTIONS(t *testing.T) {
+	handle := func(http.ResponseWriter, *http.Request, drouter.Params) {}
+
+	var decisions []Decision
+	router := New()
+	router.GET("/users", handle)
+	router.POST("/users", handle)
+	router.OnNoMatch = func(_, _ string, d Decision) {
+		decisions = append(decisions, d)
+	}
+
+	// Automatic reply
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, targetRequest(http.MethodOptions, "*"))
+	if w.Code != http.StatusOK || w.Header().Get("Allow") != "GET, OPTIONS, POST" {
+		t.Errorf("wrong automatic reply: %d %q", w.Code, w.Header().Get("Allow"))
+	}
+
+	// The server-wide handler replaces the automatic reply, but not
+	// GlobalOPTIONS for paths
+	router.HandleOPTIONS = false
+	router.GlobalOPTIONS = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+	router.ServerOPTIONS = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Server", "options")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, targetRequest(http.MethodOptions, "*"))
+	if w.Code != http.StatusNoContent || w.Header().Get("X-Server") != "options" || w.Header().Get("Allow") != "GET, OPTIONS, POST" {
+		t.Errorf("wrong server reply: %d %v", w.Code, w.Header())
+	}
+	router.HandleOPTIONS = true
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodOptions, "/users", nil))
+	if w.Code != http.StatusAccepted {
+		t.Errorf("wrong reply for a path: %d", w.Code)
+	}
+
+	// Other methods must not target the server
+	for _, method := range []string{http.MethodGet, http.MethodPost, "PROPFIND"} {
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, targetRequest(method, "*"))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: wrong status: %d", method, w.Code)
+		}
+	}
+	if len(decisions) != 3 || decisions[0].Status != http.StatusBadRequest {
+		t.Errorf("wrong decisions %+v", decisions)
+	}
+}
@@ -0,0 +1,251 @@
+package dhttprouter
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+// Timeout limits the time a route may take to respond. It can be used as
+// per-route Middleware via its Wrap method.
+//
+// In contrast to http.TimeoutHandler, the response is not buffered, which
+// keeps streaming responses working. Instead two deadlines are distinguished:
+// If the handle did not write the response header within Header, the client
+// receives a 503 (Service Unavailable) response. If the complete response
+// took longer than Response, the connection is aborted, as the status has
+// already been sent. Responses which turn out to be streams, i.e. which were
+// flushed, hijacked or have the content type text/event-stream, are exempt
+// from the Response deadline. Upgrade requests (e.g. WebSocket) are exempt
+// from both deadlines.
+//
+// In all cases the request context is canceled on timeout, so handles should
+// stop their work. Writes after a timeout fail with http.ErrHandlerTimeout.
+// The earlier deadline is available to handles via DeadlineFromContext and
+// can be passed on to upstreams with DeadlineTransport.
+type Timeout struct {
+	// Deadline for writing the response header. Disabled if zero.
+	Header time.Duration
+
+	// Deadline for the complete response. Disabled if zero.
+	Response time.Duration
+
+	// Configurable http.Handler which is called when the Header deadline
+	// passed. If it is not set, http.Error with http.StatusServiceUnavailable
+	// is used.
+	TimedOut http.Handler
+}
+
+// Wrap returns a handle which enforces the deadlines on the given handle.
+func (t Timeout) Wrap(handle HttpHandle) HttpHandle {
+	return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		if isUpgrade(req) || (t.Header <= 0 && t.Response <= 0) {
+			handle(w, req, ps)
+			return
+		}
+
+		ctx, cancel := context.WithCancel(req.Context())
+		defer cancel()
+		if d := t.deadline(); d > 0 {
+			ctx = withDeadline(ctx, time.Now().Add(d))
+		}
+		req = req.WithContext(ctx)
+
+		// The handle may outlive this function, while ps is returned to the
+		// router's pool
+		ps = ps.Clone()
+
+		tw := &timeoutWriter{w: w, h: make(http.Header)}
+		done := make(chan struct{})
+		panicChan := make(chan interface{}, 1)
+		go func() {
+			defer func() {
+				if p := recover(); p != nil {
+					panicChan <- p
+					return
+				}
+				close(done)
+			}()
+			handle(tw, req, ps)
+		}()
+
+		var headerC, responseC <-chan time.Time
+		if t.Header > 0 {
+			timer := time.NewTimer(t.Header)
+			defer timer.Stop()
+			headerC = timer.C
+		}
+		if t.Response > 0 {
+			timer := time.NewTimer(t.Response)
+			defer timer.Stop()
+			responseC = timer.C
+		}
+
+		for {
+			select {
+			case p := <-panicChan:
+				panic(p)
+
+			case <-done:
+				return
+
+			case <-headerC:
+				headerC = nil
+				tw.mu.Lock()
+				if tw.wroteHeader || tw.hijacked {
+					tw.mu.Unlock()
+					continue
+				}
+				tw.timedOut = true
+				tw.mu.Unlock()
+				cancel()
+				t.timedOut(w, req)
+				return
+
+			case <-responseC:
+				responseC = nil
+				tw.mu.Lock()
+				if tw.streaming || tw.hijacked {
+					tw.mu.Unlock()
+					continue
+				}
+				wroteHeader := tw.wroteHeader
+				tw.timedOut = true
+				tw.mu.Unlock()
+				cancel()
+				if !wroteHeader {
+					t.timedOut(w, req)
+					return
+				}
+				// The status is already sent, the client must not mistake the
+				// truncated response for a complete one
+				panic(http.ErrAbortHandler)
+			}
+		}
+	}
+}
+
+// deadline returns the time after which the Timeout gives up on a request
+// which did not yet write its response header, or zero if there is none.
+func (t Timeout) deadline() time.Duration {
+	if t.Header > 0 && (t.Response <= 0 || t.Header < t.Response) {
+		return t.Header
+	}
+	return t.Response
+}
+
+func (t Timeout) timedOut(w http.ResponseWriter, req *http.Request) {
+	if t.TimedOut != nil {
+		t.TimedOut.ServeHTTP(w, req)
+	} else {
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+	}
+}
+
+// isUpgrade reports whether the client requests a protocol upgrade.
+func isUpgrade(req *http.Request) bool {
+	for _, v := range req.Header["Connection"] {
+		for _, token := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// timeoutWriter passes writes on to the underlying writer until the request
+// timed out. The handle gets its own header map, so it never touches the
+// headers of a timeout response.
+type timeoutWriter struct {
+	mu sync.Mutex
+	w  http.ResponseWriter
+	h  http.Header
+
+	wroteHeader bool
+	streaming   bool
+	hijacked    bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.h
+}
+
+func (tw *timeoutWriter) writeHeaderLocked(code int) {
+	dst := tw.w.Header()
+	for k, v := range tw.h {
+		dst[k] = v
+	}
+	if strings.HasPrefix(dst.Get("Content-Type"), "text/event-stream") {
+		tw.streaming = true
+	}
+	tw.wroteHeader = true
+	tw.w.WriteHeader(code)
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.wroteHeader || tw.hijacked {
+		return
+	}
+	tw.writeHeaderLocked(code)
+}
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.hijacked {
+		return 0, http.ErrHijacked
+	}
+	if !tw.wroteHeader {
+		tw.writeHeaderLocked(http.StatusOK)
+	}
+	return tw.w.Write(p)
+}
+
+// Flush implements http.Flusher. Flushed responses count as streams.
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.hijacked {
+		return
+	}
+	if !tw.wroteHeader {
+		tw.writeHeaderLocked(http.StatusOK)
+	}
+	tw.streaming = true
+	if f, ok := tw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker. Hijacked connections are exempt from all
+// deadlines.
+func (tw *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return nil, nil, http.ErrHandlerTimeout
+	}
+	hj, ok := tw.w.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("dhttprouter: response writer does not support hijacking")
+	}
+	conn, rw, err := hj.Hijack()
+	if err == nil {
+		tw.hijacked = true
+	}
+	return conn, rw, err
+}
@@ -0,0 +1,116 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestTimeout(t *testing.T) {
+	canceled := make(chan error, 1)
+	block := func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		<-req.Context().Done()
+		_, err := w.Write([]byte("late"))
+		canceled <- err
+	}
+	fast := func(w http.ResponseWriter, _ *http.Request, ps drouter.Params) {
+		w.Header().Set("X-Fast", ps.ByName("name"))
+		w.Write([]byte("fast"))
+	}
+	stream := func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		w.Write([]byte("a"))
+		w.(http.Flusher).Flush()
+		time.Sleep(30 * time.Millisecond)
+		w.Write([]byte("b"))
+	}
+	events := func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		time.Sleep(30 * time.Millisecond)
+		w.Write([]byte("data: done\n\n"))
+	}
+
+	timeout := Timeout{Header: 10 * time.Millisecond, Response: 10 * time.Millisecond}
+
+	router := New()
+	router.GET("/block", block, timeout.Wrap)
+	router.GET("/fast/:name", fast, timeout.Wrap)
+	router.GET("/stream", stream, timeout.Wrap)
+	router.GET("/events", events, timeout.Wrap)
+
+	// Header deadline
+	r, _ := http.NewRequest(http.MethodGet, "/block", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("timed out request failed: Code=%d", w.Code)
+	}
+	if err := <-canceled; err != http.ErrHandlerTimeout {
+		t.Errorf("unexpected error for write after timeout: %v", err)
+	}
+
+	// Within the deadlines
+	r, _ = http.NewRequest(http.MethodGet, "/fast/gopher", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || w.Body.String() != "fast" || w.Header().Get("X-Fast") != "gopher" {
+		t.Errorf("fast request failed: Code=%d, Body=%q, Header=%v", w.Code, w.Body.String(), w.Header())
+	}
+
+	// Streams are exempt from the response deadline
+	for _, path := range []string{"/stream", "/events"} {
+		r, _ = http.NewRequest(http.MethodGet, path, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if w.Code != http.StatusOK || w.Body.String() != map[string]string{"/stream": "ab", "/events": "data: done\n\n"}[path] {
+			t.Errorf("stream %s failed: Code=%d, Body=%q", path, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestTimeoutResponse(t *testing.T) {
+	slow := func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		w.WriteHeader(http.StatusOK)
+		<-req.Context().Done()
+	}
+
+	router := New()
+	router.PanicHandler = func(w http.ResponseWriter, _ *http.Request, _ interface{}) {
+		t.Error("abort was passed to the PanicHandler")
+	}
+	router.GET("/slow", slow, Timeout{Response: 10 * time.Millisecond}.Wrap)
+
+	// The status was already sent, so the response is aborted
+	r, _ := http.NewRequest(http.MethodGet, "/slow", nil)
+	w := httptest.NewRecorder()
+	recv := catchPanic(func() {
+		router.ServeHTTP(w, r)
+	})
+	if recv != http.ErrAbortHandler {
+		t.Errorf("response was not aborted: %v", recv)
+	}
+}
+
+func TestTimeoutUpgrade(t *testing.T) {
+	handled := false
+	upgrade := func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusSwitchingProtocols)
+		handled = true
+	}
+
+	router := New()
+	router.GET("/ws", upgrade, Timeout{Header: 5 * time.Millisecond}.Wrap)
+
+	r, _ := http.NewRequest(http.MethodGet, "/ws", nil)
+	r.Header.Set("Connection", "keep-alive, Upgrade")
+	r.Header.Set("Upgrade", "websocket")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if !handled || w.Code != http.StatusSwitchingProtocols {
+		t.Errorf("upgrade request failed: Code=%d, handled=%t", w.Code, handled)
+	}
+}
@@ -0,0 +1,103 @@
+package dhttprouter
+
+import (
+	"context"
+	"net/http"
+)
+
+// Tracer starts spans, e.g. by adapting an OpenTelemetry trace.Tracer:
+//
+//	type otelTracer struct{ trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string, attrs map[string]string) (context.Context, dhttprouter.Span) {
+//		kvs := make([]attribute.KeyValue, 0, len(attrs))
+//		for k, v := range attrs {
+//			kvs = append(kvs, attribute.String(k, v))
+//		}
+//		ctx, span := t.Tracer.Start(ctx, name,
+//			trace.WithSpanKind(trace.SpanKindServer), trace.WithAttributes(kvs...))
+//		return ctx, otelSpan{span}
+//	}
+//
+// where otelSpan sets the "http.response.status_code" attribute in SetStatus,
+// marks 5xx responses as errors and ends the span in End.
+type Tracer interface {
+	// Start starts a span with the given name and attributes as child of the
+	// span in ctx, if any, and returns a context carrying the new span.
+	Start(ctx context.Context, name string, attrs map[string]string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// SetStatus records the status code of the response. It is called once
+	// before End.
+	SetStatus(code int)
+
+	// End ends the span.
+	End()
+}
+
+// Tracing starts a span per request once the route is resolved, so the span
+// is named after the matched route pattern instead of the request path, which
+// keeps the number of span names bounded. Middleware wrapping the router
+// cannot do this, as it runs before the lookup.
+//
+//	router.Tracing = &dhttprouter.Tracing{
+//		Tracer: otelTracer{otel.Tracer("api")},
+//		Extract: func(ctx context.Context, h http.Header) context.Context {
+//			return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(h))
+//		},
+//	}
+//
+// The span carries the attributes "http.route", "http.request.method" and
+// "url.path" and is stored in the request context, so spans of outgoing
+// requests of the handle become its children. The values of params redacted
+// by the RedactionPolicy are masked in "url.path". Requests which are not
+// routed to a handle, i.e. 404 and 405 responses, are not traced.
+type Tracing struct {
+	Tracer Tracer
+
+	// Optional function extracting the trace context propagated by the
+	// client from the request headers, so the span continues its trace.
+	Extract func(ctx context.Context, header http.Header) context.Context
+
+	// Optional function naming the spans. If it is not set, spans are named
+	// by the method and the route pattern, e.g. "GET /users/:id".
+	SpanName func(method, route string) string
+}
+
+// start starts the span of the request matched to the route, with path as
+// "url.path" attribute unless it is empty. The returned function, which must
+// be deferred, ends the span with the status of the response written through
+// the returned writer.
+func (t *Tracing) start(rt *route, w http.ResponseWriter, req *http.Request, path string) (http.ResponseWriter, *http.Request, func()) {
+	ctx := req.Context()
+	if t.Extract != nil {
+		ctx = t.Extract(ctx, req.Header)
+	}
+
+	if path == "" {
+		path = req.URL.Path
+	}
+	name := rt.method + " " + rt.path
+	if t.SpanName != nil {
+		name = t.SpanName(rt.method, rt.path)
+	}
+	ctx, span := t.Tracer.Start(ctx, name, map[string]string{
+		"http.route":          rt.path,
+		"http.request.method": req.Method,
+		"url.path":            path,
+	})
+
+	sw := &statusWriter{ResponseWriter: w}
+	return sw, req.WithContext(ctx), func() {
+		// Panics are recorded as 500 and passed on to the PanicHandler
+		if p := recover(); p != nil {
+			span.SetStatus(http.StatusInternalServerError)
+			span.End()
+			panic(p)
+		}
+		span.SetStatus(sw.Status())
+		span.End()
+	}
+}
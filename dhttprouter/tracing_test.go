@@ -0,0 +1,89 @@
+package dhttprouter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+type testSpan struct {
+	name   string
+	parent string
+	attrs  map[string]string
+	status int
+	ended  bool
+}
+
+func (s *testSpan) SetStatus(code int) { s.status = code }
+func (s *testSpan) End()               { s.ended = true }
+
+type testSpanKey struct{}
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, attrs map[string]string) (context.Context, Span) {
+	parent, _ := ctx.Value(testSpanKey{}).(string)
+	span := &testSpan{name: name, parent: parent, attrs: attrs}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, testSpanKey{}, name), span
+}
+
+func TestRouterTracing(t *testing.T) {
+	tracer := &testTracer{}
+	var inner string
+
+	router := New()
+	router.Tracing = &Tracing{
+		Tracer: tracer,
+		Extract: func(ctx context.Context, h http.Header) context.Context {
+			if id := h.Get("X-Trace"); id != "" {
+				return context.WithValue(ctx, testSpanKey{}, id)
+			}
+			return ctx
+		},
+	}
+	router.PanicHandler = func(w http.ResponseWriter, _ *http.Request, _ interface{}) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	router.GET("/users/:id", func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		inner, _ = req.Context().Value(testSpanKey{}).(string)
+		w.WriteHeader(http.StatusCreated)
+	})
+	router.GET("/panic", func(http.ResponseWriter, *http.Request, drouter.Params) {
+		panic("boom")
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set("X-Trace", "client")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("wrong number of spans: %d", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	if span.name != "GET /users/:id" || span.parent != "client" || !span.ended || span.status != http.StatusCreated {
+		t.Errorf("wrong span %+v", span)
+	}
+	if span.attrs["http.route"] != "/users/:id" || span.attrs["url.path"] != "/users/42" || span.attrs["http.request.method"] != "GET" {
+		t.Errorf("wrong attributes %v", span.attrs)
+	}
+	if inner != "GET /users/:id" {
+		t.Errorf("span is not in the request context: %q", inner)
+	}
+	if span := tracer.spans[1]; !span.ended || span.status != http.StatusInternalServerError {
+		t.Errorf("wrong span for panic %+v", span)
+	}
+
+	router.Tracing.SpanName = func(method, route string) string { return route }
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/7", nil))
+	if name := tracer.spans[2].name; name != "/users/:id" {
+		t.Errorf("wrong custom span name %q", name)
+	}
+}
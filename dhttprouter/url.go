@@ -0,0 +1,70 @@
+package dhttprouter
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/thekhanj/drouter"
+)
+
+// BuildHost builds a hostname from a host pattern by filling in the given
+// parameter values. Labels of the pattern starting with ':' are parameters,
+// e.g. ":tenant.api.example.com" with tenant=acme yields
+// "acme.api.example.com". A port suffix like ":8443" is kept as it is.
+// It returns an error if a parameter is missing, a value is not a single
+// label or the pattern contains a '*' wildcard label, which can't be built.
+func BuildHost(pattern string, ps drouter.Params) (string, error) {
+	host, port := splitHostPort(pattern)
+
+	labels := strings.Split(host, ".")
+	for i, label := range labels {
+		switch {
+		case label == "*":
+			return "", errors.New("wildcard label in host '" + pattern + "' cannot be built")
+		case len(label) > 1 && label[0] == ':':
+			value := ps.ByName(label[1:])
+			if value == "" {
+				return "", errors.New("missing value for parameter '" + label[1:] + "' in host '" + pattern + "'")
+			}
+			if strings.ContainsAny(value, ".:/") {
+				return "", errors.New("value for parameter '" + label[1:] + "' is not a single label in host '" + pattern + "'")
+			}
+			labels[i] = value
+		}
+	}
+	return strings.Join(labels, ".") + port, nil
+}
+
+// splitHostPort splits a numeric port suffix, including the ':', off a host.
+func splitHostPort(host string) (string, string) {
+	i := strings.LastIndexByte(host, ':')
+	if i < 0 || i == len(host)-1 {
+		return host, ""
+	}
+	for _, c := range []byte(host[i+1:]) {
+		if c < '0' || c > '9' {
+			return host, ""
+		}
+	}
+	return host[:i], host[i:]
+}
+
+// BuildURL builds an absolute URL from a scheme, a host pattern and a route
+// pattern, filling in the parameters of both from ps. It is meant for links
+// leaving the current request context, e.g. in emails and webhooks, which
+// often point to another subdomain.
+// See BuildHost and drouter.BuildPath for the pattern rules.
+func BuildURL(scheme, host, path string, ps drouter.Params) (string, error) {
+	h, err := BuildHost(host, ps)
+	if err != nil {
+		return "", err
+	}
+	p, err := drouter.BuildPath(path, ps)
+	if err != nil {
+		return "", err
+	}
+
+	u := url.URL{Scheme: scheme, Host: h, Path: p}
+	return u.String(), nil
+}
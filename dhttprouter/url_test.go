@@ -0,0 +1,36 @@
+package dhttprouter
+
+import (
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestBuildURL(t *testing.T) {
+	ps := drouter.Params{
+		{Key: "tenant", Value: "acme"},
+		{Key: "id", Value: "42"},
+		{Key: "file", Value: "/a b.txt"},
+		{Key: "dotted", Value: "a.b"},
+	}
+
+	tests := []struct {
+		host, path string
+		want       string
+		err        bool
+	}{
+		{"example.com", "/", "https://example.com/", false},
+		{":tenant.api.example.com", "/users/:id", "https://acme.api.example.com/users/42", false},
+		{":tenant.example.com:8443", "/files/*file", "https://acme.example.com:8443/files/a%20b.txt", false},
+		{"*.example.com", "/", "", true},
+		{":missing.example.com", "/", "", true},
+		{":dotted.example.com", "/", "", true},
+		{"example.com", "/users/:missing", "", true},
+	}
+	for _, test := range tests {
+		got, err := BuildURL("https", test.host, test.path, ps)
+		if (err != nil) != test.err || got != test.want {
+			t.Errorf("wrong URL for %s%s: want %q (error %t), got %q (%v)", test.host, test.path, test.want, test.err, got, err)
+		}
+	}
+}
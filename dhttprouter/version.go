@@ -0,0 +1,24 @@
+package dhttprouter
+
+import "sync/atomic"
+
+// Version returns the version of the route table. It starts at zero and is
+// incremented on every change of the registered routes, so operators can
+// confirm which route configuration served a given request.
+func (r *HttpRouter) Version() uint64 {
+	return atomic.LoadUint64(&r.version)
+}
+
+// bumpVersion increments the version after a change of the route table
+// being registered to.
+func (r *HttpRouter) bumpVersion() {
+	r.mutableTable().version = atomic.AddUint64(&r.version, 1)
+}
+
+// storeTable makes t the current route table with a new version, which is
+// set before t is served, so the version served with a request always
+// belongs to the table which served it.
+func (r *HttpRouter) storeTable(t *routeTable) {
+	t.version = atomic.AddUint64(&r.version, 1)
+	r.table.Store(t)
+}
@@ -0,0 +1,88 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterVersion(t *testing.T) {
+	handlerFunc := func(_ http.ResponseWriter, _ *http.Request, _ drouter.Params) {}
+
+	router := New()
+	if v := router.Version(); v != 0 {
+		t.Errorf("wrong initial version: want 0, got %d", v)
+	}
+
+	router.GET("/a", handlerFunc)
+	router.POST("/a", handlerFunc)
+	if v := router.Version(); v != 2 {
+		t.Errorf("wrong version: want 2, got %d", v)
+	}
+
+	// opt-in header
+	r, _ := http.NewRequest(http.MethodGet, "/a", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	if h := w.Header().Get("X-Route-Version"); h != "" {
+		t.Errorf("unexpected version header %q", h)
+	}
+
+	router.VersionHeader = "X-Route-Version"
+	for _, path := range []string{"/a", "/nope"} {
+		r, _ := http.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		if h := w.Header().Get("X-Route-Version"); h != "2" {
+			t.Errorf("wrong version header for %s: want 2, got %q", path, h)
+		}
+	}
+}
+
+func TestRouterVersionHeaderSwap(t *testing.T) {
+	serve := func(body string) HttpHandle {
+		return func(w http.ResponseWriter, _ *http.Request, _ drouter.Params) {
+			w.Write([]byte(body))
+		}
+	}
+
+	router := New()
+	router.VersionHeader = "X-Route-Version"
+	router.GET("/a", serve("old"))
+
+	// The table is swapped after the request arrived, before its route is
+	// looked up
+	router.CanonicalQuery = &CanonicalQuery{Rewrite: func(url.Values) {
+		next := New()
+		next.GET("/a", serve("new"))
+		router.Swap(next)
+	}}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/a?x=1", nil))
+	if w.Body.String() != "old" || w.Header().Get("X-Route-Version") != "1" {
+		t.Errorf("wrong response: %q served with version %q", w.Body, w.Header().Get("X-Route-Version"))
+	}
+
+	router.CanonicalQuery = nil
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/a", nil))
+	if w.Body.String() != "new" || w.Header().Get("X-Route-Version") != "2" {
+		t.Errorf("wrong response: %q served with version %q", w.Body, w.Header().Get("X-Route-Version"))
+	}
+
+	// Updates are versioned as well
+	router.Update(func() error {
+		router.Remove(http.MethodGet, "/a")
+		router.GET("/a", serve("updated"))
+		return nil
+	})
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/a", nil))
+	if v := router.Version(); w.Body.String() != "updated" || w.Header().Get("X-Route-Version") != strconv.FormatUint(v, 10) {
+		t.Errorf("wrong response: %q served with version %q, want %d", w.Body, w.Header().Get("X-Route-Version"), v)
+	}
+}
@@ -0,0 +1,65 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/thekhanj/drouter"
+)
+
+// WebDAVMethods are the methods registered by WebDAV: those of HTTP used by
+// WebDAV clients and those defined by RFC 4918.
+var WebDAVMethods = []string{
+	http.MethodOptions,
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPut,
+	http.MethodDelete,
+	"PROPFIND",
+	"PROPPATCH",
+	"MKCOL",
+	"COPY",
+	"MOVE",
+	"LOCK",
+	"UNLOCK",
+}
+
+// WebDAV registers the handler for all WebDAVMethods on the given path
+// prefix and every path below it, e.g. a *webdav.Handler of
+// golang.org/x/net/webdav:
+//
+//	router.WebDAV("/dav", &webdav.Handler{
+//		Prefix:     "/dav",
+//		FileSystem: webdav.Dir("data"),
+//		LockSystem: webdav.NewMemLS(),
+//	})
+//
+// The handler sees the full request path, so it must strip the prefix
+// itself, like webdav.Handler does with its Prefix. The OPTIONS requests
+// below the prefix are passed to the handler as well, which advertises the
+// WebDAV capabilities. The middleware is applied to all routes.
+func (r *HttpRouter) WebDAV(prefix string, handler http.Handler, middleware ...Middleware) {
+	if len(prefix) < 1 || prefix[0] != '/' {
+		panic("path must begin with '/' in path '" + prefix + "'")
+	}
+	if handler == nil {
+		panic("handler must not be nil")
+	}
+
+	handle := func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		handler.ServeHTTP(w, req)
+	}
+
+	prefix = strings.TrimSuffix(prefix, "/")
+	paths := []string{prefix + "/*filepath"}
+	if prefix != "" {
+		paths = append(paths, prefix)
+	}
+	for _, path := range paths {
+		for _, method := range WebDAVMethods {
+			if err := r.tryHandle(method, path, handle, handler, middleware); err != nil {
+				panic(err.Error())
+			}
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestRouterWebDAV(t *testing.T) {
+	var served string
+	dav := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		served = req.Method + " " + req.URL.Path
+		w.WriteHeader(http.StatusMultiStatus)
+	})
+
+	var wrapped int
+	router := New()
+	router.WebDAV("/dav/", dav, func(handle HttpHandle) HttpHandle {
+		wrapped++
+		return handle
+	})
+	router.GET("/other", func(http.ResponseWriter, *http.Request, drouter.Params) {})
+
+	tests := []struct {
+		method, path string
+		served       bool
+	}{
+		{"PROPFIND", "/dav", true},
+		{"PROPFIND", "/dav/", true},
+		{"MKCOL", "/dav/a/b", true},
+		{"LOCK", "/dav/a/b.txt", true},
+		{http.MethodOptions, "/dav/a", true},
+		{"PROPFIND", "/other", false},
+	}
+	for _, tt := range tests {
+		served = ""
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if got := served != ""; got != tt.served {
+			t.Errorf("%s %s: want served %v, got %q", tt.method, tt.path, tt.served, served)
+		}
+		if tt.served && (served != tt.method+" "+tt.path || w.Code != http.StatusMultiStatus) {
+			t.Errorf("%s %s: wrong request %q or code %d", tt.method, tt.path, served, w.Code)
+		}
+	}
+
+	if wrapped != 2*len(WebDAVMethods) {
+		t.Errorf("middleware was applied to %d routes", wrapped)
+	}
+
+	root := New()
+	root.WebDAV("/", dav)
+	served = ""
+	root.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("PROPFIND", "/", nil))
+	if served != "PROPFIND /" {
+		t.Errorf("root was not served: %q", served)
+	}
+
+	if recv := catchPanic(func() { router.WebDAV("/files", nil) }); recv == nil {
+		t.Error("no panic for nil handler")
+	}
+}
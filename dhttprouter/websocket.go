@@ -0,0 +1,234 @@
+package dhttprouter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/thekhanj/drouter"
+)
+
+// WebSocketPolicy checks the origin of WebSocket handshakes and negotiates
+// their subprotocol before the handle of the route runs, so the upgrade
+// policy is declared next to the route. It can be used as per-route
+// Middleware via its Wrap method:
+//
+//	chat := dhttprouter.WebSocketPolicy{
+//		AllowedOrigins: []string{"https://example.com"},
+//		Subprotocols:   []string{"chat.v2", "chat.v1"},
+//	}
+//	router.GET("/chat", func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+//		upgrader := websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }}
+//		proto := dhttprouter.WebSocketSubprotocol(req.Context())
+//		conn, err := upgrader.Upgrade(w, req, http.Header{"Sec-WebSocket-Protocol": {proto}})
+//		...
+//	}, chat.Wrap)
+//
+// Requests which are no WebSocket handshakes are passed to the handle
+// unchanged.
+type WebSocketPolicy struct {
+	// Origins allowed to open connections, e.g. "https://example.com".
+	// "*" allows all origins, a single '*' within an origin matches any
+	// subdomain, e.g. "https://*.example.com". If empty, only the origin of
+	// the request's host is allowed. Handshakes without Origin header, which
+	// are not sent by browsers, are always allowed.
+	AllowedOrigins []string
+
+	// Subprotocols supported by the route, in order of preference. The
+	// first one offered by the client is selected, see WebSocketSubprotocol.
+	Subprotocols []string
+
+	// If enabled, handshakes offering none of the Subprotocols are rejected.
+	// Otherwise the connection is opened without subprotocol.
+	RequireSubprotocol bool
+
+	// Optional function which is called for rejected handshakes with the
+	// status code, 403 for forbidden origins and 400 for missing
+	// subprotocols. If it is not set, http.Error is used.
+	Rejected func(w http.ResponseWriter, req *http.Request, code int)
+}
+
+type webSocketSubprotocolKey struct{}
+
+// WebSocketSubprotocol returns the subprotocol a WebSocketPolicy selected
+// for the handshake, or an empty string if none was selected.
+func WebSocketSubprotocol(ctx context.Context) string {
+	proto, _ := ctx.Value(webSocketSubprotocolKey{}).(string)
+	return proto
+}
+
+// Wrap returns a handle which enforces the policy on the given handle.
+func (p WebSocketPolicy) Wrap(handle HttpHandle) HttpHandle {
+	return func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		if !isWebSocket(req) {
+			handle(w, req, ps)
+			return
+		}
+
+		if !p.allowOrigin(req) {
+			p.reject(w, req, http.StatusForbidden)
+			return
+		}
+
+		if proto := p.subprotocol(req); proto != "" {
+			req = req.WithContext(context.WithValue(req.Context(), webSocketSubprotocolKey{}, proto))
+		} else if p.RequireSubprotocol {
+			p.reject(w, req, http.StatusBadRequest)
+			return
+		}
+		handle(w, req, ps)
+	}
+}
+
+func (p WebSocketPolicy) reject(w http.ResponseWriter, req *http.Request, code int) {
+	if p.Rejected != nil {
+		p.Rejected(w, req, code)
+	} else {
+		http.Error(w, http.StatusText(code), code)
+	}
+}
+
+// allowOrigin reports whether the origin of the handshake is allowed.
+func (p WebSocketPolicy) allowOrigin(req *http.Request) bool {
+	origin := req.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	if len(p.AllowedOrigins) == 0 {
+		u, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(u.Host, req.Host)
+	}
+	for _, o := range p.AllowedOrigins {
+		if o == "*" || matchOrigin(o, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// subprotocol returns the most preferred of the Subprotocols offered by the
+// client, or an empty string if there is none.
+func (p WebSocketPolicy) subprotocol(req *http.Request) string {
+	for _, proto := range p.Subprotocols {
+		for _, v := range req.Header["Sec-Websocket-Protocol"] {
+			for _, offered := range strings.Split(v, ",") {
+				if strings.TrimSpace(offered) == proto {
+					return proto
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// WebSocketUpgrader completes WebSocket handshakes, so any WebSocket library
+// can be plugged into the router, see HttpRouter.WebSocket. An adapter of
+// gorilla/websocket looks like:
+//
+//	type gorillaUpgrader struct{ websocket.Upgrader }
+//
+//	func (u gorillaUpgrader) Upgrade(w http.ResponseWriter, req *http.Request, subprotocol string) (io.Closer, error) {
+//		var h http.Header
+//		if subprotocol != "" {
+//			h = http.Header{"Sec-Websocket-Protocol": {subprotocol}}
+//		}
+//		return u.Upgrader.Upgrade(w, req, h)
+//	}
+type WebSocketUpgrader interface {
+	// Upgrade completes the handshake with the given subprotocol, which is
+	// empty if none was selected, and returns the connection. If it fails,
+	// it must have answered the request.
+	Upgrade(w http.ResponseWriter, req *http.Request, subprotocol string) (io.Closer, error)
+}
+
+// WebSocketHandler serves WebSocket connections, see HttpRouter.WebSocket.
+// The connection is the one returned by the WebSocketUpgrader, e.g. a
+// *websocket.Conn of gorilla/websocket.
+type WebSocketHandler interface {
+	ServeWebSocket(conn io.Closer, req *http.Request, ps drouter.Params)
+}
+
+// WebSocketHandlerFunc is an adapter which allows the usage of a function as
+// WebSocketHandler.
+type WebSocketHandlerFunc func(conn io.Closer, req *http.Request, ps drouter.Params)
+
+// ServeWebSocket calls f(conn, req, ps).
+func (f WebSocketHandlerFunc) ServeWebSocket(conn io.Closer, req *http.Request, ps drouter.Params) {
+	f(conn, req, ps)
+}
+
+// WebSocket registers a GET route whose handshakes are upgraded by the
+// router's WebSocketUpgrader and whose connections are served by the
+// handler, e.g.
+//
+//	router.WebSocketUpgrader = gorillaUpgrader{}
+//	router.WebSocket("/rooms/:room", dhttprouter.WebSocketHandlerFunc(func(c io.Closer, req *http.Request, ps drouter.Params) {
+//		conn := c.(*websocket.Conn)
+//		...
+//	}), dhttprouter.WebSocketPolicy{Subprotocols: []string{"chat"}}.Wrap)
+//
+// Requests which are no WebSocket handshakes are answered with 426 Upgrade
+// Required, handshakes of other versions than 13 as well, with the supported
+// version, and handshakes without key with 400 Bad Request. The middleware
+// runs before the upgrade, so it can still reject the handshake, e.g. a
+// WebSocketPolicy, whose selected subprotocol is passed to the upgrader.
+// Panics of the handler are passed to the PanicHandler like those of other
+// handles. The connection is closed when the handler returns. As the
+// handler runs within the route's handle, the Params stay valid until it
+// returns.
+// WebSocket panics if the router has no WebSocketUpgrader.
+func (r *HttpRouter) WebSocket(path string, handler WebSocketHandler, middleware ...Middleware) {
+	if r.WebSocketUpgrader == nil {
+		panic("router must have a WebSocketUpgrader for WebSocket route '" + path + "'")
+	}
+	if handler == nil {
+		panic("handler must not be nil")
+	}
+	upgrader := r.WebSocketUpgrader
+
+	handle := func(w http.ResponseWriter, req *http.Request, ps drouter.Params) {
+		if !isWebSocket(req) {
+			w.Header().Set("Upgrade", "websocket")
+			w.Header().Set("Connection", "Upgrade")
+			http.Error(w, http.StatusText(http.StatusUpgradeRequired), http.StatusUpgradeRequired)
+			return
+		}
+		if req.Header.Get("Sec-Websocket-Version") != "13" {
+			w.Header().Set("Sec-Websocket-Version", "13")
+			http.Error(w, http.StatusText(http.StatusUpgradeRequired), http.StatusUpgradeRequired)
+			return
+		}
+		if req.Header.Get("Sec-Websocket-Key") == "" {
+			http.Error(w, "missing Sec-WebSocket-Key", http.StatusBadRequest)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, req, WebSocketSubprotocol(req.Context()))
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		handler.ServeWebSocket(conn, req, ps)
+	}
+	if err := r.tryHandle(http.MethodGet, path, handle, handler, middleware); err != nil {
+		panic(err.Error())
+	}
+}
+
+// isWebSocket reports whether the request is a WebSocket handshake.
+func isWebSocket(req *http.Request) bool {
+	if !isUpgrade(req) {
+		return false
+	}
+	for _, v := range req.Header["Upgrade"] {
+		for _, token := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "websocket") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package dhttprouter
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+)
+
+func TestWebSocketPolicy(t *testing.T) {
+	var proto string
+	handle := func(w http.ResponseWriter, req *http.Request, _ drouter.Params) {
+		proto = WebSocketSubprotocol(req.Context())
+		w.WriteHeader(http.StatusSwitchingProtocols)
+	}
+
+	router := New()
+	router.GET("/chat", handle, WebSocketPolicy{
+		AllowedOrigins:     []string{"https://example.com", "https://*.example.org"},
+		Subprotocols:       []string{"chat.v2", "chat.v1"},
+		RequireSubprotocol: true,
+	}.Wrap)
+	router.GET("/feed", handle, WebSocketPolicy{Subprotocols: []string{"feed"}}.Wrap)
+
+	tests := []struct {
+		path, origin, protocols string
+		upgrade                 bool
+		code                    int
+		proto                   string
+	}{
+		{"/chat", "https://example.com", "chat.v1, chat.v2", true, http.StatusSwitchingProtocols, "chat.v2"},
+		{"/chat", "https://a.example.org", "chat.v1", true, http.StatusSwitchingProtocols, "chat.v1"},
+		{"/chat", "", "chat.v1", true, http.StatusSwitchingProtocols, "chat.v1"},
+		{"/chat", "https://evil.com", "chat.v1", true, http.StatusForbidden, ""},
+		{"/chat", "https://example.com", "other", true, http.StatusBadRequest, ""},
+		{"/chat", "https://evil.com", "", false, http.StatusSwitchingProtocols, ""},
+		{"/feed", "http://example.com", "other", true, http.StatusSwitchingProtocols, ""},
+		{"/feed", "http://example.com", "feed", true, http.StatusSwitchingProtocols, "feed"},
+		{"/feed", "http://evil.com", "feed", true, http.StatusForbidden, ""},
+	}
+	for _, tt := range tests {
+		proto = ""
+		req := httptest.NewRequest(http.MethodGet, "http://example.com"+tt.path, nil)
+		if tt.upgrade {
+			req.Header.Set("Connection", "Upgrade")
+			req.Header.Set("Upgrade", "websocket")
+		}
+		if tt.origin != "" {
+			req.Header.Set("Origin", tt.origin)
+		}
+		if tt.protocols != "" {
+			req.Header.Set("Sec-WebSocket-Protocol", tt.protocols)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tt.code || proto != tt.proto {
+			t.Errorf("%s from %q offering %q: want %d %q, got %d %q",
+				tt.path, tt.origin, tt.protocols, tt.code, tt.proto, w.Code, proto)
+		}
+	}
+}
+
+type testWebSocketConn struct {
+	closed bool
+}
+
+func (c *testWebSocketConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+type testUpgrader struct {
+	conn        *testWebSocketConn
+	subprotocol string
+}
+
+func (u *testUpgrader) Upgrade(w http.ResponseWriter, _ *http.Request, subprotocol string) (io.Closer, error) {
+	u.subprotocol = subprotocol
+	w.WriteHeader(http.StatusSwitchingProtocols)
+	u.conn = &testWebSocketConn{}
+	return u.conn, nil
+}
+
+func TestRouterWebSocket(t *testing.T) {
+	upgrader := &testUpgrader{}
+	var room string
+	router := New()
+	router.WebSocketUpgrader = upgrader
+	router.PanicHandler = func(http.ResponseWriter, *http.Request, interface{}) {}
+	router.WebSocket("/rooms/:room", WebSocketHandlerFunc(func(conn io.Closer, req *http.Request, ps drouter.Params) {
+		if conn.(*testWebSocketConn).closed {
+			t.Error("connection was closed before the handler returned")
+		}
+		room = ps.ByName("room")
+		if room == "panic" {
+			panic("boom")
+		}
+	}), WebSocketPolicy{Subprotocols: []string{"chat"}}.Wrap)
+
+	handshake := func(path, version, key string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set("Sec-WebSocket-Version", version)
+		req.Header.Set("Sec-WebSocket-Key", key)
+		req.Header.Set("Sec-WebSocket-Protocol", "chat")
+		return req
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, handshake("/rooms/lobby", "13", "dGhlIHNhbXBsZSBub25jZQ=="))
+	if w.Code != http.StatusSwitchingProtocols || room != "lobby" || upgrader.subprotocol != "chat" || !upgrader.conn.closed {
+		t.Errorf("handshake failed: %d, room %q, subprotocol %q", w.Code, room, upgrader.subprotocol)
+	}
+
+	tests := []struct {
+		req    *http.Request
+		code   int
+		header string
+	}{
+		{httptest.NewRequest(http.MethodGet, "/rooms/lobby", nil), http.StatusUpgradeRequired, "Upgrade"},
+		{handshake("/rooms/lobby", "8", "key"), http.StatusUpgradeRequired, "Sec-Websocket-Version"},
+		{handshake("/rooms/lobby", "13", ""), http.StatusBadRequest, ""},
+	}
+	for i, tt := range tests {
+		upgrader.conn = nil
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, tt.req)
+		if w.Code != tt.code || upgrader.conn != nil {
+			t.Errorf("%d: want %d, got %d", i, tt.code, w.Code)
+		}
+		if tt.header != "" && w.Header().Get(tt.header) == "" {
+			t.Errorf("%d: header %s is missing", i, tt.header)
+		}
+	}
+
+	// Panics are recovered and the connection is closed
+	router.ServeHTTP(httptest.NewRecorder(), handshake("/rooms/panic", "13", "key"))
+	if !upgrader.conn.closed {
+		t.Error("connection was not closed after panic")
+	}
+
+	if recv := catchPanic(func() { New().WebSocket("/ws", WebSocketHandlerFunc(nil)) }); recv == nil {
+		t.Error("no panic without upgrader")
+	}
+}
@@ -0,0 +1,44 @@
+package dhttprouter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatusWriter(t *testing.T) {
+	tests := []struct {
+		write func(w http.ResponseWriter)
+		code  int
+	}{
+		{func(w http.ResponseWriter) {}, http.StatusOK},
+		{func(w http.ResponseWriter) { w.Write([]byte("x")) }, http.StatusOK},
+		{func(w http.ResponseWriter) { w.WriteHeader(http.StatusTeapot) }, http.StatusTeapot},
+		{func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusEarlyHints)
+			w.WriteHeader(http.StatusCreated)
+		}, http.StatusCreated},
+		{func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusNotFound)
+			w.WriteHeader(http.StatusOK)
+		}, http.StatusNotFound},
+		{func(w http.ResponseWriter) { w.(http.Flusher).Flush() }, http.StatusOK},
+	}
+	for i, tr := range tests {
+		rec := httptest.NewRecorder()
+		sw := &statusWriter{ResponseWriter: rec}
+		tr.write(sw)
+		if sw.Status() != tr.code {
+			t.Errorf("test %d: wrong status: want %d, got %d", i, tr.code, sw.Status())
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	sw := &statusWriter{ResponseWriter: rec}
+	if sw.Unwrap() != rec {
+		t.Error("Unwrap did not return the underlying writer")
+	}
+	if _, _, err := sw.Hijack(); err == nil {
+		t.Error("hijacking a writer without support did not fail")
+	}
+}
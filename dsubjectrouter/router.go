@@ -0,0 +1,202 @@
+// Package dsubjectrouter routes NATS subjects onto handlers for pub/sub
+// dispatch, using the radix tree of drouter. Handlers subscribe to subjects,
+// which are dot-separated tokens and may contain the NATS wildcards '*',
+// matching a single token, and '>', matching one or more tokens at the end
+// of the subject:
+//
+//	router := dsubjectrouter.New[func(msg *nats.Msg, ps drouter.Params)]()
+//	router.Subscribe("orders.*.created", notifyCustomer)
+//	router.Subscribe("orders.>", audit)
+//
+//	for _, m := range router.Lookup("orders.eu.created") {
+//		m.Handler(msg, m.Params) // notifyCustomer with "1"="eu", then audit
+//	}
+//
+// The tokens matched by '*' are captured as Params named after the index of
+// their token, starting at 0, the tokens matched by '>' as a single Param
+// named ">", e.g. "eu.created" for the subject above.
+//
+// In contrast to the routers of HTTP paths, subscriptions may overlap: a
+// subject is dispatched to the handlers of all matching subscriptions.
+// Tokens must not contain '/', which separates the levels of the tree.
+package dsubjectrouter
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/thekhanj/drouter"
+)
+
+// FullWildcardParam is the name of the Param holding the tokens matched by
+// the full wildcard '>'.
+const FullWildcardParam = ">"
+
+// Match is a subscription matching a subject.
+type Match[H any] struct {
+	// The subscribed subject, e.g. "orders.*.created"
+	Subject string
+
+	Handler H
+
+	// Values of the wildcards of the subscription
+	Params drouter.Params
+}
+
+// Router maps NATS subjects onto handlers of type H.
+//
+// Overlapping subscriptions, e.g. "orders.*" and "orders.eu", conflict
+// within a radix tree. They are therefore spread over as many trees as
+// needed, so each tree is conflict-free, and a lookup collects the matches
+// of all trees. Few subscriptions overlap in practice, which keeps the
+// number of trees small.
+type Router[H any] struct {
+	trees     []*drouter.Router[*entry[H]]
+	entries   map[string]*entry[H]
+	maxParams uint16
+}
+
+// entry holds the handlers subscribed to a subject, in the order of their
+// subscription.
+type entry[H any] struct {
+	subject  string
+	handlers []H
+}
+
+// New returns a new empty router for handlers of type H.
+func New[H any]() *Router[H] {
+	return &Router[H]{entries: make(map[string]*entry[H])}
+}
+
+// Subscribe registers the handler for the given subject. A subject may be
+// subscribed to by several handlers. It panics if the subject is invalid.
+func (r *Router[H]) Subscribe(subject string, handler H) {
+	if err := r.TrySubscribe(subject, handler); err != nil {
+		panic(err.Error())
+	}
+}
+
+// TrySubscribe is like Subscribe, but returns a *drouter.RouteError instead
+// of panicking if the subject is invalid.
+func (r *Router[H]) TrySubscribe(subject string, handler H) error {
+	if e := r.entries[subject]; e != nil {
+		e.handlers = append(e.handlers, handler)
+		return nil
+	}
+
+	path, err := compile(subject)
+	if err != nil {
+		return err
+	}
+	e := &entry[H]{subject: subject, handlers: []H{handler}}
+	if err := r.insert(path, e); err != nil {
+		return err
+	}
+	r.entries[subject] = e
+	if n := drouter.CountParams(path); n > r.maxParams {
+		r.maxParams = n
+	}
+	return nil
+}
+
+// insert adds the entry to the first tree it does not conflict with, or to a
+// new tree.
+func (r *Router[H]) insert(path string, e *entry[H]) error {
+	for _, tree := range r.trees {
+		err := tree.TryAddRoute(path, e)
+		if err == nil {
+			return nil
+		}
+		if rerr, ok := err.(*drouter.RouteError); !ok || rerr.Kind != drouter.Conflict {
+			return err
+		}
+	}
+
+	tree := drouter.New[*entry[H]]()
+	if err := tree.TryAddRoute(path, e); err != nil {
+		return err
+	}
+	r.trees = append(r.trees, tree)
+	return nil
+}
+
+// compile translates a subject into a route path of the tree.
+func compile(subject string) (string, error) {
+	invalid := func(msg string) error {
+		return &drouter.RouteError{
+			Kind:    drouter.InvalidPath,
+			Path:    subject,
+			Message: msg + " in subject '" + subject + "'",
+		}
+	}
+
+	tokens := strings.Split(subject, ".")
+	var path strings.Builder
+	for i, token := range tokens {
+		path.WriteByte('/')
+		switch {
+		case token == "":
+			return "", invalid("tokens must not be empty")
+		case token == "*":
+			path.WriteString(":" + strconv.Itoa(i))
+		case token == ">":
+			if i != len(tokens)-1 {
+				return "", invalid("'>' must be the last token")
+			}
+			path.WriteString("*" + FullWildcardParam)
+		case strings.ContainsAny(token, "*> \t/:"):
+			return "", invalid("tokens must not contain wildcards, whitespace, '/' or ':'")
+		default:
+			path.WriteString(token)
+		}
+	}
+	return path.String(), nil
+}
+
+// Lookup returns the subscriptions matching the subject, with one Match per
+// handler. Matches of the same subscription are ordered as the handlers
+// subscribed. It returns nil if the subject has no subscribers or is no
+// valid subject.
+func (r *Router[H]) Lookup(subject string) []Match[H] {
+	if subject == "" || strings.ContainsRune(subject, '/') {
+		return nil
+	}
+	path := "/" + strings.ReplaceAll(subject, ".", "/")
+
+	var matches []Match[H]
+	for _, tree := range r.trees {
+		var ps drouter.Params
+		if r.maxParams > 0 {
+			ps = make(drouter.Params, 0, r.maxParams)
+		}
+		e, _ := tree.Lookup(path, &ps)
+		if e == nil {
+			continue
+		}
+
+		if strings.HasSuffix(e.subject, ">") {
+			// The catch-all value is a path with the slash before the tokens
+			last := &ps[len(ps)-1]
+			last.Value = strings.ReplaceAll(last.Value[1:], "/", ".")
+		}
+		for _, h := range e.handlers {
+			matches = append(matches, Match[H]{Subject: e.subject, Handler: h, Params: ps})
+		}
+	}
+	return matches
+}
+
+// Walk calls fn for every subscribed subject with its handlers, until fn
+// returns false.
+func (r *Router[H]) Walk(fn func(subject string, handlers []H) bool) {
+	for _, tree := range r.trees {
+		stop := false
+		tree.Walk(func(_ string, e *entry[H]) bool {
+			stop = !fn(e.subject, e.handlers)
+			return !stop
+		})
+		if stop {
+			return
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package dsubjectrouter
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRouter(t *testing.T) {
+	router := New[string]()
+	router.Subscribe("orders.*.created", "notify")
+	router.Subscribe("orders.eu.created", "eu")
+	router.Subscribe("orders.>", "audit")
+	router.Subscribe("orders.>", "archive")
+	router.Subscribe("payments.*", "payments")
+
+	tests := []struct {
+		subject  string
+		handlers string
+	}{
+		{"orders.eu.created", "archive,audit,eu,notify"},
+		{"orders.us.created", "archive,audit,notify"},
+		{"orders.us", "archive,audit"},
+		{"orders", ""},
+		{"payments.refund", "payments"},
+		{"payments.refund.partial", ""},
+		{"", ""},
+		{"orders/eu", ""},
+	}
+	for _, tt := range tests {
+		var handlers []string
+		for _, m := range router.Lookup(tt.subject) {
+			handlers = append(handlers, m.Handler)
+		}
+		sort.Strings(handlers)
+		if got := strings.Join(handlers, ","); got != tt.handlers {
+			t.Errorf("%q: want %q, got %q", tt.subject, tt.handlers, got)
+		}
+	}
+
+	for _, m := range router.Lookup("orders.eu.created") {
+		switch m.Subject {
+		case "orders.*.created":
+			if m.Params.ByName("1") != "eu" {
+				t.Errorf("wrong params %v", m.Params)
+			}
+		case "orders.>":
+			if m.Params.ByName(FullWildcardParam) != "eu.created" {
+				t.Errorf("wrong params %v", m.Params)
+			}
+		}
+	}
+
+	// Handlers of a subscription keep their order
+	var order []string
+	for _, m := range router.Lookup("orders.x") {
+		order = append(order, m.Handler)
+	}
+	if strings.Join(order, ",") != "audit,archive" {
+		t.Errorf("wrong order %v", order)
+	}
+
+	subjects := 0
+	router.Walk(func(string, []string) bool {
+		subjects++
+		return true
+	})
+	if subjects != 4 {
+		t.Errorf("wrong number of subjects: %d", subjects)
+	}
+}
+
+func TestRouterInvalidSubject(t *testing.T) {
+	router := New[string]()
+	for _, subject := range []string{"", "a..b", "a.>.b", "a.b*", "a/b", "a.:b", "a b"} {
+		if err := router.TrySubscribe(subject, "h"); err == nil {
+			t.Errorf("no error for subject %q", subject)
+		}
+	}
+}
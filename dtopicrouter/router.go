@@ -0,0 +1,155 @@
+// Package dtopicrouter routes MQTT topics onto handlers, e.g. for the
+// dispatch of messages in brokers and IoT gateways, using the radix tree of
+// drouter. Handlers are registered for topic filters, which may contain the
+// MQTT wildcards '+', matching a single level, and '#', matching any number
+// of levels at the end of the filter:
+//
+//	router := dtopicrouter.New[func(payload []byte, ps drouter.Params)]()
+//	router.Handle("sensors/+/temperature", storeTemperature)
+//	router.Handle("devices/#", logDeviceEvent)
+//
+//	handler, ps, ok := router.Lookup("sensors/kitchen/temperature")
+//	// ps.ByName("1") == "kitchen"
+//
+// The levels matched by '+' are captured as Params named after the index of
+// their level, starting at 0, the levels matched by '#' as a single Param
+// named "#", e.g. "a/b" for the topic "devices/a/b".
+//
+// Unlike an MQTT broker, which delivers a message to every matching
+// subscription, the router selects a single handler per topic. Like the
+// routes of an HTTP router, filters which overlap at a level, e.g.
+// "sensors/+/temperature" and "sensors/kitchen/temperature", conflict.
+package dtopicrouter
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/thekhanj/drouter"
+)
+
+// MultiLevelParam is the name of the Param holding the levels matched by the
+// multi-level wildcard '#'.
+const MultiLevelParam = "#"
+
+// Router maps MQTT topics onto handlers of type H.
+type Router[H any] struct {
+	tree      *drouter.Router[*entry[H]]
+	maxParams uint16
+}
+
+// entry is a registered handler. The tree stores pointers, so a missing
+// handler is told apart from a nil one.
+type entry[H any] struct {
+	filter  string
+	handler H
+}
+
+// New returns a new empty router for handlers of type H.
+func New[H any]() *Router[H] {
+	return &Router[H]{tree: drouter.New[*entry[H]]()}
+}
+
+// Handle registers the handler for the given topic filter. It panics if the
+// filter is invalid or conflicts with a registered one.
+func (r *Router[H]) Handle(filter string, handler H) {
+	if err := r.TryHandle(filter, handler); err != nil {
+		panic(err.Error())
+	}
+}
+
+// TryHandle is like Handle, but returns a *drouter.RouteError instead of
+// panicking if the filter can not be registered.
+func (r *Router[H]) TryHandle(filter string, handler H) error {
+	path, err := compile(filter)
+	if err != nil {
+		return err
+	}
+	if err := r.tree.TryAddRoute(path, &entry[H]{filter: filter, handler: handler}); err != nil {
+		return err
+	}
+	if n := drouter.CountParams(path); n > r.maxParams {
+		r.maxParams = n
+	}
+	return nil
+}
+
+// compile translates a topic filter into a route path of the tree.
+func compile(filter string) (string, error) {
+	invalid := func(msg string) error {
+		return &drouter.RouteError{
+			Kind:    drouter.InvalidPath,
+			Path:    filter,
+			Message: msg + " in topic filter '" + filter + "'",
+		}
+	}
+	if filter == "" {
+		return "", invalid("topic filter must not be empty")
+	}
+
+	levels := strings.Split(filter, "/")
+	var path strings.Builder
+	for i, level := range levels {
+		path.WriteByte('/')
+		switch {
+		case level == "+":
+			path.WriteString(":" + strconv.Itoa(i))
+		case level == "#":
+			if i != len(levels)-1 {
+				return "", invalid("'#' must be the last level")
+			}
+			path.WriteString("*" + MultiLevelParam)
+		case strings.ContainsAny(level, "+#"):
+			return "", invalid("wildcards must occupy an entire level")
+		case strings.ContainsAny(level, ":*"):
+			return "", invalid("levels must not contain ':' or '*'")
+		default:
+			path.WriteString(level)
+		}
+	}
+	return path.String(), nil
+}
+
+// Lookup returns the handler whose filter matches the topic, along with the
+// levels captured by its wildcards. If no handler matches, ok is false.
+// As in MQTT, a filter ending with '#' also matches its parent level, e.g.
+// "devices/#" matches "devices", and topics starting with '$' are not
+// matched by filters starting with a wildcard.
+func (r *Router[H]) Lookup(topic string) (handler H, ps drouter.Params, ok bool) {
+	if topic == "" {
+		return handler, nil, false
+	}
+	if r.maxParams > 0 {
+		ps = make(drouter.Params, 0, r.maxParams)
+	}
+
+	path := "/" + topic
+	e, tsr := r.tree.Lookup(path, &ps)
+	if e == nil && tsr {
+		ps = ps[:0]
+		if e, _ = r.tree.Lookup(path+"/", &ps); e != nil && !strings.HasSuffix(e.filter, "#") {
+			e = nil
+		}
+	}
+	if e == nil {
+		return handler, nil, false
+	}
+	if topic[0] == '$' && (e.filter[0] == '+' || e.filter[0] == '#') {
+		return handler, nil, false
+	}
+
+	if strings.HasSuffix(e.filter, "#") {
+		// The catch-all value starts with the slash before the levels
+		last := &ps[len(ps)-1]
+		last.Value = last.Value[1:]
+	}
+	return e.handler, ps, true
+}
+
+// Walk calls fn for every registered filter with its handler, until fn
+// returns false.
+func (r *Router[H]) Walk(fn func(filter string, handler H) bool) {
+	r.tree.Walk(func(_ string, e *entry[H]) bool {
+		return fn(e.filter, e.handler)
+	})
+}
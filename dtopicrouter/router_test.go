@@ -0,0 +1,79 @@
+package dtopicrouter
+
+import (
+	"testing"
+)
+
+func TestRouter(t *testing.T) {
+	router := New[string]()
+	router.Handle("sensors/+/temperature", "temperature")
+	router.Handle("rooms/+/+/humidity", "humidity")
+	router.Handle("devices/#", "devices")
+	router.Handle("status", "status")
+
+	tests := []struct {
+		topic   string
+		handler string
+		key     string
+		value   string
+	}{
+		{"sensors/kitchen/temperature", "temperature", "1", "kitchen"},
+		{"rooms/house/cellar/humidity", "humidity", "2", "cellar"},
+		{"devices/a/b", "devices", MultiLevelParam, "a/b"},
+		{"devices", "devices", MultiLevelParam, ""},
+		{"status", "status", "", ""},
+		{"sensors/kitchen", "", "", ""},
+		{"sensors/kitchen/temperature/max", "", "", ""},
+		{"", "", "", ""},
+	}
+	for _, tt := range tests {
+		handler, ps, ok := router.Lookup(tt.topic)
+		if ok != (tt.handler != "") || handler != tt.handler {
+			t.Errorf("%q: want handler %q, got %q", tt.topic, tt.handler, handler)
+		}
+		if tt.key != "" && ps.ByName(tt.key) != tt.value {
+			t.Errorf("%q: want %s=%q, got %v", tt.topic, tt.key, tt.value, ps)
+		}
+	}
+
+	var filters []string
+	router.Walk(func(filter string, _ string) bool {
+		filters = append(filters, filter)
+		return true
+	})
+	if len(filters) != 4 {
+		t.Errorf("wrong filters %v", filters)
+	}
+}
+
+func TestRouterSystemTopics(t *testing.T) {
+	router := New[string]()
+	router.Handle("#", "all")
+
+	if _, _, ok := router.Lookup("$SYS/uptime"); ok {
+		t.Error("system topic matched a wildcard filter")
+	}
+	if _, ps, ok := router.Lookup("a/b"); !ok || ps.ByName(MultiLevelParam) != "a/b" {
+		t.Errorf("topic was not matched: %v", ps)
+	}
+
+	router = New[string]()
+	router.Handle("$SYS/+", "sys")
+	if h, _, _ := router.Lookup("$SYS/uptime"); h != "sys" {
+		t.Errorf("system topic was not matched by explicit filter: %q", h)
+	}
+}
+
+func TestRouterInvalidFilter(t *testing.T) {
+	router := New[string]()
+	for _, filter := range []string{"", "a/#/b", "a/b+", "a/#b", "a/:b", "a/*"} {
+		if err := router.TryHandle(filter, "h"); err == nil {
+			t.Errorf("no error for filter %q", filter)
+		}
+	}
+
+	router.Handle("a/+/c", "h")
+	if err := router.TryHandle("a/b/c", "h"); err == nil {
+		t.Error("no error for overlapping filter")
+	}
+}
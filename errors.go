@@ -0,0 +1,156 @@
+package drouter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RouteErrorKind classifies why a route could not be added.
+type RouteErrorKind uint8
+
+const (
+	// InvalidPath means the path does not begin with '/'.
+	InvalidPath RouteErrorKind = iota + 1
+
+	// InvalidWildcard means a wildcard of the path is malformed, e.g. it has
+	// no name, shares its segment with another wildcard, is a catch-all not at
+	// the end of the path or has an unknown constraint.
+	InvalidWildcard
+
+	// Conflict means the route conflicts with a registered one, e.g. because
+	// a handle is already registered for the path or a wildcard would make
+	// existing routes unreachable.
+	Conflict
+
+	// InvalidHandle means the handle is nil.
+	InvalidHandle
+
+	// InvalidMethod means the method is empty. It is used by routers keeping
+	// a tree per method.
+	InvalidMethod
+
+	// TooManyParams means the route has more parameters than the Params
+	// pooled by the router can hold. It is used by routers with a fixed
+	// capacity of their pooled Params.
+	TooManyParams
+)
+
+var routeErrorKinds = [...]string{
+	InvalidPath:     "invalid path",
+	InvalidWildcard: "invalid wildcard",
+	Conflict:        "conflict",
+	InvalidHandle:   "invalid handle",
+	InvalidMethod:   "invalid method",
+	TooManyParams:   "too many params",
+}
+
+func (k RouteErrorKind) String() string {
+	if int(k) < len(routeErrorKinds) && routeErrorKinds[k] != "" {
+		return routeErrorKinds[k]
+	}
+	return fmt.Sprintf("RouteErrorKind(%d)", uint8(k))
+}
+
+// RouteError describes why a route could not be added. It is returned by
+// TryAddRoute and AddRoute panics with it for conflicts.
+type RouteError struct {
+	Kind RouteErrorKind
+
+	// The path of the route
+	Path string
+
+	// A registered route the path conflicts with. Only set for conflicts.
+	Existing string
+
+	// The segment of the path where it clashes with the existing route,
+	// e.g. a wildcard. Only set for conflicts.
+	Segment string
+
+	// Human-readable description of the error
+	Message string
+}
+
+func (e *RouteError) Error() string {
+	return e.Message
+}
+
+// TryAddRoute is like AddRoute, but returns a *RouteError instead of
+// panicking if the route can not be added. The router is left unchanged in
+// that case.
+func (r *Router[T]) TryAddRoute(path string, handle T) (err error) {
+	if len(path) < 1 || path[0] != '/' {
+		return &RouteError{Kind: InvalidPath, Path: path, Message: "path must begin with '/' in path '" + path + "'"}
+	}
+	if any(handle) == nil {
+		return &RouteError{Kind: InvalidHandle, Path: path, Message: "handle must not be nil"}
+	}
+	if _, err := ParsePattern(path); err != nil {
+		return &RouteError{Kind: InvalidWildcard, Path: path, Message: err.Error()}
+	}
+
+	// The pattern is valid, so the tree can only reject it due to a conflict
+	defer func() {
+		if rcv := recover(); rcv != nil {
+			// Undo the changes made before the conflict was detected
+			r.root.normalize()
+			if rerr, ok := rcv.(*RouteError); ok {
+				err = rerr
+			} else {
+				err = &RouteError{Kind: Conflict, Path: path, Message: fmt.Sprint(rcv)}
+			}
+		}
+	}()
+	r.AddRoute(path, handle)
+	return nil
+}
+
+// CheckConflicts reports routes shadowing each other across the whole tree.
+// Routes matching the same request paths are already rejected by AddRoute,
+// but routes only differing in case, e.g. /Users and /users, both match
+// exact lookups while case-insensitive lookups like FindCaseInsensitivePath,
+// which are used for fixed path redirects, only find one of them.
+// For each such route an error of kind Conflict is returned, naming the
+// first route in tree order it clashes with as Existing.
+func (r *Router[T]) CheckConflicts() []*RouteError {
+	var conflicts []*RouteError
+	seen := make(map[string]string)
+
+	r.Walk(func(path string, _ T) bool {
+		key := foldPattern(path)
+		if existing, ok := seen[key]; ok {
+			conflicts = append(conflicts, &RouteError{
+				Kind:     Conflict,
+				Path:     path,
+				Existing: existing,
+				Message: "path '" + path + "' only differs in case from route '" +
+					existing + "', case-insensitive lookups can not tell them apart",
+			})
+		} else {
+			seen[key] = path
+		}
+		return true
+	})
+	return conflicts
+}
+
+// foldPattern returns the pattern with its static parts in lower case and
+// the names of its wildcards removed, so patterns matching the same paths
+// case-insensitively have the same result.
+func foldPattern(pattern string) string {
+	var b []byte
+	for len(pattern) > 0 {
+		i := strings.IndexAny(pattern, ":*")
+		if i < 0 {
+			return string(b) + strings.ToLower(pattern)
+		}
+		b = append(b, strings.ToLower(pattern[:i])...)
+		b = append(b, pattern[i])
+
+		end := strings.IndexByte(pattern[i:], '/')
+		if end < 0 {
+			break
+		}
+		pattern = pattern[i+end:]
+	}
+	return string(b)
+}
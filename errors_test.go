@@ -0,0 +1,166 @@
+package drouter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRouterTryAddRoute(t *testing.T) {
+	router := New[Handle]()
+	routes := []string{
+		"/",
+		"/cmd/:tool/:sub",
+		"/src/*filepath",
+		"/search/",
+		"/search/:query",
+		"/user_:name",
+		"/user_:name/about",
+		"/files/:dir/*filepath",
+		"/doc/",
+		"/doc/go_faq.html",
+		"/info/:user/public",
+		"/info/:user/project/:project",
+	}
+	for _, route := range routes {
+		if err := router.TryAddRoute(route, fakeHandle(route)); err != nil {
+			t.Fatalf("unexpected error for route '%s': %v", route, err)
+		}
+	}
+	before := treeShape(router.root)
+
+	tests := []struct {
+		path string
+		kind RouteErrorKind
+	}{
+		{"", InvalidPath},
+		{"cmd", InvalidPath},
+		{"/cmd/:", InvalidWildcard},
+		{"/cmd/:a:b", InvalidWildcard},
+		{"/src/*filepath/x", InvalidWildcard},
+		{"/user/:id|nope", InvalidWildcard},
+		{"/", Conflict},
+		{"/cmd/vet", Conflict},
+		{"/cmd/:other", Conflict},
+		{"/src/*other", Conflict},
+		{"/src/:file", Conflict},
+		{"/user_x", Conflict},
+		{"/info/:id/public", Conflict},
+		{"/doc/:page", Conflict},
+		{"/se:x", Conflict},
+		{"/info/:user/pub:x", Conflict},
+	}
+	for _, test := range tests {
+		err := router.TryAddRoute(test.path, fakeHandle(test.path))
+		var rerr *RouteError
+		if !errors.As(err, &rerr) {
+			t.Errorf("expected RouteError for route '%s', got %v", test.path, err)
+			continue
+		}
+		if rerr.Kind != test.kind || rerr.Path != test.path || rerr.Error() == "" {
+			t.Errorf("unexpected error for route '%s': kind=%v path=%q msg=%q",
+				test.path, rerr.Kind, rerr.Path, rerr.Error())
+		}
+	}
+
+	err := router.TryAddRoute("/nil", nil)
+	if rerr, ok := err.(*RouteError); !ok || rerr.Kind != InvalidHandle {
+		t.Errorf("expected InvalidHandle error, got %v", err)
+	}
+
+	// Failed insertions must leave the tree unchanged
+	if after := treeShape(router.root); after != before {
+		t.Errorf("tree changed by failed insertions:\nbefore: %s\nafter:  %s", before, after)
+	}
+	checkPriorities(t, router.root)
+	checkIndices(t, router.root)
+	if n := router.Len(); n != len(routes) {
+		t.Errorf("Len() = %d, want %d", n, len(routes))
+	}
+	for _, route := range routes {
+		if handle, _ := router.Lookup(route, nil); handle == nil {
+			t.Errorf("route '%s' lost", route)
+		}
+	}
+
+	// The router remains usable
+	if err := router.TryAddRoute("/src2/x", fakeHandle("/src2/x")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRouteErrorKindString(t *testing.T) {
+	if s := Conflict.String(); s != "conflict" {
+		t.Errorf("Conflict.String() = %q", s)
+	}
+	if s := RouteErrorKind(0).String(); s != "RouteErrorKind(0)" {
+		t.Errorf("RouteErrorKind(0).String() = %q", s)
+	}
+}
+
+func TestRouterConflictDetails(t *testing.T) {
+	tests := []struct {
+		routes   []string
+		path     string
+		existing string
+		segment  string
+	}{
+		{[]string{"/cmd/:tool/:sub"}, "/cmd/vet", "/cmd/:tool/:sub", "vet"},
+		{[]string{"/cmd/:tool/:sub"}, "/cmd/:name", "/cmd/:tool/:sub", ":name"},
+		{[]string{"/src/*filepath"}, "/src/:file", "/src/*filepath", "/:file"},
+		{[]string{"/users/new", "/users/all"}, "/users/:id", "/users/new", ":id"},
+		{[]string{"/src/"}, "/src/*filepath", "/src/", "*filepath"},
+		{[]string{"/users/:id"}, "/users/:id", "/users/:id", ""},
+	}
+	for _, test := range tests {
+		router := New[Handle]()
+		for _, route := range test.routes {
+			router.AddRoute(route, fakeHandle(route))
+		}
+
+		recv := catchPanic(func() {
+			router.AddRoute(test.path, fakeHandle(test.path))
+		})
+		rerr, ok := recv.(*RouteError)
+		if !ok {
+			t.Errorf("expected RouteError for route '%s', got %v", test.path, recv)
+			continue
+		}
+		if rerr.Kind != Conflict || rerr.Path != test.path || rerr.Existing != test.existing || rerr.Segment != test.segment {
+			t.Errorf("unexpected error for route '%s': %+v", test.path, rerr)
+		}
+	}
+}
+
+func TestRouterCheckConflicts(t *testing.T) {
+	router := New[Handle]()
+	for _, route := range []string{
+		"/users/:id",
+		"/Users/:name",
+		"/docs/Intro",
+		"/docs/intro",
+		"/docs/setup",
+		"/files/*filepath",
+	} {
+		router.AddRoute(route, fakeHandle(route))
+	}
+
+	conflicts := router.CheckConflicts()
+	if len(conflicts) != 2 {
+		t.Fatalf("expected 2 conflicts, got %d: %v", len(conflicts), conflicts)
+	}
+	pairs := make(map[string]bool)
+	for _, c := range conflicts {
+		if c.Kind != Conflict || c.Error() == "" {
+			t.Errorf("unexpected conflict: %+v", c)
+		}
+		pairs[c.Existing+" "+c.Path] = true
+		pairs[c.Path+" "+c.Existing] = true
+	}
+	if !pairs["/users/:id /Users/:name"] || !pairs["/docs/Intro /docs/intro"] {
+		t.Errorf("unexpected conflicts: %v", pairs)
+	}
+
+	if conflicts := New[Handle]().CheckConflicts(); len(conflicts) != 0 {
+		t.Errorf("unexpected conflicts for empty router: %v", conflicts)
+	}
+}
@@ -0,0 +1,427 @@
+// Package gen generates Go code matching a fixed list of routes, for
+// services whose routes are known at build time. The generated matcher
+// compares whole path elements in switch statements instead of walking the
+// tree of a drouter.Router, and returns a constant identifying the matched
+// route instead of a handle, so lookups neither chase tree nodes nor call
+// through interfaces.
+//
+// The drouter-gen command wraps Generate, e.g. in a go:generate directive:
+//
+//	//go:generate go run github.com/thekhanj/drouter/cmd/drouter-gen -pkg routes -o routes_gen.go routes.txt
+//
+// The generated package is then used like this:
+//
+//	var ps drouter.Params
+//	switch routes.Match(req.Method, req.URL.Path, &ps) {
+//	case routes.GetUsersByID:
+//		getUser(w, req, ps)
+//	case routes.NoRoute:
+//		http.NotFound(w, req)
+//	}
+//
+// The routes must be accepted by drouter.Router.AddRoute and are matched
+// the same way, except that the generated matcher does not recommend
+// trailing slash redirects. Params must span whole path elements, e.g.
+// "/users/:id", and must not have constraints or transforms.
+package gen
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/thekhanj/drouter"
+)
+
+// Route is a route of the generated matcher.
+type Route struct {
+	Method string
+	Path   string
+
+	// Name of the generated constant, an exported Go identifier. If empty,
+	// it is derived from the method and the path, e.g. GetUsersByID for
+	// GET /users/:id.
+	Name string
+}
+
+// ParseRoutes reads a route list with one route per line, consisting of the
+// method, the path and an optional name separated by white space, e.g.
+//
+//	# Users
+//	GET    /users/:id  GetUser
+//	DELETE /users/:id
+//
+// Empty lines and lines starting with '#' are skipped.
+func ParseRoutes(rd io.Reader) ([]Route, error) {
+	var routes []Route
+	sc := bufio.NewScanner(rd)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || text[0] == '#' {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("line %d: want method, path and optional name, got %q", line, text)
+		}
+		rt := Route{Method: fields[0], Path: fields[1]}
+		if len(fields) == 3 {
+			rt.Name = fields[2]
+		}
+		routes = append(routes, rt)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return routes, nil
+}
+
+// Generate returns the formatted source of a Go file of the given package
+// with a matcher for the routes. The file declares the type Route with a
+// constant per route, in the order of the list, preceded by NoRoute, and
+// the function
+//
+//	func Match(method, path string, ps *drouter.Params) Route
+//
+// which returns the route matching the method and path and appends the
+// values of its params to ps, if not nil, or returns NoRoute. It returns an
+// error if a route is invalid, conflicts with another one or its name is
+// not unique.
+func Generate(pkg string, routes []Route) ([]byte, error) {
+	if !token.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("invalid package name %q", pkg)
+	}
+	if len(routes) == 0 {
+		return nil, errors.New("no routes")
+	}
+
+	g := &generator{routes: make([]Route, len(routes)), roots: make(map[string]*node)}
+	trees := make(map[string]*drouter.Router[int])
+	names := make(map[string]Route)
+	for i, rt := range routes {
+		fail := func(msg string) error {
+			return fmt.Errorf("%s %s: %s", rt.Method, rt.Path, msg)
+		}
+
+		if rt.Method == "" {
+			return nil, fail("method must not be empty")
+		}
+		// Reject conflicts the same way as the router
+		if trees[rt.Method] == nil {
+			trees[rt.Method] = drouter.New[int]()
+		}
+		if err := trees[rt.Method].TryAddRoute(rt.Path, i); err != nil {
+			return nil, fail(err.Error())
+		}
+		elems, err := parseElems(rt.Path)
+		if err != nil {
+			return nil, fail(err.Error())
+		}
+
+		if rt.Name == "" {
+			rt.Name = deriveName(rt.Method, elems)
+		}
+		if !token.IsIdentifier(rt.Name) || !token.IsExported(rt.Name) || reserved[rt.Name] {
+			return nil, fail("invalid name " + strconv.Quote(rt.Name))
+		}
+		if other, ok := names[rt.Name]; ok {
+			return nil, fail("name " + rt.Name + " is also used by " + other.Method + " " + other.Path)
+		}
+		names[rt.Name] = rt
+
+		g.routes[i] = rt
+		g.add(rt.Method, elems, i+1)
+	}
+	return g.generate(pkg)
+}
+
+// Names declared by the generated file besides the route constants
+var reserved = map[string]bool{"Route": true, "NoRoute": true, "Match": true}
+
+// elem is a path element of a route, i.e. the text between two slashes.
+type elem struct {
+	kind drouter.SegmentKind
+
+	// The literal text for static elements, the parameter name otherwise
+	value string
+}
+
+// parseElems splits a route path into its elements.
+func parseElems(path string) ([]elem, error) {
+	segments, err := drouter.ParsePattern(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, seg := range segments {
+		if seg.Constraint != "" || len(seg.Transforms) > 0 {
+			return nil, errors.New("constraints and transforms are not supported")
+		}
+	}
+
+	parts := strings.Split(path[1:], "/")
+	elems := make([]elem, len(parts))
+	for i, part := range parts {
+		switch {
+		case strings.HasPrefix(part, ":"):
+			elems[i] = elem{drouter.ParamSegment, part[1:]}
+		case strings.HasPrefix(part, "*"):
+			elems[i] = elem{drouter.CatchAllSegment, part[1:]}
+		case strings.ContainsAny(part, ":*"):
+			return nil, errors.New("params must span a whole path element")
+		default:
+			elems[i] = elem{drouter.StaticSegment, part}
+		}
+	}
+	return elems, nil
+}
+
+// Words written in upper case in derived names
+var initialisms = map[string]bool{
+	"API": true, "HTML": true, "HTTP": true, "ID": true, "IP": true,
+	"JSON": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// deriveName derives the name of a route from its method and path elements,
+// e.g. GetUsersByID for GET /users/:id.
+func deriveName(method string, elems []elem) string {
+	name := camelCase(method)
+	for _, e := range elems {
+		if e.kind == drouter.ParamSegment {
+			name += "By"
+		}
+		name += camelCase(e.value)
+	}
+	return name
+}
+
+// camelCase joins the alphanumeric words of s, each starting with an upper
+// case letter.
+func camelCase(s string) string {
+	words := strings.FieldsFunc(s, func(c rune) bool {
+		return !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9')
+	})
+	for i, w := range words {
+		if upper := strings.ToUpper(w); initialisms[upper] {
+			words[i] = upper
+		} else {
+			words[i] = upper[:1] + strings.ToLower(w[1:])
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// node is a path element in the trie of a method. The children are
+// exclusive, as the routes were checked for conflicts, except for an empty
+// static element, which params never match.
+type node struct {
+	// Number of the route ending at the node, 0 if none
+	route int
+
+	static map[string]*node
+
+	param    *node
+	paramKey string
+
+	// Number of the catch-all route below the node, 0 if none
+	catchAll    int
+	catchAllKey string
+}
+
+func (n *node) hasChildren() bool {
+	return len(n.static) > 0 || n.param != nil || n.catchAll != 0
+}
+
+type generator struct {
+	buf    bytes.Buffer
+	routes []Route
+	roots  map[string]*node
+}
+
+// add adds the route with the given number to the trie of the method.
+func (g *generator) add(method string, elems []elem, route int) {
+	n := g.roots[method]
+	if n == nil {
+		n = &node{}
+		g.roots[method] = n
+	}
+
+	for _, e := range elems {
+		switch e.kind {
+		case drouter.StaticSegment:
+			if n.static == nil {
+				n.static = make(map[string]*node)
+			}
+			if n.static[e.value] == nil {
+				n.static[e.value] = &node{}
+			}
+			n = n.static[e.value]
+		case drouter.ParamSegment:
+			if n.param == nil {
+				n.param = &node{}
+				n.paramKey = e.value
+			}
+			n = n.param
+		case drouter.CatchAllSegment:
+			n.catchAll = route
+			n.catchAllKey = e.value
+			return
+		}
+	}
+	n.route = route
+}
+
+func (g *generator) p(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+	g.buf.WriteByte('\n')
+}
+
+func (g *generator) generate(pkg string) ([]byte, error) {
+	methods := make([]string, 0, len(g.roots))
+	for method := range g.roots {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	g.p("// Code generated by drouter-gen. DO NOT EDIT.")
+	g.p("")
+	g.p("package %s", pkg)
+	g.p("")
+	g.p("import (")
+	g.p("%q", "strings")
+	g.p("")
+	g.p("%q", "github.com/thekhanj/drouter")
+	g.p(")")
+	g.p("")
+	g.p("// Route identifies a route matched by Match.")
+	g.p("type Route int")
+	g.p("")
+	g.p("const (")
+	g.p("NoRoute Route = iota")
+	for _, rt := range g.routes {
+		g.p("%s // %s %s", rt.Name, rt.Method, rt.Path)
+	}
+	g.p(")")
+	g.p("")
+	g.p("var routeMethods = [...]string{")
+	for _, rt := range g.routes {
+		g.p("%s: %q,", rt.Name, rt.Method)
+	}
+	g.p("}")
+	g.p("")
+	g.p("var routePaths = [...]string{")
+	for _, rt := range g.routes {
+		g.p("%s: %q,", rt.Name, rt.Path)
+	}
+	g.p("}")
+	g.p("")
+	g.p("// Method returns the method of the route, or an empty string for NoRoute.")
+	g.p("func (r Route) Method() string { return routeMethods[r] }")
+	g.p("")
+	g.p("// Path returns the path pattern of the route, or an empty string for NoRoute.")
+	g.p("func (r Route) Path() string { return routePaths[r] }")
+	g.p("")
+	g.p("func (r Route) String() string {")
+	g.p("if r == NoRoute { return %q }", "NoRoute")
+	g.p("return routeMethods[r] + %q + routePaths[r]", " ")
+	g.p("}")
+	g.p("")
+	g.p("// Match returns the route matching the method and path and appends the")
+	g.p("// values of its params to ps, if not nil, or returns NoRoute.")
+	g.p("func Match(method, path string, ps *drouter.Params) Route {")
+	g.p("if path == %q || path[0] != '/' { return NoRoute }", "")
+	g.p("n := 0")
+	g.p("if ps != nil { n = len(*ps) }")
+	g.p("r := NoRoute")
+	g.p("switch method {")
+	for i, method := range methods {
+		g.p("case %q:", method)
+		g.p("r = match%d(path, ps)", i)
+	}
+	g.p("}")
+	g.p("if r == NoRoute && ps != nil { *ps = (*ps)[:n] }")
+	g.p("return r")
+	g.p("}")
+	g.p("")
+	g.p("// segment returns the path element starting at start and its end.")
+	g.p("func segment(path string, start int) (string, int) {")
+	g.p("if i := strings.IndexByte(path[start:], '/'); i >= 0 { return path[start:start+i], start+i }")
+	g.p("return path[start:], len(path)")
+	g.p("}")
+
+	for i, method := range methods {
+		g.p("")
+		g.p("func match%d(path string, ps *drouter.Params) Route { // %s", i, method)
+		g.children(g.roots[method], "0", 0)
+		g.p("}")
+	}
+
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+// children generates the code matching the children of n, whose element
+// ended at the '/' at the index held by end.
+func (g *generator) children(n *node, end string, depth int) {
+	start := end + "+1"
+	if end == "0" {
+		start = "1"
+	}
+
+	if len(n.static) > 0 || n.param != nil {
+		s, e := "s"+strconv.Itoa(depth), "e"+strconv.Itoa(depth)
+		g.p("%s, %s := segment(path, %s)", s, e, start)
+
+		if len(n.static) > 0 {
+			keys := make([]string, 0, len(n.static))
+			for key := range n.static {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			g.p("switch %s {", s)
+			for _, key := range keys {
+				g.p("case %q:", key)
+				g.node(n.static[key], e, depth)
+			}
+			g.p("}")
+		}
+		if n.param != nil {
+			g.p("if %s != %q {", s, "")
+			g.p("if ps != nil { *ps = append(*ps, drouter.Param{Key: %q, Value: %s}) }", n.paramKey, s)
+			g.node(n.param, e, depth)
+			g.p("}")
+		}
+	}
+
+	if n.catchAll != 0 {
+		g.p("if ps != nil { *ps = append(*ps, drouter.Param{Key: %q, Value: path[%s:]}) }", n.catchAllKey, end)
+		g.p("return %s", g.routes[n.catchAll-1].Name)
+		return
+	}
+	g.p("return NoRoute")
+}
+
+// node generates the code matching n, whose element ends at the index held
+// by end.
+func (g *generator) node(n *node, end string, depth int) {
+	if n.route != 0 {
+		g.p("if %s == len(path) { return %s }", end, g.routes[n.route-1].Name)
+	}
+	if n.hasChildren() {
+		g.p("if %s < len(path) {", end)
+		g.children(n, end, depth+1)
+		g.p("}")
+	}
+	g.p("return NoRoute")
+}
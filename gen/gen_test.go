@@ -0,0 +1,234 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/thekhanj/drouter"
+	"github.com/thekhanj/drouter/bench"
+	"github.com/thekhanj/drouter/gen/internal/example"
+	"github.com/thekhanj/drouter/gen/internal/githubapi"
+)
+
+// matcher is a generated package under test.
+type matcher struct {
+	dir   string
+	match func(method, path string, ps *drouter.Params) int
+}
+
+var matchers = map[string]matcher{
+	"example": {"internal/example", func(method, path string, ps *drouter.Params) int {
+		return int(example.Match(method, path, ps))
+	}},
+	"githubapi": {"internal/githubapi", func(method, path string, ps *drouter.Params) int {
+		return int(githubapi.Match(method, path, ps))
+	}},
+}
+
+func readRoutes(t testing.TB, dir string) []Route {
+	f, err := os.Open(dir + "/routes.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	routes, err := ParseRoutes(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return routes
+}
+
+func TestGeneratedUpToDate(t *testing.T) {
+	for pkg, m := range matchers {
+		src, err := Generate(pkg, readRoutes(t, m.dir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		generated, err := ioutil.ReadFile(m.dir + "/routes_gen.go")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(src, generated) {
+			t.Errorf("%s: generated file is outdated, run go generate", pkg)
+		}
+	}
+}
+
+func TestGeneratedMatchesRouter(t *testing.T) {
+	for pkg, m := range matchers {
+		routes := readRoutes(t, m.dir)
+		trees := make(map[string]*drouter.Router[int])
+		var workload []bench.Route
+		for i, rt := range routes {
+			if trees[rt.Method] == nil {
+				trees[rt.Method] = drouter.New[int]()
+			}
+			trees[rt.Method].AddRoute(rt.Path, i+1)
+			workload = append(workload, bench.Route{Method: rt.Method, Path: rt.Path})
+		}
+
+		type request struct{ method, path string }
+		var reqs []request
+		for _, req := range bench.Requests(workload) {
+			path := req.URL.Path
+			reqs = append(reqs,
+				request{req.Method, path},
+				request{req.Method, path + "/"},
+				request{req.Method, strings.TrimSuffix(path, "/")},
+				request{req.Method, path + "/x"},
+				request{req.Method, path + "x"},
+				request{otherMethod(req.Method), path},
+			)
+		}
+		for _, path := range []string{"", "users", "/", "//", "/users//", "/files/", "/files", "/files/a/b/", "/uploads/b/", "/missing"} {
+			reqs = append(reqs, request{"GET", path}, request{"PUT", path})
+		}
+
+		for _, req := range reqs {
+			var want drouter.Params
+			wantRoute := 0
+			if tree := trees[req.method]; tree != nil && strings.HasPrefix(req.path, "/") {
+				wantRoute, _ = tree.Lookup(req.path, &want)
+			}
+			if wantRoute == 0 {
+				want = nil
+			}
+
+			ps := drouter.Params{{Key: "before", Value: "kept"}}
+			got := m.match(req.method, req.path, &ps)
+			if got != wantRoute || fmt.Sprint(ps[1:]) != fmt.Sprint(want) {
+				t.Errorf("%s: %s %q: want route %d %v, got %d %v", pkg, req.method, req.path, wantRoute, want, got, ps[1:])
+			}
+			if ps[0].Key != "before" {
+				t.Errorf("%s: %s %q: params were overwritten: %v", pkg, req.method, req.path, ps)
+			}
+			if m.match(req.method, req.path, nil) != got {
+				t.Errorf("%s: %s %q: wrong route without params", pkg, req.method, req.path)
+			}
+		}
+	}
+}
+
+// otherMethod returns another method than the given one.
+func otherMethod(method string) string {
+	if method == "GET" {
+		return "POST"
+	}
+	return "GET"
+}
+
+func TestGeneratedRoute(t *testing.T) {
+	if r := example.GetUser; r.Method() != "GET" || r.Path() != "/users/:id" || r.String() != "GET /users/:id" {
+		t.Errorf("wrong route %v", r)
+	}
+	if r := example.NoRoute; r.Method() != "" || r.Path() != "" || r.String() != "NoRoute" {
+		t.Errorf("wrong route %v", r)
+	}
+	// Derived names
+	if r := example.MSearchDevicesByType; r.Method() != "M-SEARCH" {
+		t.Errorf("wrong route %v", r)
+	}
+	if r := example.GetAPIV1JSON; r.Path() != "/api/v1.json" {
+		t.Errorf("wrong route %v", r)
+	}
+}
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := ParseRoutes(strings.NewReader("# comment\n\nGET /users/:id GetUser\n  DELETE\t/users/:id  \n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Route{{"GET", "/users/:id", "GetUser"}, {"DELETE", "/users/:id", ""}}
+	if !reflect.DeepEqual(routes, want) {
+		t.Errorf("wrong routes %v", routes)
+	}
+
+	_, err = ParseRoutes(strings.NewReader("GET /users\nGET\n"))
+	if err == nil || !strings.HasPrefix(err.Error(), "line 2: ") {
+		t.Errorf("wrong error %v", err)
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		pkg    string
+		routes []Route
+		err    string
+	}{
+		{"routes", nil, "no routes"},
+		{"my-routes", []Route{{"GET", "/", ""}}, `invalid package name "my-routes"`},
+		{"routes", []Route{{"", "/", ""}}, " /: method must not be empty"},
+		{"routes", []Route{{"GET", "users", ""}}, "GET users: path must begin with '/' in path 'users'"},
+		{"routes", []Route{{"GET", "/a/:b", ""}, {"GET", "/a/:c", ""}}, "GET /a/:c: "},
+		{"routes", []Route{{"GET", "/users/:id|int", ""}}, "GET /users/:id|int: constraints and transforms are not supported"},
+		{"routes", []Route{{"GET", "/users/user_:id", ""}}, "GET /users/user_:id: params must span a whole path element"},
+		{"routes", []Route{{"GET", "/", "index"}}, `GET /: invalid name "index"`},
+		{"routes", []Route{{"GET", "/", "Match"}}, `GET /: invalid name "Match"`},
+		{"routes", []Route{{"GET", "/users", ""}, {"GET", "/users/", ""}}, "GET /users/: name GetUsers is also used by GET /users"},
+	}
+	for _, tt := range tests {
+		_, err := Generate(tt.pkg, tt.routes)
+		if err == nil || !strings.HasPrefix(err.Error(), tt.err) {
+			t.Errorf("%v: wrong error: want %q, got %v", tt.routes, tt.err, err)
+		}
+	}
+}
+
+func TestDeriveName(t *testing.T) {
+	tests := []struct {
+		method, path, name string
+	}{
+		{"GET", "/", "Get"},
+		{"GET", "/users/:id", "GetUsersByID"},
+		{"DELETE", "/repos/:owner/:repo/git/refs/*ref", "DeleteReposByOwnerByRepoGitRefsRef"},
+		{"POST", "/users/:user_id/received_events", "PostUsersByUserIDReceivedEvents"},
+		{"M-SEARCH", "/api/v1.json", "MSearchAPIV1JSON"},
+	}
+	for _, tt := range tests {
+		elems, err := parseElems(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name := deriveName(tt.method, elems); name != tt.name {
+			t.Errorf("%s %s: wrong name: want %s, got %s", tt.method, tt.path, tt.name, name)
+		}
+	}
+}
+
+func BenchmarkGenerated(b *testing.B) {
+	reqs := bench.Requests(bench.GitHubAPI())
+	ps := make(drouter.Params, 0, 8)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, req := range reqs {
+			ps = ps[:0]
+			githubapi.Match(req.Method, req.URL.Path, &ps)
+		}
+	}
+}
+
+func BenchmarkTree(b *testing.B) {
+	trees := make(map[string]*drouter.Router[int])
+	for i, rt := range bench.GitHubAPI() {
+		if trees[rt.Method] == nil {
+			trees[rt.Method] = drouter.New[int]()
+		}
+		trees[rt.Method].AddRoute(rt.Path, i+1)
+	}
+	reqs := bench.Requests(bench.GitHubAPI())
+	ps := make(drouter.Params, 0, 8)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, req := range reqs {
+			ps = ps[:0]
+			trees[req.Method].Lookup(req.URL.Path, &ps)
+		}
+	}
+}
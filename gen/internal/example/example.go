@@ -0,0 +1,6 @@
+// Package example is a matcher generated for routes covering the pattern
+// syntax supported by drouter-gen, which is compared with drouter.Router in
+// its tests.
+package example
+
+//go:generate go run ../../../cmd/drouter-gen -pkg example -o routes_gen.go routes.txt
@@ -0,0 +1,300 @@
+// Code generated by drouter-gen. DO NOT EDIT.
+
+package example
+
+import (
+	"strings"
+
+	"github.com/thekhanj/drouter"
+)
+
+// Route identifies a route matched by Match.
+type Route int
+
+const (
+	NoRoute              Route = iota
+	Index                      // GET /
+	ListUsers                  // GET /users
+	ListUsersSlash             // GET /users/
+	GetUser                    // GET /users/:id
+	GetPost                    // GET /users/:id/posts/:post
+	PostUsers                  // POST /users
+	DeleteUsersByID            // DELETE /users/:id
+	Files                      // GET /files/*filepath
+	Assets                     // GET /static/assets/*filepath
+	Upload                     // PUT /uploads/:bucket/*key
+	GetAPIV1JSON               // GET /api/v1.json
+	GetAPIV1Users              // GET /api/v1/users
+	MSearchDevicesByType       // M-SEARCH /devices/:type
+)
+
+var routeMethods = [...]string{
+	Index:                "GET",
+	ListUsers:            "GET",
+	ListUsersSlash:       "GET",
+	GetUser:              "GET",
+	GetPost:              "GET",
+	PostUsers:            "POST",
+	DeleteUsersByID:      "DELETE",
+	Files:                "GET",
+	Assets:               "GET",
+	Upload:               "PUT",
+	GetAPIV1JSON:         "GET",
+	GetAPIV1Users:        "GET",
+	MSearchDevicesByType: "M-SEARCH",
+}
+
+var routePaths = [...]string{
+	Index:                "/",
+	ListUsers:            "/users",
+	ListUsersSlash:       "/users/",
+	GetUser:              "/users/:id",
+	GetPost:              "/users/:id/posts/:post",
+	PostUsers:            "/users",
+	DeleteUsersByID:      "/users/:id",
+	Files:                "/files/*filepath",
+	Assets:               "/static/assets/*filepath",
+	Upload:               "/uploads/:bucket/*key",
+	GetAPIV1JSON:         "/api/v1.json",
+	GetAPIV1Users:        "/api/v1/users",
+	MSearchDevicesByType: "/devices/:type",
+}
+
+// Method returns the method of the route, or an empty string for NoRoute.
+func (r Route) Method() string { return routeMethods[r] }
+
+// Path returns the path pattern of the route, or an empty string for NoRoute.
+func (r Route) Path() string { return routePaths[r] }
+
+func (r Route) String() string {
+	if r == NoRoute {
+		return "NoRoute"
+	}
+	return routeMethods[r] + " " + routePaths[r]
+}
+
+// Match returns the route matching the method and path and appends the
+// values of its params to ps, if not nil, or returns NoRoute.
+func Match(method, path string, ps *drouter.Params) Route {
+	if path == "" || path[0] != '/' {
+		return NoRoute
+	}
+	n := 0
+	if ps != nil {
+		n = len(*ps)
+	}
+	r := NoRoute
+	switch method {
+	case "DELETE":
+		r = match0(path, ps)
+	case "GET":
+		r = match1(path, ps)
+	case "M-SEARCH":
+		r = match2(path, ps)
+	case "POST":
+		r = match3(path, ps)
+	case "PUT":
+		r = match4(path, ps)
+	}
+	if r == NoRoute && ps != nil {
+		*ps = (*ps)[:n]
+	}
+	return r
+}
+
+// segment returns the path element starting at start and its end.
+func segment(path string, start int) (string, int) {
+	if i := strings.IndexByte(path[start:], '/'); i >= 0 {
+		return path[start : start+i], start + i
+	}
+	return path[start:], len(path)
+}
+
+func match0(path string, ps *drouter.Params) Route { // DELETE
+	s0, e0 := segment(path, 1)
+	switch s0 {
+	case "users":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "id", Value: s1})
+				}
+				if e1 == len(path) {
+					return DeleteUsersByID
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	}
+	return NoRoute
+}
+
+func match1(path string, ps *drouter.Params) Route { // GET
+	s0, e0 := segment(path, 1)
+	switch s0 {
+	case "":
+		if e0 == len(path) {
+			return Index
+		}
+		return NoRoute
+	case "api":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			switch s1 {
+			case "v1":
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					switch s2 {
+					case "users":
+						if e2 == len(path) {
+							return GetAPIV1Users
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			case "v1.json":
+				if e1 == len(path) {
+					return GetAPIV1JSON
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "files":
+		if e0 < len(path) {
+			if ps != nil {
+				*ps = append(*ps, drouter.Param{Key: "filepath", Value: path[e0:]})
+			}
+			return Files
+		}
+		return NoRoute
+	case "static":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			switch s1 {
+			case "assets":
+				if e1 < len(path) {
+					if ps != nil {
+						*ps = append(*ps, drouter.Param{Key: "filepath", Value: path[e1:]})
+					}
+					return Assets
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "users":
+		if e0 == len(path) {
+			return ListUsers
+		}
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			switch s1 {
+			case "":
+				if e1 == len(path) {
+					return ListUsersSlash
+				}
+				return NoRoute
+			}
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "id", Value: s1})
+				}
+				if e1 == len(path) {
+					return GetUser
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					switch s2 {
+					case "posts":
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "post", Value: s3})
+								}
+								if e3 == len(path) {
+									return GetPost
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	}
+	return NoRoute
+}
+
+func match2(path string, ps *drouter.Params) Route { // M-SEARCH
+	s0, e0 := segment(path, 1)
+	switch s0 {
+	case "devices":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "type", Value: s1})
+				}
+				if e1 == len(path) {
+					return MSearchDevicesByType
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	}
+	return NoRoute
+}
+
+func match3(path string, ps *drouter.Params) Route { // POST
+	s0, e0 := segment(path, 1)
+	switch s0 {
+	case "users":
+		if e0 == len(path) {
+			return PostUsers
+		}
+		return NoRoute
+	}
+	return NoRoute
+}
+
+func match4(path string, ps *drouter.Params) Route { // PUT
+	s0, e0 := segment(path, 1)
+	switch s0 {
+	case "uploads":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "bucket", Value: s1})
+				}
+				if e1 < len(path) {
+					if ps != nil {
+						*ps = append(*ps, drouter.Param{Key: "key", Value: path[e1:]})
+					}
+					return Upload
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	}
+	return NoRoute
+}
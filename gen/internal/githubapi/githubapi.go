@@ -0,0 +1,5 @@
+// Package githubapi is a matcher generated for the routes of the GitHub API,
+// which is compared with drouter.Router in its tests.
+package githubapi
+
+//go:generate go run ../../../cmd/drouter-gen -pkg githubapi -o routes_gen.go routes.txt
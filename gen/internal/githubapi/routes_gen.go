@@ -0,0 +1,3106 @@
+// Code generated by drouter-gen. DO NOT EDIT.
+
+package githubapi
+
+import (
+	"strings"
+
+	"github.com/thekhanj/drouter"
+)
+
+// Route identifies a route matched by Match.
+type Route int
+
+const (
+	NoRoute                                                  Route = iota
+	GetAuthorizations                                              // GET /authorizations
+	GetAuthorizationsByID                                          // GET /authorizations/:id
+	PostAuthorizations                                             // POST /authorizations
+	DeleteAuthorizationsByID                                       // DELETE /authorizations/:id
+	GetApplicationsByClientIDTokensByAccessToken                   // GET /applications/:client_id/tokens/:access_token
+	DeleteApplicationsByClientIDTokens                             // DELETE /applications/:client_id/tokens
+	DeleteApplicationsByClientIDTokensByAccessToken                // DELETE /applications/:client_id/tokens/:access_token
+	GetEvents                                                      // GET /events
+	GetReposByOwnerByRepoEvents                                    // GET /repos/:owner/:repo/events
+	GetNetworksByOwnerByRepoEvents                                 // GET /networks/:owner/:repo/events
+	GetOrgsByOrgEvents                                             // GET /orgs/:org/events
+	GetUsersByUserReceivedEvents                                   // GET /users/:user/received_events
+	GetUsersByUserReceivedEventsPublic                             // GET /users/:user/received_events/public
+	GetUsersByUserEvents                                           // GET /users/:user/events
+	GetUsersByUserEventsPublic                                     // GET /users/:user/events/public
+	GetUsersByUserEventsOrgsByOrg                                  // GET /users/:user/events/orgs/:org
+	GetFeeds                                                       // GET /feeds
+	GetNotifications                                               // GET /notifications
+	GetReposByOwnerByRepoNotifications                             // GET /repos/:owner/:repo/notifications
+	PutNotifications                                               // PUT /notifications
+	PutReposByOwnerByRepoNotifications                             // PUT /repos/:owner/:repo/notifications
+	GetNotificationsThreadsByID                                    // GET /notifications/threads/:id
+	GetNotificationsThreadsByIDSubscription                        // GET /notifications/threads/:id/subscription
+	PutNotificationsThreadsByIDSubscription                        // PUT /notifications/threads/:id/subscription
+	DeleteNotificationsThreadsByIDSubscription                     // DELETE /notifications/threads/:id/subscription
+	GetReposByOwnerByRepoStargazers                                // GET /repos/:owner/:repo/stargazers
+	GetUsersByUserStarred                                          // GET /users/:user/starred
+	GetUserStarred                                                 // GET /user/starred
+	GetUserStarredByOwnerByRepo                                    // GET /user/starred/:owner/:repo
+	PutUserStarredByOwnerByRepo                                    // PUT /user/starred/:owner/:repo
+	DeleteUserStarredByOwnerByRepo                                 // DELETE /user/starred/:owner/:repo
+	GetReposByOwnerByRepoSubscribers                               // GET /repos/:owner/:repo/subscribers
+	GetUsersByUserSubscriptions                                    // GET /users/:user/subscriptions
+	GetUserSubscriptions                                           // GET /user/subscriptions
+	GetReposByOwnerByRepoSubscription                              // GET /repos/:owner/:repo/subscription
+	PutReposByOwnerByRepoSubscription                              // PUT /repos/:owner/:repo/subscription
+	DeleteReposByOwnerByRepoSubscription                           // DELETE /repos/:owner/:repo/subscription
+	GetUserSubscriptionsByOwnerByRepo                              // GET /user/subscriptions/:owner/:repo
+	PutUserSubscriptionsByOwnerByRepo                              // PUT /user/subscriptions/:owner/:repo
+	DeleteUserSubscriptionsByOwnerByRepo                           // DELETE /user/subscriptions/:owner/:repo
+	GetUsersByUserGists                                            // GET /users/:user/gists
+	GetGists                                                       // GET /gists
+	GetGistsByID                                                   // GET /gists/:id
+	PostGists                                                      // POST /gists
+	PutGistsByIDStar                                               // PUT /gists/:id/star
+	DeleteGistsByIDStar                                            // DELETE /gists/:id/star
+	GetGistsByIDStar                                               // GET /gists/:id/star
+	PostGistsByIDForks                                             // POST /gists/:id/forks
+	DeleteGistsByID                                                // DELETE /gists/:id
+	GetReposByOwnerByRepoGitBlobsBySha                             // GET /repos/:owner/:repo/git/blobs/:sha
+	PostReposByOwnerByRepoGitBlobs                                 // POST /repos/:owner/:repo/git/blobs
+	GetReposByOwnerByRepoGitCommitsBySha                           // GET /repos/:owner/:repo/git/commits/:sha
+	PostReposByOwnerByRepoGitCommits                               // POST /repos/:owner/:repo/git/commits
+	GetReposByOwnerByRepoGitRefs                                   // GET /repos/:owner/:repo/git/refs
+	PostReposByOwnerByRepoGitRefs                                  // POST /repos/:owner/:repo/git/refs
+	GetReposByOwnerByRepoGitTagsBySha                              // GET /repos/:owner/:repo/git/tags/:sha
+	PostReposByOwnerByRepoGitTags                                  // POST /repos/:owner/:repo/git/tags
+	GetReposByOwnerByRepoGitTreesBySha                             // GET /repos/:owner/:repo/git/trees/:sha
+	PostReposByOwnerByRepoGitTrees                                 // POST /repos/:owner/:repo/git/trees
+	GetIssues                                                      // GET /issues
+	GetUserIssues                                                  // GET /user/issues
+	GetOrgsByOrgIssues                                             // GET /orgs/:org/issues
+	GetReposByOwnerByRepoIssues                                    // GET /repos/:owner/:repo/issues
+	GetReposByOwnerByRepoIssuesByNumber                            // GET /repos/:owner/:repo/issues/:number
+	PostReposByOwnerByRepoIssues                                   // POST /repos/:owner/:repo/issues
+	GetReposByOwnerByRepoAssignees                                 // GET /repos/:owner/:repo/assignees
+	GetReposByOwnerByRepoAssigneesByAssignee                       // GET /repos/:owner/:repo/assignees/:assignee
+	GetReposByOwnerByRepoIssuesByNumberComments                    // GET /repos/:owner/:repo/issues/:number/comments
+	PostReposByOwnerByRepoIssuesByNumberComments                   // POST /repos/:owner/:repo/issues/:number/comments
+	GetReposByOwnerByRepoIssuesByNumberEvents                      // GET /repos/:owner/:repo/issues/:number/events
+	GetReposByOwnerByRepoLabels                                    // GET /repos/:owner/:repo/labels
+	GetReposByOwnerByRepoLabelsByName                              // GET /repos/:owner/:repo/labels/:name
+	PostReposByOwnerByRepoLabels                                   // POST /repos/:owner/:repo/labels
+	DeleteReposByOwnerByRepoLabelsByName                           // DELETE /repos/:owner/:repo/labels/:name
+	GetReposByOwnerByRepoIssuesByNumberLabels                      // GET /repos/:owner/:repo/issues/:number/labels
+	PostReposByOwnerByRepoIssuesByNumberLabels                     // POST /repos/:owner/:repo/issues/:number/labels
+	DeleteReposByOwnerByRepoIssuesByNumberLabelsByName             // DELETE /repos/:owner/:repo/issues/:number/labels/:name
+	PutReposByOwnerByRepoIssuesByNumberLabels                      // PUT /repos/:owner/:repo/issues/:number/labels
+	DeleteReposByOwnerByRepoIssuesByNumberLabels                   // DELETE /repos/:owner/:repo/issues/:number/labels
+	GetReposByOwnerByRepoMilestonesByNumberLabels                  // GET /repos/:owner/:repo/milestones/:number/labels
+	GetReposByOwnerByRepoMilestones                                // GET /repos/:owner/:repo/milestones
+	GetReposByOwnerByRepoMilestonesByNumber                        // GET /repos/:owner/:repo/milestones/:number
+	PostReposByOwnerByRepoMilestones                               // POST /repos/:owner/:repo/milestones
+	DeleteReposByOwnerByRepoMilestonesByNumber                     // DELETE /repos/:owner/:repo/milestones/:number
+	GetEmojis                                                      // GET /emojis
+	GetGitignoreTemplates                                          // GET /gitignore/templates
+	GetGitignoreTemplatesByName                                    // GET /gitignore/templates/:name
+	PostMarkdown                                                   // POST /markdown
+	PostMarkdownRaw                                                // POST /markdown/raw
+	GetMeta                                                        // GET /meta
+	GetRateLimit                                                   // GET /rate_limit
+	GetUsersByUserOrgs                                             // GET /users/:user/orgs
+	GetUserOrgs                                                    // GET /user/orgs
+	GetOrgsByOrg                                                   // GET /orgs/:org
+	GetOrgsByOrgMembers                                            // GET /orgs/:org/members
+	GetOrgsByOrgMembersByUser                                      // GET /orgs/:org/members/:user
+	DeleteOrgsByOrgMembersByUser                                   // DELETE /orgs/:org/members/:user
+	GetOrgsByOrgPublicMembers                                      // GET /orgs/:org/public_members
+	GetOrgsByOrgPublicMembersByUser                                // GET /orgs/:org/public_members/:user
+	PutOrgsByOrgPublicMembersByUser                                // PUT /orgs/:org/public_members/:user
+	DeleteOrgsByOrgPublicMembersByUser                             // DELETE /orgs/:org/public_members/:user
+	GetOrgsByOrgTeams                                              // GET /orgs/:org/teams
+	GetTeamsByID                                                   // GET /teams/:id
+	PostOrgsByOrgTeams                                             // POST /orgs/:org/teams
+	DeleteTeamsByID                                                // DELETE /teams/:id
+	GetTeamsByIDMembers                                            // GET /teams/:id/members
+	GetTeamsByIDMembersByUser                                      // GET /teams/:id/members/:user
+	PutTeamsByIDMembersByUser                                      // PUT /teams/:id/members/:user
+	DeleteTeamsByIDMembersByUser                                   // DELETE /teams/:id/members/:user
+	GetTeamsByIDRepos                                              // GET /teams/:id/repos
+	GetTeamsByIDReposByOwnerByRepo                                 // GET /teams/:id/repos/:owner/:repo
+	PutTeamsByIDReposByOwnerByRepo                                 // PUT /teams/:id/repos/:owner/:repo
+	DeleteTeamsByIDReposByOwnerByRepo                              // DELETE /teams/:id/repos/:owner/:repo
+	GetUserTeams                                                   // GET /user/teams
+	GetReposByOwnerByRepoPulls                                     // GET /repos/:owner/:repo/pulls
+	GetReposByOwnerByRepoPullsByNumber                             // GET /repos/:owner/:repo/pulls/:number
+	PostReposByOwnerByRepoPulls                                    // POST /repos/:owner/:repo/pulls
+	GetReposByOwnerByRepoPullsByNumberCommits                      // GET /repos/:owner/:repo/pulls/:number/commits
+	GetReposByOwnerByRepoPullsByNumberFiles                        // GET /repos/:owner/:repo/pulls/:number/files
+	GetReposByOwnerByRepoPullsByNumberMerge                        // GET /repos/:owner/:repo/pulls/:number/merge
+	PutReposByOwnerByRepoPullsByNumberMerge                        // PUT /repos/:owner/:repo/pulls/:number/merge
+	GetReposByOwnerByRepoPullsByNumberComments                     // GET /repos/:owner/:repo/pulls/:number/comments
+	PutReposByOwnerByRepoPullsByNumberComments                     // PUT /repos/:owner/:repo/pulls/:number/comments
+	GetUserRepos                                                   // GET /user/repos
+	GetUsersByUserRepos                                            // GET /users/:user/repos
+	GetOrgsByOrgRepos                                              // GET /orgs/:org/repos
+	GetRepositories                                                // GET /repositories
+	PostUserRepos                                                  // POST /user/repos
+	PostOrgsByOrgRepos                                             // POST /orgs/:org/repos
+	GetReposByOwnerByRepo                                          // GET /repos/:owner/:repo
+	DeleteReposByOwnerByRepo                                       // DELETE /repos/:owner/:repo
+	GetReposByOwnerByRepoContributors                              // GET /repos/:owner/:repo/contributors
+	GetReposByOwnerByRepoLanguages                                 // GET /repos/:owner/:repo/languages
+	GetReposByOwnerByRepoTeams                                     // GET /repos/:owner/:repo/teams
+	GetReposByOwnerByRepoTags                                      // GET /repos/:owner/:repo/tags
+	GetReposByOwnerByRepoBranches                                  // GET /repos/:owner/:repo/branches
+	GetReposByOwnerByRepoBranchesByBranch                          // GET /repos/:owner/:repo/branches/:branch
+	GetReposByOwnerByRepoCollaborators                             // GET /repos/:owner/:repo/collaborators
+	GetReposByOwnerByRepoCollaboratorsByUser                       // GET /repos/:owner/:repo/collaborators/:user
+	PutReposByOwnerByRepoCollaboratorsByUser                       // PUT /repos/:owner/:repo/collaborators/:user
+	DeleteReposByOwnerByRepoCollaboratorsByUser                    // DELETE /repos/:owner/:repo/collaborators/:user
+	GetReposByOwnerByRepoComments                                  // GET /repos/:owner/:repo/comments
+	GetReposByOwnerByRepoCommitsByShaComments                      // GET /repos/:owner/:repo/commits/:sha/comments
+	PostReposByOwnerByRepoCommitsByShaComments                     // POST /repos/:owner/:repo/commits/:sha/comments
+	GetReposByOwnerByRepoCommentsByID                              // GET /repos/:owner/:repo/comments/:id
+	DeleteReposByOwnerByRepoCommentsByID                           // DELETE /repos/:owner/:repo/comments/:id
+	GetReposByOwnerByRepoCommits                                   // GET /repos/:owner/:repo/commits
+	GetReposByOwnerByRepoCommitsBySha                              // GET /repos/:owner/:repo/commits/:sha
+	GetReposByOwnerByRepoReadme                                    // GET /repos/:owner/:repo/readme
+	GetReposByOwnerByRepoKeys                                      // GET /repos/:owner/:repo/keys
+	GetReposByOwnerByRepoKeysByID                                  // GET /repos/:owner/:repo/keys/:id
+	PostReposByOwnerByRepoKeys                                     // POST /repos/:owner/:repo/keys
+	DeleteReposByOwnerByRepoKeysByID                               // DELETE /repos/:owner/:repo/keys/:id
+	GetReposByOwnerByRepoDownloads                                 // GET /repos/:owner/:repo/downloads
+	GetReposByOwnerByRepoDownloadsByID                             // GET /repos/:owner/:repo/downloads/:id
+	DeleteReposByOwnerByRepoDownloadsByID                          // DELETE /repos/:owner/:repo/downloads/:id
+	GetReposByOwnerByRepoForks                                     // GET /repos/:owner/:repo/forks
+	PostReposByOwnerByRepoForks                                    // POST /repos/:owner/:repo/forks
+	GetReposByOwnerByRepoHooks                                     // GET /repos/:owner/:repo/hooks
+	GetReposByOwnerByRepoHooksByID                                 // GET /repos/:owner/:repo/hooks/:id
+	PostReposByOwnerByRepoHooks                                    // POST /repos/:owner/:repo/hooks
+	PostReposByOwnerByRepoHooksByIDTests                           // POST /repos/:owner/:repo/hooks/:id/tests
+	DeleteReposByOwnerByRepoHooksByID                              // DELETE /repos/:owner/:repo/hooks/:id
+	PostReposByOwnerByRepoMerges                                   // POST /repos/:owner/:repo/merges
+	GetReposByOwnerByRepoReleases                                  // GET /repos/:owner/:repo/releases
+	GetReposByOwnerByRepoReleasesByID                              // GET /repos/:owner/:repo/releases/:id
+	PostReposByOwnerByRepoReleases                                 // POST /repos/:owner/:repo/releases
+	DeleteReposByOwnerByRepoReleasesByID                           // DELETE /repos/:owner/:repo/releases/:id
+	GetReposByOwnerByRepoReleasesByIDAssets                        // GET /repos/:owner/:repo/releases/:id/assets
+	GetReposByOwnerByRepoStatsContributors                         // GET /repos/:owner/:repo/stats/contributors
+	GetReposByOwnerByRepoStatsCommitActivity                       // GET /repos/:owner/:repo/stats/commit_activity
+	GetReposByOwnerByRepoStatsCodeFrequency                        // GET /repos/:owner/:repo/stats/code_frequency
+	GetReposByOwnerByRepoStatsParticipation                        // GET /repos/:owner/:repo/stats/participation
+	GetReposByOwnerByRepoStatsPunchCard                            // GET /repos/:owner/:repo/stats/punch_card
+	GetReposByOwnerByRepoStatusesByRef                             // GET /repos/:owner/:repo/statuses/:ref
+	PostReposByOwnerByRepoStatusesByRef                            // POST /repos/:owner/:repo/statuses/:ref
+	GetSearchRepositories                                          // GET /search/repositories
+	GetSearchCode                                                  // GET /search/code
+	GetSearchIssues                                                // GET /search/issues
+	GetSearchUsers                                                 // GET /search/users
+	GetLegacyIssuesSearchByOwnerByRepositoryByStateByKeyword       // GET /legacy/issues/search/:owner/:repository/:state/:keyword
+	GetLegacyReposSearchByKeyword                                  // GET /legacy/repos/search/:keyword
+	GetLegacyUserSearchByKeyword                                   // GET /legacy/user/search/:keyword
+	GetLegacyUserEmailByEmail                                      // GET /legacy/user/email/:email
+	GetUsersByUser                                                 // GET /users/:user
+	GetUser                                                        // GET /user
+	GetUsers                                                       // GET /users
+	GetUserEmails                                                  // GET /user/emails
+	PostUserEmails                                                 // POST /user/emails
+	DeleteUserEmails                                               // DELETE /user/emails
+	GetUsersByUserFollowers                                        // GET /users/:user/followers
+	GetUserFollowers                                               // GET /user/followers
+	GetUsersByUserFollowing                                        // GET /users/:user/following
+	GetUserFollowing                                               // GET /user/following
+	GetUserFollowingByUser                                         // GET /user/following/:user
+	GetUsersByUserFollowingByTargetUser                            // GET /users/:user/following/:target_user
+	PutUserFollowingByUser                                         // PUT /user/following/:user
+	DeleteUserFollowingByUser                                      // DELETE /user/following/:user
+	GetUsersByUserKeys                                             // GET /users/:user/keys
+	GetUserKeys                                                    // GET /user/keys
+	GetUserKeysByID                                                // GET /user/keys/:id
+	PostUserKeys                                                   // POST /user/keys
+	DeleteUserKeysByID                                             // DELETE /user/keys/:id
+)
+
+var routeMethods = [...]string{
+	GetAuthorizations:                               "GET",
+	GetAuthorizationsByID:                           "GET",
+	PostAuthorizations:                              "POST",
+	DeleteAuthorizationsByID:                        "DELETE",
+	GetApplicationsByClientIDTokensByAccessToken:    "GET",
+	DeleteApplicationsByClientIDTokens:              "DELETE",
+	DeleteApplicationsByClientIDTokensByAccessToken: "DELETE",
+	GetEvents:                                          "GET",
+	GetReposByOwnerByRepoEvents:                        "GET",
+	GetNetworksByOwnerByRepoEvents:                     "GET",
+	GetOrgsByOrgEvents:                                 "GET",
+	GetUsersByUserReceivedEvents:                       "GET",
+	GetUsersByUserReceivedEventsPublic:                 "GET",
+	GetUsersByUserEvents:                               "GET",
+	GetUsersByUserEventsPublic:                         "GET",
+	GetUsersByUserEventsOrgsByOrg:                      "GET",
+	GetFeeds:                                           "GET",
+	GetNotifications:                                   "GET",
+	GetReposByOwnerByRepoNotifications:                 "GET",
+	PutNotifications:                                   "PUT",
+	PutReposByOwnerByRepoNotifications:                 "PUT",
+	GetNotificationsThreadsByID:                        "GET",
+	GetNotificationsThreadsByIDSubscription:            "GET",
+	PutNotificationsThreadsByIDSubscription:            "PUT",
+	DeleteNotificationsThreadsByIDSubscription:         "DELETE",
+	GetReposByOwnerByRepoStargazers:                    "GET",
+	GetUsersByUserStarred:                              "GET",
+	GetUserStarred:                                     "GET",
+	GetUserStarredByOwnerByRepo:                        "GET",
+	PutUserStarredByOwnerByRepo:                        "PUT",
+	DeleteUserStarredByOwnerByRepo:                     "DELETE",
+	GetReposByOwnerByRepoSubscribers:                   "GET",
+	GetUsersByUserSubscriptions:                        "GET",
+	GetUserSubscriptions:                               "GET",
+	GetReposByOwnerByRepoSubscription:                  "GET",
+	PutReposByOwnerByRepoSubscription:                  "PUT",
+	DeleteReposByOwnerByRepoSubscription:               "DELETE",
+	GetUserSubscriptionsByOwnerByRepo:                  "GET",
+	PutUserSubscriptionsByOwnerByRepo:                  "PUT",
+	DeleteUserSubscriptionsByOwnerByRepo:               "DELETE",
+	GetUsersByUserGists:                                "GET",
+	GetGists:                                           "GET",
+	GetGistsByID:                                       "GET",
+	PostGists:                                          "POST",
+	PutGistsByIDStar:                                   "PUT",
+	DeleteGistsByIDStar:                                "DELETE",
+	GetGistsByIDStar:                                   "GET",
+	PostGistsByIDForks:                                 "POST",
+	DeleteGistsByID:                                    "DELETE",
+	GetReposByOwnerByRepoGitBlobsBySha:                 "GET",
+	PostReposByOwnerByRepoGitBlobs:                     "POST",
+	GetReposByOwnerByRepoGitCommitsBySha:               "GET",
+	PostReposByOwnerByRepoGitCommits:                   "POST",
+	GetReposByOwnerByRepoGitRefs:                       "GET",
+	PostReposByOwnerByRepoGitRefs:                      "POST",
+	GetReposByOwnerByRepoGitTagsBySha:                  "GET",
+	PostReposByOwnerByRepoGitTags:                      "POST",
+	GetReposByOwnerByRepoGitTreesBySha:                 "GET",
+	PostReposByOwnerByRepoGitTrees:                     "POST",
+	GetIssues:                                          "GET",
+	GetUserIssues:                                      "GET",
+	GetOrgsByOrgIssues:                                 "GET",
+	GetReposByOwnerByRepoIssues:                        "GET",
+	GetReposByOwnerByRepoIssuesByNumber:                "GET",
+	PostReposByOwnerByRepoIssues:                       "POST",
+	GetReposByOwnerByRepoAssignees:                     "GET",
+	GetReposByOwnerByRepoAssigneesByAssignee:           "GET",
+	GetReposByOwnerByRepoIssuesByNumberComments:        "GET",
+	PostReposByOwnerByRepoIssuesByNumberComments:       "POST",
+	GetReposByOwnerByRepoIssuesByNumberEvents:          "GET",
+	GetReposByOwnerByRepoLabels:                        "GET",
+	GetReposByOwnerByRepoLabelsByName:                  "GET",
+	PostReposByOwnerByRepoLabels:                       "POST",
+	DeleteReposByOwnerByRepoLabelsByName:               "DELETE",
+	GetReposByOwnerByRepoIssuesByNumberLabels:          "GET",
+	PostReposByOwnerByRepoIssuesByNumberLabels:         "POST",
+	DeleteReposByOwnerByRepoIssuesByNumberLabelsByName: "DELETE",
+	PutReposByOwnerByRepoIssuesByNumberLabels:          "PUT",
+	DeleteReposByOwnerByRepoIssuesByNumberLabels:       "DELETE",
+	GetReposByOwnerByRepoMilestonesByNumberLabels:      "GET",
+	GetReposByOwnerByRepoMilestones:                    "GET",
+	GetReposByOwnerByRepoMilestonesByNumber:            "GET",
+	PostReposByOwnerByRepoMilestones:                   "POST",
+	DeleteReposByOwnerByRepoMilestonesByNumber:         "DELETE",
+	GetEmojis:                                                "GET",
+	GetGitignoreTemplates:                                    "GET",
+	GetGitignoreTemplatesByName:                              "GET",
+	PostMarkdown:                                             "POST",
+	PostMarkdownRaw:                                          "POST",
+	GetMeta:                                                  "GET",
+	GetRateLimit:                                             "GET",
+	GetUsersByUserOrgs:                                       "GET",
+	GetUserOrgs:                                              "GET",
+	GetOrgsByOrg:                                             "GET",
+	GetOrgsByOrgMembers:                                      "GET",
+	GetOrgsByOrgMembersByUser:                                "GET",
+	DeleteOrgsByOrgMembersByUser:                             "DELETE",
+	GetOrgsByOrgPublicMembers:                                "GET",
+	GetOrgsByOrgPublicMembersByUser:                          "GET",
+	PutOrgsByOrgPublicMembersByUser:                          "PUT",
+	DeleteOrgsByOrgPublicMembersByUser:                       "DELETE",
+	GetOrgsByOrgTeams:                                        "GET",
+	GetTeamsByID:                                             "GET",
+	PostOrgsByOrgTeams:                                       "POST",
+	DeleteTeamsByID:                                          "DELETE",
+	GetTeamsByIDMembers:                                      "GET",
+	GetTeamsByIDMembersByUser:                                "GET",
+	PutTeamsByIDMembersByUser:                                "PUT",
+	DeleteTeamsByIDMembersByUser:                             "DELETE",
+	GetTeamsByIDRepos:                                        "GET",
+	GetTeamsByIDReposByOwnerByRepo:                           "GET",
+	PutTeamsByIDReposByOwnerByRepo:                           "PUT",
+	DeleteTeamsByIDReposByOwnerByRepo:                        "DELETE",
+	GetUserTeams:                                             "GET",
+	GetReposByOwnerByRepoPulls:                               "GET",
+	GetReposByOwnerByRepoPullsByNumber:                       "GET",
+	PostReposByOwnerByRepoPulls:                              "POST",
+	GetReposByOwnerByRepoPullsByNumberCommits:                "GET",
+	GetReposByOwnerByRepoPullsByNumberFiles:                  "GET",
+	GetReposByOwnerByRepoPullsByNumberMerge:                  "GET",
+	PutReposByOwnerByRepoPullsByNumberMerge:                  "PUT",
+	GetReposByOwnerByRepoPullsByNumberComments:               "GET",
+	PutReposByOwnerByRepoPullsByNumberComments:               "PUT",
+	GetUserRepos:                                             "GET",
+	GetUsersByUserRepos:                                      "GET",
+	GetOrgsByOrgRepos:                                        "GET",
+	GetRepositories:                                          "GET",
+	PostUserRepos:                                            "POST",
+	PostOrgsByOrgRepos:                                       "POST",
+	GetReposByOwnerByRepo:                                    "GET",
+	DeleteReposByOwnerByRepo:                                 "DELETE",
+	GetReposByOwnerByRepoContributors:                        "GET",
+	GetReposByOwnerByRepoLanguages:                           "GET",
+	GetReposByOwnerByRepoTeams:                               "GET",
+	GetReposByOwnerByRepoTags:                                "GET",
+	GetReposByOwnerByRepoBranches:                            "GET",
+	GetReposByOwnerByRepoBranchesByBranch:                    "GET",
+	GetReposByOwnerByRepoCollaborators:                       "GET",
+	GetReposByOwnerByRepoCollaboratorsByUser:                 "GET",
+	PutReposByOwnerByRepoCollaboratorsByUser:                 "PUT",
+	DeleteReposByOwnerByRepoCollaboratorsByUser:              "DELETE",
+	GetReposByOwnerByRepoComments:                            "GET",
+	GetReposByOwnerByRepoCommitsByShaComments:                "GET",
+	PostReposByOwnerByRepoCommitsByShaComments:               "POST",
+	GetReposByOwnerByRepoCommentsByID:                        "GET",
+	DeleteReposByOwnerByRepoCommentsByID:                     "DELETE",
+	GetReposByOwnerByRepoCommits:                             "GET",
+	GetReposByOwnerByRepoCommitsBySha:                        "GET",
+	GetReposByOwnerByRepoReadme:                              "GET",
+	GetReposByOwnerByRepoKeys:                                "GET",
+	GetReposByOwnerByRepoKeysByID:                            "GET",
+	PostReposByOwnerByRepoKeys:                               "POST",
+	DeleteReposByOwnerByRepoKeysByID:                         "DELETE",
+	GetReposByOwnerByRepoDownloads:                           "GET",
+	GetReposByOwnerByRepoDownloadsByID:                       "GET",
+	DeleteReposByOwnerByRepoDownloadsByID:                    "DELETE",
+	GetReposByOwnerByRepoForks:                               "GET",
+	PostReposByOwnerByRepoForks:                              "POST",
+	GetReposByOwnerByRepoHooks:                               "GET",
+	GetReposByOwnerByRepoHooksByID:                           "GET",
+	PostReposByOwnerByRepoHooks:                              "POST",
+	PostReposByOwnerByRepoHooksByIDTests:                     "POST",
+	DeleteReposByOwnerByRepoHooksByID:                        "DELETE",
+	PostReposByOwnerByRepoMerges:                             "POST",
+	GetReposByOwnerByRepoReleases:                            "GET",
+	GetReposByOwnerByRepoReleasesByID:                        "GET",
+	PostReposByOwnerByRepoReleases:                           "POST",
+	DeleteReposByOwnerByRepoReleasesByID:                     "DELETE",
+	GetReposByOwnerByRepoReleasesByIDAssets:                  "GET",
+	GetReposByOwnerByRepoStatsContributors:                   "GET",
+	GetReposByOwnerByRepoStatsCommitActivity:                 "GET",
+	GetReposByOwnerByRepoStatsCodeFrequency:                  "GET",
+	GetReposByOwnerByRepoStatsParticipation:                  "GET",
+	GetReposByOwnerByRepoStatsPunchCard:                      "GET",
+	GetReposByOwnerByRepoStatusesByRef:                       "GET",
+	PostReposByOwnerByRepoStatusesByRef:                      "POST",
+	GetSearchRepositories:                                    "GET",
+	GetSearchCode:                                            "GET",
+	GetSearchIssues:                                          "GET",
+	GetSearchUsers:                                           "GET",
+	GetLegacyIssuesSearchByOwnerByRepositoryByStateByKeyword: "GET",
+	GetLegacyReposSearchByKeyword:                            "GET",
+	GetLegacyUserSearchByKeyword:                             "GET",
+	GetLegacyUserEmailByEmail:                                "GET",
+	GetUsersByUser:                                           "GET",
+	GetUser:                                                  "GET",
+	GetUsers:                                                 "GET",
+	GetUserEmails:                                            "GET",
+	PostUserEmails:                                           "POST",
+	DeleteUserEmails:                                         "DELETE",
+	GetUsersByUserFollowers:                                  "GET",
+	GetUserFollowers:                                         "GET",
+	GetUsersByUserFollowing:                                  "GET",
+	GetUserFollowing:                                         "GET",
+	GetUserFollowingByUser:                                   "GET",
+	GetUsersByUserFollowingByTargetUser:                      "GET",
+	PutUserFollowingByUser:                                   "PUT",
+	DeleteUserFollowingByUser:                                "DELETE",
+	GetUsersByUserKeys:                                       "GET",
+	GetUserKeys:                                              "GET",
+	GetUserKeysByID:                                          "GET",
+	PostUserKeys:                                             "POST",
+	DeleteUserKeysByID:                                       "DELETE",
+}
+
+var routePaths = [...]string{
+	GetAuthorizations:                               "/authorizations",
+	GetAuthorizationsByID:                           "/authorizations/:id",
+	PostAuthorizations:                              "/authorizations",
+	DeleteAuthorizationsByID:                        "/authorizations/:id",
+	GetApplicationsByClientIDTokensByAccessToken:    "/applications/:client_id/tokens/:access_token",
+	DeleteApplicationsByClientIDTokens:              "/applications/:client_id/tokens",
+	DeleteApplicationsByClientIDTokensByAccessToken: "/applications/:client_id/tokens/:access_token",
+	GetEvents:                                          "/events",
+	GetReposByOwnerByRepoEvents:                        "/repos/:owner/:repo/events",
+	GetNetworksByOwnerByRepoEvents:                     "/networks/:owner/:repo/events",
+	GetOrgsByOrgEvents:                                 "/orgs/:org/events",
+	GetUsersByUserReceivedEvents:                       "/users/:user/received_events",
+	GetUsersByUserReceivedEventsPublic:                 "/users/:user/received_events/public",
+	GetUsersByUserEvents:                               "/users/:user/events",
+	GetUsersByUserEventsPublic:                         "/users/:user/events/public",
+	GetUsersByUserEventsOrgsByOrg:                      "/users/:user/events/orgs/:org",
+	GetFeeds:                                           "/feeds",
+	GetNotifications:                                   "/notifications",
+	GetReposByOwnerByRepoNotifications:                 "/repos/:owner/:repo/notifications",
+	PutNotifications:                                   "/notifications",
+	PutReposByOwnerByRepoNotifications:                 "/repos/:owner/:repo/notifications",
+	GetNotificationsThreadsByID:                        "/notifications/threads/:id",
+	GetNotificationsThreadsByIDSubscription:            "/notifications/threads/:id/subscription",
+	PutNotificationsThreadsByIDSubscription:            "/notifications/threads/:id/subscription",
+	DeleteNotificationsThreadsByIDSubscription:         "/notifications/threads/:id/subscription",
+	GetReposByOwnerByRepoStargazers:                    "/repos/:owner/:repo/stargazers",
+	GetUsersByUserStarred:                              "/users/:user/starred",
+	GetUserStarred:                                     "/user/starred",
+	GetUserStarredByOwnerByRepo:                        "/user/starred/:owner/:repo",
+	PutUserStarredByOwnerByRepo:                        "/user/starred/:owner/:repo",
+	DeleteUserStarredByOwnerByRepo:                     "/user/starred/:owner/:repo",
+	GetReposByOwnerByRepoSubscribers:                   "/repos/:owner/:repo/subscribers",
+	GetUsersByUserSubscriptions:                        "/users/:user/subscriptions",
+	GetUserSubscriptions:                               "/user/subscriptions",
+	GetReposByOwnerByRepoSubscription:                  "/repos/:owner/:repo/subscription",
+	PutReposByOwnerByRepoSubscription:                  "/repos/:owner/:repo/subscription",
+	DeleteReposByOwnerByRepoSubscription:               "/repos/:owner/:repo/subscription",
+	GetUserSubscriptionsByOwnerByRepo:                  "/user/subscriptions/:owner/:repo",
+	PutUserSubscriptionsByOwnerByRepo:                  "/user/subscriptions/:owner/:repo",
+	DeleteUserSubscriptionsByOwnerByRepo:               "/user/subscriptions/:owner/:repo",
+	GetUsersByUserGists:                                "/users/:user/gists",
+	GetGists:                                           "/gists",
+	GetGistsByID:                                       "/gists/:id",
+	PostGists:                                          "/gists",
+	PutGistsByIDStar:                                   "/gists/:id/star",
+	DeleteGistsByIDStar:                                "/gists/:id/star",
+	GetGistsByIDStar:                                   "/gists/:id/star",
+	PostGistsByIDForks:                                 "/gists/:id/forks",
+	DeleteGistsByID:                                    "/gists/:id",
+	GetReposByOwnerByRepoGitBlobsBySha:                 "/repos/:owner/:repo/git/blobs/:sha",
+	PostReposByOwnerByRepoGitBlobs:                     "/repos/:owner/:repo/git/blobs",
+	GetReposByOwnerByRepoGitCommitsBySha:               "/repos/:owner/:repo/git/commits/:sha",
+	PostReposByOwnerByRepoGitCommits:                   "/repos/:owner/:repo/git/commits",
+	GetReposByOwnerByRepoGitRefs:                       "/repos/:owner/:repo/git/refs",
+	PostReposByOwnerByRepoGitRefs:                      "/repos/:owner/:repo/git/refs",
+	GetReposByOwnerByRepoGitTagsBySha:                  "/repos/:owner/:repo/git/tags/:sha",
+	PostReposByOwnerByRepoGitTags:                      "/repos/:owner/:repo/git/tags",
+	GetReposByOwnerByRepoGitTreesBySha:                 "/repos/:owner/:repo/git/trees/:sha",
+	PostReposByOwnerByRepoGitTrees:                     "/repos/:owner/:repo/git/trees",
+	GetIssues:                                          "/issues",
+	GetUserIssues:                                      "/user/issues",
+	GetOrgsByOrgIssues:                                 "/orgs/:org/issues",
+	GetReposByOwnerByRepoIssues:                        "/repos/:owner/:repo/issues",
+	GetReposByOwnerByRepoIssuesByNumber:                "/repos/:owner/:repo/issues/:number",
+	PostReposByOwnerByRepoIssues:                       "/repos/:owner/:repo/issues",
+	GetReposByOwnerByRepoAssignees:                     "/repos/:owner/:repo/assignees",
+	GetReposByOwnerByRepoAssigneesByAssignee:           "/repos/:owner/:repo/assignees/:assignee",
+	GetReposByOwnerByRepoIssuesByNumberComments:        "/repos/:owner/:repo/issues/:number/comments",
+	PostReposByOwnerByRepoIssuesByNumberComments:       "/repos/:owner/:repo/issues/:number/comments",
+	GetReposByOwnerByRepoIssuesByNumberEvents:          "/repos/:owner/:repo/issues/:number/events",
+	GetReposByOwnerByRepoLabels:                        "/repos/:owner/:repo/labels",
+	GetReposByOwnerByRepoLabelsByName:                  "/repos/:owner/:repo/labels/:name",
+	PostReposByOwnerByRepoLabels:                       "/repos/:owner/:repo/labels",
+	DeleteReposByOwnerByRepoLabelsByName:               "/repos/:owner/:repo/labels/:name",
+	GetReposByOwnerByRepoIssuesByNumberLabels:          "/repos/:owner/:repo/issues/:number/labels",
+	PostReposByOwnerByRepoIssuesByNumberLabels:         "/repos/:owner/:repo/issues/:number/labels",
+	DeleteReposByOwnerByRepoIssuesByNumberLabelsByName: "/repos/:owner/:repo/issues/:number/labels/:name",
+	PutReposByOwnerByRepoIssuesByNumberLabels:          "/repos/:owner/:repo/issues/:number/labels",
+	DeleteReposByOwnerByRepoIssuesByNumberLabels:       "/repos/:owner/:repo/issues/:number/labels",
+	GetReposByOwnerByRepoMilestonesByNumberLabels:      "/repos/:owner/:repo/milestones/:number/labels",
+	GetReposByOwnerByRepoMilestones:                    "/repos/:owner/:repo/milestones",
+	GetReposByOwnerByRepoMilestonesByNumber:            "/repos/:owner/:repo/milestones/:number",
+	PostReposByOwnerByRepoMilestones:                   "/repos/:owner/:repo/milestones",
+	DeleteReposByOwnerByRepoMilestonesByNumber:         "/repos/:owner/:repo/milestones/:number",
+	GetEmojis:                                                "/emojis",
+	GetGitignoreTemplates:                                    "/gitignore/templates",
+	GetGitignoreTemplatesByName:                              "/gitignore/templates/:name",
+	PostMarkdown:                                             "/markdown",
+	PostMarkdownRaw:                                          "/markdown/raw",
+	GetMeta:                                                  "/meta",
+	GetRateLimit:                                             "/rate_limit",
+	GetUsersByUserOrgs:                                       "/users/:user/orgs",
+	GetUserOrgs:                                              "/user/orgs",
+	GetOrgsByOrg:                                             "/orgs/:org",
+	GetOrgsByOrgMembers:                                      "/orgs/:org/members",
+	GetOrgsByOrgMembersByUser:                                "/orgs/:org/members/:user",
+	DeleteOrgsByOrgMembersByUser:                             "/orgs/:org/members/:user",
+	GetOrgsByOrgPublicMembers:                                "/orgs/:org/public_members",
+	GetOrgsByOrgPublicMembersByUser:                          "/orgs/:org/public_members/:user",
+	PutOrgsByOrgPublicMembersByUser:                          "/orgs/:org/public_members/:user",
+	DeleteOrgsByOrgPublicMembersByUser:                       "/orgs/:org/public_members/:user",
+	GetOrgsByOrgTeams:                                        "/orgs/:org/teams",
+	GetTeamsByID:                                             "/teams/:id",
+	PostOrgsByOrgTeams:                                       "/orgs/:org/teams",
+	DeleteTeamsByID:                                          "/teams/:id",
+	GetTeamsByIDMembers:                                      "/teams/:id/members",
+	GetTeamsByIDMembersByUser:                                "/teams/:id/members/:user",
+	PutTeamsByIDMembersByUser:                                "/teams/:id/members/:user",
+	DeleteTeamsByIDMembersByUser:                             "/teams/:id/members/:user",
+	GetTeamsByIDRepos:                                        "/teams/:id/repos",
+	GetTeamsByIDReposByOwnerByRepo:                           "/teams/:id/repos/:owner/:repo",
+	PutTeamsByIDReposByOwnerByRepo:                           "/teams/:id/repos/:owner/:repo",
+	DeleteTeamsByIDReposByOwnerByRepo:                        "/teams/:id/repos/:owner/:repo",
+	GetUserTeams:                                             "/user/teams",
+	GetReposByOwnerByRepoPulls:                               "/repos/:owner/:repo/pulls",
+	GetReposByOwnerByRepoPullsByNumber:                       "/repos/:owner/:repo/pulls/:number",
+	PostReposByOwnerByRepoPulls:                              "/repos/:owner/:repo/pulls",
+	GetReposByOwnerByRepoPullsByNumberCommits:                "/repos/:owner/:repo/pulls/:number/commits",
+	GetReposByOwnerByRepoPullsByNumberFiles:                  "/repos/:owner/:repo/pulls/:number/files",
+	GetReposByOwnerByRepoPullsByNumberMerge:                  "/repos/:owner/:repo/pulls/:number/merge",
+	PutReposByOwnerByRepoPullsByNumberMerge:                  "/repos/:owner/:repo/pulls/:number/merge",
+	GetReposByOwnerByRepoPullsByNumberComments:               "/repos/:owner/:repo/pulls/:number/comments",
+	PutReposByOwnerByRepoPullsByNumberComments:               "/repos/:owner/:repo/pulls/:number/comments",
+	GetUserRepos:                                             "/user/repos",
+	GetUsersByUserRepos:                                      "/users/:user/repos",
+	GetOrgsByOrgRepos:                                        "/orgs/:org/repos",
+	GetRepositories:                                          "/repositories",
+	PostUserRepos:                                            "/user/repos",
+	PostOrgsByOrgRepos:                                       "/orgs/:org/repos",
+	GetReposByOwnerByRepo:                                    "/repos/:owner/:repo",
+	DeleteReposByOwnerByRepo:                                 "/repos/:owner/:repo",
+	GetReposByOwnerByRepoContributors:                        "/repos/:owner/:repo/contributors",
+	GetReposByOwnerByRepoLanguages:                           "/repos/:owner/:repo/languages",
+	GetReposByOwnerByRepoTeams:                               "/repos/:owner/:repo/teams",
+	GetReposByOwnerByRepoTags:                                "/repos/:owner/:repo/tags",
+	GetReposByOwnerByRepoBranches:                            "/repos/:owner/:repo/branches",
+	GetReposByOwnerByRepoBranchesByBranch:                    "/repos/:owner/:repo/branches/:branch",
+	GetReposByOwnerByRepoCollaborators:                       "/repos/:owner/:repo/collaborators",
+	GetReposByOwnerByRepoCollaboratorsByUser:                 "/repos/:owner/:repo/collaborators/:user",
+	PutReposByOwnerByRepoCollaboratorsByUser:                 "/repos/:owner/:repo/collaborators/:user",
+	DeleteReposByOwnerByRepoCollaboratorsByUser:              "/repos/:owner/:repo/collaborators/:user",
+	GetReposByOwnerByRepoComments:                            "/repos/:owner/:repo/comments",
+	GetReposByOwnerByRepoCommitsByShaComments:                "/repos/:owner/:repo/commits/:sha/comments",
+	PostReposByOwnerByRepoCommitsByShaComments:               "/repos/:owner/:repo/commits/:sha/comments",
+	GetReposByOwnerByRepoCommentsByID:                        "/repos/:owner/:repo/comments/:id",
+	DeleteReposByOwnerByRepoCommentsByID:                     "/repos/:owner/:repo/comments/:id",
+	GetReposByOwnerByRepoCommits:                             "/repos/:owner/:repo/commits",
+	GetReposByOwnerByRepoCommitsBySha:                        "/repos/:owner/:repo/commits/:sha",
+	GetReposByOwnerByRepoReadme:                              "/repos/:owner/:repo/readme",
+	GetReposByOwnerByRepoKeys:                                "/repos/:owner/:repo/keys",
+	GetReposByOwnerByRepoKeysByID:                            "/repos/:owner/:repo/keys/:id",
+	PostReposByOwnerByRepoKeys:                               "/repos/:owner/:repo/keys",
+	DeleteReposByOwnerByRepoKeysByID:                         "/repos/:owner/:repo/keys/:id",
+	GetReposByOwnerByRepoDownloads:                           "/repos/:owner/:repo/downloads",
+	GetReposByOwnerByRepoDownloadsByID:                       "/repos/:owner/:repo/downloads/:id",
+	DeleteReposByOwnerByRepoDownloadsByID:                    "/repos/:owner/:repo/downloads/:id",
+	GetReposByOwnerByRepoForks:                               "/repos/:owner/:repo/forks",
+	PostReposByOwnerByRepoForks:                              "/repos/:owner/:repo/forks",
+	GetReposByOwnerByRepoHooks:                               "/repos/:owner/:repo/hooks",
+	GetReposByOwnerByRepoHooksByID:                           "/repos/:owner/:repo/hooks/:id",
+	PostReposByOwnerByRepoHooks:                              "/repos/:owner/:repo/hooks",
+	PostReposByOwnerByRepoHooksByIDTests:                     "/repos/:owner/:repo/hooks/:id/tests",
+	DeleteReposByOwnerByRepoHooksByID:                        "/repos/:owner/:repo/hooks/:id",
+	PostReposByOwnerByRepoMerges:                             "/repos/:owner/:repo/merges",
+	GetReposByOwnerByRepoReleases:                            "/repos/:owner/:repo/releases",
+	GetReposByOwnerByRepoReleasesByID:                        "/repos/:owner/:repo/releases/:id",
+	PostReposByOwnerByRepoReleases:                           "/repos/:owner/:repo/releases",
+	DeleteReposByOwnerByRepoReleasesByID:                     "/repos/:owner/:repo/releases/:id",
+	GetReposByOwnerByRepoReleasesByIDAssets:                  "/repos/:owner/:repo/releases/:id/assets",
+	GetReposByOwnerByRepoStatsContributors:                   "/repos/:owner/:repo/stats/contributors",
+	GetReposByOwnerByRepoStatsCommitActivity:                 "/repos/:owner/:repo/stats/commit_activity",
+	GetReposByOwnerByRepoStatsCodeFrequency:                  "/repos/:owner/:repo/stats/code_frequency",
+	GetReposByOwnerByRepoStatsParticipation:                  "/repos/:owner/:repo/stats/participation",
+	GetReposByOwnerByRepoStatsPunchCard:                      "/repos/:owner/:repo/stats/punch_card",
+	GetReposByOwnerByRepoStatusesByRef:                       "/repos/:owner/:repo/statuses/:ref",
+	PostReposByOwnerByRepoStatusesByRef:                      "/repos/:owner/:repo/statuses/:ref",
+	GetSearchRepositories:                                    "/search/repositories",
+	GetSearchCode:                                            "/search/code",
+	GetSearchIssues:                                          "/search/issues",
+	GetSearchUsers:                                           "/search/users",
+	GetLegacyIssuesSearchByOwnerByRepositoryByStateByKeyword: "/legacy/issues/search/:owner/:repository/:state/:keyword",
+	GetLegacyReposSearchByKeyword:                            "/legacy/repos/search/:keyword",
+	GetLegacyUserSearchByKeyword:                             "/legacy/user/search/:keyword",
+	GetLegacyUserEmailByEmail:                                "/legacy/user/email/:email",
+	GetUsersByUser:                                           "/users/:user",
+	GetUser:                                                  "/user",
+	GetUsers:                                                 "/users",
+	GetUserEmails:                                            "/user/emails",
+	PostUserEmails:                                           "/user/emails",
+	DeleteUserEmails:                                         "/user/emails",
+	GetUsersByUserFollowers:                                  "/users/:user/followers",
+	GetUserFollowers:                                         "/user/followers",
+	GetUsersByUserFollowing:                                  "/users/:user/following",
+	GetUserFollowing:                                         "/user/following",
+	GetUserFollowingByUser:                                   "/user/following/:user",
+	GetUsersByUserFollowingByTargetUser:                      "/users/:user/following/:target_user",
+	PutUserFollowingByUser:                                   "/user/following/:user",
+	DeleteUserFollowingByUser:                                "/user/following/:user",
+	GetUsersByUserKeys:                                       "/users/:user/keys",
+	GetUserKeys:                                              "/user/keys",
+	GetUserKeysByID:                                          "/user/keys/:id",
+	PostUserKeys:                                             "/user/keys",
+	DeleteUserKeysByID:                                       "/user/keys/:id",
+}
+
+// Method returns the method of the route, or an empty string for NoRoute.
+func (r Route) Method() string { return routeMethods[r] }
+
+// Path returns the path pattern of the route, or an empty string for NoRoute.
+func (r Route) Path() string { return routePaths[r] }
+
+func (r Route) String() string {
+	if r == NoRoute {
+		return "NoRoute"
+	}
+	return routeMethods[r] + " " + routePaths[r]
+}
+
+// Match returns the route matching the method and path and appends the
+// values of its params to ps, if not nil, or returns NoRoute.
+func Match(method, path string, ps *drouter.Params) Route {
+	if path == "" || path[0] != '/' {
+		return NoRoute
+	}
+	n := 0
+	if ps != nil {
+		n = len(*ps)
+	}
+	r := NoRoute
+	switch method {
+	case "DELETE":
+		r = match0(path, ps)
+	case "GET":
+		r = match1(path, ps)
+	case "POST":
+		r = match2(path, ps)
+	case "PUT":
+		r = match3(path, ps)
+	}
+	if r == NoRoute && ps != nil {
+		*ps = (*ps)[:n]
+	}
+	return r
+}
+
+// segment returns the path element starting at start and its end.
+func segment(path string, start int) (string, int) {
+	if i := strings.IndexByte(path[start:], '/'); i >= 0 {
+		return path[start : start+i], start + i
+	}
+	return path[start:], len(path)
+}
+
+func match0(path string, ps *drouter.Params) Route { // DELETE
+	s0, e0 := segment(path, 1)
+	switch s0 {
+	case "applications":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "client_id", Value: s1})
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					switch s2 {
+					case "tokens":
+						if e2 == len(path) {
+							return DeleteApplicationsByClientIDTokens
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "access_token", Value: s3})
+								}
+								if e3 == len(path) {
+									return DeleteApplicationsByClientIDTokensByAccessToken
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "authorizations":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "id", Value: s1})
+				}
+				if e1 == len(path) {
+					return DeleteAuthorizationsByID
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "gists":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "id", Value: s1})
+				}
+				if e1 == len(path) {
+					return DeleteGistsByID
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					switch s2 {
+					case "star":
+						if e2 == len(path) {
+							return DeleteGistsByIDStar
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "notifications":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			switch s1 {
+			case "threads":
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "id", Value: s2})
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							switch s3 {
+							case "subscription":
+								if e3 == len(path) {
+									return DeleteNotificationsThreadsByIDSubscription
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "orgs":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "org", Value: s1})
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					switch s2 {
+					case "members":
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "user", Value: s3})
+								}
+								if e3 == len(path) {
+									return DeleteOrgsByOrgMembersByUser
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					case "public_members":
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "user", Value: s3})
+								}
+								if e3 == len(path) {
+									return DeleteOrgsByOrgPublicMembersByUser
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "repos":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "owner", Value: s1})
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "repo", Value: s2})
+						}
+						if e2 == len(path) {
+							return DeleteReposByOwnerByRepo
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							switch s3 {
+							case "collaborators":
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "user", Value: s4})
+										}
+										if e4 == len(path) {
+											return DeleteReposByOwnerByRepoCollaboratorsByUser
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "comments":
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "id", Value: s4})
+										}
+										if e4 == len(path) {
+											return DeleteReposByOwnerByRepoCommentsByID
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "downloads":
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "id", Value: s4})
+										}
+										if e4 == len(path) {
+											return DeleteReposByOwnerByRepoDownloadsByID
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "hooks":
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "id", Value: s4})
+										}
+										if e4 == len(path) {
+											return DeleteReposByOwnerByRepoHooksByID
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "issues":
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "number", Value: s4})
+										}
+										if e4 < len(path) {
+											s5, e5 := segment(path, e4+1)
+											switch s5 {
+											case "labels":
+												if e5 == len(path) {
+													return DeleteReposByOwnerByRepoIssuesByNumberLabels
+												}
+												if e5 < len(path) {
+													s6, e6 := segment(path, e5+1)
+													if s6 != "" {
+														if ps != nil {
+															*ps = append(*ps, drouter.Param{Key: "name", Value: s6})
+														}
+														if e6 == len(path) {
+															return DeleteReposByOwnerByRepoIssuesByNumberLabelsByName
+														}
+														return NoRoute
+													}
+													return NoRoute
+												}
+												return NoRoute
+											}
+											return NoRoute
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "keys":
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "id", Value: s4})
+										}
+										if e4 == len(path) {
+											return DeleteReposByOwnerByRepoKeysByID
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "labels":
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "name", Value: s4})
+										}
+										if e4 == len(path) {
+											return DeleteReposByOwnerByRepoLabelsByName
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "milestones":
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "number", Value: s4})
+										}
+										if e4 == len(path) {
+											return DeleteReposByOwnerByRepoMilestonesByNumber
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "releases":
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "id", Value: s4})
+										}
+										if e4 == len(path) {
+											return DeleteReposByOwnerByRepoReleasesByID
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "subscription":
+								if e3 == len(path) {
+									return DeleteReposByOwnerByRepoSubscription
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "teams":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "id", Value: s1})
+				}
+				if e1 == len(path) {
+					return DeleteTeamsByID
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					switch s2 {
+					case "members":
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "user", Value: s3})
+								}
+								if e3 == len(path) {
+									return DeleteTeamsByIDMembersByUser
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					case "repos":
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "owner", Value: s3})
+								}
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "repo", Value: s4})
+										}
+										if e4 == len(path) {
+											return DeleteTeamsByIDReposByOwnerByRepo
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "user":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			switch s1 {
+			case "emails":
+				if e1 == len(path) {
+					return DeleteUserEmails
+				}
+				return NoRoute
+			case "following":
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "user", Value: s2})
+						}
+						if e2 == len(path) {
+							return DeleteUserFollowingByUser
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			case "keys":
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "id", Value: s2})
+						}
+						if e2 == len(path) {
+							return DeleteUserKeysByID
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			case "starred":
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "owner", Value: s2})
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "repo", Value: s3})
+								}
+								if e3 == len(path) {
+									return DeleteUserStarredByOwnerByRepo
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			case "subscriptions":
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "owner", Value: s2})
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "repo", Value: s3})
+								}
+								if e3 == len(path) {
+									return DeleteUserSubscriptionsByOwnerByRepo
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	}
+	return NoRoute
+}
+
+func match1(path string, ps *drouter.Params) Route { // GET
+	s0, e0 := segment(path, 1)
+	switch s0 {
+	case "applications":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "client_id", Value: s1})
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					switch s2 {
+					case "tokens":
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "access_token", Value: s3})
+								}
+								if e3 == len(path) {
+									return GetApplicationsByClientIDTokensByAccessToken
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "authorizations":
+		if e0 == len(path) {
+			return GetAuthorizations
+		}
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "id", Value: s1})
+				}
+				if e1 == len(path) {
+					return GetAuthorizationsByID
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "emojis":
+		if e0 == len(path) {
+			return GetEmojis
+		}
+		return NoRoute
+	case "events":
+		if e0 == len(path) {
+			return GetEvents
+		}
+		return NoRoute
+	case "feeds":
+		if e0 == len(path) {
+			return GetFeeds
+		}
+		return NoRoute
+	case "gists":
+		if e0 == len(path) {
+			return GetGists
+		}
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "id", Value: s1})
+				}
+				if e1 == len(path) {
+					return GetGistsByID
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					switch s2 {
+					case "star":
+						if e2 == len(path) {
+							return GetGistsByIDStar
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "gitignore":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			switch s1 {
+			case "templates":
+				if e1 == len(path) {
+					return GetGitignoreTemplates
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "name", Value: s2})
+						}
+						if e2 == len(path) {
+							return GetGitignoreTemplatesByName
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "issues":
+		if e0 == len(path) {
+			return GetIssues
+		}
+		return NoRoute
+	case "legacy":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			switch s1 {
+			case "issues":
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					switch s2 {
+					case "search":
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "owner", Value: s3})
+								}
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "repository", Value: s4})
+										}
+										if e4 < len(path) {
+											s5, e5 := segment(path, e4+1)
+											if s5 != "" {
+												if ps != nil {
+													*ps = append(*ps, drouter.Param{Key: "state", Value: s5})
+												}
+												if e5 < len(path) {
+													s6, e6 := segment(path, e5+1)
+													if s6 != "" {
+														if ps != nil {
+															*ps = append(*ps, drouter.Param{Key: "keyword", Value: s6})
+														}
+														if e6 == len(path) {
+															return GetLegacyIssuesSearchByOwnerByRepositoryByStateByKeyword
+														}
+														return NoRoute
+													}
+													return NoRoute
+												}
+												return NoRoute
+											}
+											return NoRoute
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			case "repos":
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					switch s2 {
+					case "search":
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "keyword", Value: s3})
+								}
+								if e3 == len(path) {
+									return GetLegacyReposSearchByKeyword
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			case "user":
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					switch s2 {
+					case "email":
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "email", Value: s3})
+								}
+								if e3 == len(path) {
+									return GetLegacyUserEmailByEmail
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					case "search":
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "keyword", Value: s3})
+								}
+								if e3 == len(path) {
+									return GetLegacyUserSearchByKeyword
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "meta":
+		if e0 == len(path) {
+			return GetMeta
+		}
+		return NoRoute
+	case "networks":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "owner", Value: s1})
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "repo", Value: s2})
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							switch s3 {
+							case "events":
+								if e3 == len(path) {
+									return GetNetworksByOwnerByRepoEvents
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "notifications":
+		if e0 == len(path) {
+			return GetNotifications
+		}
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			switch s1 {
+			case "threads":
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "id", Value: s2})
+						}
+						if e2 == len(path) {
+							return GetNotificationsThreadsByID
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							switch s3 {
+							case "subscription":
+								if e3 == len(path) {
+									return GetNotificationsThreadsByIDSubscription
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "orgs":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "org", Value: s1})
+				}
+				if e1 == len(path) {
+					return GetOrgsByOrg
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					switch s2 {
+					case "events":
+						if e2 == len(path) {
+							return GetOrgsByOrgEvents
+						}
+						return NoRoute
+					case "issues":
+						if e2 == len(path) {
+							return GetOrgsByOrgIssues
+						}
+						return NoRoute
+					case "members":
+						if e2 == len(path) {
+							return GetOrgsByOrgMembers
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "user", Value: s3})
+								}
+								if e3 == len(path) {
+									return GetOrgsByOrgMembersByUser
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					case "public_members":
+						if e2 == len(path) {
+							return GetOrgsByOrgPublicMembers
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "user", Value: s3})
+								}
+								if e3 == len(path) {
+									return GetOrgsByOrgPublicMembersByUser
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					case "repos":
+						if e2 == len(path) {
+							return GetOrgsByOrgRepos
+						}
+						return NoRoute
+					case "teams":
+						if e2 == len(path) {
+							return GetOrgsByOrgTeams
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "rate_limit":
+		if e0 == len(path) {
+			return GetRateLimit
+		}
+		return NoRoute
+	case "repos":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "owner", Value: s1})
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "repo", Value: s2})
+						}
+						if e2 == len(path) {
+							return GetReposByOwnerByRepo
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							switch s3 {
+							case "assignees":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoAssignees
+								}
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "assignee", Value: s4})
+										}
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoAssigneesByAssignee
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "branches":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoBranches
+								}
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "branch", Value: s4})
+										}
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoBranchesByBranch
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "collaborators":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoCollaborators
+								}
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "user", Value: s4})
+										}
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoCollaboratorsByUser
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "comments":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoComments
+								}
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "id", Value: s4})
+										}
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoCommentsByID
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "commits":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoCommits
+								}
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "sha", Value: s4})
+										}
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoCommitsBySha
+										}
+										if e4 < len(path) {
+											s5, e5 := segment(path, e4+1)
+											switch s5 {
+											case "comments":
+												if e5 == len(path) {
+													return GetReposByOwnerByRepoCommitsByShaComments
+												}
+												return NoRoute
+											}
+											return NoRoute
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "contributors":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoContributors
+								}
+								return NoRoute
+							case "downloads":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoDownloads
+								}
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "id", Value: s4})
+										}
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoDownloadsByID
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "events":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoEvents
+								}
+								return NoRoute
+							case "forks":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoForks
+								}
+								return NoRoute
+							case "git":
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									switch s4 {
+									case "blobs":
+										if e4 < len(path) {
+											s5, e5 := segment(path, e4+1)
+											if s5 != "" {
+												if ps != nil {
+													*ps = append(*ps, drouter.Param{Key: "sha", Value: s5})
+												}
+												if e5 == len(path) {
+													return GetReposByOwnerByRepoGitBlobsBySha
+												}
+												return NoRoute
+											}
+											return NoRoute
+										}
+										return NoRoute
+									case "commits":
+										if e4 < len(path) {
+											s5, e5 := segment(path, e4+1)
+											if s5 != "" {
+												if ps != nil {
+													*ps = append(*ps, drouter.Param{Key: "sha", Value: s5})
+												}
+												if e5 == len(path) {
+													return GetReposByOwnerByRepoGitCommitsBySha
+												}
+												return NoRoute
+											}
+											return NoRoute
+										}
+										return NoRoute
+									case "refs":
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoGitRefs
+										}
+										return NoRoute
+									case "tags":
+										if e4 < len(path) {
+											s5, e5 := segment(path, e4+1)
+											if s5 != "" {
+												if ps != nil {
+													*ps = append(*ps, drouter.Param{Key: "sha", Value: s5})
+												}
+												if e5 == len(path) {
+													return GetReposByOwnerByRepoGitTagsBySha
+												}
+												return NoRoute
+											}
+											return NoRoute
+										}
+										return NoRoute
+									case "trees":
+										if e4 < len(path) {
+											s5, e5 := segment(path, e4+1)
+											if s5 != "" {
+												if ps != nil {
+													*ps = append(*ps, drouter.Param{Key: "sha", Value: s5})
+												}
+												if e5 == len(path) {
+													return GetReposByOwnerByRepoGitTreesBySha
+												}
+												return NoRoute
+											}
+											return NoRoute
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "hooks":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoHooks
+								}
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "id", Value: s4})
+										}
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoHooksByID
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "issues":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoIssues
+								}
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "number", Value: s4})
+										}
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoIssuesByNumber
+										}
+										if e4 < len(path) {
+											s5, e5 := segment(path, e4+1)
+											switch s5 {
+											case "comments":
+												if e5 == len(path) {
+													return GetReposByOwnerByRepoIssuesByNumberComments
+												}
+												return NoRoute
+											case "events":
+												if e5 == len(path) {
+													return GetReposByOwnerByRepoIssuesByNumberEvents
+												}
+												return NoRoute
+											case "labels":
+												if e5 == len(path) {
+													return GetReposByOwnerByRepoIssuesByNumberLabels
+												}
+												return NoRoute
+											}
+											return NoRoute
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "keys":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoKeys
+								}
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "id", Value: s4})
+										}
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoKeysByID
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "labels":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoLabels
+								}
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "name", Value: s4})
+										}
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoLabelsByName
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "languages":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoLanguages
+								}
+								return NoRoute
+							case "milestones":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoMilestones
+								}
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "number", Value: s4})
+										}
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoMilestonesByNumber
+										}
+										if e4 < len(path) {
+											s5, e5 := segment(path, e4+1)
+											switch s5 {
+											case "labels":
+												if e5 == len(path) {
+													return GetReposByOwnerByRepoMilestonesByNumberLabels
+												}
+												return NoRoute
+											}
+											return NoRoute
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "notifications":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoNotifications
+								}
+								return NoRoute
+							case "pulls":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoPulls
+								}
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "number", Value: s4})
+										}
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoPullsByNumber
+										}
+										if e4 < len(path) {
+											s5, e5 := segment(path, e4+1)
+											switch s5 {
+											case "comments":
+												if e5 == len(path) {
+													return GetReposByOwnerByRepoPullsByNumberComments
+												}
+												return NoRoute
+											case "commits":
+												if e5 == len(path) {
+													return GetReposByOwnerByRepoPullsByNumberCommits
+												}
+												return NoRoute
+											case "files":
+												if e5 == len(path) {
+													return GetReposByOwnerByRepoPullsByNumberFiles
+												}
+												return NoRoute
+											case "merge":
+												if e5 == len(path) {
+													return GetReposByOwnerByRepoPullsByNumberMerge
+												}
+												return NoRoute
+											}
+											return NoRoute
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "readme":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoReadme
+								}
+								return NoRoute
+							case "releases":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoReleases
+								}
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "id", Value: s4})
+										}
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoReleasesByID
+										}
+										if e4 < len(path) {
+											s5, e5 := segment(path, e4+1)
+											switch s5 {
+											case "assets":
+												if e5 == len(path) {
+													return GetReposByOwnerByRepoReleasesByIDAssets
+												}
+												return NoRoute
+											}
+											return NoRoute
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "stargazers":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoStargazers
+								}
+								return NoRoute
+							case "stats":
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									switch s4 {
+									case "code_frequency":
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoStatsCodeFrequency
+										}
+										return NoRoute
+									case "commit_activity":
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoStatsCommitActivity
+										}
+										return NoRoute
+									case "contributors":
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoStatsContributors
+										}
+										return NoRoute
+									case "participation":
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoStatsParticipation
+										}
+										return NoRoute
+									case "punch_card":
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoStatsPunchCard
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "statuses":
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "ref", Value: s4})
+										}
+										if e4 == len(path) {
+											return GetReposByOwnerByRepoStatusesByRef
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "subscribers":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoSubscribers
+								}
+								return NoRoute
+							case "subscription":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoSubscription
+								}
+								return NoRoute
+							case "tags":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoTags
+								}
+								return NoRoute
+							case "teams":
+								if e3 == len(path) {
+									return GetReposByOwnerByRepoTeams
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "repositories":
+		if e0 == len(path) {
+			return GetRepositories
+		}
+		return NoRoute
+	case "search":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			switch s1 {
+			case "code":
+				if e1 == len(path) {
+					return GetSearchCode
+				}
+				return NoRoute
+			case "issues":
+				if e1 == len(path) {
+					return GetSearchIssues
+				}
+				return NoRoute
+			case "repositories":
+				if e1 == len(path) {
+					return GetSearchRepositories
+				}
+				return NoRoute
+			case "users":
+				if e1 == len(path) {
+					return GetSearchUsers
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "teams":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "id", Value: s1})
+				}
+				if e1 == len(path) {
+					return GetTeamsByID
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					switch s2 {
+					case "members":
+						if e2 == len(path) {
+							return GetTeamsByIDMembers
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "user", Value: s3})
+								}
+								if e3 == len(path) {
+									return GetTeamsByIDMembersByUser
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					case "repos":
+						if e2 == len(path) {
+							return GetTeamsByIDRepos
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "owner", Value: s3})
+								}
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "repo", Value: s4})
+										}
+										if e4 == len(path) {
+											return GetTeamsByIDReposByOwnerByRepo
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "user":
+		if e0 == len(path) {
+			return GetUser
+		}
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			switch s1 {
+			case "emails":
+				if e1 == len(path) {
+					return GetUserEmails
+				}
+				return NoRoute
+			case "followers":
+				if e1 == len(path) {
+					return GetUserFollowers
+				}
+				return NoRoute
+			case "following":
+				if e1 == len(path) {
+					return GetUserFollowing
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "user", Value: s2})
+						}
+						if e2 == len(path) {
+							return GetUserFollowingByUser
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			case "issues":
+				if e1 == len(path) {
+					return GetUserIssues
+				}
+				return NoRoute
+			case "keys":
+				if e1 == len(path) {
+					return GetUserKeys
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "id", Value: s2})
+						}
+						if e2 == len(path) {
+							return GetUserKeysByID
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			case "orgs":
+				if e1 == len(path) {
+					return GetUserOrgs
+				}
+				return NoRoute
+			case "repos":
+				if e1 == len(path) {
+					return GetUserRepos
+				}
+				return NoRoute
+			case "starred":
+				if e1 == len(path) {
+					return GetUserStarred
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "owner", Value: s2})
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "repo", Value: s3})
+								}
+								if e3 == len(path) {
+									return GetUserStarredByOwnerByRepo
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			case "subscriptions":
+				if e1 == len(path) {
+					return GetUserSubscriptions
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "owner", Value: s2})
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "repo", Value: s3})
+								}
+								if e3 == len(path) {
+									return GetUserSubscriptionsByOwnerByRepo
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			case "teams":
+				if e1 == len(path) {
+					return GetUserTeams
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "users":
+		if e0 == len(path) {
+			return GetUsers
+		}
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "user", Value: s1})
+				}
+				if e1 == len(path) {
+					return GetUsersByUser
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					switch s2 {
+					case "events":
+						if e2 == len(path) {
+							return GetUsersByUserEvents
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							switch s3 {
+							case "orgs":
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "org", Value: s4})
+										}
+										if e4 == len(path) {
+											return GetUsersByUserEventsOrgsByOrg
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "public":
+								if e3 == len(path) {
+									return GetUsersByUserEventsPublic
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					case "followers":
+						if e2 == len(path) {
+							return GetUsersByUserFollowers
+						}
+						return NoRoute
+					case "following":
+						if e2 == len(path) {
+							return GetUsersByUserFollowing
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "target_user", Value: s3})
+								}
+								if e3 == len(path) {
+									return GetUsersByUserFollowingByTargetUser
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					case "gists":
+						if e2 == len(path) {
+							return GetUsersByUserGists
+						}
+						return NoRoute
+					case "keys":
+						if e2 == len(path) {
+							return GetUsersByUserKeys
+						}
+						return NoRoute
+					case "orgs":
+						if e2 == len(path) {
+							return GetUsersByUserOrgs
+						}
+						return NoRoute
+					case "received_events":
+						if e2 == len(path) {
+							return GetUsersByUserReceivedEvents
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							switch s3 {
+							case "public":
+								if e3 == len(path) {
+									return GetUsersByUserReceivedEventsPublic
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					case "repos":
+						if e2 == len(path) {
+							return GetUsersByUserRepos
+						}
+						return NoRoute
+					case "starred":
+						if e2 == len(path) {
+							return GetUsersByUserStarred
+						}
+						return NoRoute
+					case "subscriptions":
+						if e2 == len(path) {
+							return GetUsersByUserSubscriptions
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	}
+	return NoRoute
+}
+
+func match2(path string, ps *drouter.Params) Route { // POST
+	s0, e0 := segment(path, 1)
+	switch s0 {
+	case "authorizations":
+		if e0 == len(path) {
+			return PostAuthorizations
+		}
+		return NoRoute
+	case "gists":
+		if e0 == len(path) {
+			return PostGists
+		}
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "id", Value: s1})
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					switch s2 {
+					case "forks":
+						if e2 == len(path) {
+							return PostGistsByIDForks
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "markdown":
+		if e0 == len(path) {
+			return PostMarkdown
+		}
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			switch s1 {
+			case "raw":
+				if e1 == len(path) {
+					return PostMarkdownRaw
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "orgs":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "org", Value: s1})
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					switch s2 {
+					case "repos":
+						if e2 == len(path) {
+							return PostOrgsByOrgRepos
+						}
+						return NoRoute
+					case "teams":
+						if e2 == len(path) {
+							return PostOrgsByOrgTeams
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "repos":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "owner", Value: s1})
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "repo", Value: s2})
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							switch s3 {
+							case "commits":
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "sha", Value: s4})
+										}
+										if e4 < len(path) {
+											s5, e5 := segment(path, e4+1)
+											switch s5 {
+											case "comments":
+												if e5 == len(path) {
+													return PostReposByOwnerByRepoCommitsByShaComments
+												}
+												return NoRoute
+											}
+											return NoRoute
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "forks":
+								if e3 == len(path) {
+									return PostReposByOwnerByRepoForks
+								}
+								return NoRoute
+							case "git":
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									switch s4 {
+									case "blobs":
+										if e4 == len(path) {
+											return PostReposByOwnerByRepoGitBlobs
+										}
+										return NoRoute
+									case "commits":
+										if e4 == len(path) {
+											return PostReposByOwnerByRepoGitCommits
+										}
+										return NoRoute
+									case "refs":
+										if e4 == len(path) {
+											return PostReposByOwnerByRepoGitRefs
+										}
+										return NoRoute
+									case "tags":
+										if e4 == len(path) {
+											return PostReposByOwnerByRepoGitTags
+										}
+										return NoRoute
+									case "trees":
+										if e4 == len(path) {
+											return PostReposByOwnerByRepoGitTrees
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "hooks":
+								if e3 == len(path) {
+									return PostReposByOwnerByRepoHooks
+								}
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "id", Value: s4})
+										}
+										if e4 < len(path) {
+											s5, e5 := segment(path, e4+1)
+											switch s5 {
+											case "tests":
+												if e5 == len(path) {
+													return PostReposByOwnerByRepoHooksByIDTests
+												}
+												return NoRoute
+											}
+											return NoRoute
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "issues":
+								if e3 == len(path) {
+									return PostReposByOwnerByRepoIssues
+								}
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "number", Value: s4})
+										}
+										if e4 < len(path) {
+											s5, e5 := segment(path, e4+1)
+											switch s5 {
+											case "comments":
+												if e5 == len(path) {
+													return PostReposByOwnerByRepoIssuesByNumberComments
+												}
+												return NoRoute
+											case "labels":
+												if e5 == len(path) {
+													return PostReposByOwnerByRepoIssuesByNumberLabels
+												}
+												return NoRoute
+											}
+											return NoRoute
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "keys":
+								if e3 == len(path) {
+									return PostReposByOwnerByRepoKeys
+								}
+								return NoRoute
+							case "labels":
+								if e3 == len(path) {
+									return PostReposByOwnerByRepoLabels
+								}
+								return NoRoute
+							case "merges":
+								if e3 == len(path) {
+									return PostReposByOwnerByRepoMerges
+								}
+								return NoRoute
+							case "milestones":
+								if e3 == len(path) {
+									return PostReposByOwnerByRepoMilestones
+								}
+								return NoRoute
+							case "pulls":
+								if e3 == len(path) {
+									return PostReposByOwnerByRepoPulls
+								}
+								return NoRoute
+							case "releases":
+								if e3 == len(path) {
+									return PostReposByOwnerByRepoReleases
+								}
+								return NoRoute
+							case "statuses":
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "ref", Value: s4})
+										}
+										if e4 == len(path) {
+											return PostReposByOwnerByRepoStatusesByRef
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "user":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			switch s1 {
+			case "emails":
+				if e1 == len(path) {
+					return PostUserEmails
+				}
+				return NoRoute
+			case "keys":
+				if e1 == len(path) {
+					return PostUserKeys
+				}
+				return NoRoute
+			case "repos":
+				if e1 == len(path) {
+					return PostUserRepos
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	}
+	return NoRoute
+}
+
+func match3(path string, ps *drouter.Params) Route { // PUT
+	s0, e0 := segment(path, 1)
+	switch s0 {
+	case "gists":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "id", Value: s1})
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					switch s2 {
+					case "star":
+						if e2 == len(path) {
+							return PutGistsByIDStar
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "notifications":
+		if e0 == len(path) {
+			return PutNotifications
+		}
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			switch s1 {
+			case "threads":
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "id", Value: s2})
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							switch s3 {
+							case "subscription":
+								if e3 == len(path) {
+									return PutNotificationsThreadsByIDSubscription
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "orgs":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "org", Value: s1})
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					switch s2 {
+					case "public_members":
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "user", Value: s3})
+								}
+								if e3 == len(path) {
+									return PutOrgsByOrgPublicMembersByUser
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "repos":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "owner", Value: s1})
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "repo", Value: s2})
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							switch s3 {
+							case "collaborators":
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "user", Value: s4})
+										}
+										if e4 == len(path) {
+											return PutReposByOwnerByRepoCollaboratorsByUser
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "issues":
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "number", Value: s4})
+										}
+										if e4 < len(path) {
+											s5, e5 := segment(path, e4+1)
+											switch s5 {
+											case "labels":
+												if e5 == len(path) {
+													return PutReposByOwnerByRepoIssuesByNumberLabels
+												}
+												return NoRoute
+											}
+											return NoRoute
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "notifications":
+								if e3 == len(path) {
+									return PutReposByOwnerByRepoNotifications
+								}
+								return NoRoute
+							case "pulls":
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "number", Value: s4})
+										}
+										if e4 < len(path) {
+											s5, e5 := segment(path, e4+1)
+											switch s5 {
+											case "comments":
+												if e5 == len(path) {
+													return PutReposByOwnerByRepoPullsByNumberComments
+												}
+												return NoRoute
+											case "merge":
+												if e5 == len(path) {
+													return PutReposByOwnerByRepoPullsByNumberMerge
+												}
+												return NoRoute
+											}
+											return NoRoute
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							case "subscription":
+								if e3 == len(path) {
+									return PutReposByOwnerByRepoSubscription
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "teams":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			if s1 != "" {
+				if ps != nil {
+					*ps = append(*ps, drouter.Param{Key: "id", Value: s1})
+				}
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					switch s2 {
+					case "members":
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "user", Value: s3})
+								}
+								if e3 == len(path) {
+									return PutTeamsByIDMembersByUser
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					case "repos":
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "owner", Value: s3})
+								}
+								if e3 < len(path) {
+									s4, e4 := segment(path, e3+1)
+									if s4 != "" {
+										if ps != nil {
+											*ps = append(*ps, drouter.Param{Key: "repo", Value: s4})
+										}
+										if e4 == len(path) {
+											return PutTeamsByIDReposByOwnerByRepo
+										}
+										return NoRoute
+									}
+									return NoRoute
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	case "user":
+		if e0 < len(path) {
+			s1, e1 := segment(path, e0+1)
+			switch s1 {
+			case "following":
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "user", Value: s2})
+						}
+						if e2 == len(path) {
+							return PutUserFollowingByUser
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			case "starred":
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "owner", Value: s2})
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "repo", Value: s3})
+								}
+								if e3 == len(path) {
+									return PutUserStarredByOwnerByRepo
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			case "subscriptions":
+				if e1 < len(path) {
+					s2, e2 := segment(path, e1+1)
+					if s2 != "" {
+						if ps != nil {
+							*ps = append(*ps, drouter.Param{Key: "owner", Value: s2})
+						}
+						if e2 < len(path) {
+							s3, e3 := segment(path, e2+1)
+							if s3 != "" {
+								if ps != nil {
+									*ps = append(*ps, drouter.Param{Key: "repo", Value: s3})
+								}
+								if e3 == len(path) {
+									return PutUserSubscriptionsByOwnerByRepo
+								}
+								return NoRoute
+							}
+							return NoRoute
+						}
+						return NoRoute
+					}
+					return NoRoute
+				}
+				return NoRoute
+			}
+			return NoRoute
+		}
+		return NoRoute
+	}
+	return NoRoute
+}
@@ -1,3 +1,3 @@
 module github.com/thekhanj/drouter
 
-go 1.7
+go 1.18
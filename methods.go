@@ -0,0 +1,179 @@
+package drouter
+
+import "sort"
+
+// MethodRouter is a radix tree whose routes hold a small table mapping
+// request methods onto handles of type T, instead of one tree per method.
+// A request is looked up with a single walk of the tree, which yields the
+// handle of its method as well as the methods allowed for its path, e.g. for
+// the Allow header of 405 responses. Trailing slash recommendations and
+// case-insensitive lookups depend on the path only, so they are the same for
+// all methods.
+//
+// All methods share the wildcards of the tree, so routes conflict across
+// methods as they do within a Router: GET /users/:id and POST /users/new can
+// not be registered together, while they can in separate trees per method.
+// Like Router, a MethodRouter must not be changed concurrently with lookups.
+type MethodRouter[T any] struct {
+	tree *Router[*methodTable[T]]
+
+	// Tables by route path, to find the table of a path when registering
+	// routes. Lookups only use the tree.
+	paths map[string]*methodTable[T]
+
+	len int
+}
+
+// methodTable maps methods onto handles. It is a pair of sorted slices, as
+// routes rarely have more than a few methods.
+type methodTable[T any] struct {
+	methods []string
+	handles []T
+}
+
+func (t *methodTable[T]) index(method string) (int, bool) {
+	i := sort.SearchStrings(t.methods, method)
+	return i, i < len(t.methods) && t.methods[i] == method
+}
+
+// NewMethodRouter returns a new empty MethodRouter for handles of type T.
+func NewMethodRouter[T any]() *MethodRouter[T] {
+	return &MethodRouter[T]{tree: New[*methodTable[T]]()}
+}
+
+// Lookup returns the handle registered for the given method and path and
+// stores the values of the wildcards in params, like Router.Lookup. The
+// methods registered for the path are returned as allowed, in lexical order,
+// also if there is no handle for the method itself. The slice is shared
+// with the router and must not be modified. If no route matches the path,
+// allowed is nil and tsr recommends a redirect with (without) a trailing
+// slash. Params are only stored if a handle is returned.
+func (r *MethodRouter[T]) Lookup(method, path string, params *Params) (handle T, allowed []string, tsr bool) {
+	var n int
+	if params != nil {
+		n = len(*params)
+	}
+	t, tsr := r.tree.Lookup(path, params)
+	if t == nil {
+		return handle, nil, tsr
+	}
+	if i, ok := t.index(method); ok {
+		return t.handles[i], t.methods, false
+	}
+	if params != nil {
+		*params = (*params)[:n]
+	}
+	return handle, t.methods, false
+}
+
+// Allowed returns the methods registered for routes matching the path, in
+// lexical order, see Lookup.
+func (r *MethodRouter[T]) Allowed(path string) []string {
+	t, _ := r.tree.Lookup(path, nil)
+	if t == nil {
+		return nil
+	}
+	return t.methods
+}
+
+// AddRoute registers a new handle with the given method and path. It panics
+// with a *RouteError if the method is empty, the path is invalid, a handle is
+// already registered for the method and path or the path conflicts with the
+// route of another path. See TryAddRoute for adding routes without
+// panicking.
+func (r *MethodRouter[T]) AddRoute(method, path string, handle T) {
+	if err := r.TryAddRoute(method, path, handle); err != nil {
+		panic(err)
+	}
+}
+
+// TryAddRoute is like AddRoute, but returns a *RouteError instead of
+// panicking if the route can not be added. The router is left unchanged in
+// that case.
+func (r *MethodRouter[T]) TryAddRoute(method, path string, handle T) error {
+	if method == "" {
+		return &RouteError{Kind: InvalidMethod, Path: path, Message: "method must not be empty"}
+	}
+	if any(handle) == nil {
+		return &RouteError{Kind: InvalidHandle, Path: path, Message: "handle must not be nil"}
+	}
+
+	t := r.paths[path]
+	if t == nil {
+		t = &methodTable[T]{}
+		if err := r.tree.TryAddRoute(path, t); err != nil {
+			return err
+		}
+		if r.paths == nil {
+			r.paths = make(map[string]*methodTable[T])
+		}
+		r.paths[path] = t
+	}
+
+	i, ok := t.index(method)
+	if ok {
+		return &RouteError{
+			Kind:     Conflict,
+			Path:     path,
+			Existing: path,
+			Message:  "a handle is already registered for method " + method + " and path '" + path + "'",
+		}
+	}
+	// Copy on insert, so slices returned as allowed are never changed
+	t.methods = append(append(append(make([]string, 0, len(t.methods)+1), t.methods[:i]...), method), t.methods[i:]...)
+	t.handles = append(append(append(make([]T, 0, len(t.handles)+1), t.handles[:i]...), handle), t.handles[i:]...)
+	r.len++
+	return nil
+}
+
+// RemoveRoute removes the route registered with exactly the given method and
+// path, e.g. "/users/:id", and reports whether there was one.
+func (r *MethodRouter[T]) RemoveRoute(method, path string) bool {
+	t := r.paths[path]
+	if t == nil {
+		return false
+	}
+	i, ok := t.index(method)
+	if !ok {
+		return false
+	}
+
+	if len(t.methods) == 1 {
+		r.tree.RemoveRoute(path)
+		delete(r.paths, path)
+	} else {
+		t.methods = append(append(make([]string, 0, len(t.methods)-1), t.methods[:i]...), t.methods[i+1:]...)
+		t.handles = append(append(make([]T, 0, len(t.handles)-1), t.handles[:i]...), t.handles[i+1:]...)
+	}
+	r.len--
+	return true
+}
+
+// Walk calls fn for every route of the router with its method, path and
+// handle, ordered by the structure of the tree and the methods of each path
+// in lexical order. It stops as soon as fn returns false.
+func (r *MethodRouter[T]) Walk(fn func(method, path string, handle T) bool) {
+	r.tree.Walk(func(path string, t *methodTable[T]) bool {
+		for i, method := range t.methods {
+			if !fn(method, path, t.handles[i]) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// Len returns the number of routes, i.e. pairs of method and path, in the
+// router.
+func (r *MethodRouter[T]) Len() int {
+	return r.len
+}
+
+// FindCaseInsensitivePath looks up the path case-insensitively regardless of
+// the method, see Router.FindCaseInsensitivePath.
+func (r *MethodRouter[T]) FindCaseInsensitivePath(path string, fixTrailingSlash bool) (fixedPath string, found bool) {
+	if r.len == 0 {
+		return "", false
+	}
+	return r.tree.FindCaseInsensitivePath(path, fixTrailingSlash)
+}
@@ -0,0 +1,178 @@
+package drouter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMethodRouterLookup(t *testing.T) {
+	router := NewMethodRouter[string]()
+	router.AddRoute("GET", "/users/:id", "get")
+	router.AddRoute("PUT", "/users/:id", "put")
+	router.AddRoute("DELETE", "/users/:id", "delete")
+	router.AddRoute("GET", "/static/", "static")
+
+	params := make(Params, 0, 1)
+	handle, allowed, tsr := router.Lookup("PUT", "/users/gopher", &params)
+	if handle != "put" || tsr {
+		t.Fatalf("Lookup(PUT) = %q, tsr %v; want put", handle, tsr)
+	}
+	if want := []string{"DELETE", "GET", "PUT"}; !reflect.DeepEqual(allowed, want) {
+		t.Errorf("allowed = %v; want %v", allowed, want)
+	}
+	if want := (Params{Param{"id", "gopher"}}); !reflect.DeepEqual(params, want) {
+		t.Errorf("params = %v; want %v", params, want)
+	}
+
+	// A missing method still reports the allowed ones, but stores no params
+	params = params[:0]
+	handle, allowed, _ = router.Lookup("POST", "/users/gopher", &params)
+	if handle != "" {
+		t.Errorf("Lookup(POST) = %q; want no handle", handle)
+	}
+	if want := []string{"DELETE", "GET", "PUT"}; !reflect.DeepEqual(allowed, want) {
+		t.Errorf("allowed = %v; want %v", allowed, want)
+	}
+	if len(params) != 0 {
+		t.Errorf("params = %v; want none", params)
+	}
+
+	handle, allowed, tsr = router.Lookup("GET", "/static", nil)
+	if handle != "" || allowed != nil || !tsr {
+		t.Errorf("Lookup(/static) = %q, %v, tsr %v; want TSR", handle, allowed, tsr)
+	}
+
+	if got := router.Allowed("/users/1"); !reflect.DeepEqual(got, []string{"DELETE", "GET", "PUT"}) {
+		t.Errorf("Allowed = %v", got)
+	}
+	if got := router.Allowed("/nope"); got != nil {
+		t.Errorf("Allowed(/nope) = %v; want nil", got)
+	}
+}
+
+func TestMethodRouterLookupAllowedStable(t *testing.T) {
+	router := NewMethodRouter[string]()
+	router.AddRoute("GET", "/", "get")
+
+	_, allowed, _ := router.Lookup("GET", "/", nil)
+	router.AddRoute("POST", "/", "post")
+	if !reflect.DeepEqual(allowed, []string{"GET"}) {
+		t.Errorf("allowed returned before adding a method changed to %v", allowed)
+	}
+}
+
+func TestMethodRouterTryAddRoute(t *testing.T) {
+	router := NewMethodRouter[string]()
+	router.AddRoute("GET", "/users/:id", "get")
+
+	tests := []struct {
+		method, path, handle string
+		kind                 RouteErrorKind
+	}{
+		{"", "/users", "h", InvalidMethod},
+		{"GET", "users", "h", InvalidPath},
+		{"GET", "/users/:id", "h", Conflict},
+		// Wildcards are shared across methods
+		{"POST", "/users/new", "h", Conflict},
+	}
+	for _, tt := range tests {
+		err := router.TryAddRoute(tt.method, tt.path, tt.handle)
+		var rerr *RouteError
+		if !errors.As(err, &rerr) || rerr.Kind != tt.kind {
+			t.Errorf("TryAddRoute(%q, %q) = %v; want %v", tt.method, tt.path, err, tt.kind)
+		}
+	}
+	if n := router.Len(); n != 1 {
+		t.Errorf("Len = %d after failed registrations; want 1", n)
+	}
+	if _, ok := router.paths["/users/new"]; ok {
+		t.Error("conflicting path was recorded")
+	}
+
+	err := NewMethodRouter[Handle]().TryAddRoute("GET", "/", nil)
+	if rerr, ok := err.(*RouteError); !ok || rerr.Kind != InvalidHandle {
+		t.Errorf("TryAddRoute(nil) = %v; want %v", err, InvalidHandle)
+	}
+
+	defer func() {
+		if _, ok := recover().(*RouteError); !ok {
+			t.Error("AddRoute did not panic with a *RouteError")
+		}
+	}()
+	router.AddRoute("GET", "/users/:id", "again")
+}
+
+func TestMethodRouterRemoveRoute(t *testing.T) {
+	router := NewMethodRouter[string]()
+	router.AddRoute("GET", "/users/:id", "get")
+	router.AddRoute("PUT", "/users/:id", "put")
+
+	if router.RemoveRoute("POST", "/users/:id") {
+		t.Error("removed unregistered method")
+	}
+	if router.RemoveRoute("GET", "/users/1") {
+		t.Error("removed route by request path")
+	}
+	if !router.RemoveRoute("GET", "/users/:id") {
+		t.Fatal("failed to remove GET")
+	}
+	if got := router.Allowed("/users/1"); !reflect.DeepEqual(got, []string{"PUT"}) {
+		t.Errorf("Allowed = %v; want [PUT]", got)
+	}
+	if !router.RemoveRoute("PUT", "/users/:id") {
+		t.Fatal("failed to remove PUT")
+	}
+	if got := router.Allowed("/users/1"); got != nil {
+		t.Errorf("Allowed = %v after removing all methods; want nil", got)
+	}
+	if n := router.Len(); n != 0 {
+		t.Errorf("Len = %d; want 0", n)
+	}
+
+	// The wildcard is gone, so a static route can take its place
+	router.AddRoute("POST", "/users/new", "new")
+	if handle, _, _ := router.Lookup("POST", "/users/new", nil); handle != "new" {
+		t.Errorf("Lookup = %q; want new", handle)
+	}
+}
+
+func TestMethodRouterWalk(t *testing.T) {
+	router := NewMethodRouter[string]()
+	router.AddRoute("POST", "/a", "1")
+	router.AddRoute("GET", "/a", "2")
+	router.AddRoute("GET", "/b", "3")
+
+	var got []string
+	router.Walk(func(method, path, handle string) bool {
+		got = append(got, method+" "+path+" "+handle)
+		return true
+	})
+	want := []string{"GET /a 2", "POST /a 1", "GET /b 3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Walk = %v; want %v", got, want)
+	}
+	if n := router.Len(); n != 3 {
+		t.Errorf("Len = %d; want 3", n)
+	}
+
+	got = got[:0]
+	router.Walk(func(method, path, _ string) bool {
+		got = append(got, method+" "+path)
+		return false
+	})
+	if len(got) != 1 {
+		t.Errorf("Walk did not stop: %v", got)
+	}
+}
+
+func TestMethodRouterFindCaseInsensitivePath(t *testing.T) {
+	router := NewMethodRouter[string]()
+	if _, found := router.FindCaseInsensitivePath("/users", true); found {
+		t.Error("found path in empty router")
+	}
+	router.AddRoute("GET", "/users", "get")
+	if fixed, found := router.FindCaseInsensitivePath("/USERS/", true); !found || fixed != "/users" {
+		t.Errorf("FindCaseInsensitivePath = %q, %v; want /users", fixed, found)
+	}
+}
@@ -0,0 +1,146 @@
+package drouter
+
+import (
+	"errors"
+	"strings"
+)
+
+// SegmentKind is the kind of a Segment of a route pattern.
+type SegmentKind uint8
+
+const (
+	// StaticSegment is a literal part of a pattern, possibly spanning
+	// several path elements, e.g. "/users/".
+	StaticSegment SegmentKind = iota
+
+	// ParamSegment is a named parameter, e.g. ":id", which matches a single
+	// path element.
+	ParamSegment
+
+	// CatchAllSegment is a catch-all parameter, e.g. "*filepath", which
+	// matches the remainder of the path, including the leading '/'.
+	CatchAllSegment
+)
+
+// Segment is a part of a parsed route pattern.
+type Segment struct {
+	Kind SegmentKind
+
+	// The literal text for static segments, the parameter name otherwise
+	Value string
+
+	// The name of the constraint of a named parameter, if any
+	Constraint string
+
+	// The names of the transforms of a named parameter, if any
+	Transforms []string
+}
+
+// ParsePattern splits a route pattern into its static parts, named parameters
+// and catch-all parameters, applying the same rules as Router.AddRoute.
+// For example "/users/:id/files/*path" is parsed into the segments
+// "/users/", :id, "/files" and *path. Transforms and constraints of named
+// parameters, as in ":name|lower" or ":id|int", are split off the name.
+// It returns an error if the pattern is invalid.
+func ParsePattern(path string) ([]Segment, error) {
+	if len(path) < 1 || path[0] != '/' {
+		return nil, errors.New("path must begin with '/' in path '" + path + "'")
+	}
+
+	var segments []Segment
+	rest := path
+	for {
+		wildcard, i, valid := findWildcard(rest)
+		if i < 0 {
+			if rest != "" {
+				segments = append(segments, Segment{Kind: StaticSegment, Value: rest})
+			}
+			return segments, nil
+		}
+
+		if !valid {
+			return nil, errors.New("only one wildcard per path segment is allowed, has: '" +
+				wildcard + "' in path '" + path + "'")
+		}
+		if len(wildcard) < 2 {
+			return nil, errors.New("wildcards must be named with a non-empty name in path '" + path + "'")
+		}
+
+		if wildcard[0] == ':' {
+			if i > 0 {
+				segments = append(segments, Segment{Kind: StaticSegment, Value: rest[:i]})
+			}
+			spec, err := parseParam(wildcard[1:])
+			if err != nil {
+				return nil, errors.New(err.Error() + " in path '" + path + "'")
+			}
+			segments = append(segments, Segment{
+				Kind:       ParamSegment,
+				Value:      spec.key,
+				Constraint: spec.constraint,
+				Transforms: spec.transforms,
+			})
+			rest = rest[i+len(wildcard):]
+			continue
+		}
+
+		if strings.IndexByte(wildcard, '|') >= 0 {
+			return nil, errors.New("constraints are only allowed for named parameters in path '" + path + "'")
+		}
+		if i+len(wildcard) != len(rest) {
+			return nil, errors.New("catch-all routes are only allowed at the end of the path in path '" + path + "'")
+		}
+		if i == 0 || rest[i-1] != '/' {
+			return nil, errors.New("no / before catch-all in path '" + path + "'")
+		}
+
+		// The '/' before the catch-all is part of the parameter value
+		if i > 1 {
+			segments = append(segments, Segment{Kind: StaticSegment, Value: rest[:i-1]})
+		}
+		segments = append(segments, Segment{Kind: CatchAllSegment, Value: wildcard[1:]})
+		return segments, nil
+	}
+}
+
+// BuildPath builds a request path from a route pattern by filling in the
+// given parameter values, e.g. "/users/:id" with id=42 yields "/users/42".
+// Catch-all values are inserted as they are, a missing leading '/' is added.
+// It returns an error if the pattern is invalid, a parameter is missing or a
+// named parameter value contains a '/' or does not satisfy its constraint.
+func BuildPath(pattern string, ps Params) (string, error) {
+	segments, err := ParsePattern(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, 0, len(pattern)+16)
+	for _, seg := range segments {
+		switch seg.Kind {
+		case StaticSegment:
+			buf = append(buf, seg.Value...)
+
+		case ParamSegment:
+			value := ps.ByName(seg.Value)
+			if value == "" {
+				return "", errors.New("missing value for parameter '" + seg.Value + "' in path '" + pattern + "'")
+			}
+			if strings.IndexByte(value, '/') >= 0 {
+				return "", errors.New("value for parameter '" + seg.Value + "' contains '/' in path '" + pattern + "'")
+			}
+			if seg.Constraint != "" && !lookupConstraint(seg.Constraint)(value) {
+				return "", errors.New("value for parameter '" + seg.Value + "' does not satisfy constraint '" +
+					seg.Constraint + "' in path '" + pattern + "'")
+			}
+			buf = append(buf, value...)
+
+		case CatchAllSegment:
+			value := ps.ByName(seg.Value)
+			if len(value) == 0 || value[0] != '/' {
+				buf = append(buf, '/')
+			}
+			buf = append(buf, value...)
+		}
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,102 @@
+package drouter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		path string
+		want []Segment
+	}{
+		{"/", []Segment{{StaticSegment, "/", "", nil}}},
+		{"/users", []Segment{{StaticSegment, "/users", "", nil}}},
+		{"/users/:id", []Segment{{StaticSegment, "/users/", "", nil}, {ParamSegment, "id", "", nil}}},
+		{"/users/:id/posts/:post", []Segment{
+			{StaticSegment, "/users/", "", nil}, {ParamSegment, "id", "", nil},
+			{StaticSegment, "/posts/", "", nil}, {ParamSegment, "post", "", nil},
+		}},
+		{"/user_:name", []Segment{{StaticSegment, "/user_", "", nil}, {ParamSegment, "name", "", nil}}},
+		{"/src/*filepath", []Segment{{StaticSegment, "/src", "", nil}, {CatchAllSegment, "filepath", "", nil}}},
+		{"/*filepath", []Segment{{CatchAllSegment, "filepath", "", nil}}},
+		{"/:a/*b", []Segment{{StaticSegment, "/", "", nil}, {ParamSegment, "a", "", nil}, {CatchAllSegment, "b", "", nil}}},
+		{"/users/:id|int", []Segment{{StaticSegment, "/users/", "", nil}, {ParamSegment, "id", "int", nil}}},
+		{"/users/:name|trim|lower|alnum", []Segment{
+			{StaticSegment, "/users/", "", nil}, {ParamSegment, "name", "alnum", []string{"trim", "lower"}},
+		}},
+	}
+	for _, test := range tests {
+		got, err := ParsePattern(test.path)
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", test.path, err)
+		} else if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("wrong segments for %s: want %v, got %v", test.path, test.want, got)
+		}
+	}
+}
+
+func TestParsePatternInvalid(t *testing.T) {
+	tests := []struct {
+		path string
+		err  string
+	}{
+		{"", "must begin with '/'"},
+		{"users", "must begin with '/'"},
+		{"/:foo:bar", "only one wildcard per path segment"},
+		{"/:foo*bar", "only one wildcard per path segment"},
+		{"/user:", "non-empty name"},
+		{"/src/*", "non-empty name"},
+		{"/src/*filepath/x", "only allowed at the end"},
+		{"/src*filepath", "no / before catch-all"},
+		{"/users/:id|nope", "unknown constraint"},
+		{"/users/:id|", "unknown constraint"},
+		{"/users/:|int", "non-empty name"},
+		{"/src/*filepath|int", "only allowed for named parameters"},
+		{"/users/:id|int|lower", "must precede constraint"},
+		{"/users/:id|int|alnum", "only one constraint"},
+	}
+	for _, test := range tests {
+		_, err := ParsePattern(test.path)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("wrong error for %s: want %q, got %v", test.path, test.err, err)
+		}
+
+		// The tree must reject the pattern as well
+		if test.path != "" && test.path[0] == '/' {
+			recv := catchPanic(func() {
+				New[Handle]().AddRoute(test.path, fakeHandle(test.path))
+			})
+			if recv == nil {
+				t.Errorf("tree accepted invalid pattern %s", test.path)
+			}
+		}
+	}
+}
+
+func TestBuildPath(t *testing.T) {
+	ps := Params{{"id", "42"}, {"file", "/a/b.txt"}, {"name", "x"}, {"slash", "a/b"}}
+	tests := []struct {
+		pattern string
+		want    string
+		err     bool
+	}{
+		{"/users", "/users", false},
+		{"/users/:id", "/users/42", false},
+		{"/users/:id/files/*file", "/users/42/files/a/b.txt", false},
+		{"/user_:name/*name", "/user_x/x", false},
+		{"/files/*none", "/files/", false},
+		{"/users/:missing", "", true},
+		{"/users/:slash", "", true},
+		{"/users/:id|int", "/users/42", false},
+		{"/users/:name|int", "", true},
+		{"users", "", true},
+	}
+	for _, test := range tests {
+		got, err := BuildPath(test.pattern, ps)
+		if (err != nil) != test.err || got != test.want {
+			t.Errorf("wrong result for %s: want %q (error %t), got %q (%v)", test.pattern, test.want, test.err, got, err)
+		}
+	}
+}
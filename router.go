@@ -1,6 +1,9 @@
 package drouter
 
-import "context"
+import (
+	"bytes"
+	"context"
+)
 
 // Param is a single URL parameter, consisting of a key and a value.
 type Param struct {
@@ -11,8 +14,23 @@ type Param struct {
 // Params is a Param-slice, as returned by the router.
 // The slice is ordered, the first URL parameter is also the first slice value.
 // It is therefore safe to read values by the index.
+//
+// The values are slices of the looked up path and never copied. Since strings
+// are immutable, a value stays valid as long as it is referenced. The slice
+// itself however is usually taken from a pool by the caller of Lookup and
+// reused for the next request once the handle returned. Use Clone to keep
+// the Params beyond that, e.g. in a goroutine started by the handle.
 type Params []Param
 
+// Clone returns a copy of the Params which is not affected by the reuse of
+// the original slice.
+func (ps Params) Clone() Params {
+	if ps == nil {
+		return nil
+	}
+	return append(make(Params, 0, len(ps)), ps...)
+}
+
 // ByName returns the value of the first Param which key matches the given name.
 // If no matching Param is found, an empty string is returned.
 func (ps Params) ByName(name string) string {
@@ -36,61 +54,112 @@ func ParamsFromContext(ctx context.Context) Params {
 }
 
 // MatchedRoutePathParam is the Param name under which the path of the matched
-// route is stored, if Router.SaveMatchedRoutePath is set.
+// route is stored, if SaveMatchedRoutePath of the dhttprouter.HttpRouter is
+// enabled.
 var MatchedRoutePathParam = "$matchedRoutePath"
 
 // MatchedRoutePath retrieves the path of the matched route.
-// Router.SaveMatchedRoutePath must have been enabled when the respective
-// handle was added, otherwise this function always returns an empty string.
+// SaveMatchedRoutePath of the dhttprouter.HttpRouter must be enabled when the
+// request is served, otherwise this function always returns an empty string.
 func (ps Params) MatchedRoutePath() string {
 	return ps.ByName(MatchedRoutePathParam)
 }
 
+// Handle is the type of untyped handles, for routers storing handles of
+// different types, i.e. New[Handle]().
 type Handle interface{}
 
-type Router struct {
-	root *node
+// Router is a radix tree mapping route paths onto handles of type T.
+// Storing the concrete handle type, e.g. a function type, spares users the
+// type assertions and keeps lookups free of allocations.
+type Router[T any] struct {
+	root *node[T]
 }
 
-func New() *Router {
-	return &Router{}
+// New returns a new empty router for handles of type T.
+func New[T any]() *Router[T] {
+	return &Router[T]{}
 }
 
-func (r *Router) Lookup(path string, params *Params) (Handle, bool) {
-	root := r.root
-
-	if root == nil {
-		return nil, false
+// Lookup returns the handle registered for the given path and stores the
+// values of its wildcards in params, which should have enough capacity for
+// them, see CountParams. Otherwise it grows, which allocates.
+// If no handle is found, the zero value of T is returned, along with a
+// recommendation whether a handle exists for the path with (without) a
+// trailing slash.
+func (r *Router[T]) Lookup(path string, params *Params) (handle T, tsr bool) {
+	if r.root == nil {
+		return handle, false
 	}
 
-	handle, tsr := root.getValue(path, params)
-
-	if params == nil {
-		return handle, tsr
+	h, tsr := r.root.getValue(path, params)
+	if h != nil {
+		handle = *h
 	}
-
 	return handle, tsr
 }
 
-func (r *Router) AddRoute(path string, handle Handle) {
+// AddRoute registers a new handle with the given path.
+// It panics if the path is invalid or conflicts with a registered route, in
+// the latter case with a *RouteError naming the conflicting route. See
+// TryAddRoute for adding routes without panicking.
+func (r *Router[T]) AddRoute(path string, handle T) {
 	if len(path) < 1 || path[0] != '/' {
 		panic("path must begin with '/' in path '" + path + "'")
 	}
 
-	if handle == nil {
+	if any(handle) == nil {
 		panic("handle must not be nil")
 	}
 
 	root := r.root
 
 	if root == nil {
-		root = new(node)
+		root = new(node[T])
 		r.root = root
 	}
 
 	root.addRoute(path, handle)
 }
 
-func (r *Router) FindCaseInsensitivePath(path string, fixTrailingSlash bool) (fixedPath string, found bool) {
+// Walk calls fn for every route of the router with its path and handle,
+// ordered by the structure of the tree. It stops as soon as fn returns false.
+func (r *Router[T]) Walk(fn func(path string, handle T) bool) {
+	if r.root != nil {
+		r.root.walk("", fn)
+	}
+}
+
+// DumpTree returns the structure of the radix tree for debugging, one node
+// per line with its path, type, priority and indices. Children are indented
+// below their parent in the order they are tried during lookups.
+func (r *Router[T]) DumpTree() string {
+	if r.root == nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	r.root.dump(&buf, 0)
+	return buf.String()
+}
+
+// Len returns the number of routes in the router.
+func (r *Router[T]) Len() int {
+	if r.root == nil {
+		return 0
+	}
+	return int(r.root.priority)
+}
+
+// RemoveRoute removes the route registered with exactly the given path, e.g.
+// "/users/:id", and reports whether there was one.
+// Like AddRoute, it must not be called concurrently with lookups.
+func (r *Router[T]) RemoveRoute(path string) bool {
+	if r.root == nil {
+		return false
+	}
+	return r.root.removeRoute(path) != nil
+}
+
+func (r *Router[T]) FindCaseInsensitivePath(path string, fixTrailingSlash bool) (fixedPath string, found bool) {
 	return r.root.findCaseInsensitivePath(path, fixTrailingSlash)
 }
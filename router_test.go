@@ -12,7 +12,7 @@ func TestRouterLookup(t *testing.T) {
 	}
 	wantParams := Params{Param{"name", "gopher"}}
 
-	router := New()
+	router := New[Handle]()
 
 	// try empty router first
 	params := make(Params, 0, 1)
@@ -74,3 +74,171 @@ func TestRouterLookup(t *testing.T) {
 		t.Error("Got wrong TSR recommendation!")
 	}
 }
+
+func TestRouterTyped(t *testing.T) {
+	type handler func(Params) string
+
+	router := New[handler]()
+	router.AddRoute("/users/:id", func(ps Params) string { return "user " + ps.ByName("id") })
+
+	params := make(Params, 0, 1)
+	handle, _ := router.Lookup("/users/42", &params)
+	if handle == nil {
+		t.Fatal("Got no handle!")
+	}
+	if got := handle(params); got != "user 42" {
+		t.Errorf("wrong result: %q", got)
+	}
+
+	// Misses return the zero value of the handle type
+	ids := New[int]()
+	ids.AddRoute("/answer", 42)
+	if id, _ := ids.Lookup("/answer", nil); id != 42 {
+		t.Errorf("wrong handle: want 42, got %d", id)
+	}
+	if id, tsr := ids.Lookup("/answer/", nil); id != 0 || !tsr {
+		t.Errorf("wrong lookup of miss: got %d, tsr %v", id, tsr)
+	}
+}
+
+func TestRouterRemoveRoute(t *testing.T) {
+	router := New[Handle]()
+	if router.RemoveRoute("/user/:name") {
+		t.Error("removed route from empty router")
+	}
+
+	router.AddRoute("/user/:name", func() {})
+	router.AddRoute("/user/:name/about", func() {})
+	if router.Len() != 2 {
+		t.Errorf("wrong number of routes: %d", router.Len())
+	}
+
+	if !router.RemoveRoute("/user/:name") {
+		t.Error("failed to remove route")
+	}
+	if router.RemoveRoute("/user/gopher/about") {
+		t.Error("removed route by request path")
+	}
+	if handle, _ := router.Lookup("/user/gopher", nil); handle != nil {
+		t.Error("removed route still matches")
+	}
+	if handle, _ := router.Lookup("/user/gopher/about", nil); handle == nil {
+		t.Error("remaining route no longer matches")
+	}
+	if router.Len() != 1 {
+		t.Errorf("wrong number of routes: %d", router.Len())
+	}
+}
+
+func TestRouterWalk(t *testing.T) {
+	router := New[Handle]()
+	router.Walk(func(path string, _ Handle) bool {
+		t.Errorf("empty router walked route '%s'", path)
+		return true
+	})
+
+	routes := map[string]bool{
+		"/":                   false,
+		"/users/:id|int":      false,
+		"/users/:id|int/edit": false,
+		"/src/*filepath":      false,
+		"/search/":            false,
+	}
+	for path := range routes {
+		router.AddRoute(path, path)
+	}
+
+	router.Walk(func(path string, handle Handle) bool {
+		if handle != path {
+			t.Errorf("wrong handle for route '%s': %v", path, handle)
+		}
+		if visited, ok := routes[path]; !ok || visited {
+			t.Errorf("unexpected route '%s'", path)
+		}
+		routes[path] = true
+		return true
+	})
+	for path, visited := range routes {
+		if !visited {
+			t.Errorf("route '%s' not walked", path)
+		}
+	}
+
+	n := 0
+	router.Walk(func(string, Handle) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("walk did not stop: %d routes", n)
+	}
+}
+
+func TestRouterDumpTree(t *testing.T) {
+	router := New[Handle]()
+	if dump := router.DumpTree(); dump != "" {
+		t.Errorf("unexpected dump of empty router: %q", dump)
+	}
+
+	router.AddRoute("/", "/")
+	router.AddRoute("/users/:id", "/users/:id")
+	router.AddRoute("/users/:id/posts", "/users/:id/posts")
+	router.AddRoute("/src/*filepath", "/src/*filepath")
+
+	want := `"/" (root, priority 4, indices "us", handle)
+  "users/" (static, priority 2, wildcard child)
+    ":id" (param, priority 2, indices "/", handle)
+      "/posts" (static, priority 1, handle)
+  "src" (static, priority 1, indices "/")
+    "" (catchAll, priority 1, wildcard child)
+      "/*filepath" (catchAll, priority 1, handle)
+`
+	if dump := router.DumpTree(); dump != want {
+		t.Errorf("wrong dump:\n%s\nwant:\n%s", dump, want)
+	}
+}
+
+func TestParamsClone(t *testing.T) {
+	if Params(nil).Clone() != nil {
+		t.Error("clone of nil Params is not nil")
+	}
+
+	ps := Params{{"id", "42"}, {"name", "gopher"}}
+	clone := ps.Clone()
+	ps[0].Value = "reused"
+	if !reflect.DeepEqual(clone, Params{{"id", "42"}, {"name", "gopher"}}) {
+		t.Errorf("clone affected by reuse of the original: %v", clone)
+	}
+}
+
+func TestRouterLookupAllocs(t *testing.T) {
+	router := New[Handle]()
+	router.AddRoute("/users/:user/repos/:repo/issues/:issue", "issue")
+
+	// Param values are slices of the path, looking them up does not allocate
+	params := make(Params, 0, 3)
+	allocs := testing.AllocsPerRun(100, func() {
+		params = params[:0]
+		router.Lookup("/users/gopher/repos/drouter/issues/42", &params)
+	})
+	if allocs > 0 {
+		t.Errorf("lookup with params allocated %v times", allocs)
+	}
+	if params.ByName("issue") != "42" {
+		t.Errorf("wrong params: %v", params)
+	}
+}
+
+func TestRouterLookupGrowsParams(t *testing.T) {
+	router := New[Handle]()
+	router.AddRoute("/:a/:b/*c", fakeHandle("abc"))
+
+	ps := make(Params, 0, 1)
+	if handle, _ := router.Lookup("/1/2/3", &ps); handle == nil {
+		t.Fatal("route not found")
+	}
+	want := Params{{"a", "1"}, {"b", "2"}, {"c", "/3"}}
+	if !reflect.DeepEqual(ps, want) {
+		t.Errorf("wrong params: want %v, got %v", want, ps)
+	}
+}
@@ -0,0 +1,142 @@
+package drouter
+
+import (
+	"sort"
+	"strings"
+)
+
+// Suggest returns the registered route paths which are closest to the given
+// path, ordered by ascending distance.
+// The distance is an edit distance over path segments: replacing a segment
+// costs the edit distance between the two segments, inserting or deleting
+// a segment costs its length. Named parameters match any segment and
+// catch-all parameters match any remainder of the path at no cost.
+// Only routes within maxDistance are returned; exact matches are omitted.
+func (r *Router[T]) Suggest(path string, maxDistance int) []string {
+	if r.root == nil || maxDistance <= 0 {
+		return nil
+	}
+
+	type suggestion struct {
+		path     string
+		distance int
+	}
+	var suggestions []suggestion
+
+	segs := splitSegments(path)
+	r.root.walk("", func(route string, _ T) bool {
+		if d := segmentDistance(segs, splitSegments(route), maxDistance); d > 0 && d <= maxDistance {
+			suggestions = append(suggestions, suggestion{route, d})
+		}
+		return true
+	})
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].distance != suggestions[j].distance {
+			return suggestions[i].distance < suggestions[j].distance
+		}
+		return suggestions[i].path < suggestions[j].path
+	})
+
+	if len(suggestions) == 0 {
+		return nil
+	}
+
+	paths := make([]string, len(suggestions))
+	for i := range suggestions {
+		paths[i] = suggestions[i].path
+	}
+	return paths
+}
+
+// PathDistance returns the distance between a request path and a route path
+// as used by Router.Suggest. Computation stops early once the distance is known
+// to exceed maxDistance, in which case maxDistance+1 is returned.
+func PathDistance(path, route string, maxDistance int) int {
+	return segmentDistance(splitSegments(path), splitSegments(route), maxDistance)
+}
+
+// splitSegments splits a path into its segments. A trailing slash is kept as
+// an empty last segment, so /a and /a/ are one edit apart.
+func splitSegments(path string) []string {
+	return strings.Split(strings.TrimPrefix(path, "/"), "/")
+}
+
+// segmentDistance computes the edit distance between the segments of a path
+// and the segments of a route. Computation stops early once the distance is
+// known to exceed max, in which case max+1 is returned.
+func segmentDistance(path, route []string, max int) int {
+	prev := make([]int, len(route)+1)
+	cur := make([]int, len(route)+1)
+	for j := 1; j <= len(route); j++ {
+		prev[j] = prev[j-1] + segmentCost(route[j-1])
+	}
+
+	for i := 1; i <= len(path); i++ {
+		cur[0] = prev[0] + segmentCost(path[i-1])
+		rowMin := cur[0]
+		for j := 1; j <= len(route); j++ {
+			seg := route[j-1]
+			if len(seg) > 0 && seg[0] == '*' {
+				// A catch-all swallows the rest of the path
+				cur[j] = prev[j-1]
+				if prev[j] < cur[j] {
+					cur[j] = prev[j]
+				}
+			} else {
+				cur[j] = prev[j-1] + stringDistance(path[i-1], seg)
+				if d := prev[j] + segmentCost(path[i-1]); d < cur[j] {
+					cur[j] = d
+				}
+				if d := cur[j-1] + segmentCost(seg); d < cur[j] {
+					cur[j] = d
+				}
+			}
+			if cur[j] < rowMin {
+				rowMin = cur[j]
+			}
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(route)]
+}
+
+// segmentCost is the cost of inserting or deleting a whole segment.
+func segmentCost(seg string) int {
+	if len(seg) == 0 || seg[0] == ':' || seg[0] == '*' {
+		return 1
+	}
+	return len(seg)
+}
+
+// stringDistance computes the Levenshtein distance between a path segment and
+// a route segment. Named parameters match every non-empty segment.
+func stringDistance(a, b string) int {
+	if len(b) > 0 && b[0] == ':' {
+		if a == "" {
+			return 1
+		}
+		return 0
+	}
+
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(min(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
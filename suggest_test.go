@@ -0,0 +1,77 @@
+package drouter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRouterSuggest(t *testing.T) {
+	router := New[Handle]()
+	for _, route := range []string{
+		"/users",
+		"/users/:id",
+		"/users/:id/posts",
+		"/user/settings",
+		"/static/*filepath",
+		"/about",
+	} {
+		router.AddRoute(route, fakeHandle(route))
+	}
+
+	tests := []struct {
+		path        string
+		maxDistance int
+		want        []string
+	}{
+		{"/usres", 2, []string{"/users"}},
+		{"/users/42/post", 1, []string{"/users/:id/posts"}},
+		{"/users/42/pots", 1, []string{"/users/:id/posts"}},
+		{"/users/42/pots", 2, []string{"/users/:id/posts", "/users/:id"}},
+		{"/user/setings", 1, []string{"/user/settings", "/users/:id"}},
+		{"/statc/css/app.css", 1, []string{"/static/*filepath"}},
+		{"/abuot", 2, []string{"/about"}},
+		{"/user", 1, []string{"/users"}},
+		{"/users/42", 1, nil}, // exact match
+		{"/completely/different", 2, nil},
+		{"/usres", 0, nil},
+	}
+	for _, test := range tests {
+		if got := router.Suggest(test.path, test.maxDistance); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("wrong suggestions for %s: want %v, got %v", test.path, test.want, got)
+		}
+	}
+
+	// Suggestions are ordered by distance
+	got := router.Suggest("/users/1/p", 5)
+	want := []string{"/users/:id", "/users", "/users/:id/posts"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong suggestion order: want %v, got %v", want, got)
+	}
+
+	if got := New[Handle]().Suggest("/users", 2); got != nil {
+		t.Errorf("empty router returned suggestions: %v", got)
+	}
+}
+
+func TestPathDistance(t *testing.T) {
+	tests := []struct {
+		path, route string
+		want        int
+	}{
+		{"/users/42", "/users/:id", 0},
+		{"/usres/42", "/users/:id", 2},
+		{"/users", "/users/:id", 1},
+		{"/users/42/posts", "/users/:id", 2},
+		{"/files/a/b/c", "/files/*path", 0},
+		{"/a/b/c", "/x/y/z", 3},
+	}
+	for _, test := range tests {
+		if got := PathDistance(test.path, test.route, 10); got != test.want {
+			t.Errorf("wrong distance between %s and %s: want %d, got %d", test.path, test.route, test.want, got)
+		}
+	}
+
+	if got := PathDistance("/aaaa/bbbb", "/cccc/dddd", 2); got != 3 {
+		t.Errorf("bounded distance not cut off: want 3, got %d", got)
+	}
+}
@@ -0,0 +1,139 @@
+package drouter
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+var transforms = struct {
+	sync.RWMutex
+	m map[string]func(string) string
+}{
+	m: map[string]func(string) string{
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+		"trim":  strings.TrimSpace,
+	},
+}
+
+// RegisterTransform registers a named parameter transform, which can then be
+// attached to named parameters of routes in front of their constraint, e.g.
+// "/users/:name|trim|lower" or "/tags/:slug|lower|alnum".
+// The transforms of a parameter are applied in order to its value when a
+// request path is matched, before the constraint is checked and before the
+// value is passed to the handle, so canonicalization happens uniformly at
+// the routing layer.
+//
+// The following transforms are built in:
+//
+//	lower  strings.ToLower
+//	upper  strings.ToUpper
+//	trim   strings.TrimSpace
+//
+// Unicode normalization requires tables this package does not ship, but can
+// be registered with golang.org/x/text/unicode/norm:
+//
+//	drouter.RegisterTransform("nfc", norm.NFC.String)
+//
+// Transforms share their names with constraints and are resolved when a
+// route is added, so they must be registered before. RegisterTransform panics
+// if the name is empty or contains '/', '|', ':' or '*', if fn is nil or if a
+// transform or constraint with the name is already registered.
+func RegisterTransform(name string, fn func(string) string) {
+	if name == "" {
+		panic("transform name must not be empty")
+	}
+	for i := 0; i < len(name); i++ {
+		switch name[i] {
+		case '/', '|', ':', '*':
+			panic("invalid character '" + name[i:i+1] + "' in transform name '" + name + "'")
+		}
+	}
+	if fn == nil {
+		panic("transform function must not be nil")
+	}
+	registerMu.Lock()
+	defer registerMu.Unlock()
+	if lookupConstraint(name) != nil {
+		panic("a constraint named '" + name + "' is already registered")
+	}
+
+	transforms.Lock()
+	defer transforms.Unlock()
+	if _, ok := transforms.m[name]; ok {
+		panic("a transform named '" + name + "' is already registered")
+	}
+	transforms.m[name] = fn
+}
+
+// Transform returns the function of the transform registered with the given
+// name, or nil if there is none.
+func Transform(name string) func(string) string {
+	return lookupTransform(name)
+}
+
+// lookupTransform returns the transform registered with the given name, or
+// nil if there is none.
+func lookupTransform(name string) func(string) string {
+	transforms.RLock()
+	fn := transforms.m[name]
+	transforms.RUnlock()
+	return fn
+}
+
+// paramSpec is a parsed named parameter like "name|trim|lower|alnum".
+type paramSpec struct {
+	key        string
+	transforms []string
+	constraint string
+
+	transform func(string) string
+	check     func(string) bool
+}
+
+// parseParam parses a named parameter without its leading ':' into its name,
+// transforms and constraint.
+func parseParam(param string) (paramSpec, error) {
+	names := strings.Split(param, "|")
+	spec := paramSpec{key: names[0]}
+	if spec.key == "" {
+		return spec, errors.New("wildcards must be named with a non-empty name")
+	}
+
+	var fns []func(string) string
+	for _, name := range names[1:] {
+		if fn := lookupTransform(name); fn != nil {
+			if spec.constraint != "" {
+				return spec, errors.New("transform '" + name + "' must precede constraint '" + spec.constraint + "'")
+			}
+			spec.transforms = append(spec.transforms, name)
+			fns = append(fns, fn)
+			continue
+		}
+		check := lookupConstraint(name)
+		if check == nil {
+			return spec, errors.New("unknown constraint '" + name + "'")
+		}
+		if spec.constraint != "" {
+			return spec, errors.New("only one constraint per parameter is allowed, has: '" +
+				spec.constraint + "' and '" + name + "'")
+		}
+		spec.constraint = name
+		spec.check = check
+	}
+
+	switch len(fns) {
+	case 0:
+	case 1:
+		spec.transform = fns[0]
+	default:
+		spec.transform = func(value string) string {
+			for _, fn := range fns {
+				value = fn(value)
+			}
+			return value
+		}
+	}
+	return spec, nil
+}
@@ -0,0 +1,70 @@
+package drouter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTreeTransforms(t *testing.T) {
+	tree := &node[Handle]{}
+
+	routes := [...]string{
+		"/users/:name|trim|lower",
+		"/tags/:slug|lower|alpha",
+		"/codes/:code|upper/details",
+	}
+	for _, route := range routes {
+		tree.addRoute(route, fakeHandle(route))
+	}
+
+	checkRequests(t, tree, testRequests{
+		{"/users/%20Gopher%20", false, "/users/:name|trim|lower", Params{Param{"name", "%20gopher%20"}}},
+		{"/users/ Gopher ", false, "/users/:name|trim|lower", Params{Param{"name", "gopher"}}},
+		{"/tags/GoLang", false, "/tags/:slug|lower|alpha", Params{Param{"slug", "golang"}}},
+		{"/tags/Go1", true, "", nil},
+		{"/codes/abc/details", false, "/codes/:code|upper/details", Params{Param{"code", "ABC"}}},
+	})
+
+	// The constraint is checked after the transforms
+	RegisterTransform("test-strip-dashes", func(s string) string {
+		return strings.ReplaceAll(s, "-", "")
+	})
+	tree.addRoute("/isbn/:isbn|test-strip-dashes|uint", fakeHandle("isbn"))
+	checkRequests(t, tree, testRequests{
+		{"/isbn/978-3-16-148410-0", false, "isbn", Params{Param{"isbn", "9783161484100"}}},
+		{"/isbn/978-x", true, "", nil},
+	})
+}
+
+func TestRegisterTransform(t *testing.T) {
+	if Transform("lower") == nil || Transform("nope") != nil {
+		t.Error("wrong built-in transforms")
+	}
+
+	tests := []struct {
+		name string
+		fn   func(string) string
+		err  string
+	}{
+		{"", strings.ToLower, "must not be empty"},
+		{"a|b", strings.ToLower, "invalid character"},
+		{"test-nil", nil, "must not be nil"},
+		{"lower", strings.ToLower, "transform named 'lower' is already registered"},
+		{"int", strings.ToLower, "constraint named 'int' is already registered"},
+	}
+	for _, test := range tests {
+		recv := catchPanic(func() {
+			RegisterTransform(test.name, test.fn)
+		})
+		if rs, ok := recv.(string); !ok || !strings.Contains(rs, test.err) {
+			t.Errorf("wrong panic for %q: want %q, got %v", test.name, test.err, recv)
+		}
+	}
+
+	recv := catchPanic(func() {
+		RegisterConstraint("trim", isAlpha)
+	})
+	if rs, ok := recv.(string); !ok || !strings.Contains(rs, "transform named 'trim'") {
+		t.Errorf("constraint shadowing a transform was registered: %v", recv)
+	}
+}
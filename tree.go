@@ -5,6 +5,8 @@
 package drouter
 
 import (
+	"bytes"
+	"fmt"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -51,6 +53,8 @@ func findWildcard(path string) (wilcard string, i int, valid bool) {
 	return "", -1, false
 }
 
+// CountParams returns the number of named and catch-all parameters in the
+// given route pattern. It is used to size the Params slices passed to Lookup.
 func CountParams(path string) uint16 {
 	var n uint
 	for i := range []byte(path) {
@@ -71,18 +75,24 @@ const (
 	catchAll
 )
 
-type node struct {
+type node[T any] struct {
 	path      string
 	indices   string
 	wildChild bool
 	nType     nodeType
 	priority  uint32
-	children  []*node
-	handle    Handle
+	children  []*node[T]
+	handle    *T
+
+	// Name, constraint and transforms of param nodes, see RegisterConstraint
+	// and RegisterTransform
+	key       string
+	check     func(string) bool
+	transform func(string) string
 }
 
 // Increments priority of the given child and reorders if necessary
-func (n *node) incrementChildPrio(pos int) int {
+func (n *node[T]) incrementChildPrio(pos int) int {
 	cs := n.children
 	cs[pos].priority++
 	prio := cs[pos].priority
@@ -104,9 +114,33 @@ func (n *node) incrementChildPrio(pos int) int {
 	return newPos
 }
 
+// Decrements priority of the given child and reorders if necessary
+func (n *node[T]) decrementChildPrio(pos int) int {
+	cs := n.children
+	cs[pos].priority--
+	prio := cs[pos].priority
+
+	// Adjust position (move to back)
+	newPos := pos
+	for ; newPos < len(cs)-1 && cs[newPos+1].priority > prio; newPos++ {
+		// Swap node positions
+		cs[newPos+1], cs[newPos] = cs[newPos], cs[newPos+1]
+	}
+
+	// Build new index char string
+	if newPos != pos {
+		n.indices = n.indices[:pos] + // Unchanged prefix, might be empty
+			n.indices[pos+1:newPos+1] + // Chars moving to the front
+			n.indices[pos:pos+1] + // The index char we move
+			n.indices[newPos+1:] // Unchanged rest
+	}
+
+	return newPos
+}
+
 // addRoute adds a node with the given handler to the path.
 // Not concurrency-safe!
-func (n *node) addRoute(path string, handler Handle) {
+func (n *node[T]) addRoute(path string, handler T) {
 	fullPath := path
 	n.priority++
 
@@ -126,7 +160,7 @@ walk:
 
 		// Split edge
 		if i < len(n.path) {
-			child := node{
+			child := node[T]{
 				path:      n.path[i:],
 				wildChild: n.wildChild,
 				nType:     static,
@@ -136,7 +170,7 @@ walk:
 				priority:  n.priority - 1,
 			}
 
-			n.children = []*node{&child}
+			n.children = []*node[T]{&child}
 			// []byte for proper unicode char conversion, see #65
 			n.indices = string([]byte{n.path[i]})
 			n.path = path[:i]
@@ -165,12 +199,19 @@ walk:
 					if n.nType != catchAll {
 						pathSeg = strings.SplitN(pathSeg, "/", 2)[0]
 					}
-					prefix := fullPath[:strings.Index(fullPath, pathSeg)] + n.path
-					panic("'" + pathSeg +
-						"' in new path '" + fullPath +
-						"' conflicts with existing wildcard '" + n.path +
-						"' in existing prefix '" + prefix +
-						"'")
+					parent := fullPath[:strings.Index(fullPath, pathSeg)]
+					existing := n.firstRoute(parent)
+					panic(&RouteError{
+						Kind:     Conflict,
+						Path:     fullPath,
+						Existing: existing,
+						Segment:  pathSeg,
+						Message: "'" + pathSeg +
+							"' in new path '" + fullPath +
+							"' conflicts with existing wildcard '" + n.path +
+							"' in existing prefix '" + parent + n.path +
+							"' of route '" + existing + "'",
+					})
 				}
 			}
 
@@ -196,7 +237,7 @@ walk:
 			if idxc != ':' && idxc != '*' {
 				// []byte for proper unicode char conversion, see #65
 				n.indices += string([]byte{idxc})
-				child := &node{}
+				child := &node[T]{}
 				n.children = append(n.children, child)
 				n.incrementChildPrio(len(n.indices) - 1)
 				n = child
@@ -207,14 +248,19 @@ walk:
 
 		// Otherwise add handler to current node
 		if n.handle != nil {
-			panic("a handler is already registered for path '" + fullPath + "'")
+			panic(&RouteError{
+				Kind:     Conflict,
+				Path:     fullPath,
+				Existing: fullPath,
+				Message:  "a handler is already registered for path '" + fullPath + "'",
+			})
 		}
-		n.handle = handler
+		n.handle = &handler
 		return
 	}
 }
 
-func (n *node) insertChild(path, fullPath string, handler Handle) {
+func (n *node[T]) insertChild(path, fullPath string, handler T) {
 	for {
 		// Find prefix until first wildcard
 		wildcard, i, valid := findWildcard(path)
@@ -236,8 +282,21 @@ func (n *node) insertChild(path, fullPath string, handler Handle) {
 		// Check if this node has existing children which would be
 		// unreachable if we insert the wildcard here
 		if len(n.children) > 0 {
-			panic("wildcard segment '" + wildcard +
-				"' conflicts with existing children in path '" + fullPath + "'")
+			existing := ""
+			for _, child := range n.children {
+				if existing = child.firstRoute(fullPath[:len(fullPath)-len(path)]); existing != "" {
+					break
+				}
+			}
+			panic(&RouteError{
+				Kind:     Conflict,
+				Path:     fullPath,
+				Existing: existing,
+				Segment:  wildcard,
+				Message: "wildcard segment '" + wildcard +
+					"' conflicts with existing children in path '" + fullPath +
+					"', e.g. route '" + existing + "'",
+			})
 		}
 
 		// param
@@ -248,12 +307,20 @@ func (n *node) insertChild(path, fullPath string, handler Handle) {
 				path = path[i:]
 			}
 
+			spec, err := parseParam(wildcard[1:])
+			if err != nil {
+				panic(err.Error() + " in path '" + fullPath + "'")
+			}
+
 			n.wildChild = true
-			child := &node{
-				nType: param,
-				path:  wildcard,
+			child := &node[T]{
+				nType:     param,
+				path:      wildcard,
+				key:       spec.key,
+				check:     spec.check,
+				transform: spec.transform,
 			}
-			n.children = []*node{child}
+			n.children = []*node[T]{child}
 			n = child
 			n.priority++
 
@@ -261,26 +328,37 @@ func (n *node) insertChild(path, fullPath string, handler Handle) {
 			// will be another non-wildcard subpath starting with '/'
 			if len(wildcard) < len(path) {
 				path = path[len(wildcard):]
-				child := &node{
+				child := &node[T]{
 					priority: 1,
 				}
-				n.children = []*node{child}
+				n.children = []*node[T]{child}
 				n = child
 				continue
 			}
 
 			// Otherwise we're done. Insert the handler in the new leaf
-			n.handle = handler
+			n.handle = &handler
 			return
 		}
 
 		// catchAll
+		if strings.IndexByte(wildcard, '|') >= 0 {
+			panic("constraints are only allowed for named parameters in path '" + fullPath + "'")
+		}
 		if i+len(wildcard) != len(path) {
 			panic("catch-all routes are only allowed at the end of the path in path '" + fullPath + "'")
 		}
 
 		if len(n.path) > 0 && n.path[len(n.path)-1] == '/' {
-			panic("catch-all conflicts with existing handler for the path segment root in path '" + fullPath + "'")
+			existing := fullPath[:len(fullPath)-len(path)]
+			panic(&RouteError{
+				Kind:     Conflict,
+				Path:     fullPath,
+				Existing: existing,
+				Segment:  wildcard,
+				Message: "catch-all conflicts with existing handler for the path segment root in path '" +
+					fullPath + "' of route '" + existing + "'",
+			})
 		}
 
 		// Currently fixed width 1 for '/'
@@ -292,30 +370,161 @@ func (n *node) insertChild(path, fullPath string, handler Handle) {
 		n.path = path[:i]
 
 		// First node: catchAll node with empty path
-		child := &node{
+		child := &node[T]{
 			wildChild: true,
 			nType:     catchAll,
 		}
-		n.children = []*node{child}
+		n.children = []*node[T]{child}
 		n.indices = string('/')
 		n = child
 		n.priority++
 
 		// Second node: node holding the variable
-		child = &node{
+		child = &node[T]{
 			path:     path[i:],
 			nType:    catchAll,
-			handle:   handler,
+			handle:   &handler,
 			priority: 1,
 		}
-		n.children = []*node{child}
+		n.children = []*node[T]{child}
 
 		return
 	}
 
 	// If no wildcard was found, simply insert the path and handler
 	n.path = path
-	n.handle = handler
+	n.handle = &handler
+}
+
+// removeRoute removes the handler registered with exactly the given path and
+// returns it, or nil if there is none.
+// Nodes left without handler and children are removed and a node left with a
+// single static child is merged with it, so the tree stays as compact as if
+// the route had never been added.
+// Not concurrency-safe!
+func (n *node[T]) removeRoute(path string) *T {
+	type step struct {
+		parent *node[T]
+		pos    int
+	}
+	var steps []step
+
+	// Walk down the tree, wildcards are matched literally
+	cur := n
+	for {
+		if len(path) < len(cur.path) || path[:len(cur.path)] != cur.path {
+			return nil
+		}
+		path = path[len(cur.path):]
+		if path == "" {
+			break
+		}
+
+		pos := -1
+		switch {
+		case cur.nType == param:
+			if path[0] == '/' && len(cur.children) > 0 {
+				pos = 0
+			}
+		case cur.wildChild:
+			pos = 0
+		default:
+			pos = strings.IndexByte(cur.indices, path[0])
+		}
+		if pos < 0 {
+			return nil
+		}
+		steps = append(steps, step{cur, pos})
+		cur = cur.children[pos]
+	}
+
+	handle := cur.handle
+	if handle == nil {
+		return nil
+	}
+	cur.handle = nil
+
+	// Remove empty nodes bottom-up
+	for len(steps) > 0 && cur.handle == nil && len(cur.children) == 0 {
+		s := steps[len(steps)-1]
+		steps = steps[:len(steps)-1]
+
+		p := s.parent
+		copy(p.children[s.pos:], p.children[s.pos+1:])
+		p.children[len(p.children)-1] = nil
+		p.children = p.children[:len(p.children)-1]
+		if p.wildChild {
+			p.wildChild = false
+		} else if p.indices != "" {
+			p.indices = p.indices[:s.pos] + p.indices[s.pos+1:]
+		}
+		cur = p
+	}
+
+	// Merge with a single remaining static child
+	if cur.handle == nil && !cur.wildChild && len(cur.children) == 1 &&
+		(cur.nType == static || cur.nType == root) && cur.children[0].nType == static {
+		child := cur.children[0]
+		cur.path += child.path
+		cur.indices = child.indices
+		cur.wildChild = child.wildChild
+		cur.children = child.children
+		cur.handle = child.handle
+	}
+
+	// Update the priorities along the remaining path
+	for i := len(steps) - 1; i >= 0; i-- {
+		steps[i].parent.decrementChildPrio(steps[i].pos)
+	}
+	n.priority--
+
+	// Reset an empty tree
+	if n.handle == nil && len(n.children) == 0 {
+		*n = node[T]{}
+	}
+
+	return handle
+}
+
+// normalize recomputes the priorities of the subtree of n, restores the order
+// of the children and merges nodes without handle with their single static
+// child. It repairs the tree after an insertion failed half-way through and
+// returns the priority of n.
+func (n *node[T]) normalize() uint32 {
+	var prio uint32
+	if n.handle != nil {
+		prio++
+	}
+	for _, child := range n.children {
+		prio += child.normalize()
+	}
+	n.priority = prio
+
+	if n.handle == nil && !n.wildChild && len(n.children) == 1 &&
+		(n.nType == static || n.nType == root) && n.children[0].nType == static {
+		child := n.children[0]
+		n.path += child.path
+		n.indices = child.indices
+		n.wildChild = child.wildChild
+		n.children = child.children
+		n.handle = child.handle
+	}
+
+	// Order children by priority, keeping the index chars in sync
+	cs := n.children
+	indexed := len(n.indices) == len(cs)
+	for i := 1; i < len(cs); i++ {
+		for j := i; j > 0 && cs[j-1].priority < cs[j].priority; j-- {
+			cs[j-1], cs[j] = cs[j], cs[j-1]
+			if indexed {
+				b := []byte(n.indices)
+				b[j-1], b[j] = b[j], b[j-1]
+				n.indices = string(b)
+			}
+		}
+	}
+
+	return prio
 }
 
 // Returns the handler registered with the given path (key). The values of
@@ -323,7 +532,7 @@ func (n *node) insertChild(path, fullPath string, handler Handle) {
 // If no handler can be found, a TSR (trailing slash redirect) recommendation
 // is made if a handler exists with an extra (without the) trailing slash for
 // the given path.
-func (n *node) getValue(path string, params *Params) (handler Handle, tsr bool) {
+func (n *node[T]) getValue(path string, params *Params) (handler *T, tsr bool) {
 walk: // Outer loop for walking the tree
 	for {
 		prefix := n.path
@@ -360,15 +569,25 @@ walk: // Outer loop for walking the tree
 						end++
 					}
 
+					// Transforms canonicalize the value before it is checked
+					value := path[:end]
+					if n.transform != nil {
+						value = n.transform(value)
+					}
+
+					// A value violating the constraint does not match
+					if n.check != nil && !n.check(value) {
+						return
+					}
+
 					// Save param value
 					if params != nil {
-						// Expand slice within preallocated capacity
-						i := len(*params)
-						*params = (*params)[:i+1]
-						(*params)[i] = Param{
-							Key:   n.path[1:],
-							Value: path[:end],
-						}
+						// Expand slice within preallocated capacity, or
+						// grow it if the capacity is exceeded
+						*params = append(*params, Param{
+							Key:   n.key,
+							Value: value,
+						})
 					}
 
 					// We need to go deeper!
@@ -398,13 +617,12 @@ walk: // Outer loop for walking the tree
 				case catchAll:
 					// Save param value
 					if params != nil {
-						// Expand slice within preallocated capacity
-						i := len(*params)
-						*params = (*params)[:i+1]
-						(*params)[i] = Param{
+						// Expand slice within preallocated capacity, or
+						// grow it if the capacity is exceeded
+						*params = append(*params, Param{
 							Key:   n.path[2:],
 							Value: path,
-						}
+						})
 					}
 
 					handler = n.handle
@@ -460,7 +678,7 @@ walk: // Outer loop for walking the tree
 // It can optionally also fix trailing slashes.
 // It returns the case-corrected path and a bool indicating whether the lookup
 // was successful.
-func (n *node) findCaseInsensitivePath(path string, fixTrailingSlash bool) (fixedPath string, found bool) {
+func (n *node[T]) findCaseInsensitivePath(path string, fixTrailingSlash bool) (fixedPath string, found bool) {
 	const stackBufSize = 128
 
 	// Use a static sized buffer on the stack in the common case.
@@ -497,7 +715,7 @@ func shiftNRuneBytes(rb [4]byte, n int) [4]byte {
 }
 
 // Recursive case-insensitive lookup function used by n.findCaseInsensitivePath
-func (n *node) findCaseInsensitivePathRec(path string, ciPath []byte, rb [4]byte, fixTrailingSlash bool) []byte {
+func (n *node[T]) findCaseInsensitivePathRec(path string, ciPath []byte, rb [4]byte, fixTrailingSlash bool) []byte {
 	npLen := len(n.path)
 
 walk: // Outer loop for walking the tree
@@ -601,6 +819,16 @@ walk: // Outer loop for walking the tree
 					end++
 				}
 
+				if n.check != nil {
+					value := path[:end]
+					if n.transform != nil {
+						value = n.transform(value)
+					}
+					if !n.check(value) {
+						return nil
+					}
+				}
+
 				// Add param value to case insensitive path
 				ciPath = append(ciPath, path[:end]...)
 
@@ -677,3 +905,54 @@ walk: // Outer loop for walking the tree
 	}
 	return nil
 }
+
+// walk calls fn for every node with a handle in the subtree of n, passing the
+// full route path of the node. It stops and returns false as soon as fn
+// returns false.
+func (n *node[T]) walk(prefix string, fn func(path string, handle T) bool) bool {
+	path := prefix + n.path
+	if n.handle != nil && !fn(path, *n.handle) {
+		return false
+	}
+	for _, child := range n.children {
+		if !child.walk(path, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// firstRoute returns the path of the first route in the subtree of n, which
+// is reached by the given prefix, or an empty string if there is none.
+func (n *node[T]) firstRoute(prefix string) (route string) {
+	n.walk(prefix, func(path string, _ T) bool {
+		route = path
+		return false
+	})
+	return route
+}
+
+// dump writes the structure of the subtree of n to buf, one node per line,
+// indented by depth.
+func (n *node[T]) dump(buf *bytes.Buffer, depth int) {
+	for i := 0; i < depth; i++ {
+		buf.WriteString("  ")
+	}
+
+	types := [...]string{static: "static", root: "root", param: "param", catchAll: "catchAll"}
+	fmt.Fprintf(buf, "%q (%s, priority %d", n.path, types[n.nType], n.priority)
+	if n.indices != "" {
+		fmt.Fprintf(buf, ", indices %q", n.indices)
+	}
+	if n.wildChild {
+		buf.WriteString(", wildcard child")
+	}
+	if n.handle != nil {
+		buf.WriteString(", handle")
+	}
+	buf.WriteString(")\n")
+
+	for _, child := range n.children {
+		child.dump(buf, depth+1)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 	"testing"
 )
@@ -43,7 +44,7 @@ func getParams() *Params {
 	return &ps
 }
 
-func checkRequests(t *testing.T, tree *node, requests testRequests) {
+func checkRequests(t *testing.T, tree *node[Handle], requests testRequests) {
 	for _, request := range requests {
 		psp := getParams()
 		handle, _ := tree.getValue(request.path, psp)
@@ -56,7 +57,7 @@ func checkRequests(t *testing.T, tree *node, requests testRequests) {
 		case request.nilHandler:
 			t.Errorf("handle mismatch for route '%s': Expected nil handle", request.path)
 		default:
-			handle.(func(params Params))(nil)
+			(*handle).(func(params Params))(nil)
 			if fakeHandleValue != request.route {
 				t.Errorf("handle mismatch for route '%s': Wrong handle (%s != %s)", request.path, fakeHandleValue, request.route)
 			}
@@ -73,7 +74,7 @@ func checkRequests(t *testing.T, tree *node, requests testRequests) {
 	}
 }
 
-func checkPriorities(t *testing.T, n *node) uint32 {
+func checkPriorities(t *testing.T, n *node[Handle]) uint32 {
 	var prio uint32
 	for i := range n.children {
 		prio += checkPriorities(t, n.children[i])
@@ -103,7 +104,7 @@ func TestCountParams(t *testing.T) {
 }
 
 func TestTreeAddAndGet(t *testing.T) {
-	tree := &node{}
+	tree := &node[Handle]{}
 
 	routes := [...]string{
 		"/hi",
@@ -142,7 +143,7 @@ func TestTreeAddAndGet(t *testing.T) {
 }
 
 func TestTreeWildcard(t *testing.T) {
-	tree := &node{}
+	tree := &node[Handle]{}
 
 	routes := [...]string{
 		"/",
@@ -201,12 +202,12 @@ type testRoute struct {
 }
 
 func testRoutes(t *testing.T, routes []testRoute) {
-	tree := &node{}
+	tree := &node[Handle]{}
 
 	for i := range routes {
 		route := routes[i]
 		recv := catchPanic(func() {
-			tree.addRoute(route.path, nil)
+			tree.addRoute(route.path, fakeHandle(route.path))
 		})
 
 		if route.conflict {
@@ -235,7 +236,7 @@ func TestTreeWildcardConflict(t *testing.T) {
 		{"/search/invalid", true},
 		{"/user_:name", false},
 		{"/user_x", true},
-		{"/user_:name", false},
+		{"/user_:name", true},
 		{"/id:id", false},
 		{"/id/:id", true},
 	}
@@ -259,7 +260,7 @@ func TestTreeChildConflict(t *testing.T) {
 }
 
 func TestTreeDupliatePath(t *testing.T) {
-	tree := &node{}
+	tree := &node[Handle]{}
 
 	routes := [...]string{
 		"/",
@@ -279,7 +280,7 @@ func TestTreeDupliatePath(t *testing.T) {
 
 		// Add again
 		recv = catchPanic(func() {
-			tree.addRoute(route, nil)
+			tree.addRoute(route, fakeHandle(route))
 		})
 		if recv == nil {
 			t.Fatalf("no panic while inserting duplicate route '%s", route)
@@ -298,7 +299,7 @@ func TestTreeDupliatePath(t *testing.T) {
 }
 
 func TestEmptyWildcardName(t *testing.T) {
-	tree := &node{}
+	tree := &node[Handle]{}
 
 	routes := [...]string{
 		"/user:",
@@ -309,7 +310,7 @@ func TestEmptyWildcardName(t *testing.T) {
 	for i := range routes {
 		route := routes[i]
 		recv := catchPanic(func() {
-			tree.addRoute(route, nil)
+			tree.addRoute(route, fakeHandle(route))
 		})
 		if recv == nil {
 			t.Fatalf("no panic while inserting route with empty wildcard name '%s", route)
@@ -337,7 +338,7 @@ func TestTreeCatchAllConflictRoot(t *testing.T) {
 }
 
 func TestTreeCatchMaxParams(t *testing.T) {
-	tree := &node{}
+	tree := &node[Handle]{}
 	route := "/cmd/*filepath"
 	tree.addRoute(route, fakeHandle(route))
 }
@@ -353,9 +354,9 @@ func TestTreeDoubleWildcard(t *testing.T) {
 
 	for i := range routes {
 		route := routes[i]
-		tree := &node{}
+		tree := &node[Handle]{}
 		recv := catchPanic(func() {
-			tree.addRoute(route, nil)
+			tree.addRoute(route, fakeHandle(route))
 		})
 
 		if rs, ok := recv.(string); !ok || !strings.HasPrefix(rs, panicMsg) {
@@ -365,7 +366,7 @@ func TestTreeDoubleWildcard(t *testing.T) {
 }
 
 func TestTreeTrailingSlashRedirect(t *testing.T) {
-	tree := &node{}
+	tree := &node[Handle]{}
 
 	routes := [...]string{
 		"/hi",
@@ -451,7 +452,7 @@ func TestTreeTrailingSlashRedirect(t *testing.T) {
 }
 
 func TestTreeRootTrailingSlashRedirect(t *testing.T) {
-	tree := &node{}
+	tree := &node[Handle]{}
 
 	recv := catchPanic(func() {
 		tree.addRoute("/:test", fakeHandle("/:test"))
@@ -469,7 +470,7 @@ func TestTreeRootTrailingSlashRedirect(t *testing.T) {
 }
 
 func TestTreeFindCaseInsensitivePath(t *testing.T) {
-	tree := &node{}
+	tree := &node[Handle]{}
 
 	longPath := "/l" + strings.Repeat("o", 128) + "ng"
 	lOngPath := "/l" + strings.Repeat("O", 128) + "ng/"
@@ -634,7 +635,7 @@ func TestTreeFindCaseInsensitivePath(t *testing.T) {
 func TestTreeInvalidNodeType(t *testing.T) {
 	const panicMsg = "invalid node type"
 
-	tree := &node{}
+	tree := &node[Handle]{}
 	tree.addRoute("/", fakeHandle("/"))
 	tree.addRoute("/:page", fakeHandle("/:page"))
 
@@ -678,7 +679,7 @@ func TestTreeWildcardConflictEx(t *testing.T) {
 		// I have to re-create a 'tree', because the 'tree' will be
 		// in an inconsistent state when the loop recovers from the
 		// panic which threw by 'addRoute' function.
-		tree := &node{}
+		tree := &node[Handle]{}
 		routes := [...]string{
 			"/con:tact",
 			"/who/are/*you",
@@ -709,7 +710,7 @@ func TestRedirectTrailingSlash(t *testing.T) {
 		{"/hello/:name/234"},
 	}
 
-	node := &node{}
+	node := &node[Handle]{}
 	for _, item := range data {
 		node.addRoute(item.path, fakeHandle("test"))
 	}
@@ -719,3 +720,129 @@ func TestRedirectTrailingSlash(t *testing.T) {
 		t.Fatalf("want true, is false")
 	}
 }
+
+// treeShape describes the structure of a tree independent of the order of
+// children with the same priority.
+func treeShape(n *node[Handle]) string {
+	children := make([]string, len(n.children))
+	for i, child := range n.children {
+		children[i] = treeShape(child)
+	}
+	sort.Strings(children)
+	return fmt.Sprintf("{%q %d %t %t %d [%s]}",
+		n.path, n.nType, n.wildChild, n.handle != nil, n.priority, strings.Join(children, " "))
+}
+
+// checkIndices verifies that the index chars match the children and that
+// children are ordered by priority.
+func checkIndices(t *testing.T, n *node[Handle]) {
+	if !n.wildChild && n.nType != param && len(n.indices) != len(n.children) {
+		t.Errorf("indices mismatch for node '%s': %q for %d children", n.path, n.indices, len(n.children))
+	}
+	for i, child := range n.children {
+		if i < len(n.indices) && len(child.path) > 0 && child.path[0] != n.indices[i] {
+			t.Errorf("index char mismatch for node '%s': %q != %q", n.path, n.indices[i], child.path[0])
+		}
+		if i > 0 && n.children[i-1].priority < child.priority {
+			t.Errorf("children of node '%s' not ordered by priority", n.path)
+		}
+		checkIndices(t, child)
+	}
+}
+
+func TestTreeRemove(t *testing.T) {
+	routes := [...]string{
+		"/",
+		"/cmd/:tool/:sub",
+		"/cmd/:tool/",
+		"/src/*filepath",
+		"/search/",
+		"/search/:query",
+		"/user_:name",
+		"/user_:name/about",
+		"/files/:dir/*filepath",
+		"/doc/",
+		"/doc/go_faq.html",
+		"/doc/go1.html",
+		"/info/:user/public",
+		"/info/:user/project/:project",
+		"/α",
+		"/β",
+	}
+
+	// Remove each route on its own and in sequence
+	for skip := range routes {
+		tree := &node[Handle]{}
+		for _, route := range routes {
+			tree.addRoute(route, fakeHandle(route))
+		}
+
+		removed := map[string]bool{}
+		for i := skip; i < len(routes)+skip; i++ {
+			route := routes[i%len(routes)]
+			if tree.removeRoute(route) == nil {
+				t.Errorf("failed to remove route '%s'", route)
+			}
+			if tree.removeRoute(route) != nil {
+				t.Errorf("removed route '%s' twice", route)
+			}
+			removed[route] = true
+
+			// The result must look like a tree built from the remaining routes
+			fresh := &node[Handle]{}
+			for _, route := range routes {
+				if !removed[route] {
+					fresh.addRoute(route, fakeHandle(route))
+				}
+			}
+			if got, want := treeShape(tree), treeShape(fresh); got != want {
+				t.Errorf("wrong tree after removing '%s':\n got %s\nwant %s", route, got, want)
+				return
+			}
+			checkPriorities(t, tree)
+			checkIndices(t, tree)
+
+			for _, route := range routes {
+				handle, _ := tree.getValue(route, nil)
+				if removed[route] && handle != nil {
+					t.Errorf("removed route '%s' still matches", route)
+				} else if !removed[route] && handle == nil {
+					t.Errorf("route '%s' no longer matches after removing '%s'", route, routes[i%len(routes)])
+				}
+			}
+		}
+
+		// The emptied tree can be reused
+		tree.addRoute("/new", fakeHandle("/new"))
+		checkRequests(t, tree, testRequests{
+			{"/new", false, "/new", nil},
+		})
+	}
+}
+
+func TestTreeRemoveUnknown(t *testing.T) {
+	tree := &node[Handle]{}
+	if tree.removeRoute("/") != nil {
+		t.Error("removed route from empty tree")
+	}
+
+	for _, route := range [...]string{"/users/:id", "/users/:id/posts", "/src/*filepath", "/about"} {
+		tree.addRoute(route, fakeHandle(route))
+	}
+	for _, route := range [...]string{
+		"/users/",
+		"/users/42",
+		"/users/:name",
+		"/users/:identity",
+		"/users/:id/",
+		"/src/*path",
+		"/src/",
+		"/abou",
+		"/about/",
+	} {
+		if tree.removeRoute(route) != nil {
+			t.Errorf("removed unregistered route '%s'", route)
+		}
+	}
+	checkPriorities(t, tree)
+}